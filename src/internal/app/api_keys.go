@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/usecase"
+)
+
+// issueAPIKeyOptions are the parsed arguments of the api-keys issue command.
+type issueAPIKeyOptions struct {
+	owner  string
+	scopes []string
+	ttl    time.Duration
+}
+
+// APIKeys implements the api-keys command:
+//
+//	api-keys issue -owner OWNER [-scopes "SCOPE ..."] [-ttl DURATION]
+//
+// issue stores a new API key in the configured database and prints it. It issues
+// the first admin key, which then manages further keys through the admin API. The
+// in-memory driver keeps nothing beyond the command, so it is rejected.
+func APIKeys(cfg *Config, args []string, out io.Writer) error {
+	opts, err := parseAPIKeysArgs(args, out)
+	if err != nil {
+		return err
+	}
+	if cfg == nil || cfg.Database == nil {
+		return errors.New("database configuration is required")
+	}
+	if cfg.Database.DriverName() == database.DriverMemory {
+		return errors.New("api-keys needs a persistent database; DB_DRIVER=memory keeps no keys")
+	}
+
+	ctx := context.Background()
+	logger := initializeLogger(ctx, cfg)
+	db, err := connectToDatabase(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db, logger)
+
+	appLogic, err := initializeAppLogic(db, logger)
+	if err != nil {
+		return err
+	}
+	return runIssueAPIKey(ctx, appLogic, opts, out)
+}
+
+// parseAPIKeysArgs reads the subcommand followed by its flags.
+func parseAPIKeysArgs(args []string, out io.Writer) (issueAPIKeyOptions, error) {
+	var opts issueAPIKeyOptions
+	if len(args) == 0 || args[0] != "issue" {
+		return opts, errors.New("usage: api-keys issue -owner OWNER [-scopes \"SCOPE ...\"] [-ttl DURATION]")
+	}
+
+	var scopes string
+	fs := flag.NewFlagSet("api-keys issue", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.StringVar(&opts.owner, "owner", "", "integrator the key is issued to")
+	fs.StringVar(&scopes, "scopes", "", "space-separated scopes granted to the key, e.g. \"admin\"")
+	fs.DurationVar(&opts.ttl, "ttl", 0, "lifetime of the key; 0 issues a key that stays valid until revoked")
+	if err := fs.Parse(args[1:]); err != nil {
+		return opts, err
+	}
+	if strings.TrimSpace(opts.owner) == "" {
+		return opts, errors.New("-owner is required")
+	}
+	if opts.ttl < 0 {
+		return opts, errors.New("-ttl must not be negative")
+	}
+	opts.scopes = strings.Fields(scopes)
+	return opts, nil
+}
+
+func runIssueAPIKey(ctx context.Context, appLogic *usecase.AppLogic, opts issueAPIKeyOptions, out io.Writer) error {
+	var expiresAt *time.Time
+	if opts.ttl > 0 {
+		at := time.Now().Add(opts.ttl).UTC()
+		expiresAt = &at
+	}
+	stored, key, err := appLogic.IssueAPIKey(ctx, opts.owner, opts.scopes, expiresAt)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "issued API key %s for %s with scopes [%s]\n", stored.ID, stored.Owner, strings.Join(stored.Scopes, " "))
+	_, _ = fmt.Fprintln(out, key)
+	return nil
+}
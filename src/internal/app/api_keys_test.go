@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/usecase"
+)
+
+func TestParseAPIKeysArgs(t *testing.T) {
+	got, err := parseAPIKeysArgs([]string{"issue", "-owner", "ops", "-scopes", "admin examples:read", "-ttl", "720h"}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseAPIKeysArgs() error = %v", err)
+	}
+	want := issueAPIKeyOptions{owner: "ops", scopes: []string{"admin", "examples:read"}, ttl: 720 * time.Hour}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAPIKeysArgs() = %+v, want %+v", got, want)
+	}
+
+	for _, args := range [][]string{
+		nil,
+		{"list"},
+		{"issue"},
+		{"issue", "-owner", "ops", "-ttl", "-1h"},
+		{"issue", "-unknown"},
+	} {
+		if _, err := parseAPIKeysArgs(args, io.Discard); err == nil {
+			t.Errorf("parseAPIKeysArgs(%v) expected error", args)
+		}
+	}
+}
+
+func TestRunIssueAPIKey(t *testing.T) {
+	store := database.NewMemoryStore()
+	appLogic, _ := usecase.NewAppLogic(store, nil)
+	var out bytes.Buffer
+
+	opts := issueAPIKeyOptions{owner: "ops", scopes: []string{"admin"}, ttl: time.Hour}
+	if err := runIssueAPIKey(context.Background(), appLogic, opts, &out); err != nil {
+		t.Fatalf("runIssueAPIKey() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "for ops with scopes [admin]") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	principal, err := auth.NewAPIKeyAuthenticator(store).Authenticate(context.Background(), lines[1])
+	if err != nil || !principal.HasScopes("admin") || principal.ExpiresAt.IsZero() {
+		t.Errorf("Authenticate(printed key) = %+v, %v", principal, err)
+	}
+}
+
+func TestAPIKeysRejectsMemoryDriver(t *testing.T) {
+	cfg := &Config{Database: &database.Config{Driver: database.DriverMemory}}
+
+	err := APIKeys(cfg, []string{"issue", "-owner", "ops"}, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "persistent database") {
+		t.Errorf("APIKeys() error = %v, want persistent database error", err)
+	}
+	if err := APIKeys(nil, []string{"issue", "-owner", "ops"}, io.Discard); err == nil {
+		t.Error("APIKeys() without config should fail")
+	}
+}
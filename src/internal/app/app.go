@@ -24,6 +24,7 @@ func Run(cfg *Config, shutdownChannel chan os.Signal) error {
 	if err != nil {
 		return err
 	}
+	defer closeDatabase(db, logger)
 
 	appLogic, err := initializeAppLogic(db, logger)
 	if err != nil {
@@ -34,10 +35,14 @@ func Run(cfg *Config, shutdownChannel chan os.Signal) error {
 	if err != nil {
 		return err
 	}
-	// add event producer to app logic so we can emit events from use cases
-	appLogic.SetEventProducer(eventService)
+	// outgoing example events are written to the outbox and dispatched by the relay
 	appLogic.SetExampleTopic(cfg.Events.ExampleConsumeQueue)
 
+	outboxRelay, err := events.BuildOutboxRelay(cfg.Events, db, eventService, logger)
+	if err != nil {
+		return err
+	}
+
 	httpServer, err := buildHTTPServer(cfg, appLogic, logger)
 	if err != nil {
 		return err
@@ -48,6 +53,7 @@ func Run(cfg *Config, shutdownChannel chan os.Signal) error {
 	monitorHTTPServerErrors(ctx, srvErr, logger)
 
 	go events.StartEventService(ctx, eventService, logger)
+	go events.StartOutboxRelay(ctx, outboxRelay, logger)
 	go events.RunExampleSimulation(ctx, eventService, cfg.Events)
 
 	<-ctx.Done()
@@ -6,8 +6,10 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"time"
 
 	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/events"
 	"drblury/event-driven-service/internal/usecase"
 	"drblury/event-driven-service/pkg/logging"
 	"drblury/event-driven-service/pkg/logging/metrics"
@@ -65,14 +67,14 @@ func initializeMetrics(ctx context.Context, cfg *Config, logger *slog.Logger) er
 	return nil
 }
 
-// connectToDatabase initialises the database connection pool.
-func connectToDatabase(ctx context.Context, cfg *Config, logger *slog.Logger) (*database.Database, error) {
+// connectToDatabase opens the storage backend selected by DB_DRIVER.
+func connectToDatabase(ctx context.Context, cfg *Config, logger *slog.Logger) (database.Repository, error) {
 	if cfg == nil || cfg.Database == nil {
 		logger.Error("missing database configuration")
 		return nil, errors.New("database configuration is required")
 	}
 
-	db, err := database.NewDatabase(cfg.Database, logger, ctx)
+	db, err := database.Open(ctx, cfg.Database, logger)
 	if err != nil {
 		logger.Error("failed to connect to database", "error", err)
 		return nil, err
@@ -81,11 +83,31 @@ func connectToDatabase(ctx context.Context, cfg *Config, logger *slog.Logger) (*
 }
 
 // initializeAppLogic constructs the core application use cases.
-func initializeAppLogic(db *database.Database, logger *slog.Logger) (*usecase.AppLogic, error) {
+func initializeAppLogic(db database.Repository, logger *slog.Logger) (*usecase.AppLogic, error) {
 	appLogic, err := usecase.NewAppLogic(db, logger)
 	if err != nil {
 		logger.Error("failed to initialize app logic", "error", err)
 		return nil, err
 	}
+
+	// Replaced, patched and updated records follow the protovalidate rules of created ones.
+	validator, err := events.NewValidator()
+	if err != nil {
+		logger.Error("failed to create proto validator", "error", err)
+		return nil, err
+	}
+	appLogic.SetValidator(validator)
 	return appLogic, nil
 }
+
+// closeDatabase releases the storage backend during shutdown.
+func closeDatabase(db database.Repository, logger *slog.Logger) {
+	if db == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.Close(ctx); err != nil {
+		logger.Error("failed to close database", "error", err)
+	}
+}
@@ -245,6 +245,18 @@ func TestConnectToDatabase(t *testing.T) {
 			t.Log("connectToDatabase with empty URL succeeded unexpectedly")
 		}
 	})
+
+	t.Run("memory driver", func(t *testing.T) {
+		cfg := &Config{Database: &database.Config{Driver: database.DriverMemory}}
+		db, err := connectToDatabase(context.Background(), cfg, logger)
+		if err != nil {
+			t.Fatalf("connectToDatabase with memory driver failed: %v", err)
+		}
+		if err := db.Ping(context.Background()); err != nil {
+			t.Errorf("Ping() error = %v", err)
+		}
+		closeDatabase(db, logger)
+	})
 }
 
 func TestInitializeAppLogic(t *testing.T) {
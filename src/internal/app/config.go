@@ -1,13 +1,17 @@
 package app
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
+	"drblury/event-driven-service/internal/auth"
 	"drblury/event-driven-service/internal/database"
 	"drblury/event-driven-service/internal/domain"
 	"drblury/event-driven-service/internal/events"
+	"drblury/event-driven-service/internal/ratelimit"
 	"drblury/event-driven-service/internal/server"
+	"drblury/event-driven-service/internal/usecase"
 	"drblury/event-driven-service/pkg/logging"
 	"drblury/event-driven-service/pkg/logging/metrics"
 	"drblury/event-driven-service/pkg/logging/tracing"
@@ -15,6 +19,7 @@ import (
 	"github.com/drblury/apiweaver/router"
 	"github.com/drblury/protoflow"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
@@ -22,6 +27,8 @@ type Config struct {
 	Info      *domain.Info
 	Router    *router.Config
 	Server    *server.Config
+	Auth      *auth.Config
+	RateLimit *ratelimit.Config
 	Database  *database.Config
 	Logger    *logging.Config
 	Tracing   *tracing.Config
@@ -39,8 +46,27 @@ func SetDefaults() {
 	viper.SetDefault("APP_SERVER_CORS_ORIGINS", []string{"*"})
 	viper.SetDefault("APP_SERVER_HIDE_HEADERS", []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"})
 	viper.SetDefault("APP_SERVER_QUIETDOWN_ROUTES", []string{"/healthz", "/readyz", "/info/status"})
+	viper.SetDefault("APP_SERVER_IDEMPOTENCY_TTL", usecase.DefaultIdempotencyTTL)
 	viper.SetDefault("APP_INFO_TEMPLATE_PATH", "")
 
+	// Authentication
+	viper.SetDefault("AUTH_JWKS_REFRESH_INTERVAL", auth.DefaultJWKSRefreshInterval)
+	viper.SetDefault("AUTH_LEEWAY", 30*time.Second)
+	viper.SetDefault("AUTH_API_KEYS_ENABLED", false)
+
+	// Rate limiting
+	viper.SetDefault("RATE_LIMIT_ENABLED", false)
+	viper.SetDefault("RATE_LIMIT_DEFAULT", "600/1m")
+	viper.SetDefault("RATE_LIMIT_ROUTES", "")
+	viper.SetDefault("RATE_LIMIT_STORE", ratelimit.StoreMemory)
+	viper.SetDefault("RATE_LIMIT_TRUST_FORWARDED_FOR", false)
+
+	// Database
+	viper.SetDefault("DB_DRIVER", "mongo")
+	viper.SetDefault("DB_CONFLICT_POLICY", "reject")
+	viper.SetDefault("DB_MIGRATE_ON_START", true)
+	viper.SetDefault("DB_EXPIRY_SWEEP_INTERVAL", database.DefaultExpirySweepInterval)
+
 	// Logger
 	viper.SetDefault("LOGGER", "json")
 	viper.SetDefault("LOGGER_LEVEL", "debug")
@@ -61,6 +87,31 @@ func SetDefaults() {
 	viper.SetDefault("EVENTS_DEMO_PUBLISH_QUEUE", "messages-processed")
 	viper.SetDefault("EVENTS_EXAMPLE_CONSUME_QUEUE", "example-records")
 	viper.SetDefault("EVENTS_EXAMPLE_PUBLISH_QUEUE", "example-records-processed")
+	viper.SetDefault("EVENTS_EXAMPLE_UPDATE_CONSUME_QUEUE", "example-record-updates")
+	viper.SetDefault("EVENTS_EXAMPLE_FAILED_QUEUE", "example-records-failed")
+	viper.SetDefault("EVENTS_EXAMPLE_BATCH_CONSUME_QUEUE", "example-batches")
+	viper.SetDefault("EVENTS_EXAMPLE_BATCH_PUBLISH_QUEUE", "example-batches-completed")
+	viper.SetDefault("EVENTS_BATCH_PARALLELISM", 4)
+	viper.SetDefault("EVENTS_MAX_CONCURRENCY", 8)
+
+	// Outbox relay
+	viper.SetDefault("EVENTS_OUTBOX_POLL_INTERVAL", time.Second)
+	viper.SetDefault("EVENTS_OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("EVENTS_OUTBOX_MAX_ATTEMPTS", 10)
+	viper.SetDefault("EVENTS_OUTBOX_RETRY_INITIAL_INTERVAL", time.Second)
+	viper.SetDefault("EVENTS_OUTBOX_RETRY_MAX_INTERVAL", 5*time.Minute)
+	viper.SetDefault("EVENTS_OUTBOX_LEASE", 30*time.Second)
+
+	// Inbox deduplication
+	viper.SetDefault("EVENTS_INBOX_ENABLED", true)
+	viper.SetDefault("EVENTS_INBOX_TTL", 24*time.Hour)
+
+	// CloudEvents envelope
+	viper.SetDefault("EVENTS_CLOUDEVENTS_MODE", "binary")
+	viper.SetDefault("EVENTS_CLOUDEVENTS_SOURCE", "event-driven-service")
+
+	// Handler routes
+	viper.SetDefault("EVENTS_ROUTES_FILE", "")
 }
 
 func LoadConfig(
@@ -73,16 +124,31 @@ func LoadConfig(
 	SetDefaults()
 	viper.AutomaticEnv()
 
+	eventsCfg, err := loadEventsConfig()
+	if err != nil {
+		return nil, err
+	}
+	authCfg, err := loadAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	rateLimitCfg, err := loadRateLimitConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Info:      loadInfoConfig(version, buildDate, details, commitHash, commitDate),
 		Router:    loadRouterConfig(),
 		Server:    loadServerConfig(),
+		Auth:      authCfg,
+		RateLimit: rateLimitCfg,
 		Database:  loadDatabaseConfig(),
 		Logger:    loadLoggerConfig(),
 		Tracing:   loadTracingConfig(),
 		Metrics:   loadMetricsConfig(),
 		Protoflow: loadProtoflowConfig(),
-		Events:    loadEventsConfig(),
+		Events:    eventsCfg,
 	}, nil
 }
 
@@ -121,9 +187,48 @@ func loadServerConfig() *server.Config {
 		Address:          "0.0.0.0:" + viper.GetString("APP_SERVER_PORT"),
 		BaseURL:          viper.GetString("APP_SERVER_BASE_URL"),
 		DocsTemplatePath: viper.GetString("APP_INFO_TEMPLATE_PATH"),
+		IdempotencyTTL:   viper.GetDuration("APP_SERVER_IDEMPOTENCY_TTL"),
 	}
 }
 
+func loadAuthConfig() (*auth.Config, error) {
+	operationScopes, err := auth.ParseOperationScopes(viper.GetString("AUTH_OPERATION_SCOPES"))
+	if err != nil {
+		return nil, fmt.Errorf("AUTH_OPERATION_SCOPES: %w", err)
+	}
+
+	return &auth.Config{
+		JWKSFile:            viper.GetString("AUTH_JWKS_FILE"),
+		JWKSURL:             viper.GetString("AUTH_JWKS_URL"),
+		JWKSRefreshInterval: viper.GetDuration("AUTH_JWKS_REFRESH_INTERVAL"),
+		Issuer:              viper.GetString("AUTH_ISSUER"),
+		Audience:            viper.GetString("AUTH_AUDIENCE"),
+		Algorithms:          viper.GetStringSlice("AUTH_ALGORITHMS"),
+		Leeway:              viper.GetDuration("AUTH_LEEWAY"),
+		OperationScopes:     operationScopes,
+		APIKeys:             viper.GetBool("AUTH_API_KEYS_ENABLED"),
+	}, nil
+}
+
+func loadRateLimitConfig() (*ratelimit.Config, error) {
+	defaultLimit, err := ratelimit.ParseLimit(viper.GetString("RATE_LIMIT_DEFAULT"))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_DEFAULT: %w", err)
+	}
+	routes, err := ratelimit.ParseRouteLimits(viper.GetString("RATE_LIMIT_ROUTES"))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_ROUTES: %w", err)
+	}
+
+	return &ratelimit.Config{
+		Enabled:           viper.GetBool("RATE_LIMIT_ENABLED"),
+		Default:           defaultLimit,
+		Routes:            routes,
+		Store:             viper.GetString("RATE_LIMIT_STORE"),
+		TrustForwardedFor: viper.GetBool("RATE_LIMIT_TRUST_FORWARDED_FOR"),
+	}, nil
+}
+
 func loadLoggerConfig() *logging.Config {
 	loggerSelection := strings.ToLower(viper.GetString("LOGGER"))
 	consoleFormat := logging.ParseFormat(loggerSelection)
@@ -210,20 +315,78 @@ func loadProtoflowConfig() *protoflow.Config {
 	}
 }
 
-func loadEventsConfig() *events.Config {
+func loadEventsConfig() (*events.Config, error) {
+	routes, err := loadEventRoutes(viper.GetString("EVENTS_ROUTES_FILE"))
+	if err != nil {
+		return nil, err
+	}
+
 	return &events.Config{
-		DemoConsumeQueue:    viper.GetString("EVENTS_DEMO_CONSUME_QUEUE"),
-		DemoPublishQueue:    viper.GetString("EVENTS_DEMO_PUBLISH_QUEUE"),
-		ExampleConsumeQueue: viper.GetString("EVENTS_EXAMPLE_CONSUME_QUEUE"),
-		ExamplePublishQueue: viper.GetString("EVENTS_EXAMPLE_PUBLISH_QUEUE"),
+		DemoConsumeQueue:          viper.GetString("EVENTS_DEMO_CONSUME_QUEUE"),
+		DemoPublishQueue:          viper.GetString("EVENTS_DEMO_PUBLISH_QUEUE"),
+		ExampleConsumeQueue:       viper.GetString("EVENTS_EXAMPLE_CONSUME_QUEUE"),
+		ExamplePublishQueue:       viper.GetString("EVENTS_EXAMPLE_PUBLISH_QUEUE"),
+		ExampleUpdateConsumeQueue: viper.GetString("EVENTS_EXAMPLE_UPDATE_CONSUME_QUEUE"),
+		ExampleFailedQueue:        viper.GetString("EVENTS_EXAMPLE_FAILED_QUEUE"),
+		ExampleBatchConsumeQueue:  viper.GetString("EVENTS_EXAMPLE_BATCH_CONSUME_QUEUE"),
+		ExampleBatchPublishQueue:  viper.GetString("EVENTS_EXAMPLE_BATCH_PUBLISH_QUEUE"),
+		BatchParallelism:          viper.GetInt("EVENTS_BATCH_PARALLELISM"),
+		MaxConcurrency:            viper.GetInt("EVENTS_MAX_CONCURRENCY"),
+
+		OutboxPollInterval:         viper.GetDuration("EVENTS_OUTBOX_POLL_INTERVAL"),
+		OutboxBatchSize:            viper.GetInt("EVENTS_OUTBOX_BATCH_SIZE"),
+		OutboxMaxAttempts:          viper.GetInt("EVENTS_OUTBOX_MAX_ATTEMPTS"),
+		OutboxRetryInitialInterval: viper.GetDuration("EVENTS_OUTBOX_RETRY_INITIAL_INTERVAL"),
+		OutboxRetryMaxInterval:     viper.GetDuration("EVENTS_OUTBOX_RETRY_MAX_INTERVAL"),
+		OutboxLease:                viper.GetDuration("EVENTS_OUTBOX_LEASE"),
+
+		InboxEnabled: viper.GetBool("EVENTS_INBOX_ENABLED"),
+		InboxTTL:     viper.GetDuration("EVENTS_INBOX_TTL"),
+
+		CloudEventsMode:   viper.GetString("EVENTS_CLOUDEVENTS_MODE"),
+		CloudEventsSource: viper.GetString("EVENTS_CLOUDEVENTS_SOURCE"),
+
+		Routes: routes,
+	}, nil
+}
+
+// loadEventRoutes reads the handler routes from the routes list of the YAML or JSON
+// file at path. Without a file the routes are built from the queue settings. Unknown
+// keys are rejected, so a misspelt transport setting fails at startup.
+func loadEventRoutes(path string) ([]events.Route, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read event routes file: %w", err)
+	}
+	var routes []events.Route
+	rejectUnknownKeys := viper.DecoderConfigOption(func(dc *mapstructure.DecoderConfig) {
+		dc.ErrorUnused = true
+	})
+	if err := v.UnmarshalKey("routes", &routes, rejectUnknownKeys); err != nil {
+		return nil, fmt.Errorf("decode event routes in %s: %w", path, err)
+	}
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("event routes file %s defines no routes", path)
 	}
+	return routes, nil
 }
 
 func loadDatabaseConfig() *database.Config {
 	return &database.Config{
-		MongoURL:      viper.GetString("MONGO_URL"),
-		MongoDB:       viper.GetString("MONGO_DB"),
-		MongoUser:     viper.GetString("MONGO_USER"),
-		MongoPassword: viper.GetString("MONGO_PASSWORD"),
+		Driver:              viper.GetString("DB_DRIVER"),
+		ConflictPolicy:      viper.GetString("DB_CONFLICT_POLICY"),
+		MigrateOnStart:      viper.GetBool("DB_MIGRATE_ON_START"),
+		ExpirySweepInterval: viper.GetDuration("DB_EXPIRY_SWEEP_INTERVAL"),
+		MongoURL:            viper.GetString("MONGO_URL"),
+		MongoDB:             viper.GetString("MONGO_DB"),
+		MongoUser:           viper.GetString("MONGO_USER"),
+		MongoPassword:       viper.GetString("MONGO_PASSWORD"),
+		PostgresURL:         viper.GetString("POSTGRES_URL"),
+		SQLitePath:          viper.GetString("SQLITE_PATH"),
 	}
 }
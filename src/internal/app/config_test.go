@@ -2,8 +2,11 @@ package app
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"drblury/event-driven-service/internal/ratelimit"
 )
 
 func TestSetDefaults(t *testing.T) {
@@ -72,6 +75,9 @@ func testLoadConfigNonNil(t *testing.T, cfg *Config) {
 	if cfg.Server == nil {
 		t.Error("Server config is nil")
 	}
+	if cfg.Auth == nil {
+		t.Error("Auth config is nil")
+	}
 	if cfg.Database == nil {
 		t.Error("Database config is nil")
 	}
@@ -137,6 +143,9 @@ func TestLoadConfigServerDefaults(t *testing.T) {
 	if cfg.Server.Address != "0.0.0.0:80" {
 		t.Errorf("Server.Address = %q, want '0.0.0.0:80'", cfg.Server.Address)
 	}
+	if cfg.Server.IdempotencyTTL != 24*time.Hour {
+		t.Errorf("Server.IdempotencyTTL = %v, want 24h", cfg.Server.IdempotencyTTL)
+	}
 }
 
 func TestLoadConfigRouterDefaults(t *testing.T) {
@@ -164,6 +173,189 @@ func TestLoadConfigEventsDefaults(t *testing.T) {
 	if cfg.Events.ExampleConsumeQueue != "example-records" {
 		t.Errorf("Events.ExampleConsumeQueue = %q, want 'example-records'", cfg.Events.ExampleConsumeQueue)
 	}
+	if cfg.Events.ExampleUpdateConsumeQueue != "example-record-updates" {
+		t.Errorf("Events.ExampleUpdateConsumeQueue = %q, want 'example-record-updates'", cfg.Events.ExampleUpdateConsumeQueue)
+	}
+	if cfg.Events.ExampleFailedQueue != "example-records-failed" {
+		t.Errorf("Events.ExampleFailedQueue = %q, want 'example-records-failed'", cfg.Events.ExampleFailedQueue)
+	}
+	if cfg.Events.ExampleBatchConsumeQueue != "example-batches" {
+		t.Errorf("Events.ExampleBatchConsumeQueue = %q, want 'example-batches'", cfg.Events.ExampleBatchConsumeQueue)
+	}
+	if cfg.Events.ExampleBatchPublishQueue != "example-batches-completed" {
+		t.Errorf("Events.ExampleBatchPublishQueue = %q, want 'example-batches-completed'", cfg.Events.ExampleBatchPublishQueue)
+	}
+	if cfg.Events.BatchParallelism != 4 {
+		t.Errorf("Events.BatchParallelism = %d, want 4", cfg.Events.BatchParallelism)
+	}
+	if cfg.Events.MaxConcurrency != 8 {
+		t.Errorf("Events.MaxConcurrency = %d, want 8", cfg.Events.MaxConcurrency)
+	}
+}
+
+func TestLoadConfigOutboxDefaults(t *testing.T) {
+	SetDefaults()
+
+	cfg, err := LoadConfig("1.0.0", "2024-01-01", "Test", "abc123", "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Events.OutboxPollInterval != time.Second {
+		t.Errorf("Events.OutboxPollInterval = %v, want 1s", cfg.Events.OutboxPollInterval)
+	}
+	if cfg.Events.OutboxBatchSize != 100 {
+		t.Errorf("Events.OutboxBatchSize = %d, want 100", cfg.Events.OutboxBatchSize)
+	}
+	if cfg.Events.OutboxMaxAttempts != 10 {
+		t.Errorf("Events.OutboxMaxAttempts = %d, want 10", cfg.Events.OutboxMaxAttempts)
+	}
+	if cfg.Events.OutboxRetryMaxInterval != 5*time.Minute {
+		t.Errorf("Events.OutboxRetryMaxInterval = %v, want 5m", cfg.Events.OutboxRetryMaxInterval)
+	}
+}
+
+func TestLoadConfigInboxDefaults(t *testing.T) {
+	SetDefaults()
+
+	cfg, err := LoadConfig("1.0.0", "2024-01-01", "Test", "abc123", "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !cfg.Events.InboxEnabled {
+		t.Error("Events.InboxEnabled should be true by default")
+	}
+	if cfg.Events.InboxTTL != 24*time.Hour {
+		t.Errorf("Events.InboxTTL = %v, want 24h", cfg.Events.InboxTTL)
+	}
+}
+
+func TestLoadConfigCloudEventsDefaults(t *testing.T) {
+	SetDefaults()
+
+	cfg, err := LoadConfig("1.0.0", "2024-01-01", "Test", "abc123", "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Events.CloudEventsMode != "binary" {
+		t.Errorf("Events.CloudEventsMode = %q, want 'binary'", cfg.Events.CloudEventsMode)
+	}
+	if cfg.Events.CloudEventsSource != "event-driven-service" {
+		t.Errorf("Events.CloudEventsSource = %q, want 'event-driven-service'", cfg.Events.CloudEventsSource)
+	}
+}
+
+func TestLoadConfigEventRoutes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	content := `routes:
+  - handler: exampleRecordHandler
+    consume_queue: example-records
+    publish_queue: example-records-processed
+  - name: exampleRecordHandlerPriority
+    handler: exampleRecordHandler
+    consume_queue: example-records-priority
+  - handler: exampleBatchHandler
+    consume_queue: example-batches
+    enabled: false
+    options:
+      parallelism: 8
+  - name: exampleRecordHandlerLegacy
+    handler: exampleRecordHandler
+    consume_queue: legacy-records
+    transport:
+      pubsub_system: rabbitmq
+      rabbitmq_url: amqp://legacy:5672/
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	t.Setenv("EVENTS_ROUTES_FILE", path)
+
+	cfg, err := LoadConfig("1.0.0", "2024-01-01", "Test", "abc123", "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	routes := cfg.Events.Routes
+	if len(routes) != 4 {
+		t.Fatalf("len(Events.Routes) = %d, want 4", len(routes))
+	}
+	if routes[1].RouteName() != "exampleRecordHandlerPriority" || routes[1].ConsumeQueue != "example-records-priority" {
+		t.Errorf("Events.Routes[1] = %+v, want the priority route", routes[1])
+	}
+	if routes[2].IsEnabled() {
+		t.Error("Events.Routes[2] should be disabled")
+	}
+	if routes[2].Options["parallelism"] != "8" {
+		t.Errorf("Events.Routes[2].Options = %v, want parallelism 8", routes[2].Options)
+	}
+	if transport := routes[3].Transport; transport == nil || transport.PubSubSystem != "rabbitmq" || transport.RabbitMQURL != "amqp://legacy:5672/" {
+		t.Errorf("Events.Routes[3].Transport = %+v, want the legacy rabbitmq broker", transport)
+	}
+	if routes[0].Transport != nil {
+		t.Errorf("Events.Routes[0].Transport = %+v, want the service transport", routes[0].Transport)
+	}
+}
+
+func TestLoadConfigEventRoutesErrors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "routes.yaml")
+	if err := os.WriteFile(empty, []byte("routes: []\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	misspelt := filepath.Join(t.TempDir(), "misspelt.yaml")
+	content := `routes:
+  - handler: exampleRecordHandler
+    consume_queue: example-records
+    transport:
+      rabbitmq_uri: amqp://legacy:5672/
+`
+	if err := os.WriteFile(misspelt, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	for _, path := range []string{empty, misspelt, filepath.Join(t.TempDir(), "missing.yaml")} {
+		t.Setenv("EVENTS_ROUTES_FILE", path)
+		if _, err := LoadConfig("1.0.0", "2024-01-01", "Test", "abc123", "2024-01-01T00:00:00Z"); err == nil {
+			t.Errorf("LoadConfig() with routes file %s: expected an error", filepath.Base(path))
+		}
+	}
+}
+
+func TestLoadConfigRateLimit(t *testing.T) {
+	SetDefaults()
+
+	cfg, err := LoadConfig("1.0.0", "2024-01-01", "Test", "abc123", "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.RateLimit.Enabled || cfg.RateLimit.StoreName() != ratelimit.StoreMemory {
+		t.Errorf("RateLimit = %+v, want disabled with the memory store", cfg.RateLimit)
+	}
+	if cfg.RateLimit.Default != (ratelimit.Limit{Requests: 600, Period: time.Minute}) {
+		t.Errorf("RateLimit.Default = %v, want 600/1m", cfg.RateLimit.Default)
+	}
+
+	t.Setenv("RATE_LIMIT_ENABLED", "true")
+	t.Setenv("RATE_LIMIT_ROUTES", "CreateExampleRecord=10/1s")
+	t.Setenv("RATE_LIMIT_STORE", "mongo")
+	cfg, err = LoadConfig("1.0.0", "2024-01-01", "Test", "abc123", "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !cfg.RateLimit.Enabled || cfg.RateLimit.Routes["CreateExampleRecord"] != (ratelimit.Limit{Requests: 10, Period: time.Second}) {
+		t.Errorf("RateLimit = %+v, want enabled with the CreateExampleRecord limit", cfg.RateLimit)
+	}
+	if cfg.RateLimit.StoreName() != ratelimit.StoreMongo {
+		t.Errorf("RateLimit.StoreName() = %q, want mongo", cfg.RateLimit.StoreName())
+	}
+
+	for name, value := range map[string]string{"RATE_LIMIT_DEFAULT": "600", "RATE_LIMIT_ROUTES": "CreateExampleRecord"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := LoadConfig("1.0.0", "2024-01-01", "Test", "abc123", "2024-01-01T00:00:00Z"); err == nil {
+				t.Errorf("LoadConfig() with %s=%s: expected an error", name, value)
+			}
+		})
+	}
 }
 
 func TestLoadConfigProtoflowDefaults(t *testing.T) {
@@ -231,6 +423,31 @@ func TestLoadConfigDatabaseConfig(t *testing.T) {
 	if cfg.Database.MongoDB != "testdb" {
 		t.Errorf("Database.MongoDB = %q, want 'testdb'", cfg.Database.MongoDB)
 	}
+	if cfg.Database.Driver != "mongo" {
+		t.Errorf("Database.Driver = %q, want 'mongo'", cfg.Database.Driver)
+	}
+	if cfg.Database.ConflictPolicy != "reject" {
+		t.Errorf("Database.ConflictPolicy = %q, want 'reject'", cfg.Database.ConflictPolicy)
+	}
+	if !cfg.Database.MigrateOnStart {
+		t.Error("Database.MigrateOnStart should default to true")
+	}
+	if cfg.Database.ExpirySweepInterval != time.Minute {
+		t.Errorf("Database.ExpirySweepInterval = %v, want 1m", cfg.Database.ExpirySweepInterval)
+	}
+}
+
+func TestLoadConfigMemoryDriver(t *testing.T) {
+	os.Setenv("DB_DRIVER", "memory")
+	defer os.Unsetenv("DB_DRIVER")
+
+	cfg, err := LoadConfig("1.0.0", "2024-01-01", "Test", "abc123", "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Database.Driver != "memory" {
+		t.Errorf("Database.Driver = %q, want 'memory'", cfg.Database.Driver)
+	}
 }
 
 func TestLoadConfigWithCustomServerPort(t *testing.T) {
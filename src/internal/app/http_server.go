@@ -3,16 +3,23 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"log/slog"
 
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/events"
+	"drblury/event-driven-service/internal/ratelimit"
 	"drblury/event-driven-service/internal/server"
 	gen "drblury/event-driven-service/internal/server/gen"
 	"drblury/event-driven-service/internal/server/handler/apihandler"
 	"drblury/event-driven-service/internal/usecase"
 
 	"github.com/drblury/apiweaver/router"
+	"github.com/getkin/kin-openapi/openapi3"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
@@ -21,8 +28,13 @@ import (
 func buildHTTPServer(cfg *Config, appLogic *usecase.AppLogic, logger *slog.Logger) (*server.Server, error) {
 	apiHandler := apihandler.NewAPIHandler(appLogic, cfg.Info, logger, cfg.Server.BaseURL, cfg.Server.DocsTemplatePath)
 
-	handler := gen.HandlerFromMux(apiHandler, nil)
-	handler = otelhttp.NewHandler(handler, "/")
+	// Requests are checked against the same protovalidate rules as consumed events.
+	validator, err := events.NewValidator()
+	if err != nil {
+		logger.Error("failed to create proto validator", "error", err)
+		return nil, err
+	}
+	apiHandler.Validator = validator
 
 	swagger, err := gen.GetSwagger()
 	if err != nil {
@@ -30,9 +42,54 @@ func buildHTTPServer(cfg *Config, appLogic *usecase.AppLogic, logger *slog.Logge
 		return nil, err
 	}
 
+	authenticator, err := auth.New(cfg.Auth)
+	if err != nil {
+		logger.Error("failed to create authenticator", "error", err)
+		return nil, err
+	}
+	apiKeysEnabled := cfg.Auth != nil && cfg.Auth.APIKeys
+	if apiKeysEnabled {
+		apiKeys, err := appLogic.APIKeyAuthenticator()
+		if err != nil {
+			logger.Error("failed to create API key authenticator", "error", err)
+			return nil, err
+		}
+		apiHandler.APIKeys = apiKeys
+	}
+	if authenticator == nil && !apiKeysEnabled {
+		logger.Warn("authentication disabled, set AUTH_JWKS_FILE, AUTH_JWKS_URL or AUTH_API_KEYS_ENABLED to protect the API")
+	}
+	policy, err := buildPolicy(cfg.Auth, swagger)
+	if err != nil {
+		logger.Error("failed to build authorization policy", "error", err)
+		return nil, err
+	}
+	apiHandler.Authenticator = authenticator
+	apiHandler.Policy = policy
+	appLogic.RequireAuthentication(authenticator != nil || apiKeysEnabled)
+	appLogic.SetIdempotencyTTL(cfg.Server.IdempotencyTTL)
+
+	limiter, err := buildRateLimiter(cfg.RateLimit, appLogic, policy)
+	if err != nil {
+		logger.Error("failed to create rate limiter", "error", err)
+		return nil, err
+	}
+	apiHandler.Limiter = limiter
+
+	// The last middleware runs first, so requests are limited per authenticated caller.
+	// Authenticate charges the requests it denies to their IP address.
+	handler := gen.HandlerWithOptions(apiHandler, gen.StdHTTPServerOptions{
+		Middlewares: []gen.MiddlewareFunc{apiHandler.RateLimit, apiHandler.Authenticate},
+	})
+	handler = otelhttp.NewHandler(handler, "/")
+
+	// API keys are credentials like the Authorization header, so they never reach the request log.
+	routerCfg := *cfg.Router
+	routerCfg.HideHeaders = withHeader(routerCfg.HideHeaders, auth.APIKeyHeader)
+
 	options := []router.Option{
 		router.WithLogger(logger),
-		router.WithConfig(*cfg.Router),
+		router.WithConfig(routerCfg),
 		router.WithSwagger(swagger),
 	}
 
@@ -40,6 +97,54 @@ func buildHTTPServer(cfg *Config, appLogic *usecase.AppLogic, logger *slog.Logge
 	return server.NewServer(cfg.Server, r), nil
 }
 
+// buildPolicy derives the scopes each operation requires from the security blocks
+// of the API spec and applies the configured overrides.
+func buildPolicy(cfg *auth.Config, swagger *openapi3.T) (*auth.Policy, error) {
+	policy, err := auth.NewPolicy(swagger)
+	if err != nil {
+		return nil, err
+	}
+	if cfg != nil {
+		if err := policy.Apply(cfg.OperationScopes); err != nil {
+			return nil, fmt.Errorf("AUTH_OPERATION_SCOPES: %w", err)
+		}
+	}
+	return policy, nil
+}
+
+// buildRateLimiter returns the limiter described by cfg, or nil when rate limiting
+// is disabled. The buckets are kept in memory unless the Mongo store is selected.
+func buildRateLimiter(cfg *ratelimit.Config, appLogic *usecase.AppLogic, policy *auth.Policy) (*ratelimit.Limiter, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	for operation := range cfg.Routes {
+		if !policy.HasOperation(operation) {
+			return nil, fmt.Errorf("RATE_LIMIT_ROUTES: unknown operation %q", operation)
+		}
+	}
+
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.StoreName() == ratelimit.StoreMongo {
+		counter, err := appLogic.RateLimitCounter()
+		if err != nil {
+			return nil, fmt.Errorf("RATE_LIMIT_STORE=mongo: %w", err)
+		}
+		store = ratelimit.NewSharedStore(counter)
+	}
+	return ratelimit.New(cfg, store)
+}
+
+// withHeader returns headers with header appended unless it is already listed.
+func withHeader(headers []string, header string) []string {
+	for _, h := range headers {
+		if strings.EqualFold(strings.TrimSpace(h), header) {
+			return headers
+		}
+	}
+	return append(slices.Clone(headers), header)
+}
+
 // runHTTPServer starts the HTTP server asynchronously and forwards fatal errors to the provided channel.
 func runHTTPServer(srv *server.Server, cfg *Config, logger *slog.Logger, errChan chan<- error) {
 	if srv == nil || cfg == nil || cfg.Server == nil {
@@ -5,12 +5,20 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/database"
 	"drblury/event-driven-service/internal/domain"
+	"drblury/event-driven-service/internal/ratelimit"
 	"drblury/event-driven-service/internal/server"
+	gen "drblury/event-driven-service/internal/server/gen"
 	"drblury/event-driven-service/internal/usecase"
 
 	"github.com/drblury/apiweaver/router"
@@ -188,6 +196,162 @@ func buildFullConfigTestCase() *Config {
 	}
 }
 
+func TestBuildHTTPServerCreatesExampleRecord(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	cfg := &Config{Server: &server.Config{Address: ":0"}, Router: &router.Config{}, Info: &domain.Info{}}
+
+	store := database.NewMemoryStore()
+	appLogic, _ := usecase.NewAppLogic(store, logger)
+	appLogic.SetExampleTopic("examples")
+	srv, err := buildHTTPServer(cfg, appLogic, logger)
+	if err != nil {
+		t.Fatalf("buildHTTPServer failed: %v", err)
+	}
+
+	// The body uses the field names of the API spec, so it passes the request
+	// validation of the router and the proto rules of the handler.
+	body := `{
+		"recordId": "EX-1",
+		"title": "Example",
+		"meta": {"requestedBy": "tester", "desiredStartDate": {"year": 2025, "month": 4, "day": 18}, "priority": 3}
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /examples = %d %s, want %d", rec.Code, rec.Body, http.StatusAccepted)
+	}
+	record, err := store.GetExampleRecordByID(context.Background(), "EX-1")
+	if err != nil {
+		t.Fatalf("GetExampleRecordByID() error = %v", err)
+	}
+	if record.GetMeta().GetRequestedBy() != "tester" || record.GetMeta().GetDesiredStartDate().GetDay() != 18 {
+		t.Errorf("stored record = %v, want the submitted meta", record)
+	}
+}
+
+func TestBuildHTTPServerRejectsInvalidAuthConfig(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	cfg := &Config{
+		Server: &server.Config{Address: ":0"},
+		Router: &router.Config{},
+		Info:   &domain.Info{},
+		Auth:   &auth.Config{JWKSFile: filepath.Join(t.TempDir(), "missing.json"), Issuer: "https://issuer.example", Audience: "api"},
+	}
+
+	appLogic, _ := usecase.NewAppLogic(nil, logger)
+	if _, err := buildHTTPServer(cfg, appLogic, logger); err == nil {
+		t.Error("buildHTTPServer succeeded with a missing JWKS file")
+	}
+}
+
+func TestBuildHTTPServerWithAPIKeys(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	cfg := &Config{
+		Server: &server.Config{Address: ":0", IdempotencyTTL: time.Hour},
+		Router: &router.Config{},
+		Info:   &domain.Info{},
+		Auth:   &auth.Config{APIKeys: true},
+	}
+
+	withoutDatabase, _ := usecase.NewAppLogic(nil, logger)
+	if _, err := buildHTTPServer(cfg, withoutDatabase, logger); err == nil {
+		t.Error("buildHTTPServer accepted API keys without a database")
+	}
+
+	appLogic, _ := usecase.NewAppLogic(database.NewMemoryStore(), logger)
+	if _, err := buildHTTPServer(cfg, appLogic, logger); err != nil {
+		t.Fatalf("buildHTTPServer failed: %v", err)
+	}
+	if got := appLogic.IdempotencyTTL(); got != time.Hour {
+		t.Errorf("IdempotencyTTL() = %v, want the configured 1h", got)
+	}
+	// API keys alone turn authentication on.
+	if _, _, err := appLogic.IssueAPIKey(context.Background(), "ops", nil, nil); !errors.Is(err, domain.ErrorUnauthorized) {
+		t.Errorf("IssueAPIKey() without principal error = %v, want unauthorized", err)
+	}
+}
+
+func TestBuildRateLimiter(t *testing.T) {
+	swagger, err := gen.GetSwagger()
+	if err != nil {
+		t.Fatalf("GetSwagger() error = %v", err)
+	}
+	policy, err := auth.NewPolicy(swagger)
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+	memory, _ := usecase.NewAppLogic(database.NewMemoryStore(), nil)
+	mongo, _ := usecase.NewAppLogic(&database.Database{}, nil)
+	enabled := func(store string, routes map[string]ratelimit.Limit) *ratelimit.Config {
+		return &ratelimit.Config{Enabled: true, Default: ratelimit.Limit{Requests: 10, Period: time.Second}, Routes: routes, Store: store}
+	}
+
+	if limiter, err := buildRateLimiter(&ratelimit.Config{}, memory, policy); limiter != nil || err != nil {
+		t.Errorf("disabled buildRateLimiter() = %v, %v; want nil", limiter, err)
+	}
+	if limiter, err := buildRateLimiter(enabled("", map[string]ratelimit.Limit{"CreateExampleRecord": {Requests: 1, Period: time.Second}}), memory, policy); limiter == nil || err != nil {
+		t.Errorf("in-memory buildRateLimiter() = %v, %v", limiter, err)
+	}
+	if limiter, err := buildRateLimiter(enabled(ratelimit.StoreMongo, nil), mongo, policy); limiter == nil || err != nil {
+		t.Errorf("shared buildRateLimiter() = %v, %v", limiter, err)
+	}
+
+	if _, err := buildRateLimiter(enabled("", map[string]ratelimit.Limit{"createExampleRecord": {Requests: 1, Period: time.Second}}), memory, policy); err == nil {
+		t.Error("buildRateLimiter() accepted an unknown operation")
+	}
+	if _, err := buildRateLimiter(enabled(ratelimit.StoreMongo, nil), memory, policy); err == nil {
+		t.Error("buildRateLimiter() accepted the mongo store without MongoDB")
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	t.Parallel()
+	configured := []string{"Authorization", "Cookie"}
+
+	got := withHeader(configured, auth.APIKeyHeader)
+	if !reflect.DeepEqual(got, []string{"Authorization", "Cookie", "X-API-Key"}) {
+		t.Errorf("withHeader() = %v", got)
+	}
+	if len(configured) != 2 {
+		t.Error("withHeader() modified the configured headers")
+	}
+	if got := withHeader([]string{"x-api-key"}, auth.APIKeyHeader); len(got) != 1 {
+		t.Errorf("withHeader() = %v, want the header listed once", got)
+	}
+	if got := withHeader(nil, auth.APIKeyHeader); !reflect.DeepEqual(got, []string{"X-API-Key"}) {
+		t.Errorf("withHeader(nil) = %v", got)
+	}
+}
+
+func TestBuildPolicy(t *testing.T) {
+	t.Parallel()
+	swagger, err := gen.GetSwagger()
+	if err != nil {
+		t.Fatalf("GetSwagger() error = %v", err)
+	}
+
+	policy, err := buildPolicy(&auth.Config{OperationScopes: map[string][]string{"GetOpenAPIHTML": {"admin"}}}, swagger)
+	if err != nil {
+		t.Fatalf("buildPolicy() error = %v", err)
+	}
+	if got := policy.Requirement("GetOpenAPIHTML"); len(got) != 1 || got[0][0] != "admin" {
+		t.Errorf("Requirement(GetOpenAPIHTML) = %v, want admin", got)
+	}
+	if got := policy.Requirement("CreateExampleRecord"); len(got) != 1 || got[0][0] != "examples:write" {
+		t.Errorf("Requirement(CreateExampleRecord) = %v, want the scope of the spec", got)
+	}
+
+	if _, err := buildPolicy(&auth.Config{OperationScopes: map[string][]string{"GetDocs": {"admin"}}}, swagger); err == nil {
+		t.Error("buildPolicy() accepted an unknown operation")
+	}
+}
+
 func TestHTTPServerLifecycle(t *testing.T) {
 	t.Parallel()
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"drblury/event-driven-service/internal/database"
+)
+
+// migrateOptions are the parsed arguments of the migrate command.
+type migrateOptions struct {
+	command string
+	target  int
+	dryRun  bool
+}
+
+// Migrate implements the migrate command:
+//
+//	migrate [up|down|status] [-to VERSION] [-dry-run]
+//
+// up applies pending MongoDB migrations, up to VERSION when given. down rolls back
+// the migrations above VERSION. status lists all registered migrations. With
+// -dry-run, up and down only print the steps they would run.
+func Migrate(cfg *Config, args []string, out io.Writer) error {
+	opts, err := parseMigrateArgs(args, out)
+	if err != nil {
+		return err
+	}
+	if cfg == nil || cfg.Database == nil {
+		return errors.New("database configuration is required")
+	}
+	if driver := cfg.Database.DriverName(); driver != database.DriverMongo {
+		return fmt.Errorf("migrate manages MongoDB only; DB_DRIVER=%s applies its migrations on startup", driver)
+	}
+
+	ctx := context.Background()
+	logger := initializeLogger(ctx, cfg)
+
+	// Connect without the automatic startup migration; this command decides what runs.
+	dbCfg := *cfg.Database
+	dbCfg.MigrateOnStart = false
+	db, err := database.NewDatabase(&dbCfg, logger, ctx)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db, logger)
+
+	migrator, err := database.NewMigrator(db.DB, database.MongoMigrations, logger)
+	if err != nil {
+		return err
+	}
+	return runMigrateCommand(ctx, migrator, opts, out)
+}
+
+// parseMigrateArgs reads the optional subcommand followed by its flags.
+func parseMigrateArgs(args []string, out io.Writer) (migrateOptions, error) {
+	opts := migrateOptions{command: string(database.MigrateUp)}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		opts.command = args[0]
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.IntVar(&opts.target, "to", 0, "target version; up applies up to it, down rolls back everything above it")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "print the migrations that would run without applying them")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+
+	targetSet := false
+	fs.Visit(func(f *flag.Flag) { targetSet = targetSet || f.Name == "to" })
+
+	switch opts.command {
+	case string(database.MigrateUp), "status":
+	case string(database.MigrateDown):
+		if !targetSet {
+			return opts, errors.New("migrate down requires -to VERSION (use -to 0 to roll back everything)")
+		}
+	default:
+		return opts, fmt.Errorf("unknown migrate command %q, want up, down or status", opts.command)
+	}
+	if opts.target < 0 {
+		return opts, errors.New("-to must not be negative")
+	}
+	return opts, nil
+}
+
+// migrationRunner is the part of *database.Migrator used by the migrate command.
+// *database.Migrator satisfies it.
+type migrationRunner interface {
+	Status(ctx context.Context) ([]database.MigrationStatus, error)
+	Plan(ctx context.Context, direction database.MigrationDirection, target int) ([]database.MigrationStep, error)
+	Migrate(ctx context.Context, direction database.MigrationDirection, target int) ([]database.MigrationStep, error)
+}
+
+func runMigrateCommand(ctx context.Context, migrator migrationRunner, opts migrateOptions, out io.Writer) error {
+	if opts.command == "status" {
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02T15:04:05Z07:00")
+			}
+			_, _ = fmt.Fprintf(out, "%4d  %-40s %s\n", status.Version, status.Description, applied)
+		}
+		return nil
+	}
+
+	direction := database.MigrationDirection(opts.command)
+	run, verb := migrator.Migrate, "ran"
+	if opts.dryRun {
+		run, verb = migrator.Plan, "would run"
+	}
+
+	steps, err := run(ctx, direction, opts.target)
+	printMigrationSteps(out, verb, steps)
+	return err
+}
+
+func printMigrationSteps(out io.Writer, verb string, steps []database.MigrationStep) {
+	if len(steps) == 0 {
+		_, _ = fmt.Fprintln(out, "no migrations to run")
+		return
+	}
+	for _, step := range steps {
+		_, _ = fmt.Fprintf(out, "%s %s %4d  %s\n", verb, step.Direction, step.Version, step.Description)
+	}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+)
+
+type fakeMigrator struct {
+	planned  bool
+	migrated bool
+	steps    []database.MigrationStep
+	statuses []database.MigrationStatus
+}
+
+func (f *fakeMigrator) Status(context.Context) ([]database.MigrationStatus, error) {
+	return f.statuses, nil
+}
+
+func (f *fakeMigrator) Plan(context.Context, database.MigrationDirection, int) ([]database.MigrationStep, error) {
+	f.planned = true
+	return f.steps, nil
+}
+
+func (f *fakeMigrator) Migrate(context.Context, database.MigrationDirection, int) ([]database.MigrationStep, error) {
+	f.migrated = true
+	return f.steps, nil
+}
+
+func TestParseMigrateArgs(t *testing.T) {
+	testCases := []struct {
+		args    []string
+		want    migrateOptions
+		wantErr bool
+	}{
+		{args: nil, want: migrateOptions{command: "up"}},
+		{args: []string{"-dry-run"}, want: migrateOptions{command: "up", dryRun: true}},
+		{args: []string{"up", "-to", "3"}, want: migrateOptions{command: "up", target: 3}},
+		{args: []string{"down", "-to", "0"}, want: migrateOptions{command: "down"}},
+		{args: []string{"status"}, want: migrateOptions{command: "status"}},
+		{args: []string{"down"}, wantErr: true},
+		{args: []string{"sideways"}, wantErr: true},
+		{args: []string{"up", "-to", "-1"}, wantErr: true},
+		{args: []string{"up", "-unknown"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := parseMigrateArgs(tc.args, io.Discard)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("parseMigrateArgs(%v) expected error", tc.args)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("parseMigrateArgs(%v) = %+v, %v; want %+v", tc.args, got, err, tc.want)
+		}
+	}
+}
+
+func TestRunMigrateCommandDryRunOnlyPlans(t *testing.T) {
+	migrator := &fakeMigrator{steps: []database.MigrationStep{
+		{Version: 1, Description: "unique index", Direction: database.MigrateUp},
+	}}
+	var out bytes.Buffer
+
+	err := runMigrateCommand(context.Background(), migrator, migrateOptions{command: "up", dryRun: true}, &out)
+	if err != nil {
+		t.Fatalf("runMigrateCommand() error = %v", err)
+	}
+	if !migrator.planned || migrator.migrated {
+		t.Errorf("dry run planned=%v migrated=%v, want only planned", migrator.planned, migrator.migrated)
+	}
+	if !strings.Contains(out.String(), "would run up") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestRunMigrateCommandApplies(t *testing.T) {
+	migrator := &fakeMigrator{}
+	var out bytes.Buffer
+
+	if err := runMigrateCommand(context.Background(), migrator, migrateOptions{command: "up"}, &out); err != nil {
+		t.Fatalf("runMigrateCommand() error = %v", err)
+	}
+	if !migrator.migrated {
+		t.Error("expected migrations to run")
+	}
+	if !strings.Contains(out.String(), "no migrations to run") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestRunMigrateCommandStatus(t *testing.T) {
+	appliedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	migrator := &fakeMigrator{statuses: []database.MigrationStatus{
+		{Version: 1, Description: "one", AppliedAt: &appliedAt},
+		{Version: 2, Description: "two"},
+	}}
+	var out bytes.Buffer
+
+	if err := runMigrateCommand(context.Background(), migrator, migrateOptions{command: "status"}, &out); err != nil {
+		t.Fatalf("runMigrateCommand() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "applied 2025-01-02T03:04:05Z") || !strings.Contains(out.String(), "pending") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestMigrateRejectsNonMongoDriver(t *testing.T) {
+	cfg := &Config{Database: &database.Config{Driver: database.DriverSQLite}}
+
+	err := Migrate(cfg, []string{"status"}, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "MongoDB only") {
+		t.Errorf("Migrate() error = %v, want MongoDB-only error", err)
+	}
+	if err := Migrate(nil, nil, io.Discard); err == nil {
+		t.Error("Migrate() without config should fail")
+	}
+}
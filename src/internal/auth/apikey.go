@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+)
+
+// APIKeyHeader is the request header carrying an API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyIssuer is the issuer of principals authenticated by API key.
+const APIKeyIssuer = "api-key"
+
+// apiKeyPrefix marks keys issued by this service, so leaked keys are easy to spot.
+const apiKeyPrefix = "eds_"
+
+// apiKeyTouchInterval limits how often the last-used time of a key is written.
+const apiKeyTouchInterval = time.Minute
+
+// GenerateAPIKey returns a new random API key.
+func GenerateAPIKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generate api key: %w", err)
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 hash under which a key is stored.
+// Keys carry 256 random bits, so a fast unsalted hash does not make them guessable.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyStore is the part of database.APIKeyStore the authenticator reads from.
+type APIKeyStore interface {
+	GetAPIKeyByHash(ctx context.Context, hash string) (*database.StoredAPIKey, error)
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// APIKeyAuthenticator verifies API keys against the hashes in its store.
+type APIKeyAuthenticator struct {
+	store APIKeyStore
+	now   func() time.Time
+}
+
+// NewAPIKeyAuthenticator returns an authenticator accepting the active keys of store.
+func NewAPIKeyAuthenticator(store APIKeyStore) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{store: store, now: time.Now}
+}
+
+// Authenticate implements Authenticator. The principal is the owner of the key and
+// holds the scopes granted to it. The last-used time of the key is updated at most
+// once per minute.
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, key string) (*Principal, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, fmt.Errorf("%w: api key missing", domain.ErrorUnauthorized)
+	}
+	stored, err := a.store.GetAPIKeyByHash(ctx, HashAPIKey(key))
+	if errors.Is(err, domain.ErrorNotFound) {
+		return nil, fmt.Errorf("%w: unknown api key", domain.ErrorUnauthorized)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("look up api key: %w", err)
+	}
+
+	now := a.now()
+	if !stored.Active(now) {
+		return nil, fmt.Errorf("%w: api key %s is revoked or expired", domain.ErrorUnauthorized, stored.ID)
+	}
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= apiKeyTouchInterval {
+		// The last-used time is informational; failing to record it does not deny access.
+		_ = a.store.TouchAPIKey(ctx, stored.ID, now)
+	}
+
+	principal := &Principal{
+		Subject: stored.Owner,
+		Issuer:  APIKeyIssuer,
+		Scopes:  stored.Scopes,
+		Claims:  map[string]any{"key_id": stored.ID},
+	}
+	if stored.ExpiresAt != nil {
+		principal.ExpiresAt = *stored.ExpiresAt
+	}
+	return principal, nil
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	first, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	second, _ := GenerateAPIKey()
+	if !strings.HasPrefix(first, apiKeyPrefix) || len(first) != len(apiKeyPrefix)+43 || first == second {
+		t.Errorf("GenerateAPIKey() = %q, %q", first, second)
+	}
+	if HashAPIKey(first) == HashAPIKey(second) || HashAPIKey(first) != HashAPIKey(first) || len(HashAPIKey(first)) != 64 {
+		t.Error("HashAPIKey() is not a stable SHA-256 hex digest")
+	}
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	ctx := context.Background()
+	store := database.NewMemoryStore()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	keys := map[string]*database.StoredAPIKey{
+		"eds_active":  {ID: "01A", Owner: "billing", Scopes: []string{"examples:read"}},
+		"eds_expired": {ID: "01B", Owner: "billing", ExpiresAt: &expired},
+		"eds_revoked": {ID: "01C", Owner: "billing"},
+	}
+	for key, stored := range keys {
+		stored.Hash = HashAPIKey(key)
+		if err := store.StoreAPIKey(ctx, stored); err != nil {
+			t.Fatalf("StoreAPIKey() error = %v", err)
+		}
+	}
+	if err := store.RevokeAPIKey(ctx, "01C", now); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+
+	authenticator := NewAPIKeyAuthenticator(store)
+	authenticator.now = func() time.Time { return now }
+
+	principal, err := authenticator.Authenticate(ctx, "eds_active")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if principal.Subject != "billing" || principal.Issuer != APIKeyIssuer || !principal.HasScopes("examples:read") ||
+		principal.Claims["key_id"] != "01A" {
+		t.Errorf("principal = %+v", principal)
+	}
+	stored, _ := store.GetAPIKeyByHash(ctx, HashAPIKey("eds_active"))
+	if stored.LastUsedAt == nil || !stored.LastUsedAt.Equal(now) {
+		t.Errorf("LastUsedAt = %v, want %v", stored.LastUsedAt, now)
+	}
+
+	// Uses within a minute do not rewrite the last-used time.
+	authenticator.now = func() time.Time { return now.Add(30 * time.Second) }
+	if _, err := authenticator.Authenticate(ctx, "eds_active"); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if stored, _ := store.GetAPIKeyByHash(ctx, HashAPIKey("eds_active")); !stored.LastUsedAt.Equal(now) {
+		t.Errorf("LastUsedAt = %v, want %v", stored.LastUsedAt, now)
+	}
+
+	for _, key := range []string{"eds_expired", "eds_revoked", "eds_unknown", " "} {
+		if _, err := authenticator.Authenticate(ctx, key); !errors.Is(err, domain.ErrorUnauthorized) {
+			t.Errorf("Authenticate(%q) error = %v, want unauthorized", key, err)
+		}
+	}
+}
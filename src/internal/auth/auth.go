@@ -0,0 +1,66 @@
+// Package auth verifies the credentials presented to the HTTP API and carries the
+// verified caller, the principal, through request contexts.
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Principal is a verified caller of the API.
+type Principal struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	// Scopes are the scopes granted to the caller.
+	Scopes []string
+	// Claims holds every claim of the verified token.
+	Claims map[string]any
+}
+
+// HasScopes reports whether every given scope is granted to the principal.
+func (p *Principal) HasScopes(scopes ...string) bool {
+	if p == nil {
+		return false
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(p.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// Authenticator verifies a bearer token and returns the principal it identifies.
+// Invalid tokens are reported with errors wrapping domain.ErrorUnauthorized; other
+// errors mean the token could not be checked at all.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal stored in ctx by WithPrincipal.
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// BearerToken extracts the token of an Authorization header value that uses the
+// Bearer scheme. It reports false for other schemes and empty tokens.
+func BearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{"Bearer abc.def.ghi", "abc.def.ghi", true},
+		{"bearer   abc ", "abc", true},
+		{"Bearer ", "", false},
+		{"Basic dXNlcjpwYXNz", "", false},
+		{"abc.def.ghi", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := BearerToken(tt.header)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("BearerToken(%q) = %q, %v; want %q, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPrincipalContext(t *testing.T) {
+	if _, ok := PrincipalFrom(context.Background()); ok {
+		t.Error("PrincipalFrom(empty context) reported a principal")
+	}
+	ctx := WithPrincipal(context.Background(), &Principal{Subject: "client-1"})
+	if principal, ok := PrincipalFrom(ctx); !ok || principal.Subject != "client-1" {
+		t.Errorf("PrincipalFrom() = %+v, %v", principal, ok)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{JWKSURL: "https://issuer.example/jwks", Issuer: "https://issuer.example", Audience: "api"}
+	tests := []struct {
+		name    string
+		change  func(*Config)
+		wantErr bool
+	}{
+		{"valid", func(*Config) {}, false},
+		{"disabled", func(c *Config) { *c = Config{} }, false},
+		{"file and URL", func(c *Config) { c.JWKSFile = "jwks.json" }, true},
+		{"missing issuer", func(c *Config) { c.Issuer = "" }, true},
+		{"missing audience", func(c *Config) { c.Audience = "" }, true},
+		{"supported algorithms", func(c *Config) { c.Algorithms = []string{AlgorithmES256, AlgorithmRS256} }, false},
+		{"unsupported algorithm", func(c *Config) { c.Algorithms = []string{"none"} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.change(&cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	tests := map[string]string{
+		"invalid JSON":   `{`,
+		"no keys":        `{"keys":[]}`,
+		"only enc keys":  `{"keys":[{"kty":"oct","use":"enc","k":"c2VjcmV0"}]}`,
+		"bad modulus":    `{"keys":[{"kty":"RSA","n":"!!","e":"AQAB"}]}`,
+		"short EC point": `{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`,
+		"empty secret":   `{"keys":[{"kty":"oct","k":""}]}`,
+	}
+	for name, doc := range tests {
+		if _, err := ParseJWKS([]byte(doc)); err == nil {
+			t.Errorf("%s: ParseJWKS() succeeded", name)
+		}
+	}
+
+	set, err := ParseJWKS([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","x":"AQ"},{"kty":"oct","kid":"k1","k":"c2VjcmV0"}]}`))
+	if err != nil {
+		t.Fatalf("ParseJWKS() error = %v", err)
+	}
+	if key, err := set.Key(context.Background(), ""); err != nil || key.KeyID != "k1" {
+		t.Errorf("Key(\"\") = %+v, %v; want the only usable key", key, err)
+	}
+}
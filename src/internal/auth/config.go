@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Signing algorithms accepted for bearer tokens.
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmHS256 = "HS256"
+)
+
+// DefaultJWKSRefreshInterval is how long keys fetched from a JWKS URL are cached.
+const DefaultJWKSRefreshInterval = 15 * time.Minute
+
+// keyTypes maps each supported algorithm onto the JWK key type that verifies it.
+var keyTypes = map[string]string{
+	AlgorithmRS256: "RSA",
+	AlgorithmES256: "EC",
+	AlgorithmHS256: "oct",
+}
+
+// Config selects the JWKS holding the token verification keys and the claims
+// tokens must carry. Authentication is enabled when a JWKS file or URL is set.
+type Config struct {
+	// JWKSFile is the path of a JSON Web Key Set document.
+	JWKSFile string
+	// JWKSURL is fetched for the JSON Web Key Set when no file is set.
+	JWKSURL string
+	// JWKSRefreshInterval is how long keys fetched from JWKSURL are cached.
+	JWKSRefreshInterval time.Duration
+	// Issuer must match the iss claim of every token.
+	Issuer string
+	// Audience must be listed in the aud claim of every token.
+	Audience string
+	// Algorithms restricts the accepted signing algorithms. Empty accepts all
+	// supported algorithms.
+	Algorithms []string
+	// Leeway is the clock skew tolerated when checking exp, nbf and iat.
+	Leeway time.Duration
+	// OperationScopes replaces the scopes the API spec requires for the operations
+	// it names, see ParseOperationScopes.
+	OperationScopes map[string][]string
+	// APIKeys accepts the keys issued through the admin API in the X-API-Key header,
+	// independently of the JWKS.
+	APIKeys bool
+}
+
+// Enabled reports whether a key set is configured.
+func (c *Config) Enabled() bool {
+	return c != nil && (strings.TrimSpace(c.JWKSFile) != "" || strings.TrimSpace(c.JWKSURL) != "")
+}
+
+// Validate checks that an enabled configuration names one key set and the claims
+// to check.
+func (c *Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if strings.TrimSpace(c.JWKSFile) != "" && strings.TrimSpace(c.JWKSURL) != "" {
+		return errors.New("set either AUTH_JWKS_FILE or AUTH_JWKS_URL, not both")
+	}
+	if strings.TrimSpace(c.Issuer) == "" {
+		return errors.New("AUTH_ISSUER is required")
+	}
+	if strings.TrimSpace(c.Audience) == "" {
+		return errors.New("AUTH_AUDIENCE is required")
+	}
+	for _, alg := range c.Algorithms {
+		if _, ok := keyTypes[alg]; !ok {
+			return fmt.Errorf("unsupported AUTH_ALGORITHMS entry %q", alg)
+		}
+	}
+	return nil
+}
+
+// algorithms returns the accepted signing algorithms.
+func (c *Config) algorithms() []string {
+	if len(c.Algorithms) > 0 {
+		return c.Algorithms
+	}
+	return []string{AlgorithmRS256, AlgorithmES256, AlgorithmHS256}
+}
+
+// New returns the authenticator described by cfg, or nil when authentication is
+// disabled. A JWKS file is read right away, a JWKS URL on first use.
+func New(cfg *Config) (Authenticator, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var keys KeySet
+	if strings.TrimSpace(cfg.JWKSFile) != "" {
+		fileKeys, err := LoadJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = fileKeys
+	} else {
+		keys = NewRemoteKeySet(cfg.JWKSURL, cfg.JWKSRefreshInterval, nil)
+	}
+	return NewJWTAuthenticator(keys, cfg), nil
+}
@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+const (
+	// maxJWKSSize caps the size of fetched key set documents.
+	maxJWKSSize = 1 << 20
+	// defaultJWKSTimeout bounds a single key set fetch.
+	defaultJWKSTimeout = 10 * time.Second
+)
+
+// minJWKSRefetch is the minimum time between fetches triggered by unknown key IDs,
+// so tokens with made-up key IDs cannot hammer the key set URL.
+var minJWKSRefetch = 30 * time.Second
+
+// JSONWebKey is a verification key of a key set.
+type JSONWebKey struct {
+	KeyID string
+	// Algorithm is the algorithm the key is restricted to, empty when it is not.
+	Algorithm string
+	// Type is the JWK key type: RSA, EC or oct.
+	Type string
+	// Key is an *rsa.PublicKey, an *ecdsa.PublicKey or the []byte of a shared secret.
+	Key any
+}
+
+// KeySet resolves the key that verifies a token.
+type KeySet interface {
+	// Key returns the key with the given ID. An empty ID matches a set holding a
+	// single key.
+	Key(ctx context.Context, kid string) (*JSONWebKey, error)
+}
+
+// StaticKeySet is a key set that never changes.
+type StaticKeySet struct {
+	keys []*JSONWebKey
+}
+
+// Key implements KeySet.
+func (s *StaticKeySet) Key(_ context.Context, kid string) (*JSONWebKey, error) {
+	if key := s.lookup(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (s *StaticKeySet) lookup(kid string) *JSONWebKey {
+	if s == nil {
+		return nil
+	}
+	if kid == "" && len(s.keys) == 1 {
+		return s.keys[0]
+	}
+	for _, key := range s.keys {
+		if key.KeyID == kid {
+			return key
+		}
+	}
+	return nil
+}
+
+// jwk is the JSON representation of a key, see RFC 7517 and RFC 7518.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	K   string `json:"k,omitempty"`
+}
+
+// ParseJWKS parses a JSON Web Key Set document. Encryption keys and keys of
+// unsupported types are skipped; a set without any usable key is an error.
+func ParseJWKS(data []byte) (*StaticKeySet, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode JWKS: %w", err)
+	}
+
+	set := &StaticKeySet{}
+	for i, raw := range doc.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		if raw.Alg != "" {
+			if _, ok := keyTypes[raw.Alg]; !ok {
+				continue
+			}
+		}
+		key, err := raw.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("JWKS key %d (%q): %w", i, raw.Kid, err)
+		}
+		if key == nil {
+			continue
+		}
+		set.keys = append(set.keys, &JSONWebKey{KeyID: raw.Kid, Algorithm: raw.Alg, Type: raw.Kty, Key: key})
+	}
+	if len(set.keys) == 0 {
+		return nil, errors.New("JWKS holds no usable signing keys")
+	}
+	return set, nil
+}
+
+// publicKey decodes the key material, returning nil for unsupported key types.
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		// Only ES256 is supported, which signs on P-256.
+		if k.Crv != "P-256" {
+			return nil, nil
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x coordinate: %w", err)
+		}
+		y, err := decodeSegment(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y coordinate: %w", err)
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("coordinates are not 32 bytes long")
+		}
+		uncompressed := append(append([]byte{4}, x...), y...)
+		key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), uncompressed)
+		if err != nil {
+			return nil, err
+		}
+		return key, nil
+	case "oct":
+		secret, err := decodeSegment(k.K)
+		if err != nil {
+			return nil, fmt.Errorf("secret: %w", err)
+		}
+		if len(secret) == 0 {
+			return nil, errors.New("secret is empty")
+		}
+		return secret, nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeSegment(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := decodeSegment(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("value is empty")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// LoadJWKSFile reads and parses the key set document at path.
+func LoadJWKSFile(path string) (*StaticKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read JWKS file: %w", err)
+	}
+	set, err := ParseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return set, nil
+}
+
+// RemoteKeySet is a key set fetched from a URL. Keys are cached for the refresh
+// interval, and an unknown key ID triggers an early fetch to pick up rotated keys.
+// While the URL is unreachable the cached keys stay in use.
+type RemoteKeySet struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	mu           sync.Mutex // serializes fetches and protects the fields below
+	keys         *StaticKeySet
+	expiresAt    time.Time
+	refetchAfter time.Time
+}
+
+// NewRemoteKeySet returns a key set fetched from url. A zero refresh interval uses
+// DefaultJWKSRefreshInterval and a nil client one with a ten second timeout.
+func NewRemoteKeySet(url string, refresh time.Duration, client *http.Client) *RemoteKeySet {
+	if refresh <= 0 {
+		refresh = DefaultJWKSRefreshInterval
+	}
+	if client == nil {
+		client = &http.Client{Timeout: defaultJWKSTimeout}
+	}
+	return &RemoteKeySet{url: url, refresh: refresh, client: client}
+}
+
+// Key implements KeySet. Fetch failures without cached keys wrap
+// domain.ErrorUpstreamService.
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (*JSONWebKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	unknown := s.keys.lookup(kid) == nil && now.After(s.refetchAfter)
+	if s.keys == nil || now.After(s.expiresAt) || unknown {
+		keys, err := s.fetch(ctx)
+		switch {
+		case err == nil:
+			s.keys, s.expiresAt, s.refetchAfter = keys, now.Add(s.refresh), now.Add(minJWKSRefetch)
+		case s.keys == nil:
+			return nil, err
+		default:
+			s.expiresAt, s.refetchAfter = now.Add(minJWKSRefetch), now.Add(minJWKSRefetch)
+		}
+	}
+	return s.keys.Key(ctx, kid)
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) (*StaticKeySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: JWKS request: %w", domain.ErrorUpstreamService, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fetch JWKS: %w", domain.ErrorUpstreamService, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: fetch JWKS: status %d", domain.ErrorUpstreamService, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: read JWKS: %w", domain.ErrorUpstreamService, err)
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrorUpstreamService, err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTAuthenticator verifies JSON Web Tokens signed with a key of its key set.
+type JWTAuthenticator struct {
+	keys   KeySet
+	parser *jwt.Parser
+}
+
+// NewJWTAuthenticator returns an authenticator accepting tokens that are signed by
+// a key of keys and carry the issuer and audience of cfg. Tokens must expire.
+func NewJWTAuthenticator(keys KeySet, cfg *Config) *JWTAuthenticator {
+	return &JWTAuthenticator{
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(cfg.algorithms()),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+			jwt.WithLeeway(cfg.Leeway),
+		),
+	}
+}
+
+// Authenticate implements Authenticator.
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := a.keys.Key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		alg := t.Method.Alg()
+		if keyTypes[alg] != key.Type || (key.Algorithm != "" && key.Algorithm != alg) {
+			return nil, fmt.Errorf("key %q does not verify %s", key.KeyID, alg)
+		}
+		return key.Key, nil
+	})
+	if err != nil {
+		// An unreachable key set says nothing about the token.
+		if errors.Is(err, domain.ErrorUpstreamService) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", domain.ErrorUnauthorized, err)
+	}
+	return principalOf(claims)
+}
+
+// principalOf maps the claims of a verified token onto a principal.
+func principalOf(claims jwt.MapClaims) (*Principal, error) {
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", domain.ErrorUnauthorized)
+	}
+	// The parser already validated these claims.
+	issuer, _ := claims.GetIssuer()
+	audience, _ := claims.GetAudience()
+	expiresAt, _ := claims.GetExpirationTime()
+
+	return &Principal{
+		Subject:   subject,
+		Issuer:    issuer,
+		Audience:  audience,
+		ExpiresAt: expiresAt.Time,
+		Scopes:    scopesOf(claims),
+		Claims:    claims,
+	}, nil
+}
+
+// scopesOf reads the granted scopes from the space-delimited scope claim of RFC 8693
+// or, as some identity providers issue them, the scp claim holding a string or a list.
+func scopesOf(claims jwt.MapClaims) []string {
+	var scopes []string
+	for _, name := range []string{"scope", "scp"} {
+		switch value := claims[name].(type) {
+		case string:
+			scopes = append(scopes, strings.Fields(value)...)
+		case []any:
+			for _, item := range value {
+				if scope, ok := item.(string); ok {
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://issuer.example"
+	testAudience = "event-driven-service"
+)
+
+func newTestIssuer(t *testing.T) *LocalIssuer {
+	t.Helper()
+	issuer, err := NewLocalIssuer(testIssuer, testAudience)
+	if err != nil {
+		t.Fatalf("NewLocalIssuer() error = %v", err)
+	}
+	return issuer
+}
+
+func newTestAuthenticator(t *testing.T, issuer *LocalIssuer, cfg *Config) *JWTAuthenticator {
+	t.Helper()
+	keys, err := issuer.KeySet()
+	if err != nil {
+		t.Fatalf("KeySet() error = %v", err)
+	}
+	return NewJWTAuthenticator(keys, cfg)
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	issuer := newTestIssuer(t)
+	authenticator := newTestAuthenticator(t, issuer, issuer.Config())
+	now := time.Now()
+	claims := func(changes jwt.MapClaims) jwt.MapClaims {
+		claims := jwt.MapClaims{"iss": testIssuer, "aud": testAudience, "sub": "client-1", "exp": now.Add(time.Minute).Unix()}
+		for name, value := range changes {
+			if value == nil {
+				delete(claims, name)
+				continue
+			}
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name   string
+		alg    string
+		claims jwt.MapClaims
+		valid  bool
+	}{
+		{"RS256", AlgorithmRS256, claims(nil), true},
+		{"ES256", AlgorithmES256, claims(nil), true},
+		{"HS256", AlgorithmHS256, claims(nil), true},
+		{"audience list", AlgorithmRS256, claims(jwt.MapClaims{"aud": []string{"other", testAudience}}), true},
+		{"expired", AlgorithmRS256, claims(jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()}), false},
+		{"without expiry", AlgorithmRS256, claims(jwt.MapClaims{"exp": nil}), false},
+		{"wrong issuer", AlgorithmRS256, claims(jwt.MapClaims{"iss": "https://evil.example"}), false},
+		{"wrong audience", AlgorithmES256, claims(jwt.MapClaims{"aud": "other"}), false},
+		{"without subject", AlgorithmHS256, claims(jwt.MapClaims{"sub": nil}), false},
+		{"issued in the future", AlgorithmRS256, claims(jwt.MapClaims{"iat": now.Add(time.Hour).Unix()}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := issuer.Sign(tt.alg, tt.claims)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			principal, err := authenticator.Authenticate(context.Background(), token)
+			if !tt.valid {
+				if !errors.Is(err, domain.ErrorUnauthorized) {
+					t.Errorf("Authenticate() error = %v, want unauthorized", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if principal.Subject != "client-1" || principal.Issuer != testIssuer || principal.ExpiresAt.IsZero() {
+				t.Errorf("principal = %+v", principal)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticatorRejectsForgedTokens(t *testing.T) {
+	issuer := newTestIssuer(t)
+	authenticator := newTestAuthenticator(t, issuer, issuer.Config())
+
+	other := newTestIssuer(t)
+	forged, err := other.Token("client-1", time.Minute)
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	valid, err := issuer.Token("client-1", time.Minute)
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+		"iss": testIssuer, "aud": testAudience, "sub": "client-1", "exp": time.Now().Add(time.Minute).Unix(),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	// A token whose header claims HS256 for the RSA key must not verify against it.
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": testIssuer, "aud": testAudience, "sub": "client-1", "exp": time.Now().Add(time.Minute).Unix(),
+	})
+	confused.Header["kid"] = LocalKeyRS256
+	confusedToken, err := confused.SignedString([]byte("guess"))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"other issuer keys": forged,
+		"alg none":          unsigned,
+		"algorithm switch":  confusedToken,
+		"tampered":          valid[:len(valid)-4] + "AAAA",
+		"garbage":           "not-a-token",
+	} {
+		if _, err := authenticator.Authenticate(context.Background(), token); !errors.Is(err, domain.ErrorUnauthorized) {
+			t.Errorf("%s: Authenticate() error = %v, want unauthorized", name, err)
+		}
+	}
+}
+
+func TestJWTAuthenticatorAlgorithms(t *testing.T) {
+	issuer := newTestIssuer(t)
+	cfg := issuer.Config()
+	cfg.Algorithms = []string{AlgorithmRS256}
+	authenticator := newTestAuthenticator(t, issuer, cfg)
+
+	token, err := issuer.Sign(AlgorithmHS256, jwt.MapClaims{
+		"iss": testIssuer, "aud": testAudience, "sub": "client-1", "exp": time.Now().Add(time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if _, err := authenticator.Authenticate(context.Background(), token); !errors.Is(err, domain.ErrorUnauthorized) {
+		t.Errorf("Authenticate(HS256) error = %v, want unauthorized", err)
+	}
+}
+
+func TestNewFromJWKSFile(t *testing.T) {
+	issuer := newTestIssuer(t)
+	data, err := issuer.JWKS()
+	if err != nil {
+		t.Fatalf("JWKS() error = %v", err)
+	}
+	cfg := issuer.Config()
+	cfg.JWKSFile = filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(cfg.JWKSFile, data, 0o600); err != nil {
+		t.Fatalf("write JWKS: %v", err)
+	}
+
+	authenticator, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	token, err := issuer.Token("client-1", time.Minute)
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if _, err := authenticator.Authenticate(context.Background(), token); err != nil {
+		t.Errorf("Authenticate() error = %v", err)
+	}
+
+	cfg.JWKSFile = filepath.Join(t.TempDir(), "missing.json")
+	if _, err := New(cfg); err == nil {
+		t.Error("New() with a missing JWKS file succeeded")
+	}
+	if authenticator, err := New(&Config{}); authenticator != nil || err != nil {
+		t.Errorf("New(disabled) = %v, %v; want nil, nil", authenticator, err)
+	}
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	previous := minJWKSRefetch
+	minJWKSRefetch = 0
+	t.Cleanup(func() { minJWKSRefetch = previous })
+
+	issuer := newTestIssuer(t)
+	var fetches atomic.Int32
+	available := atomic.Bool{}
+	available.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if !available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		issuer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	keys := NewRemoteKeySet(srv.URL, time.Hour, srv.Client())
+	ctx := context.Background()
+	for range 2 {
+		if _, err := keys.Key(ctx, LocalKeyES256); err != nil {
+			t.Fatalf("Key() error = %v", err)
+		}
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("fetches = %d, want cached keys after the first fetch", got)
+	}
+
+	// Unknown key IDs trigger a fetch; failures keep the cached keys in use.
+	available.Store(false)
+	if _, err := keys.Key(ctx, "rotated"); err == nil {
+		t.Error("Key(rotated) succeeded")
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want a refetch for the unknown key", got)
+	}
+	if _, err := keys.Key(ctx, LocalKeyRS256); err != nil {
+		t.Errorf("Key() with unreachable URL error = %v, want cached key", err)
+	}
+
+	empty := NewRemoteKeySet(srv.URL, time.Hour, srv.Client())
+	if _, err := empty.Key(ctx, LocalKeyRS256); !errors.Is(err, domain.ErrorUpstreamService) {
+		t.Errorf("Key() without keys error = %v, want upstream error", err)
+	}
+}
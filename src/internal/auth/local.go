@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key IDs of the keys of a LocalIssuer.
+const (
+	LocalKeyRS256 = "local-rs256"
+	LocalKeyES256 = "local-es256"
+	LocalKeyHS256 = "local-hs256"
+)
+
+// LocalIssuer stands in for an identity provider in tests and local development.
+// It signs tokens with keys generated on creation and publishes them as a JWKS,
+// including the HS256 secret, so it must never back a production deployment.
+type LocalIssuer struct {
+	issuer   string
+	audience string
+	rsaKey   *rsa.PrivateKey
+	ecKey    *ecdsa.PrivateKey
+	secret   []byte
+}
+
+// NewLocalIssuer returns an issuer of tokens for the given issuer and audience.
+func NewLocalIssuer(issuer, audience string) (*LocalIssuer, error) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("generate RSA key: %w", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate EC key: %w", err)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate secret: %w", err)
+	}
+	return &LocalIssuer{issuer: issuer, audience: audience, rsaKey: rsaKey, ecKey: ecKey, secret: secret}, nil
+}
+
+// Config returns an authentication config accepting the tokens of the issuer once
+// a JWKS file or URL serving its key set is filled in.
+func (l *LocalIssuer) Config() *Config {
+	return &Config{Issuer: l.issuer, Audience: l.audience}
+}
+
+// Token returns an RS256 token for subject that expires after ttl and grants scopes.
+func (l *LocalIssuer) Token(subject string, ttl time.Duration, scopes ...string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss": l.issuer,
+		"aud": l.audience,
+		"sub": subject,
+		"iat": now.Unix(),
+		"exp": now.Add(ttl).Unix(),
+	}
+	if len(scopes) > 0 {
+		claims["scope"] = strings.Join(scopes, " ")
+	}
+	return l.Sign(AlgorithmRS256, claims)
+}
+
+// Sign signs claims with the key of the given algorithm. The claims are used as
+// they are, so tokens with wrong or missing registered claims can be built too.
+func (l *LocalIssuer) Sign(alg string, claims jwt.MapClaims) (string, error) {
+	var (
+		token *jwt.Token
+		key   any
+	)
+	switch alg {
+	case AlgorithmRS256:
+		token, key = jwt.NewWithClaims(jwt.SigningMethodRS256, claims), l.rsaKey
+		token.Header["kid"] = LocalKeyRS256
+	case AlgorithmES256:
+		token, key = jwt.NewWithClaims(jwt.SigningMethodES256, claims), l.ecKey
+		token.Header["kid"] = LocalKeyES256
+	case AlgorithmHS256:
+		token, key = jwt.NewWithClaims(jwt.SigningMethodHS256, claims), l.secret
+		token.Header["kid"] = LocalKeyHS256
+	default:
+		return "", fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	return token.SignedString(key)
+}
+
+// JWKS returns the key set document holding the verification keys of the issuer.
+func (l *LocalIssuer) JWKS() ([]byte, error) {
+	encode := base64.RawURLEncoding.EncodeToString
+	ecPoint, err := l.ecKey.PublicKey.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	// The uncompressed point is 0x04 followed by the 32 byte X and Y coordinates.
+	return json.Marshal(map[string][]jwk{"keys": {
+		{
+			Kty: "RSA", Kid: LocalKeyRS256, Alg: AlgorithmRS256, Use: "sig",
+			N: encode(l.rsaKey.N.Bytes()),
+			E: encode(big.NewInt(int64(l.rsaKey.E)).Bytes()),
+		},
+		{
+			Kty: "EC", Kid: LocalKeyES256, Alg: AlgorithmES256, Use: "sig", Crv: "P-256",
+			X: encode(ecPoint[1:33]),
+			Y: encode(ecPoint[33:]),
+		},
+		{Kty: "oct", Kid: LocalKeyHS256, Alg: AlgorithmHS256, Use: "sig", K: encode(l.secret)},
+	}})
+}
+
+// KeySet returns the verification keys of the issuer.
+func (l *LocalIssuer) KeySet() (*StaticKeySet, error) {
+	data, err := l.JWKS()
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// ServeHTTP serves the key set document, so the issuer can back a JWKS URL.
+func (l *LocalIssuer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	data, err := l.JWKS()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Requirement lists the scope sets that grant access to an operation. A caller
+// needs every scope of at least one set, so a set without scopes only requires
+// authentication. Operations without requirement are public.
+type Requirement [][]string
+
+// Authorize checks that principal satisfies the requirement. A missing principal
+// is reported with domain.ErrorUnauthorized, missing scopes with
+// domain.ErrorForbidden.
+func (req Requirement) Authorize(principal *Principal) error {
+	if len(req) == 0 {
+		return nil
+	}
+	if principal == nil {
+		return fmt.Errorf("%w: no verified caller", domain.ErrorUnauthorized)
+	}
+	for _, scopes := range req {
+		if principal.HasScopes(scopes...) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: requires scopes %s", domain.ErrorForbidden, req)
+}
+
+// String lists the scope sets, for example "examples:write or admin".
+func (req Requirement) String() string {
+	sets := make([]string, 0, len(req))
+	for _, scopes := range req {
+		sets = append(sets, strings.Join(scopes, " "))
+	}
+	return strings.Join(sets, " or ")
+}
+
+// Policy holds the requirement of every API operation, keyed by the operation ID
+// in the form oapi-codegen uses for ServerInterface methods, e.g. CreateExampleRecord.
+type Policy struct {
+	requirements map[string]Requirement
+	// operations maps ServeMux patterns like "GET /examples/{id}" onto operation IDs.
+	operations map[string]string
+}
+
+// NewPolicy derives the requirements from the security blocks of the operations in
+// swagger. The scopes of all schemes of a security requirement form one scope set.
+// Operations are only public through an empty security block, so an operation
+// without a security block of its own or of the spec is rejected.
+func NewPolicy(swagger *openapi3.T) (*Policy, error) {
+	if swagger == nil || swagger.Paths == nil {
+		return nil, errors.New("swagger without paths")
+	}
+	policy := &Policy{requirements: map[string]Requirement{}, operations: map[string]string{}}
+	for path, item := range swagger.Paths.Map() {
+		for method, operation := range item.Operations() {
+			if operation.OperationID == "" {
+				return nil, fmt.Errorf("%s %s has no operationId", method, path)
+			}
+			id := OperationName(operation.OperationID)
+			policy.operations[method+" "+path] = id
+
+			security := operation.Security
+			if security == nil {
+				if len(swagger.Security) == 0 {
+					return nil, fmt.Errorf("%s %s has no security block; public operations need security: []", method, path)
+				}
+				security = &swagger.Security
+			}
+			policy.requirements[id] = requirementOf(*security)
+		}
+	}
+	return policy, nil
+}
+
+// requirementOf maps security requirements onto a requirement. Any empty security
+// requirement makes the operation public. Alternatives granting access through
+// different schemes with the same scopes yield one scope set.
+func requirementOf(security openapi3.SecurityRequirements) Requirement {
+	var req Requirement
+	for _, alternative := range security {
+		if len(alternative) == 0 {
+			return nil
+		}
+		scopes := []string{}
+		for _, schemeScopes := range alternative {
+			scopes = append(scopes, schemeScopes...)
+		}
+		sort.Strings(scopes)
+		scopes = slices.Compact(scopes)
+		if !slices.ContainsFunc(req, func(set []string) bool { return slices.Equal(set, scopes) }) {
+			req = append(req, scopes)
+		}
+	}
+	return req
+}
+
+// OperationName converts an OpenAPI operationId into the name oapi-codegen gives
+// the ServerInterface method, e.g. createExampleRecord into CreateExampleRecord.
+func OperationName(operationID string) string {
+	first, size := utf8.DecodeRuneInString(operationID)
+	return string(unicode.ToUpper(first)) + operationID[size:]
+}
+
+// Require replaces the requirement of the operation with one scope set. Without
+// scopes the operation only requires authentication.
+func (p *Policy) Require(operation string, scopes ...string) error {
+	if _, ok := p.requirements[operation]; !ok {
+		return fmt.Errorf("unknown operation %q", operation)
+	}
+	p.requirements[operation] = Requirement{append([]string{}, scopes...)}
+	return nil
+}
+
+// Apply replaces the requirements of the operations in overrides, see Require.
+func (p *Policy) Apply(overrides map[string][]string) error {
+	operations := make([]string, 0, len(overrides))
+	for operation := range overrides {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+	for _, operation := range operations {
+		if err := p.Require(operation, overrides[operation]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Operation returns the ID of the operation served for the ServeMux pattern the
+// request was routed by, as set by the generated handler.
+func (p *Policy) Operation(r *http.Request) (string, bool) {
+	id, ok := p.operations[r.Pattern]
+	return id, ok
+}
+
+// Requirement returns the requirement of the operation.
+func (p *Policy) Requirement(operation string) Requirement {
+	return p.requirements[operation]
+}
+
+// HasOperation reports whether the API spec defines the operation.
+func (p *Policy) HasOperation(operation string) bool {
+	_, ok := p.requirements[operation]
+	return ok
+}
+
+// ParseOperationScopes parses requirement overrides written as comma-separated
+// Operation=scope entries. Several scopes of an entry are separated by spaces and
+// all of them are required; an entry without scopes only requires authentication.
+// For example: "GetOpenAPIJSON=admin,CreateExampleRecord=examples:write audit".
+func ParseOperationScopes(value string) (map[string][]string, error) {
+	overrides := map[string][]string{}
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		operation, scopes, found := strings.Cut(entry, "=")
+		operation = strings.TrimSpace(operation)
+		if !found || operation == "" {
+			return nil, fmt.Errorf("operation scopes entry %q is not Operation=scopes", entry)
+		}
+		overrides[operation] = strings.Fields(scopes)
+	}
+	return overrides, nil
+}
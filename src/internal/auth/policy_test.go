@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const testSpec = `
+openapi: "3.1.0"
+info: {title: test, version: "1"}
+components:
+  securitySchemes:
+    bearerAuth: {type: http, scheme: bearer}
+    apiKeyAuth: {type: apiKey, in: header, name: X-API-Key}
+security:
+  - bearerAuth: []
+paths:
+  /examples:
+    get:
+      operationId: listExampleRecords
+      security:
+        - bearerAuth: ["examples:read"]
+        - apiKeyAuth: ["examples:read"]
+      responses: {"200": {description: ok}}
+    post:
+      operationId: createExampleRecord
+      security:
+        - bearerAuth: ["examples:write"]
+          apiKeyAuth: ["examples:write", "audit"]
+        - bearerAuth: ["admin"]
+      responses: {"202": {description: ok}}
+  /docs:
+    get:
+      operationId: getOpenAPIJSON
+      security: []
+      responses: {"200": {description: ok}}
+  /me:
+    get:
+      operationId: getMe
+      responses: {"200": {description: ok}}
+  /optional:
+    get:
+      operationId: getOptional
+      security:
+        - {}
+        - bearerAuth: ["examples:read"]
+      responses: {"200": {description: ok}}
+`
+
+func newTestPolicy(t *testing.T) *Policy {
+	t.Helper()
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	policy, err := NewPolicy(swagger)
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+	return policy
+}
+
+func TestNewPolicy(t *testing.T) {
+	policy := newTestPolicy(t)
+
+	tests := map[string]Requirement{
+		"ListExampleRecords":  {{"examples:read"}},
+		"CreateExampleRecord": {{"audit", "examples:write"}, {"admin"}},
+		"GetOpenAPIJSON":      nil,
+		"GetMe":               {{}},
+		"GetOptional":         nil,
+	}
+	for operation, want := range tests {
+		if got := policy.Requirement(operation); !reflect.DeepEqual(got, want) {
+			t.Errorf("Requirement(%s) = %v, want %v", operation, got, want)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/examples", nil)
+	req.Pattern = "POST /examples"
+	if operation, ok := policy.Operation(req); !ok || operation != "CreateExampleRecord" {
+		t.Errorf("Operation(POST /examples) = %q, %v", operation, ok)
+	}
+	req.Pattern = "/"
+	if _, ok := policy.Operation(req); ok {
+		t.Error("Operation(/) found an operation")
+	}
+
+	if !policy.HasOperation("GetOptional") || policy.HasOperation("getOptional") {
+		t.Error("HasOperation() must match the generated operation names")
+	}
+}
+
+func TestNewPolicyRequiresSecurityBlocks(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: "3.1.0"
+info: {title: test, version: "1"}
+paths:
+  /me:
+    get:
+      operationId: getMe
+      responses: {"200": {description: ok}}
+`))
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	if _, err := NewPolicy(swagger); err == nil {
+		t.Error("NewPolicy() accepted an operation without a security block")
+	}
+}
+
+func TestPolicyApply(t *testing.T) {
+	policy := newTestPolicy(t)
+
+	if err := policy.Apply(map[string][]string{"GetOpenAPIJSON": {"admin"}, "GetOptional": nil}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := policy.Requirement("GetOpenAPIJSON"); !reflect.DeepEqual(got, Requirement{{"admin"}}) {
+		t.Errorf("Requirement(GetOpenAPIJSON) = %v, want admin", got)
+	}
+	if got := policy.Requirement("GetOptional"); !reflect.DeepEqual(got, Requirement{{}}) {
+		t.Errorf("Requirement(GetOptional) = %v, want authentication only", got)
+	}
+	if err := policy.Apply(map[string][]string{"getOpenAPIJSON": {"admin"}}); err == nil {
+		t.Error("Apply() accepted an unknown operation")
+	}
+}
+
+func TestRequirementAuthorize(t *testing.T) {
+	req := Requirement{{"audit", "examples:write"}, {"admin"}}
+	tests := []struct {
+		name      string
+		principal *Principal
+		want      error
+	}{
+		{"all scopes of a set", &Principal{Scopes: []string{"examples:write", "audit", "other"}}, nil},
+		{"alternative set", &Principal{Scopes: []string{"admin"}}, nil},
+		{"partial set", &Principal{Scopes: []string{"examples:write"}}, domain.ErrorForbidden},
+		{"no scopes", &Principal{}, domain.ErrorForbidden},
+		{"no principal", nil, domain.ErrorUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := req.Authorize(tt.principal); !errors.Is(err, tt.want) || (tt.want == nil) != (err == nil) {
+				t.Errorf("Authorize() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if err := Requirement(nil).Authorize(nil); err != nil {
+		t.Errorf("public Authorize() error = %v", err)
+	}
+	if err := (Requirement{{}}).Authorize(&Principal{}); err != nil {
+		t.Errorf("authentication only Authorize() error = %v", err)
+	}
+}
+
+func TestParseOperationScopes(t *testing.T) {
+	got, err := ParseOperationScopes(" GetOpenAPIJSON=admin, CreateExampleRecord=examples:write  audit,GetMe=,")
+	if err != nil {
+		t.Fatalf("ParseOperationScopes() error = %v", err)
+	}
+	want := map[string][]string{
+		"GetOpenAPIJSON":      {"admin"},
+		"CreateExampleRecord": {"examples:write", "audit"},
+		"GetMe":               {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseOperationScopes() = %v, want %v", got, want)
+	}
+
+	for _, value := range []string{"GetOpenAPIJSON", "=admin"} {
+		if _, err := ParseOperationScopes(value); err == nil {
+			t.Errorf("ParseOperationScopes(%q) succeeded", value)
+		}
+	}
+}
+
+func TestScopesOf(t *testing.T) {
+	issuer := newTestIssuer(t)
+	authenticator := newTestAuthenticator(t, issuer, issuer.Config())
+
+	token, err := issuer.Token("client-1", 5*time.Minute, "examples:read", "examples:write")
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	principal, err := authenticator.Authenticate(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if !principal.HasScopes("examples:read", "examples:write") || principal.HasScopes("admin") {
+		t.Errorf("scopes = %v", principal.Scopes)
+	}
+
+	scopes := scopesOf(map[string]any{"scp": []any{"admin", 1, "audit"}})
+	if !reflect.DeepEqual(scopes, []string{"admin", "audit"}) {
+		t.Errorf("scopesOf(scp list) = %v", scopes)
+	}
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+const (
+	apiKeyCollection    = "api_keys"
+	apiKeyHashIndexName = "api_key_hash"
+)
+
+var (
+	// ErrAPIKeyNotFound is returned for unknown API key IDs and hashes.
+	ErrAPIKeyNotFound = fmt.Errorf("api key %w", domain.ErrorNotFound)
+	// ErrAPIKeyExists is returned when the ID or hash of a new key is already stored.
+	ErrAPIKeyExists = fmt.Errorf("api key already exists: %w", domain.ErrorConflict)
+)
+
+// StoredAPIKey is an issued API key. Only the hash of the key is stored; the key
+// itself is shown once when it is issued. IDs are ULIDs, so they sort in the order
+// the keys were issued.
+type StoredAPIKey struct {
+	ID         string     `bson:"_id"`
+	Hash       string     `bson:"hash"`
+	Owner      string     `bson:"owner"`
+	Scopes     []string   `bson:"scopes"`
+	CreatedAt  time.Time  `bson:"created_at"`
+	ExpiresAt  *time.Time `bson:"expires_at,omitempty"`
+	LastUsedAt *time.Time `bson:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `bson:"revoked_at,omitempty"`
+}
+
+// Active reports whether the key is neither revoked nor expired at now.
+func (k *StoredAPIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyStore persists the hashes of issued API keys.
+type APIKeyStore interface {
+	// StoreAPIKey saves a new key; an ID or hash that is already stored is rejected.
+	StoreAPIKey(ctx context.Context, key *StoredAPIKey) error
+	GetAPIKeyByHash(ctx context.Context, hash string) (*StoredAPIKey, error)
+	// ListAPIKeys returns the keys of owner, or all keys for an empty owner, oldest first.
+	ListAPIKeys(ctx context.Context, owner string) ([]*StoredAPIKey, error)
+	// RevokeAPIKey marks the key as revoked. Revoking it again keeps the first time.
+	RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error
+	// TouchAPIKey records the time the key was last used.
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// prepareAPIKey validates a key and fills in its creation time.
+func prepareAPIKey(key *StoredAPIKey) error {
+	if key == nil || key.ID == "" || key.Hash == "" || key.Owner == "" {
+		return fmt.Errorf("api key id, hash and owner are required: %w", domain.ErrorBadRequest)
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now().UTC()
+	}
+	return nil
+}
+
+// StoreAPIKey inserts the key document. The unique hash index rejects duplicate keys.
+func (db *Database) StoreAPIKey(ctx context.Context, key *StoredAPIKey) error {
+	if err := prepareAPIKey(key); err != nil {
+		return err
+	}
+	_, err := db.DB.Collection(apiKeyCollection).InsertOne(ctx, key)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrAPIKeyExists
+	}
+	return err
+}
+
+// GetAPIKeyByHash looks up the key with the given hash.
+func (db *Database) GetAPIKeyByHash(ctx context.Context, hash string) (*StoredAPIKey, error) {
+	var key StoredAPIKey
+	err := db.DB.Collection(apiKeyCollection).FindOne(ctx, bson.M{"hash": hash}).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListAPIKeys returns the keys of owner, or all keys for an empty owner, oldest first.
+func (db *Database) ListAPIKeys(ctx context.Context, owner string) ([]*StoredAPIKey, error) {
+	query := bson.M{}
+	if owner != "" {
+		query["owner"] = owner
+	}
+	cursor, err := db.DB.Collection(apiKeyCollection).Find(ctx, query,
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	keys := []*StoredAPIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey sets the revocation time of a key that is not revoked yet.
+func (db *Database) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	collection := db.DB.Collection(apiKeyCollection)
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": revokedAt.UTC()}},
+	)
+	if err != nil || result.MatchedCount > 0 {
+		return err
+	}
+	count, err := collection.CountDocuments(ctx, bson.M{"_id": id})
+	if err == nil && count == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return err
+}
+
+// TouchAPIKey sets the time the key was last used.
+func (db *Database) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	result, err := db.DB.Collection(apiKeyCollection).UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"last_used_at": usedAt.UTC()}},
+	)
+	if err == nil && result.MatchedCount == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return err
+}
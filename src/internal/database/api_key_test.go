@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+// assertAPIKeyStore issues, looks up, touches and revokes keys on store.
+func assertAPIKeyStore(t *testing.T, store APIKeyStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	keys := []*StoredAPIKey{
+		{ID: "01A", Hash: "hash-a", Owner: "billing", Scopes: []string{"examples:read", "examples:write"}, ExpiresAt: &expiresAt},
+		{ID: "01B", Hash: "hash-b", Owner: "reporting", Scopes: []string{"examples:read"}},
+		{ID: "01C", Hash: "hash-c", Owner: "billing"},
+	}
+	for _, key := range keys {
+		if err := store.StoreAPIKey(ctx, key); err != nil {
+			t.Fatalf("StoreAPIKey(%s) error = %v", key.ID, err)
+		}
+	}
+	for _, key := range []*StoredAPIKey{
+		{ID: "01A", Hash: "hash-d", Owner: "billing"},
+		{ID: "01D", Hash: "hash-a", Owner: "billing"},
+	} {
+		if err := store.StoreAPIKey(ctx, key); !errors.Is(err, domain.ErrorConflict) {
+			t.Errorf("StoreAPIKey(%s, %s) error = %v, want conflict", key.ID, key.Hash, err)
+		}
+	}
+	if err := store.StoreAPIKey(ctx, &StoredAPIKey{ID: "01E", Hash: "hash-e"}); !errors.Is(err, domain.ErrorBadRequest) {
+		t.Errorf("StoreAPIKey() without owner error = %v, want bad request", err)
+	}
+
+	got, err := store.GetAPIKeyByHash(ctx, "hash-a")
+	if err != nil {
+		t.Fatalf("GetAPIKeyByHash() error = %v", err)
+	}
+	if got.ID != "01A" || got.Owner != "billing" || !reflect.DeepEqual(got.Scopes, []string{"examples:read", "examples:write"}) ||
+		got.CreatedAt.IsZero() || got.ExpiresAt == nil || !got.ExpiresAt.Equal(expiresAt) || got.LastUsedAt != nil || got.RevokedAt != nil {
+		t.Errorf("GetAPIKeyByHash() = %+v", got)
+	}
+	if _, err := store.GetAPIKeyByHash(ctx, "hash-x"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("GetAPIKeyByHash(unknown) error = %v, want not found", err)
+	}
+
+	list, err := store.ListAPIKeys(ctx, "billing")
+	if err != nil || len(list) != 2 || list[0].ID != "01A" || list[1].ID != "01C" {
+		t.Errorf("ListAPIKeys(billing) = %+v, %v; want 01A and 01C", list, err)
+	}
+	if list, err := store.ListAPIKeys(ctx, ""); err != nil || len(list) != 3 {
+		t.Errorf("ListAPIKeys() = %+v, %v; want all keys", list, err)
+	}
+
+	usedAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.TouchAPIKey(ctx, "01B", usedAt); err != nil {
+		t.Fatalf("TouchAPIKey() error = %v", err)
+	}
+	revokedAt := usedAt.Add(time.Hour)
+	if err := store.RevokeAPIKey(ctx, "01B", revokedAt); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	if err := store.RevokeAPIKey(ctx, "01B", revokedAt.Add(time.Hour)); err != nil {
+		t.Fatalf("RevokeAPIKey() again error = %v", err)
+	}
+	got, err = store.GetAPIKeyByHash(ctx, "hash-b")
+	if err != nil {
+		t.Fatalf("GetAPIKeyByHash() error = %v", err)
+	}
+	if got.LastUsedAt == nil || !got.LastUsedAt.Equal(usedAt) || got.RevokedAt == nil || !got.RevokedAt.Equal(revokedAt) {
+		t.Errorf("touched and revoked key = %+v", got)
+	}
+	if got.Active(revokedAt) {
+		t.Error("revoked key is active")
+	}
+
+	for name, err := range map[string]error{
+		"TouchAPIKey":  store.TouchAPIKey(ctx, "01X", usedAt),
+		"RevokeAPIKey": store.RevokeAPIKey(ctx, "01X", revokedAt),
+	} {
+		if !errors.Is(err, domain.ErrorNotFound) {
+			t.Errorf("%s(unknown) error = %v, want not found", name, err)
+		}
+	}
+}
+
+func TestStoredAPIKeyActive(t *testing.T) {
+	now := time.Now()
+	expired := now.Add(-time.Second)
+	later := now.Add(time.Hour)
+
+	if !(&StoredAPIKey{}).Active(now) || !(&StoredAPIKey{ExpiresAt: &later}).Active(now) {
+		t.Error("unexpired key is not active")
+	}
+	if (&StoredAPIKey{ExpiresAt: &expired}).Active(now) || (&StoredAPIKey{ExpiresAt: &now}).Active(now) {
+		t.Error("expired key is active")
+	}
+}
+
+func TestMemoryStoreAPIKeys(t *testing.T) {
+	t.Parallel()
+	assertAPIKeyStore(t, NewMemoryStore())
+}
+
+func TestSQLiteStoreAPIKeys(t *testing.T) {
+	t.Parallel()
+	assertAPIKeyStore(t, newTestSQLiteStore(t, SQLiteMemoryPath))
+}
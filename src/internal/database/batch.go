@@ -0,0 +1,191 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+const batchCollection = "example_batches"
+
+// Batch states.
+const (
+	BatchStatusRunning   = "running"
+	BatchStatusCompleted = "completed"
+)
+
+// Per-record outcomes of a batch. The values double as the names of the counters.
+const (
+	BatchOutcomeSucceeded = "succeeded"
+	BatchOutcomeFailed    = "failed"
+	BatchOutcomeSkipped   = "skipped"
+)
+
+// ErrBatchNotFound is returned when no progress is stored for a batch ID.
+var ErrBatchNotFound = fmt.Errorf("batch %w", domain.ErrorNotFound)
+
+// BatchProgress tracks how far the processing of a batch request got. It is created
+// when the batch starts, so a redelivered request resumes instead of starting over.
+type BatchProgress struct {
+	BatchID     string               `bson:"_id"`
+	Status      string               `bson:"status"`
+	Total       int                  `bson:"total"`
+	Succeeded   int                  `bson:"succeeded"`
+	Failed      int                  `bson:"failed"`
+	Skipped     int                  `bson:"skipped"`
+	Outcomes    []BatchRecordOutcome `bson:"outcomes"`
+	StartedAt   time.Time            `bson:"started_at"`
+	UpdatedAt   time.Time            `bson:"updated_at"`
+	CompletedAt *time.Time           `bson:"completed_at,omitempty"`
+}
+
+// BatchRecordOutcome is the result of one record of a batch. Status and Note carry the
+// ExampleResult of processed records; the error fields are set for failed ones.
+type BatchRecordOutcome struct {
+	RecordID    string    `bson:"record_id"`
+	Outcome     string    `bson:"outcome"`
+	Status      string    `bson:"status,omitempty"`
+	Note        string    `bson:"note,omitempty"`
+	ErrorCode   string    `bson:"error_code,omitempty"`
+	Message     string    `bson:"message,omitempty"`
+	Retryable   bool      `bson:"retryable,omitempty"`
+	ProcessedAt time.Time `bson:"processed_at"`
+}
+
+// BatchStore persists the progress of batch requests.
+type BatchStore interface {
+	// StartBatch stores progress for a new batch and returns the stored progress,
+	// which already contains the outcomes recorded before when the batch is resumed.
+	StartBatch(ctx context.Context, progress *BatchProgress) (*BatchProgress, error)
+	// RecordBatchOutcome adds the outcome of one record. Outcomes of records that
+	// already have one are ignored.
+	RecordBatchOutcome(ctx context.Context, batchID string, outcome BatchRecordOutcome) error
+	CompleteBatch(ctx context.Context, batchID string, completedAt time.Time) error
+	GetBatch(ctx context.Context, batchID string) (*BatchProgress, error)
+}
+
+// prepareBatchProgress validates a new batch and fills in its initial state.
+func prepareBatchProgress(progress *BatchProgress) error {
+	if progress == nil || progress.BatchID == "" {
+		return fmt.Errorf("batch id is required: %w", domain.ErrorBadRequest)
+	}
+	now := time.Now().UTC()
+	progress.Status = BatchStatusRunning
+	progress.Succeeded, progress.Failed, progress.Skipped = 0, 0, 0
+	progress.Outcomes = []BatchRecordOutcome{}
+	progress.StartedAt = now
+	progress.UpdatedAt = now
+	progress.CompletedAt = nil
+	return nil
+}
+
+func validBatchOutcome(outcome string) error {
+	switch outcome {
+	case BatchOutcomeSucceeded, BatchOutcomeFailed, BatchOutcomeSkipped:
+		return nil
+	default:
+		return fmt.Errorf("unknown batch outcome %q", outcome)
+	}
+}
+
+// count adds an outcome to the matching counter.
+func (p *BatchProgress) count(outcome string) {
+	switch outcome {
+	case BatchOutcomeSucceeded:
+		p.Succeeded++
+	case BatchOutcomeFailed:
+		p.Failed++
+	case BatchOutcomeSkipped:
+		p.Skipped++
+	}
+}
+
+// StartBatch inserts the batch document unless it exists and returns the stored one.
+func (db *Database) StartBatch(ctx context.Context, progress *BatchProgress) (*BatchProgress, error) {
+	if err := prepareBatchProgress(progress); err != nil {
+		return nil, err
+	}
+
+	var stored BatchProgress
+	err := db.DB.Collection(batchCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": progress.BatchID},
+		// The upsert takes _id from the filter.
+		bson.M{"$setOnInsert": bson.M{
+			"status":     progress.Status,
+			"total":      progress.Total,
+			"succeeded":  0,
+			"failed":     0,
+			"skipped":    0,
+			"outcomes":   progress.Outcomes,
+			"started_at": progress.StartedAt,
+			"updated_at": progress.UpdatedAt,
+		}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&stored)
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// RecordBatchOutcome pushes the outcome and bumps its counter in one update. The
+// filter skips records that already have an outcome.
+func (db *Database) RecordBatchOutcome(ctx context.Context, batchID string, outcome BatchRecordOutcome) error {
+	if err := validBatchOutcome(outcome.Outcome); err != nil {
+		return err
+	}
+
+	collection := db.DB.Collection(batchCollection)
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": batchID, "outcomes.record_id": bson.M{"$ne": outcome.RecordID}},
+		bson.M{
+			"$push": bson.M{"outcomes": outcome},
+			"$inc":  bson.M{outcome.Outcome: 1},
+			"$set":  bson.M{"updated_at": time.Now().UTC()},
+		},
+	)
+	if err != nil || result.MatchedCount > 0 {
+		return err
+	}
+
+	// No match means either a duplicate outcome or an unknown batch.
+	if err := collection.FindOne(ctx, bson.M{"_id": batchID}).Err(); errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrBatchNotFound
+	}
+	return nil
+}
+
+// CompleteBatch marks the batch completed.
+func (db *Database) CompleteBatch(ctx context.Context, batchID string, completedAt time.Time) error {
+	result, err := db.DB.Collection(batchCollection).UpdateOne(ctx,
+		bson.M{"_id": batchID},
+		bson.M{"$set": bson.M{"status": BatchStatusCompleted, "completed_at": completedAt.UTC(), "updated_at": completedAt.UTC()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrBatchNotFound
+	}
+	return nil
+}
+
+// GetBatch returns the stored progress of a batch.
+func (db *Database) GetBatch(ctx context.Context, batchID string) (*BatchProgress, error) {
+	var progress BatchProgress
+	err := db.DB.Collection(batchCollection).FindOne(ctx, bson.M{"_id": batchID}).Decode(&progress)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrBatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// assertBatchLifecycle exercises start, outcome recording, resume and completion on store.
+func assertBatchLifecycle(t *testing.T, store BatchStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	progress, err := store.StartBatch(ctx, &BatchProgress{BatchID: "batch-1", Total: 3})
+	if err != nil {
+		t.Fatalf("StartBatch() error = %v", err)
+	}
+	if progress.Status != BatchStatusRunning || progress.Total != 3 || len(progress.Outcomes) != 0 {
+		t.Fatalf("StartBatch() = %+v, want a running batch without outcomes", progress)
+	}
+
+	outcomes := []BatchRecordOutcome{
+		{RecordID: "EX-1", Outcome: BatchOutcomeSucceeded, Status: "completed", Note: "ok", ProcessedAt: now},
+		{RecordID: "EX-2", Outcome: BatchOutcomeFailed, ErrorCode: "NOT_FOUND", Message: "missing", ProcessedAt: now.Add(time.Second)},
+		// Duplicates are ignored, so redelivered batches do not double count.
+		{RecordID: "EX-1", Outcome: BatchOutcomeFailed, ProcessedAt: now.Add(2 * time.Second)},
+	}
+	for _, outcome := range outcomes {
+		if err := store.RecordBatchOutcome(ctx, "batch-1", outcome); err != nil {
+			t.Fatalf("RecordBatchOutcome(%s) error = %v", outcome.RecordID, err)
+		}
+	}
+
+	resumed, err := store.StartBatch(ctx, &BatchProgress{BatchID: "batch-1", Total: 3})
+	if err != nil {
+		t.Fatalf("StartBatch() on resume error = %v", err)
+	}
+	if resumed.Succeeded != 1 || resumed.Failed != 1 || len(resumed.Outcomes) != 2 {
+		t.Fatalf("resumed progress = %+v, want the recorded outcomes", resumed)
+	}
+	if got := resumed.Outcomes[1]; got.RecordID != "EX-2" || got.ErrorCode != "NOT_FOUND" || got.Message != "missing" {
+		t.Errorf("failed outcome = %+v", got)
+	}
+
+	if err := store.CompleteBatch(ctx, "batch-1", now); err != nil {
+		t.Fatalf("CompleteBatch() error = %v", err)
+	}
+	completed, err := store.GetBatch(ctx, "batch-1")
+	if err != nil || completed.Status != BatchStatusCompleted || completed.CompletedAt == nil {
+		t.Errorf("GetBatch() = %+v, %v; want a completed batch", completed, err)
+	}
+
+	if _, err := store.GetBatch(ctx, "unknown"); !errors.Is(err, ErrBatchNotFound) {
+		t.Errorf("GetBatch(unknown) error = %v, want ErrBatchNotFound", err)
+	}
+	if err := store.RecordBatchOutcome(ctx, "unknown", outcomes[0]); !errors.Is(err, ErrBatchNotFound) {
+		t.Errorf("RecordBatchOutcome(unknown) error = %v, want ErrBatchNotFound", err)
+	}
+	if err := store.RecordBatchOutcome(ctx, "batch-1", BatchRecordOutcome{RecordID: "EX-3", Outcome: "bogus"}); err == nil {
+		t.Error("RecordBatchOutcome() should reject unknown outcomes")
+	}
+	if _, err := store.StartBatch(ctx, &BatchProgress{}); err == nil {
+		t.Error("StartBatch() without batch id should fail")
+	}
+}
+
+func TestMemoryStoreBatch(t *testing.T) {
+	t.Parallel()
+	assertBatchLifecycle(t, NewMemoryStore())
+}
+
+func TestSQLiteStoreBatch(t *testing.T) {
+	t.Parallel()
+	assertBatchLifecycle(t, newTestSQLiteStore(t, SQLiteMemoryPath))
+}
@@ -2,17 +2,33 @@ package database
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"strings"
+	"time"
 )
 
-// Config holds MongoDB connection parameters.
+// DefaultExpirySweepInterval is how often the SQL stores delete expired rows.
+const DefaultExpirySweepInterval = time.Minute
+
+// Config holds the storage driver selection and its connection parameters.
 // SECURITY NOTE: Credentials should be provided via environment variables
 // or a secrets manager, never hardcoded or committed to version control.
 type Config struct {
-	MongoURL      string
-	MongoDB       string
-	MongoUser     string
-	MongoPassword string
+	Driver         string
+	ConflictPolicy string
+	// MigrateOnStart applies pending MongoDB migrations when connecting. SQL backends
+	// always migrate on startup.
+	MigrateOnStart bool
+	// ExpirySweepInterval is how often the SQL stores delete expired inbox entries and
+	// idempotency keys; zero disables the sweep. MongoDB expires them through TTL indexes.
+	ExpirySweepInterval time.Duration
+	MongoURL            string
+	MongoDB             string
+	MongoUser           string
+	MongoPassword       string
+	PostgresURL         string
+	SQLitePath          string
 }
 
 // Validate checks that the configuration has the required fields set.
@@ -21,6 +37,29 @@ func (c *Config) Validate() error {
 	if c == nil {
 		return errors.New("database config is nil")
 	}
+	if _, err := ParseConflictPolicy(c.ConflictPolicy); err != nil {
+		return err
+	}
+	if c.ExpirySweepInterval < 0 {
+		return errors.New("DB_EXPIRY_SWEEP_INTERVAL must not be negative")
+	}
+	switch c.DriverName() {
+	case DriverMemory:
+		return nil
+	case DriverPostgres:
+		if strings.TrimSpace(c.PostgresURL) == "" {
+			return errors.New("POSTGRES_URL is required")
+		}
+		return nil
+	case DriverSQLite:
+		if strings.TrimSpace(c.SQLitePath) == "" {
+			return errors.New("SQLITE_PATH is required")
+		}
+		return nil
+	case DriverMongo:
+	default:
+		return fmt.Errorf("unsupported DB_DRIVER %q", c.Driver)
+	}
 	if strings.TrimSpace(c.MongoURL) == "" {
 		return errors.New("MONGO_URL is required")
 	}
@@ -41,5 +80,22 @@ func (c *Config) Redacted() Config {
 	if redacted.MongoPassword != "" {
 		redacted.MongoPassword = "[REDACTED]"
 	}
+	redacted.PostgresURL = redactURL(redacted.PostgresURL)
 	return redacted
 }
+
+// redactURL masks the password of a connection URL. Values that do not parse as
+// URLs, such as key=value DSNs, are masked completely.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme == "" {
+		return "[REDACTED]"
+	}
+	if _, ok := parsed.User.Password(); ok {
+		parsed.User = url.UserPassword(parsed.User.Username(), "REDACTED")
+	}
+	return parsed.String()
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+// ConflictPolicy decides what happens when a record is stored under an ID that
+// already exists.
+type ConflictPolicy string
+
+// Supported values for DB_CONFLICT_POLICY.
+const (
+	// ConflictReject refuses the write with ErrExampleRecordExists.
+	ConflictReject ConflictPolicy = "reject"
+	// ConflictOverwrite replaces the fields of the stored record with those of the
+	// incoming one.
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictMerge copies the populated fields of the incoming record onto the stored
+	// one. Tags are combined.
+	ConflictMerge ConflictPolicy = "merge"
+)
+
+// ErrExampleRecordExists is returned when the reject policy refuses to replace a record.
+var ErrExampleRecordExists = fmt.Errorf("example record already exists: %w", domain.ErrorConflict)
+
+// ParseConflictPolicy normalises value, defaulting to ConflictReject.
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	policy := ConflictPolicy(strings.ToLower(strings.TrimSpace(value)))
+	switch policy {
+	case "":
+		return ConflictReject, nil
+	case ConflictReject, ConflictOverwrite, ConflictMerge:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unsupported DB_CONFLICT_POLICY %q", value)
+	}
+}
+
+// resolveConflict returns the record to store when incoming collides with existing,
+// or ErrExampleRecordExists when the policy rejects the write. The policies only
+// apply to the fields clients own. The status and status history of existing are
+// kept and its version is bumped, so a resubmitted record cannot rewind the
+// lifecycle of the stored one or overwrite a concurrent versioned replace.
+func (p ConflictPolicy) resolveConflict(existing, incoming *domain.ExampleRecord) (*domain.ExampleRecord, error) {
+	var resolved *domain.ExampleRecord
+	switch p {
+	case ConflictOverwrite:
+		resolved = proto.Clone(incoming).(*domain.ExampleRecord)
+	case ConflictMerge:
+		resolved = mergeExampleRecords(existing, incoming)
+	default:
+		return nil, ErrExampleRecordExists
+	}
+	resolved.Status = existing.GetStatus()
+	resolved.StatusHistory = existing.GetStatusHistory()
+	resolved.Version = existing.GetVersion() + 1
+	return resolved, nil
+}
+
+// mergeExampleRecords overlays the populated fields of incoming onto a copy of
+// existing. It merges every field; resolveConflict restores the lifecycle fields.
+func mergeExampleRecords(existing, incoming *domain.ExampleRecord) *domain.ExampleRecord {
+	merged := proto.Clone(existing).(*domain.ExampleRecord)
+	proto.Merge(merged, incoming)
+	// proto.Merge appends repeated fields; keep every tag once.
+	merged.Tags = uniqueTags(merged.GetTags())
+	return merged
+}
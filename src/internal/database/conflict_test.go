@@ -0,0 +1,111 @@
+package database
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+func TestParseConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]ConflictPolicy{
+		"":           ConflictReject,
+		"reject":     ConflictReject,
+		" Overwrite": ConflictOverwrite,
+		"MERGE":      ConflictMerge,
+	}
+	for value, want := range testCases {
+		got, err := ParseConflictPolicy(value)
+		if err != nil || got != want {
+			t.Errorf("ParseConflictPolicy(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+
+	if _, err := ParseConflictPolicy("ignore"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+	if err := (&Config{Driver: DriverMemory, ConflictPolicy: "ignore"}).Validate(); err == nil {
+		t.Error("Validate() should reject an unknown conflict policy")
+	}
+}
+
+func TestResolveConflict(t *testing.T) {
+	t.Parallel()
+
+	existing := &domain.ExampleRecord{
+		RecordId: "EX-1",
+		Title:    "stored",
+		Tags:     []string{"a", "b"},
+		Meta:     &domain.ExampleMeta{RequestedBy: "bot", Priority: 1},
+	}
+	incoming := &domain.ExampleRecord{
+		RecordId:    "EX-1",
+		Description: "incoming",
+		Tags:        []string{"b", "c"},
+		Meta:        &domain.ExampleMeta{Priority: 3},
+	}
+
+	if _, err := ConflictReject.resolveConflict(existing, incoming); !errors.Is(err, domain.ErrorConflict) {
+		t.Errorf("reject error = %v, want ErrorConflict", err)
+	}
+
+	overwritten, err := ConflictOverwrite.resolveConflict(existing, incoming)
+	if err != nil {
+		t.Fatalf("overwrite error = %v", err)
+	}
+	if overwritten.GetTitle() != "" || overwritten.GetDescription() != "incoming" || !slices.Equal(overwritten.GetTags(), []string{"b", "c"}) {
+		t.Errorf("overwritten = %v, want the incoming fields", overwritten)
+	}
+
+	merged, err := ConflictMerge.resolveConflict(existing, incoming)
+	if err != nil {
+		t.Fatalf("merge error = %v", err)
+	}
+	if merged.GetTitle() != "stored" || merged.GetDescription() != "incoming" {
+		t.Errorf("merged scalars = %q/%q", merged.GetTitle(), merged.GetDescription())
+	}
+	if merged.GetMeta().GetRequestedBy() != "bot" || merged.GetMeta().GetPriority() != 3 {
+		t.Errorf("merged meta = %v", merged.GetMeta())
+	}
+	if !slices.Equal(merged.GetTags(), []string{"a", "b", "c"}) {
+		t.Errorf("merged tags = %v, want [a b c]", merged.GetTags())
+	}
+	if existing.GetDescription() != "" {
+		t.Error("merge must not modify the stored record")
+	}
+}
+
+func TestResolveConflictKeepsLifecycle(t *testing.T) {
+	t.Parallel()
+
+	completed := domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_COMPLETED
+	queued := domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED
+	existing := &domain.ExampleRecord{
+		RecordId: "EX-1",
+		Version:  2,
+		Status:   completed,
+		StatusHistory: []*domain.StatusTransition{
+			{To: queued},
+			{From: queued, To: domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_IN_PROGRESS},
+			{From: domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_IN_PROGRESS, To: completed},
+		},
+	}
+	// A resubmitted record arrives queued again and without a version.
+	incoming := &domain.ExampleRecord{RecordId: "EX-1", Title: "again", Status: queued, StatusHistory: []*domain.StatusTransition{{To: queued}}}
+
+	for _, policy := range []ConflictPolicy{ConflictOverwrite, ConflictMerge} {
+		resolved, err := policy.resolveConflict(existing, incoming)
+		if err != nil {
+			t.Fatalf("%q: resolveConflict() error = %v", policy, err)
+		}
+		if resolved.GetTitle() != "again" {
+			t.Errorf("%q: Title = %q, want the incoming title", policy, resolved.GetTitle())
+		}
+		if resolved.GetStatus() != completed || len(resolved.GetStatusHistory()) != 3 || resolved.GetVersion() != 3 {
+			t.Errorf("%q: resolved = %v, want the stored lifecycle at version 3", policy, resolved)
+		}
+	}
+}
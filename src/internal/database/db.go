@@ -39,10 +39,37 @@ func NewDatabase(cfg *Config, logger *slog.Logger, ctx context.Context) (*Databa
 		return nil, err
 	}
 	logger.Info("Connected to MongoDB successfully")
-	return &Database{
+	db := &Database{
 		DB:  client.Database(cfg.MongoDB),
 		Cfg: cfg,
-	}, nil
+	}
+	if cfg.MigrateOnStart {
+		if err := db.migrateOnStart(ctx, logger); err != nil {
+			logger.Error("MongoDB migrations failed", "error", err)
+			_ = client.Disconnect(ctx)
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// migrateOnStart applies all pending MongoMigrations. It uses its own timeout, as
+// waiting for another replica's lock may take longer than connecting.
+func (db *Database) migrateOnStart(ctx context.Context, logger *slog.Logger) error {
+	migrator, err := NewMigrator(db.DB, MongoMigrations, logger)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultMigrationLockWait+time.Minute)
+	defer cancel()
+
+	steps, err := migrator.Migrate(ctx, MigrateUp, 0)
+	if err != nil {
+		return err
+	}
+	logger.Info("MongoDB migrations applied", "applied", len(steps))
+	return nil
 }
 
 // Ping verifies that the MongoDB client is still reachable.
@@ -61,3 +88,11 @@ func (db *Database) Ping(ctx context.Context) error {
 
 	return client.Ping(ctx, nil)
 }
+
+// Close disconnects the underlying MongoDB client.
+func (db *Database) Close(ctx context.Context) error {
+	if db == nil || db.DB == nil {
+		return nil
+	}
+	return db.DB.Client().Disconnect(ctx)
+}
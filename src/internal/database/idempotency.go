@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	idempotencyCollection  = "idempotency_keys"
+	idempotencyExpiryIndex = "idempotency_expiry"
+)
+
+// Idempotency key states.
+const (
+	IdempotencyStatusInFlight  = "in_flight"
+	IdempotencyStatusCompleted = "completed"
+)
+
+// IdempotencyRecord is a request sent with an Idempotency-Key. Once the request
+// completed it holds the response that is replayed for retries with the same key.
+type IdempotencyRecord struct {
+	Key string `bson:"_id"`
+	// Fingerprint identifies the request the key was first used for.
+	Fingerprint string `bson:"fingerprint"`
+	// Owner is a random token of the request holding the in-flight lease. Only
+	// that request can complete or release it, not one whose lease expired and was
+	// claimed again.
+	Owner      string            `bson:"owner,omitempty"`
+	Status     string            `bson:"status"`
+	StatusCode int               `bson:"status_code,omitempty"`
+	Header     map[string]string `bson:"header,omitempty"`
+	Body       []byte            `bson:"body,omitempty"`
+	ExpiresAt  time.Time         `bson:"expires_at"`
+}
+
+// IdempotencyStore remembers the responses of requests sent with an idempotency key.
+// Records expire and are forgotten after their TTL.
+type IdempotencyStore interface {
+	// ClaimIdempotencyKey leases key to the caller for the request with fingerprint
+	// unless an unexpired record exists. It reports whether the key was claimed and
+	// returns the claimed record, holding the owner token, or the existing record.
+	ClaimIdempotencyKey(ctx context.Context, key string, fingerprint string, now time.Time, lease time.Duration) (*IdempotencyRecord, bool, error)
+	// CompleteIdempotencyKey stores the response of a key claimed by record.Owner and
+	// keeps it until record.ExpiresAt.
+	CompleteIdempotencyKey(ctx context.Context, record *IdempotencyRecord) error
+	// ReleaseIdempotencyKey drops the lease owner holds on a key whose request
+	// failed, so a retry runs the request again.
+	ReleaseIdempotencyKey(ctx context.Context, key string, owner string) error
+}
+
+// inFlightIdempotencyRecord is the record of a key claimed until now+lease.
+func inFlightIdempotencyRecord(key string, fingerprint string, now time.Time, lease time.Duration) *IdempotencyRecord {
+	return &IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Owner:       rand.Text(),
+		Status:      IdempotencyStatusInFlight,
+		ExpiresAt:   now.Add(lease).UTC(),
+	}
+}
+
+// ClaimIdempotencyKey leases the key unless an unexpired record exists. The upsert
+// only matches an expired record, so a live one collides on _id.
+func (db *Database) ClaimIdempotencyKey(
+	ctx context.Context,
+	key string,
+	fingerprint string,
+	now time.Time,
+	lease time.Duration,
+) (*IdempotencyRecord, bool, error) {
+	claimed := inFlightIdempotencyRecord(key, fingerprint, now, lease)
+	collection := db.DB.Collection(idempotencyCollection)
+
+	_, err := collection.ReplaceOne(ctx,
+		bson.M{"_id": key, "expires_at": bson.M{"$lte": now.UTC()}},
+		claimed,
+		options.Replace().SetUpsert(true),
+	)
+	if err == nil {
+		return claimed, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, false, err
+	}
+
+	var existing IdempotencyRecord
+	if err := collection.FindOne(ctx, bson.M{"_id": key}).Decode(&existing); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// Released or expired between the two calls; the caller retries later.
+			return claimed, false, nil
+		}
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+// CompleteIdempotencyKey stores the response of an in-flight key.
+func (db *Database) CompleteIdempotencyKey(ctx context.Context, record *IdempotencyRecord) error {
+	_, err := db.DB.Collection(idempotencyCollection).UpdateOne(ctx,
+		bson.M{"_id": record.Key, "owner": record.Owner, "status": IdempotencyStatusInFlight},
+		bson.M{"$set": bson.M{
+			"status":      IdempotencyStatusCompleted,
+			"status_code": record.StatusCode,
+			"header":      record.Header,
+			"body":        record.Body,
+			"expires_at":  record.ExpiresAt.UTC(),
+		}},
+	)
+	return err
+}
+
+// ReleaseIdempotencyKey deletes the record of an in-flight key.
+func (db *Database) ReleaseIdempotencyKey(ctx context.Context, key string, owner string) error {
+	_, err := db.DB.Collection(idempotencyCollection).DeleteOne(ctx,
+		bson.M{"_id": key, "owner": owner, "status": IdempotencyStatusInFlight},
+	)
+	return err
+}
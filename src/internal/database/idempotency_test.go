@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// assertIdempotencyLifecycle exercises claim, release, completion and expiry on store.
+func assertIdempotencyLifecycle(t *testing.T, store IdempotencyStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	claim := func(fingerprint string, at time.Time) (*IdempotencyRecord, bool) {
+		t.Helper()
+		record, claimed, err := store.ClaimIdempotencyKey(ctx, "key-1", fingerprint, at, time.Minute)
+		if err != nil {
+			t.Fatalf("ClaimIdempotencyKey() error = %v", err)
+		}
+		return record, claimed
+	}
+
+	first, claimed := claim("fp-1", now)
+	if !claimed || first.Owner == "" {
+		t.Fatalf("first claim = %+v, %v; want a claim with an owner", first, claimed)
+	}
+	record, claimed := claim("fp-2", now)
+	if claimed || record.Status != IdempotencyStatusInFlight || record.Fingerprint != "fp-1" {
+		t.Errorf("claim during lease = %+v, %v; want the in-flight record of fp-1", record, claimed)
+	}
+
+	if err := store.ReleaseIdempotencyKey(ctx, "key-1", first.Owner); err != nil {
+		t.Fatalf("ReleaseIdempotencyKey() error = %v", err)
+	}
+	second, claimed := claim("fp-1", now)
+	if !claimed {
+		t.Fatal("claim after release was not granted")
+	}
+
+	// The first request no longer owns the key, so it can neither release nor
+	// complete the lease of the second one.
+	_ = store.ReleaseIdempotencyKey(ctx, "key-1", first.Owner)
+	_ = store.CompleteIdempotencyKey(ctx, &IdempotencyRecord{Key: "key-1", Owner: first.Owner, StatusCode: 500, ExpiresAt: now.Add(time.Hour)})
+	if record, claimed := claim("fp-1", now); claimed || record.Status != IdempotencyStatusInFlight {
+		t.Errorf("claim after a stale release or completion = %+v, %v; want the in-flight lease", record, claimed)
+	}
+
+	completed := &IdempotencyRecord{
+		Key:        "key-1",
+		Owner:      second.Owner,
+		StatusCode: 201,
+		Header:     map[string]string{"Content-Type": "application/json"},
+		Body:       []byte(`{"record_id":"EX-1"}`),
+		ExpiresAt:  now.Add(time.Hour),
+	}
+	if err := store.CompleteIdempotencyKey(ctx, completed); err != nil {
+		t.Fatalf("CompleteIdempotencyKey() error = %v", err)
+	}
+	record, claimed = claim("fp-1", now.Add(2*time.Minute))
+	if claimed || record.Status != IdempotencyStatusCompleted || record.Fingerprint != "fp-1" {
+		t.Fatalf("claim after completion = %+v, %v; want the completed record", record, claimed)
+	}
+	if record.StatusCode != 201 || string(record.Body) != string(completed.Body) || !reflect.DeepEqual(record.Header, completed.Header) {
+		t.Errorf("stored response = %d %v %s", record.StatusCode, record.Header, record.Body)
+	}
+
+	_ = store.ReleaseIdempotencyKey(ctx, "key-1", second.Owner)
+	if _, claimed := claim("fp-1", now.Add(2*time.Minute)); claimed {
+		t.Error("release must not forget completed responses")
+	}
+	if _, claimed := claim("fp-2", now.Add(2*time.Hour)); !claimed {
+		t.Error("claim after expiry was not granted")
+	}
+}
+
+func TestMemoryStoreIdempotency(t *testing.T) {
+	t.Parallel()
+	assertIdempotencyLifecycle(t, NewMemoryStore())
+}
+
+func TestSQLiteStoreIdempotency(t *testing.T) {
+	t.Parallel()
+	assertIdempotencyLifecycle(t, newTestSQLiteStore(t, SQLiteMemoryPath))
+}
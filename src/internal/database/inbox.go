@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	inboxCollection     = "inbox_messages"
+	inboxExpiryIndexKey = "expires_at"
+	inboxExpiryIndex    = "inbox_expiry"
+)
+
+// Inbox entry states.
+const (
+	InboxStatusProcessing = "processing"
+	InboxStatusProcessed  = "processed"
+)
+
+// InboxClaim is the outcome of claiming a consumed message for processing.
+type InboxClaim int
+
+const (
+	// InboxClaimed means the message is new, or its previous entry expired, and the
+	// caller now owns it until the lease runs out.
+	InboxClaimed InboxClaim = iota
+	// InboxDuplicate means the message was already processed successfully.
+	InboxDuplicate
+	// InboxInFlight means another consumer holds an unexpired lease on the message.
+	InboxInFlight
+)
+
+// InboxStore remembers which consumed messages a handler already processed, so
+// redelivered copies can be skipped. Entries expire and are forgotten after their TTL.
+type InboxStore interface {
+	ClaimInboxMessage(ctx context.Context, handler string, uuid string, now time.Time, lease time.Duration) (InboxClaim, error)
+	CompleteInboxMessage(ctx context.Context, handler string, uuid string, expiresAt time.Time) error
+	ReleaseInboxMessage(ctx context.Context, handler string, uuid string) error
+}
+
+// inboxEntry is the inbox_messages document of a consumed message.
+type inboxEntry struct {
+	ID        string    `bson:"_id"`
+	Handler   string    `bson:"handler"`
+	UUID      string    `bson:"uuid"`
+	Status    string    `bson:"status"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+func inboxEntryID(handler string, uuid string) string {
+	return handler + "/" + uuid
+}
+
+// ClaimInboxMessage leases the message to the caller unless an unexpired entry exists.
+// The upsert only matches an expired entry, so a live one collides on _id.
+func (db *Database) ClaimInboxMessage(
+	ctx context.Context,
+	handler string,
+	uuid string,
+	now time.Time,
+	lease time.Duration,
+) (InboxClaim, error) {
+	id := inboxEntryID(handler, uuid)
+	collection := db.DB.Collection(inboxCollection)
+
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "expires_at": bson.M{"$lte": now.UTC()}},
+		bson.M{"$set": bson.M{
+			"handler":    handler,
+			"uuid":       uuid,
+			"status":     InboxStatusProcessing,
+			"expires_at": now.Add(lease).UTC(),
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err == nil {
+		return InboxClaimed, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return InboxInFlight, err
+	}
+
+	var entry inboxEntry
+	if err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&entry); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// Released or expired between the two calls; let the redelivery decide.
+			return InboxInFlight, nil
+		}
+		return InboxInFlight, err
+	}
+	return inboxClaimForStatus(entry.Status), nil
+}
+
+// CompleteInboxMessage marks the message processed and keeps the entry until expiresAt.
+func (db *Database) CompleteInboxMessage(ctx context.Context, handler string, uuid string, expiresAt time.Time) error {
+	_, err := db.DB.Collection(inboxCollection).UpdateOne(ctx,
+		bson.M{"_id": inboxEntryID(handler, uuid)},
+		bson.M{"$set": bson.M{"status": InboxStatusProcessed, "expires_at": expiresAt.UTC()}},
+	)
+	return err
+}
+
+// ReleaseInboxMessage drops a lease after failed processing so a redelivery is handled.
+func (db *Database) ReleaseInboxMessage(ctx context.Context, handler string, uuid string) error {
+	_, err := db.DB.Collection(inboxCollection).DeleteOne(ctx,
+		bson.M{"_id": inboxEntryID(handler, uuid), "status": InboxStatusProcessing},
+	)
+	return err
+}
+
+func inboxClaimForStatus(status string) InboxClaim {
+	if status == InboxStatusProcessed {
+		return InboxDuplicate
+	}
+	return InboxInFlight
+}
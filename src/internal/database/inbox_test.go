@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// assertInboxLifecycle exercises claim, release, completion and expiry on store.
+func assertInboxLifecycle(t *testing.T, store InboxStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	claim := func(uuid string, at time.Time) InboxClaim {
+		t.Helper()
+		got, err := store.ClaimInboxMessage(ctx, "handler", uuid, at, time.Minute)
+		if err != nil {
+			t.Fatalf("ClaimInboxMessage(%s) error = %v", uuid, err)
+		}
+		return got
+	}
+
+	if got := claim("id-1", now); got != InboxClaimed {
+		t.Fatalf("first claim = %v, want InboxClaimed", got)
+	}
+	if got := claim("id-1", now); got != InboxInFlight {
+		t.Errorf("claim during lease = %v, want InboxInFlight", got)
+	}
+	if got, _ := store.ClaimInboxMessage(ctx, "other", "id-1", now, time.Minute); got != InboxClaimed {
+		t.Errorf("claim by another handler = %v, want InboxClaimed", got)
+	}
+
+	if err := store.ReleaseInboxMessage(ctx, "handler", "id-1"); err != nil {
+		t.Fatalf("ReleaseInboxMessage() error = %v", err)
+	}
+	if got := claim("id-1", now); got != InboxClaimed {
+		t.Errorf("claim after release = %v, want InboxClaimed", got)
+	}
+
+	if err := store.CompleteInboxMessage(ctx, "handler", "id-1", now.Add(time.Hour)); err != nil {
+		t.Fatalf("CompleteInboxMessage() error = %v", err)
+	}
+	if got := claim("id-1", now.Add(2*time.Minute)); got != InboxDuplicate {
+		t.Errorf("claim after completion = %v, want InboxDuplicate", got)
+	}
+	_ = store.ReleaseInboxMessage(ctx, "handler", "id-1")
+	if got := claim("id-1", now.Add(2*time.Minute)); got != InboxDuplicate {
+		t.Errorf("release must not forget processed messages, claim = %v", got)
+	}
+	if got := claim("id-1", now.Add(2*time.Hour)); got != InboxClaimed {
+		t.Errorf("claim after expiry = %v, want InboxClaimed", got)
+	}
+}
+
+func TestMemoryStoreInbox(t *testing.T) {
+	t.Parallel()
+	assertInboxLifecycle(t, NewMemoryStore())
+}
+
+func TestSQLiteStoreInbox(t *testing.T) {
+	t.Parallel()
+	assertInboxLifecycle(t, newTestSQLiteStore(t, SQLiteMemoryPath))
+}
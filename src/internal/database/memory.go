@@ -0,0 +1,604 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	date "google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/protobuf/proto"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+// sweepInterval is how often MemoryStore drops expired inbox and idempotency entries.
+const sweepInterval = time.Minute
+
+// MemoryStore is a Repository that keeps everything in process memory. It is meant
+// for local runs and hermetic tests; all data is lost when the process exits.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	records     map[string]*domain.ExampleRecord
+	outbox      map[string][]*OutboxMessage
+	inbox       map[string]inboxEntry
+	batches     map[string]*BatchProgress
+	results     map[string]*StoredExampleResult
+	apiKeys     map[string]*StoredAPIKey
+	idempotency map[string]*IdempotencyRecord
+	// conflictPolicy applies to StoreExampleRecord; the zero value rejects duplicates.
+	conflictPolicy ConflictPolicy
+	lastSweep      time.Time
+}
+
+// NewMemoryStore returns an empty in-memory repository.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records:     map[string]*domain.ExampleRecord{},
+		outbox:      map[string][]*OutboxMessage{},
+		inbox:       map[string]inboxEntry{},
+		batches:     map[string]*BatchProgress{},
+		results:     map[string]*StoredExampleResult{},
+		apiKeys:     map[string]*StoredAPIKey{},
+		idempotency: map[string]*IdempotencyRecord{},
+	}
+}
+
+// Ping always succeeds for the in-memory store.
+func (m *MemoryStore) Ping(context.Context) error {
+	if m == nil {
+		return errors.New("database not configured")
+	}
+	return nil
+}
+
+// Close is a no-op for the in-memory store.
+func (m *MemoryStore) Close(context.Context) error {
+	return nil
+}
+
+// StoreExampleRecord saves a copy of a new record. An existing record with the same ID
+// is handled according to the conflict policy.
+func (m *MemoryStore) StoreExampleRecord(_ context.Context, record *domain.ExampleRecord) error {
+	if record == nil {
+		return errors.New("example record is required")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.storeExampleRecordLocked(record)
+}
+
+// StoreExampleRecordWithOutbox saves the record and the outbox row under one lock, so
+// no reader observes one without the other.
+func (m *MemoryStore) StoreExampleRecordWithOutbox(_ context.Context, record *domain.ExampleRecord, msg *OutboxMessage) error {
+	if record == nil {
+		return errors.New("example record is required")
+	}
+	if err := prepareOutboxMessage(msg); err != nil {
+		return err
+	}
+	row := *msg
+	row.Metadata = maps.Clone(msg.Metadata)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.storeExampleRecordLocked(record); err != nil {
+		return err
+	}
+	m.outbox[msg.Handler] = append(m.outbox[msg.Handler], &row)
+	return nil
+}
+
+// storeExampleRecordLocked inserts the record or resolves the collision through the
+// conflict policy. The caller holds the write lock.
+func (m *MemoryStore) storeExampleRecordLocked(record *domain.ExampleRecord) error {
+	id := record.GetRecordId()
+	existing, ok := m.records[id]
+	if !ok {
+		m.records[id] = cloneRecord(record)
+		return nil
+	}
+	resolved, err := m.conflictPolicy.resolveConflict(existing, record)
+	if err != nil {
+		return err
+	}
+	m.records[id] = cloneRecord(resolved)
+	return nil
+}
+
+func (m *MemoryStore) GetExampleRecordByID(_ context.Context, id string) (*domain.ExampleRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	record, ok := m.records[id]
+	if !ok {
+		return nil, ErrExampleRecordNotFound
+	}
+	return cloneRecord(record), nil
+}
+
+// ListExampleRecords mirrors the MongoDB implementation: records are ordered by ID
+// and paginated with the same opaque cursor.
+func (m *MemoryStore) ListExampleRecords(_ context.Context, filter ExampleRecordFilter) ([]*domain.ExampleRecord, string, error) {
+	after := ""
+	if filter.Cursor != "" {
+		decoded, err := DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = decoded
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultExampleRecordPageSize
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make([]string, 0, len(m.records))
+	for id := range m.records {
+		if id > after || after == "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	records := make([]*domain.ExampleRecord, 0, limit)
+	for _, id := range ids {
+		record := m.records[id]
+		if !matchesExampleRecordFilter(record, filter) {
+			continue
+		}
+		if len(records) == limit {
+			return records, EncodeCursor(records[limit-1].GetRecordId()), nil
+		}
+		records = append(records, cloneRecord(record))
+	}
+	return records, "", nil
+}
+
+// ReplaceExampleRecordVersion overwrites the stored record with the given ID while it
+// still has version.
+func (m *MemoryStore) ReplaceExampleRecordVersion(_ context.Context, id string, version int32, record *domain.ExampleRecord) error {
+	if record == nil {
+		return errors.New("example record is required")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.records[id]
+	if !ok {
+		return ErrExampleRecordNotFound
+	}
+	if stored.GetVersion() != version {
+		return ErrExampleRecordVersionConflict
+	}
+	m.records[id] = cloneRecord(record)
+	return nil
+}
+
+func (m *MemoryStore) DeleteExampleRecord(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.records[id]; !ok {
+		return ErrExampleRecordNotFound
+	}
+	delete(m.records, id)
+	return nil
+}
+
+func (m *MemoryStore) StoreOutboxMessage(_ context.Context, msg *OutboxMessage) error {
+	if err := prepareOutboxMessage(msg); err != nil {
+		return err
+	}
+	row := *msg
+	row.Metadata = maps.Clone(msg.Metadata)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outbox[msg.Handler] = append(m.outbox[msg.Handler], &row)
+	return nil
+}
+
+func (m *MemoryStore) OutboxHandlers(context.Context) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	handlers := make([]string, 0, len(m.outbox))
+	for handler := range m.outbox {
+		handlers = append(handlers, handler)
+	}
+	sort.Strings(handlers)
+	return handlers, nil
+}
+
+func (m *MemoryStore) ClaimOutboxMessages(
+	_ context.Context,
+	handler string,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]OutboxMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	due := make([]*OutboxMessage, 0)
+	for _, row := range m.outbox[handler] {
+		if row.Status == OutboxStatusPending && !row.NextAttemptAt.After(now) {
+			due = append(due, row)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *OutboxMessage) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]OutboxMessage, 0, len(due))
+	for _, row := range due {
+		row.NextAttemptAt = now.Add(lease)
+		claimed = append(claimed, *row)
+	}
+	return claimed, nil
+}
+
+// MarkOutboxMessageSent deletes the sent row instead of keeping it, so the outbox of
+// a long-running process does not grow without bound.
+func (m *MemoryStore) MarkOutboxMessageSent(_ context.Context, handler string, uuid string, _ time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outbox[handler] = slices.DeleteFunc(m.outbox[handler], func(row *OutboxMessage) bool {
+		return row.UUID == uuid
+	})
+	if len(m.outbox[handler]) == 0 {
+		delete(m.outbox, handler)
+	}
+	return nil
+}
+
+func (m *MemoryStore) MarkOutboxMessageFailed(
+	_ context.Context,
+	handler string,
+	uuid string,
+	reason string,
+	nextAttemptAt time.Time,
+	final bool,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, row := range m.outbox[handler] {
+		if row.UUID != uuid {
+			continue
+		}
+		row.Status = OutboxStatusPending
+		if final {
+			row.Status = OutboxStatusFailed
+		}
+		row.LastError = reason
+		row.NextAttemptAt = nextAttemptAt
+		row.Attempts++
+	}
+	return nil
+}
+
+// OutboxMessages returns copies of all outbox rows of a handler. It is intended for tests.
+func (m *MemoryStore) OutboxMessages(handler string) []OutboxMessage {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rows := make([]OutboxMessage, 0, len(m.outbox[handler]))
+	for _, row := range m.outbox[handler] {
+		rows = append(rows, *row)
+	}
+	return rows
+}
+
+func (m *MemoryStore) ClaimInboxMessage(
+	_ context.Context,
+	handler string,
+	uuid string,
+	now time.Time,
+	lease time.Duration,
+) (InboxClaim, error) {
+	id := inboxEntryID(handler, uuid)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweepLocked(now)
+	if entry, ok := m.inbox[id]; ok && entry.ExpiresAt.After(now) {
+		return inboxClaimForStatus(entry.Status), nil
+	}
+	m.inbox[id] = inboxEntry{ID: id, Handler: handler, UUID: uuid, Status: InboxStatusProcessing, ExpiresAt: now.Add(lease)}
+	return InboxClaimed, nil
+}
+
+func (m *MemoryStore) CompleteInboxMessage(_ context.Context, handler string, uuid string, expiresAt time.Time) error {
+	id := inboxEntryID(handler, uuid)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if entry, ok := m.inbox[id]; ok {
+		entry.Status = InboxStatusProcessed
+		entry.ExpiresAt = expiresAt
+		m.inbox[id] = entry
+	}
+	return nil
+}
+
+func (m *MemoryStore) ReleaseInboxMessage(_ context.Context, handler string, uuid string) error {
+	id := inboxEntryID(handler, uuid)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if entry, ok := m.inbox[id]; ok && entry.Status == InboxStatusProcessing {
+		delete(m.inbox, id)
+	}
+	return nil
+}
+
+func (m *MemoryStore) ClaimIdempotencyKey(
+	_ context.Context,
+	key string,
+	fingerprint string,
+	now time.Time,
+	lease time.Duration,
+) (*IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweepLocked(now)
+	if record, ok := m.idempotency[key]; ok && record.ExpiresAt.After(now) {
+		return cloneIdempotencyRecord(record), false, nil
+	}
+	record := inFlightIdempotencyRecord(key, fingerprint, now, lease)
+	m.idempotency[key] = record
+	return cloneIdempotencyRecord(record), true, nil
+}
+
+func (m *MemoryStore) CompleteIdempotencyKey(_ context.Context, record *IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stored, ok := m.idempotency[record.Key]; ok && stored.Status == IdempotencyStatusInFlight && stored.Owner == record.Owner {
+		completed := cloneIdempotencyRecord(record)
+		completed.Fingerprint = stored.Fingerprint
+		completed.Status = IdempotencyStatusCompleted
+		m.idempotency[record.Key] = completed
+	}
+	return nil
+}
+
+func (m *MemoryStore) ReleaseIdempotencyKey(_ context.Context, key string, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.idempotency[key]; ok && record.Status == IdempotencyStatusInFlight && record.Owner == owner {
+		delete(m.idempotency, key)
+	}
+	return nil
+}
+
+// sweepLocked drops the inbox and idempotency entries that expired by now; they
+// equal missing entries. It runs at most once per sweepInterval, so entries that are
+// never looked up again do not pile up. m.mu must be held.
+func (m *MemoryStore) sweepLocked(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	maps.DeleteFunc(m.inbox, func(_ string, entry inboxEntry) bool { return !entry.ExpiresAt.After(now) })
+	maps.DeleteFunc(m.idempotency, func(_ string, record *IdempotencyRecord) bool { return !record.ExpiresAt.After(now) })
+	m.lastSweep = now
+}
+
+func cloneIdempotencyRecord(record *IdempotencyRecord) *IdempotencyRecord {
+	clone := *record
+	clone.Header = maps.Clone(record.Header)
+	clone.Body = slices.Clone(record.Body)
+	return &clone
+}
+
+func (m *MemoryStore) StartBatch(_ context.Context, progress *BatchProgress) (*BatchProgress, error) {
+	if err := prepareBatchProgress(progress); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.batches[progress.BatchID]
+	if !ok {
+		stored = cloneBatchProgress(progress)
+		m.batches[progress.BatchID] = stored
+	}
+	return cloneBatchProgress(stored), nil
+}
+
+func (m *MemoryStore) RecordBatchOutcome(_ context.Context, batchID string, outcome BatchRecordOutcome) error {
+	if err := validBatchOutcome(outcome.Outcome); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	progress, ok := m.batches[batchID]
+	if !ok {
+		return ErrBatchNotFound
+	}
+	if slices.ContainsFunc(progress.Outcomes, func(o BatchRecordOutcome) bool { return o.RecordID == outcome.RecordID }) {
+		return nil
+	}
+	progress.Outcomes = append(progress.Outcomes, outcome)
+	progress.count(outcome.Outcome)
+	progress.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+func (m *MemoryStore) CompleteBatch(_ context.Context, batchID string, completedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	progress, ok := m.batches[batchID]
+	if !ok {
+		return ErrBatchNotFound
+	}
+	progress.Status = BatchStatusCompleted
+	progress.CompletedAt = &completedAt
+	progress.UpdatedAt = completedAt
+	return nil
+}
+
+func (m *MemoryStore) GetBatch(_ context.Context, batchID string) (*BatchProgress, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	progress, ok := m.batches[batchID]
+	if !ok {
+		return nil, ErrBatchNotFound
+	}
+	return cloneBatchProgress(progress), nil
+}
+
+func cloneBatchProgress(progress *BatchProgress) *BatchProgress {
+	clone := *progress
+	clone.Outcomes = slices.Clone(progress.Outcomes)
+	return &clone
+}
+
+func (m *MemoryStore) StoreExampleResult(_ context.Context, result *StoredExampleResult) error {
+	if err := prepareExampleResult(result); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.results[result.ID]; !ok {
+		m.results[result.ID] = cloneExampleResult(result)
+	}
+	return nil
+}
+
+func (m *MemoryStore) ListExampleResults(_ context.Context, filter ExampleResultFilter) ([]*StoredExampleResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	results := make([]*StoredExampleResult, 0)
+	for _, result := range m.results {
+		if filter.RecordID != "" && result.RecordID != filter.RecordID {
+			continue
+		}
+		if filter.CorrelationID != "" && result.CorrelationID != filter.CorrelationID {
+			continue
+		}
+		results = append(results, cloneExampleResult(result))
+	}
+	slices.SortFunc(results, func(a, b *StoredExampleResult) int {
+		if c := b.StoredAt.Compare(a.StoredAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID, a.ID)
+	})
+	if len(results) > filter.limit() {
+		results = results[:filter.limit()]
+	}
+	return results, nil
+}
+
+func cloneExampleResult(result *StoredExampleResult) *StoredExampleResult {
+	clone := *result
+	if result.ProcessedOn != nil {
+		clone.ProcessedOn = proto.Clone(result.ProcessedOn).(*date.Date)
+	}
+	return &clone
+}
+
+func (m *MemoryStore) StoreAPIKey(_ context.Context, key *StoredAPIKey) error {
+	if err := prepareAPIKey(key); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, stored := range m.apiKeys {
+		if id == key.ID || stored.Hash == key.Hash {
+			return ErrAPIKeyExists
+		}
+	}
+	m.apiKeys[key.ID] = cloneAPIKey(key)
+	return nil
+}
+
+func (m *MemoryStore) GetAPIKeyByHash(_ context.Context, hash string) (*StoredAPIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, key := range m.apiKeys {
+		if key.Hash == hash {
+			return cloneAPIKey(key), nil
+		}
+	}
+	return nil, ErrAPIKeyNotFound
+}
+
+func (m *MemoryStore) ListAPIKeys(_ context.Context, owner string) ([]*StoredAPIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]*StoredAPIKey, 0)
+	for _, key := range m.apiKeys {
+		if owner == "" || key.Owner == owner {
+			keys = append(keys, cloneAPIKey(key))
+		}
+	}
+	slices.SortFunc(keys, func(a, b *StoredAPIKey) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return keys, nil
+}
+
+func (m *MemoryStore) RevokeAPIKey(_ context.Context, id string, revokedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.apiKeys[id]
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+	if key.RevokedAt == nil {
+		revokedAt = revokedAt.UTC()
+		key.RevokedAt = &revokedAt
+	}
+	return nil
+}
+
+func (m *MemoryStore) TouchAPIKey(_ context.Context, id string, usedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.apiKeys[id]
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+	usedAt = usedAt.UTC()
+	key.LastUsedAt = &usedAt
+	return nil
+}
+
+func cloneAPIKey(key *StoredAPIKey) *StoredAPIKey {
+	clone := *key
+	clone.Scopes = slices.Clone(key.Scopes)
+	return &clone
+}
+
+// matchesExampleRecordFilter applies the non-pagination parts of the filter in memory.
+func matchesExampleRecordFilter(record *domain.ExampleRecord, filter ExampleRecordFilter) bool {
+	meta := record.GetMeta()
+	for _, tag := range filter.Tags {
+		if !slices.Contains(record.GetTags(), tag) {
+			return false
+		}
+	}
+	if filter.Priority != 0 && meta.GetPriority() != filter.Priority {
+		return false
+	}
+	if filter.RequestedBy != "" && meta.GetRequestedBy() != filter.RequestedBy {
+		return false
+	}
+
+	start := dateOrdinal(meta.GetDesiredStartDate())
+	if filter.DesiredStartFrom != nil && start < dateOrdinal(filter.DesiredStartFrom) {
+		return false
+	}
+	if filter.DesiredStartTo != nil && start > dateOrdinal(filter.DesiredStartTo) {
+		return false
+	}
+	return true
+}
+
+func cloneRecord(record *domain.ExampleRecord) *domain.ExampleRecord {
+	return proto.Clone(record).(*domain.ExampleRecord)
+}
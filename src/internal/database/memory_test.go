@@ -0,0 +1,321 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+
+	date "google.golang.org/genproto/googleapis/type/date"
+)
+
+func seedMemoryStore(t *testing.T, records ...*domain.ExampleRecord) *MemoryStore {
+	t.Helper()
+	store := NewMemoryStore()
+	for _, record := range records {
+		if err := store.StoreExampleRecord(context.Background(), record); err != nil {
+			t.Fatalf("StoreExampleRecord() error = %v", err)
+		}
+	}
+	return store
+}
+
+func TestMemoryStoreExampleRecordLifecycle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := seedMemoryStore(t, &domain.ExampleRecord{RecordId: "EX-1", Title: "first"})
+
+	got, err := store.GetExampleRecordByID(ctx, "EX-1")
+	if err != nil || got.GetTitle() != "first" {
+		t.Fatalf("GetExampleRecordByID() = %v, %v", got, err)
+	}
+
+	got.Title = "mutated"
+	if again, _ := store.GetExampleRecordByID(ctx, "EX-1"); again.GetTitle() != "first" {
+		t.Error("returned records must not alias stored records")
+	}
+
+	if err := store.ReplaceExampleRecordVersion(ctx, "EX-1", got.GetVersion(), &domain.ExampleRecord{RecordId: "EX-1", Title: "second", Version: got.GetVersion() + 1}); err != nil {
+		t.Fatalf("ReplaceExampleRecordVersion() error = %v", err)
+	}
+	if got, _ := store.GetExampleRecordByID(ctx, "EX-1"); got.GetTitle() != "second" {
+		t.Errorf("Title = %q, want 'second'", got.GetTitle())
+	}
+
+	if err := store.DeleteExampleRecord(ctx, "EX-1"); err != nil {
+		t.Fatalf("DeleteExampleRecord() error = %v", err)
+	}
+	if _, err := store.GetExampleRecordByID(ctx, "EX-1"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("expected ErrorNotFound after delete, got %v", err)
+	}
+}
+
+func TestMemoryStoreNotFound(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.ReplaceExampleRecordVersion(ctx, "missing", 0, &domain.ExampleRecord{}); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("ReplaceExampleRecordVersion() error = %v, want ErrorNotFound", err)
+	}
+	if err := store.DeleteExampleRecord(ctx, "missing"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("DeleteExampleRecord() error = %v, want ErrorNotFound", err)
+	}
+	if err := store.StoreExampleRecord(ctx, nil); err == nil {
+		t.Error("StoreExampleRecord(nil) should fail")
+	}
+}
+
+func TestMemoryStoreListPagination(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewMemoryStore()
+	for i := 5; i >= 1; i-- {
+		_ = store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: fmt.Sprintf("EX-%d", i)})
+	}
+
+	var ids []string
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		records, next, err := store.ListExampleRecords(ctx, ExampleRecordFilter{Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("ListExampleRecords() error = %v", err)
+		}
+		for _, record := range records {
+			ids = append(ids, record.GetRecordId())
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	want := []string{"EX-1", "EX-2", "EX-3", "EX-4", "EX-5"}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("paged ids = %v, want %v", ids, want)
+	}
+
+	if _, _, err := store.ListExampleRecords(ctx, ExampleRecordFilter{Cursor: "%%%"}); !errors.Is(err, domain.ErrorBadRequest) {
+		t.Errorf("invalid cursor error = %v, want ErrorBadRequest", err)
+	}
+}
+
+func TestMemoryStoreListFilters(t *testing.T) {
+	t.Parallel()
+
+	store := seedMemoryStore(t,
+		&domain.ExampleRecord{RecordId: "EX-1", Tags: []string{"a", "b"}, Meta: &domain.ExampleMeta{
+			Priority: 1, RequestedBy: "bot", DesiredStartDate: &date.Date{Year: 2025, Month: 1, Day: 10},
+		}},
+		&domain.ExampleRecord{RecordId: "EX-2", Tags: []string{"a"}, Meta: &domain.ExampleMeta{
+			Priority: 2, RequestedBy: "human", DesiredStartDate: &date.Date{Year: 2025, Month: 3, Day: 1},
+		}},
+	)
+
+	testCases := []struct {
+		name   string
+		filter ExampleRecordFilter
+		want   int
+	}{
+		{"no filter", ExampleRecordFilter{}, 2},
+		{"all tags", ExampleRecordFilter{Tags: []string{"a", "b"}}, 1},
+		{"priority", ExampleRecordFilter{Priority: 2}, 1},
+		{"requested by", ExampleRecordFilter{RequestedBy: "bot"}, 1},
+		{"date from", ExampleRecordFilter{DesiredStartFrom: &date.Date{Year: 2025, Month: 2, Day: 1}}, 1},
+		{"date range", ExampleRecordFilter{
+			DesiredStartFrom: &date.Date{Year: 2025, Month: 1, Day: 1},
+			DesiredStartTo:   &date.Date{Year: 2025, Month: 1, Day: 31},
+		}, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			records, _, err := store.ListExampleRecords(context.Background(), tc.filter)
+			if err != nil {
+				t.Fatalf("ListExampleRecords() error = %v", err)
+			}
+			if len(records) != tc.want {
+				t.Errorf("got %d records, want %d", len(records), tc.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreOutbox(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now().UTC()
+
+	if err := store.StoreOutboxMessage(ctx, &OutboxMessage{Handler: "handler", UUID: "id-1", Topic: "topic", Payload: "payload"}); err != nil {
+		t.Fatalf("StoreOutboxMessage() error = %v", err)
+	}
+	_ = store.StoreOutboxMessage(ctx, &OutboxMessage{Handler: "handler", UUID: "id-2", NextAttemptAt: now.Add(time.Hour)})
+
+	handlers, _ := store.OutboxHandlers(ctx)
+	if len(handlers) != 1 || handlers[0] != "handler" {
+		t.Fatalf("OutboxHandlers() = %v, want [handler]", handlers)
+	}
+
+	claimed, err := store.ClaimOutboxMessages(ctx, "handler", now.Add(time.Second), time.Minute, 10)
+	if err != nil || len(claimed) != 1 || claimed[0].UUID != "id-1" {
+		t.Fatalf("ClaimOutboxMessages() = %v, %v; want only id-1", claimed, err)
+	}
+	if again, _ := store.ClaimOutboxMessages(ctx, "handler", now.Add(time.Second), time.Minute, 10); len(again) != 0 {
+		t.Error("leased rows must not be claimed twice")
+	}
+
+	_ = store.MarkOutboxMessageSent(ctx, "handler", "id-1", now)
+	_ = store.MarkOutboxMessageFailed(ctx, "handler", "id-2", "boom", now, true)
+
+	// Sent rows are dropped; only the failed one is kept.
+	rows := store.OutboxMessages("handler")
+	if len(rows) != 1 || rows[0].UUID != "id-2" {
+		t.Fatalf("OutboxMessages() = %+v, want only id-2", rows)
+	}
+	if rows[0].Status != OutboxStatusFailed || rows[0].LastError != "boom" {
+		t.Errorf("unexpected failed row: %+v", rows[0])
+	}
+}
+
+func TestMemoryStoreSweepsExpiredEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now().UTC()
+
+	_, _ = store.ClaimInboxMessage(ctx, "handler", "done", now, time.Minute)
+	_ = store.CompleteInboxMessage(ctx, "handler", "done", now.Add(time.Hour))
+	_, _ = store.ClaimInboxMessage(ctx, "handler", "abandoned", now, time.Minute)
+	_, _, _ = store.ClaimIdempotencyKey(ctx, "abandoned", "fp", now, time.Minute)
+
+	// The next claim after the sweep interval drops the entries whose lease ran out.
+	later := now.Add(2 * sweepInterval)
+	_, _, _ = store.ClaimIdempotencyKey(ctx, "key-1", "fp", later, time.Minute)
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	if _, ok := store.inbox[inboxEntryID("handler", "abandoned")]; ok || len(store.inbox) != 1 {
+		t.Errorf("inbox = %v, want only the processed entry", store.inbox)
+	}
+	if _, ok := store.idempotency["abandoned"]; ok || len(store.idempotency) != 1 {
+		t.Errorf("idempotency keys = %v, want only key-1", store.idempotency)
+	}
+}
+
+func TestMemoryStorePingAndClose(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+	if err := store.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+	if err := store.Close(context.Background()); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	var nilStore *MemoryStore
+	if err := nilStore.Ping(context.Background()); err == nil {
+		t.Error("Ping() on nil store should fail")
+	}
+}
+
+func TestMemoryStoreExampleRecordWithOutbox(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.StoreExampleRecordWithOutbox(ctx, &domain.ExampleRecord{RecordId: "EX-1"}, nil); err == nil {
+		t.Fatal("expected error without outbox message")
+	}
+	if _, err := store.GetExampleRecordByID(ctx, "EX-1"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("record must not be stored without its outbox row, got %v", err)
+	}
+
+	msg := &OutboxMessage{Handler: "handler", UUID: "id-1", Topic: "topic"}
+	if err := store.StoreExampleRecordWithOutbox(ctx, &domain.ExampleRecord{RecordId: "EX-1"}, msg); err != nil {
+		t.Fatalf("StoreExampleRecordWithOutbox() error = %v", err)
+	}
+	if _, err := store.GetExampleRecordByID(ctx, "EX-1"); err != nil {
+		t.Errorf("GetExampleRecordByID() error = %v", err)
+	}
+	if rows := store.OutboxMessages("handler"); len(rows) != 1 || rows[0].Status != OutboxStatusPending {
+		t.Errorf("unexpected outbox rows: %+v", rows)
+	}
+}
+
+func TestMemoryStoreConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	testCases := []struct {
+		policy    ConflictPolicy
+		wantErr   error
+		wantTitle string
+	}{
+		{"", domain.ErrorConflict, "first"},
+		{ConflictReject, domain.ErrorConflict, "first"},
+		{ConflictOverwrite, nil, ""},
+		{ConflictMerge, nil, "first"},
+	}
+
+	for _, tc := range testCases {
+		store := seedMemoryStore(t, &domain.ExampleRecord{RecordId: "EX-1", Title: "first"})
+		store.conflictPolicy = tc.policy
+
+		err := store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: "EX-1", Description: "second"})
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%q: StoreExampleRecord() error = %v, want %v", tc.policy, err, tc.wantErr)
+		}
+		got, _ := store.GetExampleRecordByID(ctx, "EX-1")
+		if got.GetTitle() != tc.wantTitle {
+			t.Errorf("%q: Title = %q, want %q", tc.policy, got.GetTitle(), tc.wantTitle)
+		}
+	}
+
+	store := seedMemoryStore(t, &domain.ExampleRecord{RecordId: "EX-1"})
+	msg := &OutboxMessage{Handler: "handler", UUID: "id-1"}
+	if err := store.StoreExampleRecordWithOutbox(ctx, &domain.ExampleRecord{RecordId: "EX-1"}, msg); !errors.Is(err, domain.ErrorConflict) {
+		t.Errorf("StoreExampleRecordWithOutbox() error = %v, want ErrorConflict", err)
+	}
+	if rows := store.OutboxMessages("handler"); len(rows) != 0 {
+		t.Error("a rejected record must not leave an outbox row behind")
+	}
+}
+
+// assertReplaceExampleRecordVersion replaces a record conditionally on its version.
+func assertReplaceExampleRecordVersion(t *testing.T, store ExampleRecordStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	if err := store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: "EX-V", Title: "first"}); err != nil {
+		t.Fatalf("StoreExampleRecord() error = %v", err)
+	}
+	if err := store.ReplaceExampleRecordVersion(ctx, "EX-V", 0, &domain.ExampleRecord{RecordId: "EX-V", Title: "second", Version: 1}); err != nil {
+		t.Fatalf("ReplaceExampleRecordVersion() error = %v", err)
+	}
+	stale := &domain.ExampleRecord{RecordId: "EX-V", Title: "stale", Version: 1}
+	if err := store.ReplaceExampleRecordVersion(ctx, "EX-V", 0, stale); !errors.Is(err, ErrExampleRecordVersionConflict) {
+		t.Errorf("replace with a stale version error = %v, want ErrExampleRecordVersionConflict", err)
+	}
+	if got, _ := store.GetExampleRecordByID(ctx, "EX-V"); got.GetTitle() != "second" || got.GetVersion() != 1 {
+		t.Errorf("stored = %v, want the second record at version 1", got)
+	}
+	if err := store.ReplaceExampleRecordVersion(ctx, "missing", 0, stale); !errors.Is(err, ErrExampleRecordNotFound) {
+		t.Errorf("replace of a missing record error = %v, want ErrExampleRecordNotFound", err)
+	}
+}
+
+func TestMemoryStoreReplaceExampleRecordVersion(t *testing.T) {
+	t.Parallel()
+	assertReplaceExampleRecordVersion(t, NewMemoryStore())
+}
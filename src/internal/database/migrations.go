@@ -0,0 +1,294 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	migrationsCollection    = "schema_migrations"
+	migrationLockCollection = "schema_migrations_lock"
+	migrationLockID         = "migrate"
+
+	defaultMigrationLockTTL   = 5 * time.Minute
+	defaultMigrationLockWait  = 2 * time.Minute
+	defaultMigrationLockRetry = time.Second
+)
+
+// ErrMigrationLocked is returned when another process holds the migration lock for
+// longer than the migrator is willing to wait.
+var ErrMigrationLocked = errors.New("schema migrations are locked by another process")
+
+// MigrationDirection selects whether migrations are applied or rolled back.
+type MigrationDirection string
+
+// Supported migration directions.
+const (
+	MigrateUp   MigrationDirection = "up"
+	MigrateDown MigrationDirection = "down"
+)
+
+// Migration is one versioned change of the MongoDB schema, such as an index or a
+// document reshaping. Down is optional; migrations without it cannot be rolled back.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+	Down        func(ctx context.Context, db *mongo.Database) error
+}
+
+// MigrationStep is a migration the migrator ran or, in a dry run, would run.
+type MigrationStep struct {
+	Version     int
+	Description string
+	Direction   MigrationDirection
+}
+
+// MigrationStatus reports whether a registered migration has been applied.
+type MigrationStatus struct {
+	Version     int
+	Description string
+	AppliedAt   *time.Time
+}
+
+// appliedMigration is the schema_migrations document of an applied migration.
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// Migrator applies and rolls back migrations, recording them in schema_migrations.
+// A lease-based lock document keeps concurrently starting replicas from racing.
+type Migrator struct {
+	db         *mongo.Database
+	migrations []Migration
+	log        *slog.Logger
+	owner      string
+	lockTTL    time.Duration
+	lockWait   time.Duration
+	lockRetry  time.Duration
+}
+
+// NewMigrator validates the migrations and returns a migrator for db. Migrations must
+// be registered in strictly ascending version order and each needs an Up function.
+func NewMigrator(db *mongo.Database, migrations []Migration, logger *slog.Logger) (*Migrator, error) {
+	if err := validateMigrations(migrations); err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	host, _ := os.Hostname()
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		log:        logger,
+		owner:      fmt.Sprintf("%s/%d/%d", host, os.Getpid(), time.Now().UnixNano()),
+		lockTTL:    defaultMigrationLockTTL,
+		lockWait:   defaultMigrationLockWait,
+		lockRetry:  defaultMigrationLockRetry,
+	}, nil
+}
+
+// Status lists every registered migration together with its applied time.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Description: migration.Description}
+		if record, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &record.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Plan returns the steps Migrate would run without changing anything. For MigrateUp,
+// target is the highest version to apply; for MigrateDown, migrations above target
+// are rolled back. A target of 0 with MigrateUp means all migrations.
+func (m *Migrator) Plan(ctx context.Context, direction MigrationDirection, target int) ([]MigrationStep, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return planMigrations(m.migrations, applied, direction, target)
+}
+
+// Migrate takes the lock, runs the planned steps in order and returns those that
+// completed. It stops at the first failing migration.
+func (m *Migrator) Migrate(ctx context.Context, direction MigrationDirection, target int) ([]MigrationStep, error) {
+	if err := m.acquireLock(ctx); err != nil {
+		return nil, err
+	}
+	defer m.releaseLock(ctx)
+
+	// Plan under the lock, so steps applied by another replica meanwhile are skipped.
+	steps, err := m.Plan(ctx, direction, target)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]MigrationStep, 0, len(steps))
+	for _, step := range steps {
+		if err := m.run(ctx, step); err != nil {
+			return done, fmt.Errorf("migration %d (%s) %s: %w", step.Version, step.Description, step.Direction, err)
+		}
+		m.log.Info("Applied schema migration", "version", step.Version, "description", step.Description, "direction", step.Direction)
+		done = append(done, step)
+	}
+	return done, nil
+}
+
+// run executes one step and records the outcome in schema_migrations.
+func (m *Migrator) run(ctx context.Context, step MigrationStep) error {
+	migration := m.migration(step.Version)
+	collection := m.db.Collection(migrationsCollection)
+
+	if step.Direction == MigrateDown {
+		if err := migration.Down(ctx, m.db); err != nil {
+			return err
+		}
+		_, err := collection.DeleteOne(ctx, bson.M{"_id": step.Version})
+		return err
+	}
+
+	if err := migration.Up(ctx, m.db); err != nil {
+		return err
+	}
+	_, err := collection.InsertOne(ctx, appliedMigration{
+		Version:     migration.Version,
+		Description: migration.Description,
+		AppliedAt:   time.Now().UTC(),
+	})
+	return err
+}
+
+func (m *Migrator) migration(version int) Migration {
+	index := slices.IndexFunc(m.migrations, func(migration Migration) bool { return migration.Version == version })
+	return m.migrations[index]
+}
+
+// applied loads the schema_migrations documents keyed by version.
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	cursor, err := m.db.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// acquireLock claims the lock document once it is free or its lease expired. While
+// another owner holds it, the upsert collides on _id and is retried until lockWait.
+func (m *Migrator) acquireLock(ctx context.Context) error {
+	deadline := time.Now().Add(m.lockWait)
+	for {
+		now := time.Now().UTC()
+		_, err := m.db.Collection(migrationLockCollection).UpdateOne(ctx,
+			bson.M{"_id": migrationLockID, "expires_at": bson.M{"$lt": now}},
+			bson.M{"$set": bson.M{"owner": m.owner, "expires_at": now.Add(m.lockTTL)}},
+			options.Update().SetUpsert(true),
+		)
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return ErrMigrationLocked
+		}
+
+		m.log.Info("Waiting for schema migration lock")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.lockRetry):
+		}
+	}
+}
+
+func (m *Migrator) releaseLock(ctx context.Context) {
+	_, err := m.db.Collection(migrationLockCollection).DeleteOne(ctx, bson.M{"_id": migrationLockID, "owner": m.owner})
+	if err != nil {
+		m.log.Warn("Failed to release schema migration lock", "error", err)
+	}
+}
+
+// validateMigrations checks ordering, uniqueness and completeness of the registry.
+func validateMigrations(migrations []Migration) error {
+	last := 0
+	for _, migration := range migrations {
+		if migration.Version <= last {
+			return fmt.Errorf("migration %d must have a version greater than %d", migration.Version, last)
+		}
+		if migration.Up == nil {
+			return fmt.Errorf("migration %d has no Up function", migration.Version)
+		}
+		last = migration.Version
+	}
+	return nil
+}
+
+// planMigrations computes the ordered steps that move the applied set to target.
+func planMigrations(
+	migrations []Migration,
+	applied map[int]appliedMigration,
+	direction MigrationDirection,
+	target int,
+) ([]MigrationStep, error) {
+	var steps []MigrationStep
+
+	switch direction {
+	case MigrateUp:
+		for _, migration := range migrations {
+			if target > 0 && migration.Version > target {
+				break
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				steps = append(steps, MigrationStep{Version: migration.Version, Description: migration.Description, Direction: MigrateUp})
+			}
+		}
+	case MigrateDown:
+		for _, migration := range slices.Backward(migrations) {
+			if migration.Version <= target {
+				break
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == nil {
+				return nil, fmt.Errorf("migration %d (%s) cannot be rolled back", migration.Version, migration.Description)
+			}
+			steps = append(steps, MigrationStep{Version: migration.Version, Description: migration.Description, Direction: MigrateDown})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported migration direction %q", direction)
+	}
+	return steps, nil
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func noopMigration(context.Context, *mongo.Database) error { return nil }
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Description: "one", Up: noopMigration, Down: noopMigration},
+		{Version: 2, Description: "two", Up: noopMigration},
+		{Version: 5, Description: "five", Up: noopMigration, Down: noopMigration},
+	}
+}
+
+func TestValidateMigrations(t *testing.T) {
+	t.Parallel()
+
+	if err := validateMigrations(MongoMigrations); err != nil {
+		t.Errorf("registered MongoMigrations are invalid: %v", err)
+	}
+
+	testCases := map[string][]Migration{
+		"zero version":  {{Version: 0, Up: noopMigration}},
+		"duplicate":     {{Version: 1, Up: noopMigration}, {Version: 1, Up: noopMigration}},
+		"out of order":  {{Version: 2, Up: noopMigration}, {Version: 1, Up: noopMigration}},
+		"missing up fn": {{Version: 1}},
+	}
+	for name, migrations := range testCases {
+		if err := validateMigrations(migrations); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+		if _, err := NewMigrator(nil, migrations, nil); err == nil {
+			t.Errorf("%s: NewMigrator should reject invalid migrations", name)
+		}
+	}
+}
+
+func TestPlanMigrations(t *testing.T) {
+	t.Parallel()
+
+	applied := map[int]appliedMigration{1: {Version: 1}, 2: {Version: 2}}
+
+	testCases := []struct {
+		name      string
+		applied   map[int]appliedMigration
+		direction MigrationDirection
+		target    int
+		want      string
+		wantErr   bool
+	}{
+		{name: "up all from scratch", direction: MigrateUp, want: "[up1 up2 up5]"},
+		{name: "up to target", direction: MigrateUp, target: 2, want: "[up1 up2]"},
+		{name: "up skips applied", applied: applied, direction: MigrateUp, want: "[up5]"},
+		{name: "down skips pending", applied: map[int]appliedMigration{1: {Version: 1}}, direction: MigrateDown, want: "[down1]"},
+		{name: "down stops at target", applied: map[int]appliedMigration{1: {}, 5: {}}, direction: MigrateDown, target: 1, want: "[down5]"},
+		{name: "down irreversible", applied: applied, direction: MigrateDown, wantErr: true},
+		{name: "unknown direction", direction: "sideways", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			steps, err := planMigrations(testMigrations(), tc.applied, tc.direction, tc.target)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got steps %v", steps)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("planMigrations() error = %v", err)
+			}
+
+			got := make([]string, 0, len(steps))
+			for _, step := range steps {
+				got = append(got, fmt.Sprintf("%s%d", step.Direction, step.Version))
+			}
+			if fmt.Sprint(got) != tc.want {
+				t.Errorf("steps = %v, want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	tagsIndexName = "tags"
+
+	// duplicateExampleCollection keeps the records migration 1 moved aside.
+	duplicateExampleCollection = "example-records-duplicates"
+)
+
+// MongoMigrations is the ordered registry of MongoDB schema migrations. Append new
+// migrations with the next version; never renumber or edit released ones.
+var MongoMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "unique index on example record ID",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			if err := archiveDuplicateExampleRecords(ctx, db); err != nil {
+				return err
+			}
+			return createIndex(ctx, db, exampleCollection, mongo.IndexModel{
+				Keys:    bson.D{{Key: recordIDField, Value: 1}},
+				Options: options.Index().SetName(recordIDIndexName).SetUnique(true),
+			})
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndex(ctx, db, exampleCollection, recordIDIndexName)
+		},
+	},
+	{
+		Version:     2,
+		Description: "index on example record tags",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndex(ctx, db, exampleCollection, mongo.IndexModel{
+				Keys:    bson.D{{Key: tagsField, Value: 1}},
+				Options: options.Index().SetName(tagsIndexName),
+			})
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndex(ctx, db, exampleCollection, tagsIndexName)
+		},
+	},
+	{
+		Version:     3,
+		Description: "expire processed inbox messages",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndex(ctx, db, inboxCollection, mongo.IndexModel{
+				Keys:    bson.D{{Key: inboxExpiryIndexKey, Value: 1}},
+				Options: options.Index().SetName(inboxExpiryIndex).SetExpireAfterSeconds(0),
+			})
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndex(ctx, db, inboxCollection, inboxExpiryIndex)
+		},
+	},
+	{
+		Version:     4,
+		Description: "index on example results by record ID",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndex(ctx, db, resultCollection, mongo.IndexModel{
+				Keys:    bson.D{{Key: "record_id", Value: 1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName(resultRecordIndexName),
+			})
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndex(ctx, db, resultCollection, resultRecordIndexName)
+		},
+	},
+	{
+		Version:     5,
+		Description: "unique index on API key hashes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndex(ctx, db, apiKeyCollection, mongo.IndexModel{
+				Keys:    bson.D{{Key: "hash", Value: 1}},
+				Options: options.Index().SetName(apiKeyHashIndexName).SetUnique(true),
+			})
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndex(ctx, db, apiKeyCollection, apiKeyHashIndexName)
+		},
+	},
+	{
+		Version:     6,
+		Description: "expire idempotency keys",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndex(ctx, db, idempotencyCollection, mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetName(idempotencyExpiryIndex).SetExpireAfterSeconds(0),
+			})
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndex(ctx, db, idempotencyCollection, idempotencyExpiryIndex)
+		},
+	},
+	{
+		Version:     7,
+		Description: "expire rate limit buckets",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndex(ctx, db, rateLimitCollection, mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetName(rateLimitExpiryIndex).SetExpireAfterSeconds(0),
+			})
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndex(ctx, db, rateLimitCollection, rateLimitExpiryIndex)
+		},
+	},
+	{
+		Version:     8,
+		Description: "index on example results by record ID and storage time",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			if err := createIndex(ctx, db, resultCollection, mongo.IndexModel{
+				Keys:    bson.D{{Key: "record_id", Value: 1}, {Key: "stored_at", Value: -1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName(resultStoredIndexName),
+			}); err != nil {
+				return err
+			}
+			return dropIndex(ctx, db, resultCollection, resultRecordIndexName)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			if err := createIndex(ctx, db, resultCollection, mongo.IndexModel{
+				Keys:    bson.D{{Key: "record_id", Value: 1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName(resultRecordIndexName),
+			}); err != nil {
+				return err
+			}
+			return dropIndex(ctx, db, resultCollection, resultStoredIndexName)
+		},
+	},
+	{
+		Version:     9,
+		Description: "backfill the relay state of legacy outbox rows",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return backfillLegacyOutboxRows(ctx, db, time.Now().UTC())
+		},
+	},
+	{
+		Version:     10,
+		Description: "delete sent outbox rows",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return deleteSentOutboxRows(ctx, db)
+		},
+	},
+}
+
+// archiveDuplicateExampleRecords moves every record whose ID was stored before by an
+// earlier record to the duplicates collection, so the unique index can be built.
+// The earliest record is kept, as the default reject policy would have done. Each
+// record is copied before it is deleted, so a failed run can be repeated.
+func archiveDuplicateExampleRecords(ctx context.Context, db *mongo.Database) error {
+	records := db.Collection(exampleCollection)
+	archive := db.Collection(duplicateExampleCollection)
+
+	cursor, err := records.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + recordIDField},
+			{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "ids.1", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	for cursor.Next(ctx) {
+		var group struct {
+			IDs []any `bson:"ids"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return err
+		}
+		for _, id := range group.IDs[1:] {
+			var record bson.Raw
+			if err := records.FindOne(ctx, bson.M{"_id": id}).Decode(&record); err != nil {
+				return err
+			}
+			if _, err := archive.ReplaceOne(ctx, bson.M{"_id": id}, record, options.Replace().SetUpsert(true)); err != nil {
+				return err
+			}
+			if _, err := records.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+				return err
+			}
+		}
+	}
+	return cursor.Err()
+}
+
+func createIndex(ctx context.Context, db *mongo.Database, collection string, model mongo.IndexModel) error {
+	_, err := db.Collection(collection).Indexes().CreateOne(ctx, model)
+	return err
+}
+
+func dropIndex(ctx context.Context, db *mongo.Database, collection string, name string) error {
+	_, err := db.Collection(collection).Indexes().DropOne(ctx, name)
+	return err
+}
@@ -0,0 +1,197 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const outboxCollectionSuffix = "_outbox"
+
+// Outbox row states. Sent rows are deleted on dispatch, so OutboxStatusSent only
+// marks the rows that older releases kept.
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// OutboxMessage is a persisted outgoing event awaiting dispatch by the outbox relay.
+type OutboxMessage struct {
+	Handler       string            `bson:"handler"`
+	UUID          string            `bson:"uuid"`
+	Payload       string            `bson:"payload"`
+	Topic         string            `bson:"topic,omitempty"`
+	Metadata      map[string]string `bson:"metadata,omitempty"`
+	Status        string            `bson:"status"`
+	Attempts      int               `bson:"attempts"`
+	LastError     string            `bson:"last_error,omitempty"`
+	CreatedAt     time.Time         `bson:"created_at"`
+	NextAttemptAt time.Time         `bson:"next_attempt_at"`
+	SentAt        *time.Time        `bson:"sent_at,omitempty"`
+}
+
+// StoreOutboxMessage inserts a pending row into the <handler>_outbox collection.
+func (db *Database) StoreOutboxMessage(ctx context.Context, msg *OutboxMessage) error {
+	if err := prepareOutboxMessage(msg); err != nil {
+		return err
+	}
+
+	_, err := db.DB.Collection(msg.Handler+outboxCollectionSuffix).InsertOne(ctx, msg)
+	return err
+}
+
+// prepareOutboxMessage validates a new row and fills in its initial state.
+func prepareOutboxMessage(msg *OutboxMessage) error {
+	if msg == nil {
+		return errors.New("outbox message is required")
+	}
+	if msg.Handler == "" {
+		return errors.New("outbox handler is required")
+	}
+
+	now := time.Now().UTC()
+	if msg.Status == "" {
+		msg.Status = OutboxStatusPending
+	}
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = now
+	}
+	if msg.NextAttemptAt.IsZero() {
+		msg.NextAttemptAt = now
+	}
+	return nil
+}
+
+// outboxCollections lists the names of the outbox collections.
+func outboxCollections(ctx context.Context, db *mongo.Database) ([]string, error) {
+	return db.ListCollectionNames(ctx, bson.M{
+		"name": bson.M{"$regex": outboxCollectionSuffix + "$"},
+	})
+}
+
+// OutboxHandlers lists the handlers that currently own an outbox collection.
+func (db *Database) OutboxHandlers(ctx context.Context) ([]string, error) {
+	names, err := outboxCollections(ctx, db.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	handlers := make([]string, 0, len(names))
+	for _, name := range names {
+		handlers = append(handlers, strings.TrimSuffix(name, outboxCollectionSuffix))
+	}
+	return handlers, nil
+}
+
+// backfillLegacyOutboxRows makes the rows written by StoreOutgoingMessage before the
+// relay existed claimable. They only carry handler, uuid and payload, so they become
+// pending and due at now. Their topic is resolved by the relay.
+func backfillLegacyOutboxRows(ctx context.Context, db *mongo.Database, now time.Time) error {
+	names, err := outboxCollections(ctx, db)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		_, err := db.Collection(name).UpdateMany(ctx,
+			bson.M{"status": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{
+				"status":          OutboxStatusPending,
+				"attempts":        0,
+				"created_at":      now,
+				"next_attempt_at": now,
+			}},
+		)
+		if err != nil {
+			return fmt.Errorf("backfill %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// deleteSentOutboxRows removes the rows that were kept with status sent before sent
+// rows were deleted on dispatch.
+func deleteSentOutboxRows(ctx context.Context, db *mongo.Database) error {
+	names, err := outboxCollections(ctx, db)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, err := db.Collection(name).DeleteMany(ctx, bson.M{"status": OutboxStatusSent}); err != nil {
+			return fmt.Errorf("delete sent rows of %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ClaimOutboxMessages leases up to limit due rows of a handler so concurrent relays
+// do not dispatch the same message. A leased row becomes due again once lease expires.
+func (db *Database) ClaimOutboxMessages(
+	ctx context.Context,
+	handler string,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]OutboxMessage, error) {
+	collection := db.DB.Collection(handler + outboxCollectionSuffix)
+	filter := bson.M{
+		"status":          OutboxStatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	claimed := make([]OutboxMessage, 0, limit)
+	for len(claimed) < limit {
+		var row OutboxMessage
+		err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&row)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, row)
+	}
+	return claimed, nil
+}
+
+// MarkOutboxMessageSent deletes the sent row instead of keeping it, so the outbox
+// collections do not grow without bound.
+func (db *Database) MarkOutboxMessageSent(ctx context.Context, handler string, uuid string, _ time.Time) error {
+	_, err := db.DB.Collection(handler+outboxCollectionSuffix).DeleteOne(ctx, bson.M{"uuid": uuid})
+	return err
+}
+
+// MarkOutboxMessageFailed records a failed dispatch. The row is retried at nextAttemptAt
+// unless final is set, in which case it is parked with status failed.
+func (db *Database) MarkOutboxMessageFailed(
+	ctx context.Context,
+	handler string,
+	uuid string,
+	reason string,
+	nextAttemptAt time.Time,
+	final bool,
+) error {
+	status := OutboxStatusPending
+	if final {
+		status = OutboxStatusFailed
+	}
+
+	_, err := db.DB.Collection(handler+outboxCollectionSuffix).UpdateOne(ctx,
+		bson.M{"uuid": uuid},
+		bson.M{
+			"$set": bson.M{"status": status, "last_error": reason, "next_attempt_at": nextAttemptAt},
+			"$inc": bson.M{"attempts": 1},
+		},
+	)
+	return err
+}
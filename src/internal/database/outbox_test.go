@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStoreOutboxMessageRequiresMessage(t *testing.T) {
+	t.Parallel()
+
+	db := &Database{DB: nil, Cfg: &Config{}}
+
+	err := db.StoreOutboxMessage(context.Background(), nil)
+	if err == nil || err.Error() != "outbox message is required" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestStoreOutboxMessageRequiresHandler(t *testing.T) {
+	t.Parallel()
+
+	db := &Database{DB: nil, Cfg: &Config{}}
+
+	err := db.StoreOutboxMessage(context.Background(), &OutboxMessage{UUID: "uuid"})
+	if err == nil || err.Error() != "outbox handler is required" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestStoreOutboxMessageFillsDefaults(t *testing.T) {
+	t.Parallel()
+
+	db := &Database{DB: nil, Cfg: &Config{}}
+	msg := &OutboxMessage{Handler: "handler", UUID: "uuid"}
+
+	// The insert panics on a nil DB, but defaults are applied before that.
+	func() {
+		defer func() {
+			_ = recover()
+		}()
+		_ = db.StoreOutboxMessage(context.Background(), msg)
+	}()
+
+	if msg.Status != OutboxStatusPending {
+		t.Errorf("Status = %q, want %q", msg.Status, OutboxStatusPending)
+	}
+	if msg.CreatedAt.IsZero() || msg.NextAttemptAt.IsZero() {
+		t.Error("Expected CreatedAt and NextAttemptAt to be set")
+	}
+}
+
+func TestOutboxOperationsNilDB(t *testing.T) {
+	t.Parallel()
+
+	db := &Database{DB: nil, Cfg: &Config{}}
+	ctx := context.Background()
+	now := time.Now()
+
+	operations := map[string]func(){
+		"OutboxHandlers":           func() { _, _ = db.OutboxHandlers(ctx) },
+		"ClaimOutboxMessages":      func() { _, _ = db.ClaimOutboxMessages(ctx, "h", now, time.Second, 10) },
+		"MarkOutboxMessageSent":    func() { _ = db.MarkOutboxMessageSent(ctx, "h", "u", now) },
+		"MarkOutboxMessageFailed":  func() { _ = db.MarkOutboxMessageFailed(ctx, "h", "u", "err", now, false) },
+		"backfillLegacyOutboxRows": func() { _ = backfillLegacyOutboxRows(ctx, db.DB, now) },
+	}
+
+	for name, op := range operations {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Logf("%s did not panic with nil DB", name)
+				}
+			}()
+			op()
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+// postgresTestURLEnv names the PostgreSQL server the Postgres tests run against.
+// They are skipped when it is unset.
+const postgresTestURLEnv = "TEST_POSTGRES_URL"
+
+// newTestPostgresStore migrates a fresh schema of the test server and drops it
+// when the test ends, so tests neither see nor leave any rows.
+func newTestPostgresStore(t *testing.T) *SQLStore {
+	t.Helper()
+	raw := os.Getenv(postgresTestURLEnv)
+	if raw == "" {
+		t.Skipf("%s is not set", postgresTestURLEnv)
+	}
+
+	suffix := make([]byte, 8)
+	_, _ = rand.Read(suffix)
+	schema := "test_" + hex.EncodeToString(suffix)
+
+	admin, err := sql.Open("postgres", raw)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = admin.Close() })
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { _, _ = admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	dsn, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("%s must be a URL: %v", postgresTestURLEnv, err)
+	}
+	query := dsn.Query()
+	query.Set("search_path", schema)
+	dsn.RawQuery = query.Encode()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store, err := NewPostgresStore(context.Background(), &Config{Driver: DriverPostgres, PostgresURL: dsn.String()}, logger)
+	if err != nil {
+		t.Fatalf("NewPostgresStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close(context.Background()) })
+	return store
+}
+
+func TestPostgresStore(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv(postgresTestURLEnv) == "" {
+		t.Skipf("%s is not set", postgresTestURLEnv)
+	}
+	t.Run("example record lifecycle", func(t *testing.T) {
+		t.Parallel()
+		assertSQLExampleRecordLifecycle(t, newTestPostgresStore(t))
+	})
+	t.Run("conditional replace", func(t *testing.T) {
+		t.Parallel()
+		assertReplaceExampleRecordVersion(t, newTestPostgresStore(t))
+	})
+	t.Run("list example records", func(t *testing.T) {
+		t.Parallel()
+		assertSQLListExampleRecords(t, newTestPostgresStore(t))
+	})
+	t.Run("conflict policy", func(t *testing.T) {
+		t.Parallel()
+		assertSQLConflictPolicy(t, func() *SQLStore { return newTestPostgresStore(t) })
+	})
+	t.Run("concurrent stores", func(t *testing.T) {
+		t.Parallel()
+		assertConcurrentStores(t, newTestPostgresStore(t))
+	})
+	t.Run("outbox", func(t *testing.T) {
+		t.Parallel()
+		assertSQLOutbox(t, newTestPostgresStore(t))
+	})
+	t.Run("inbox", func(t *testing.T) {
+		t.Parallel()
+		assertInboxLifecycle(t, newTestPostgresStore(t))
+	})
+	t.Run("results", func(t *testing.T) {
+		t.Parallel()
+		assertResultStore(t, newTestPostgresStore(t))
+	})
+	t.Run("batches", func(t *testing.T) {
+		t.Parallel()
+		assertBatchLifecycle(t, newTestPostgresStore(t))
+	})
+	t.Run("api keys", func(t *testing.T) {
+		t.Parallel()
+		assertAPIKeyStore(t, newTestPostgresStore(t))
+	})
+	t.Run("idempotency keys", func(t *testing.T) {
+		t.Parallel()
+		assertIdempotencyLifecycle(t, newTestPostgresStore(t))
+	})
+}
+
+// assertConcurrentStores stores the same new record ID from several goroutines under
+// the overwrite and merge policies. The losers of the insert race must resolve their
+// conflict instead of failing, and merging must not drop any writer's tags.
+func assertConcurrentStores(t *testing.T, store *SQLStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	for _, policy := range []ConflictPolicy{ConflictOverwrite, ConflictMerge} {
+		store.conflictPolicy = policy
+		id := "EX-" + string(policy)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 8)
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: id, Tags: []string{fmt.Sprintf("t%d", i)}})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Errorf("%q: StoreExampleRecord() error = %v", policy, err)
+			}
+		}
+
+		got, err := store.GetExampleRecordByID(ctx, id)
+		if err != nil {
+			t.Fatalf("%q: GetExampleRecordByID() error = %v", policy, err)
+		}
+		if policy == ConflictMerge && len(got.GetTags()) != 8 {
+			t.Errorf("%q: tags = %v, want the tags of all 8 writers", policy, got.GetTags())
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	rateLimitCollection  = "rate_limits"
+	rateLimitExpiryIndex = "rate_limit_expiry"
+)
+
+// TakeRateLimitToken refills the token bucket key, which holds at most capacity
+// tokens and refills completely within period, and takes a token if one is left.
+// It returns the tokens left and whether a token was taken. The bucket is updated
+// in one atomic pipeline update, so replicas sharing the database share the bucket.
+// Buckets expire once they would be full again; a missing bucket is full.
+func (db *Database) TakeRateLimitToken(
+	ctx context.Context,
+	key string,
+	capacity int,
+	period time.Duration,
+	now time.Time,
+) (float64, bool, error) {
+	now = now.UTC()
+	full := float64(capacity)
+	perMillisecond := full / (period.Seconds() * 1000)
+	elapsed := bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{now, bson.M{"$ifNull": bson.A{"$updated_at", now}}}}}}
+	hasToken := bson.M{"$gte": bson.A{"$tokens", 1}}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"tokens": bson.M{"$min": bson.A{full, bson.M{"$add": bson.A{
+				bson.M{"$ifNull": bson.A{"$tokens", full}},
+				bson.M{"$multiply": bson.A{elapsed, perMillisecond}},
+			}}}},
+		}}},
+		// Expressions of a stage see the document before it, so both use the refilled tokens.
+		{{Key: "$set", Value: bson.M{
+			"allowed":    hasToken,
+			"tokens":     bson.M{"$cond": bson.A{hasToken, bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+			"updated_at": now,
+			"expires_at": now.Add(period),
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var bucket struct {
+		Tokens  float64 `bson:"tokens"`
+		Allowed bool    `bson:"allowed"`
+	}
+	collection := db.DB.Collection(rateLimitCollection)
+	err := collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&bucket)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another replica created the bucket concurrently; it exists now.
+		err = collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&bucket)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return bucket.Tokens, bucket.Allowed, nil
+}
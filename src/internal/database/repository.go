@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+// Supported values for DB_DRIVER.
+const (
+	DriverMongo    = "mongo"
+	DriverMemory   = "memory"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// ExampleRecordStore persists example records.
+type ExampleRecordStore interface {
+	StoreExampleRecord(ctx context.Context, record *domain.ExampleRecord) error
+	// StoreExampleRecordWithOutbox atomically saves the record together with the
+	// outbox row announcing it.
+	StoreExampleRecordWithOutbox(ctx context.Context, record *domain.ExampleRecord, msg *OutboxMessage) error
+	GetExampleRecordByID(ctx context.Context, id string) (*domain.ExampleRecord, error)
+	ListExampleRecords(ctx context.Context, filter ExampleRecordFilter) ([]*domain.ExampleRecord, string, error)
+	// ReplaceExampleRecordVersion replaces the stored record only while it still has
+	// the given version and reports ErrExampleRecordVersionConflict otherwise.
+	ReplaceExampleRecordVersion(ctx context.Context, id string, version int32, record *domain.ExampleRecord) error
+	DeleteExampleRecord(ctx context.Context, id string) error
+}
+
+// OutboxStore persists outgoing events until the outbox relay dispatched them.
+type OutboxStore interface {
+	StoreOutboxMessage(ctx context.Context, msg *OutboxMessage) error
+	OutboxHandlers(ctx context.Context) ([]string, error)
+	ClaimOutboxMessages(ctx context.Context, handler string, now time.Time, lease time.Duration, limit int) ([]OutboxMessage, error)
+	MarkOutboxMessageSent(ctx context.Context, handler string, uuid string, sentAt time.Time) error
+	MarkOutboxMessageFailed(ctx context.Context, handler string, uuid string, reason string, nextAttemptAt time.Time, final bool) error
+}
+
+// Repository is the storage contract the application depends on. Every backend
+// selectable through DB_DRIVER implements it.
+type Repository interface {
+	ExampleRecordStore
+	OutboxStore
+	InboxStore
+	BatchStore
+	ResultStore
+	APIKeyStore
+	IdempotencyStore
+	Ping(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+var (
+	_ Repository = (*Database)(nil)
+	_ Repository = (*MemoryStore)(nil)
+	_ Repository = (*SQLStore)(nil)
+)
+
+// Open connects to the backend selected by cfg.Driver.
+func Open(ctx context.Context, cfg *Config, logger *slog.Logger) (Repository, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch cfg.DriverName() {
+	case DriverMemory:
+		logger.Info("Using in-memory database, data is lost on restart")
+		store := NewMemoryStore()
+		store.conflictPolicy = cfg.conflictPolicy()
+		return store, nil
+	case DriverPostgres:
+		return NewPostgresStore(ctx, cfg, logger)
+	case DriverSQLite:
+		return NewSQLiteStore(ctx, cfg, logger)
+	case DriverMongo:
+		return NewDatabase(cfg, logger, ctx)
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", cfg.Driver)
+	}
+}
+
+// DriverName returns the normalised driver name, defaulting to MongoDB.
+func (c *Config) DriverName() string {
+	driver := strings.ToLower(strings.TrimSpace(c.Driver))
+	if driver == "" {
+		return DriverMongo
+	}
+	return driver
+}
+
+// conflictPolicy returns the configured policy. Validate rejects unknown values, so
+// parse errors fall back to the default.
+func (c *Config) conflictPolicy() ConflictPolicy {
+	if c == nil {
+		return ConflictReject
+	}
+	policy, err := ParseConflictPolicy(c.ConflictPolicy)
+	if err != nil {
+		return ConflictReject
+	}
+	return policy
+}
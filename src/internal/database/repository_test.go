@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestOpenMemoryDriver(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	for _, driver := range []string{"memory", " MEMORY "} {
+		repo, err := Open(context.Background(), &Config{Driver: driver}, logger)
+		if err != nil {
+			t.Fatalf("Open(%q) error = %v", driver, err)
+		}
+		if _, ok := repo.(*MemoryStore); !ok {
+			t.Errorf("Open(%q) returned %T, want *MemoryStore", driver, repo)
+		}
+	}
+}
+
+func TestOpenRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	testCases := []struct {
+		name string
+		cfg  *Config
+	}{
+		{"nil config", nil},
+		{"unknown driver", &Config{Driver: "cassandra"}},
+		{"mongo without url", &Config{Driver: "mongo", MongoDB: "db"}},
+		{"postgres without url", &Config{Driver: "postgres"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Open(context.Background(), tc.cfg, logger); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestConfigDriver(t *testing.T) {
+	t.Parallel()
+
+	if got := (&Config{}).DriverName(); got != DriverMongo {
+		t.Errorf("DriverName() = %q, want %q", got, DriverMongo)
+	}
+	if err := (&Config{Driver: DriverMemory}).Validate(); err != nil {
+		t.Errorf("memory driver should not require Mongo settings: %v", err)
+	}
+}
+
+func TestDatabaseCloseNil(t *testing.T) {
+	t.Parallel()
+
+	var db *Database
+	if err := db.Close(context.Background()); err != nil {
+		t.Errorf("Close() on nil database error = %v", err)
+	}
+	if err := (&Database{}).Close(context.Background()); err != nil {
+		t.Errorf("Close() without handle error = %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	date "google.golang.org/genproto/googleapis/type/date"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+const (
+	resultCollection      = "example_results"
+	resultRecordIndexName = "record_results"
+	resultStoredIndexName = "record_results_by_stored_at"
+)
+
+// DefaultExampleResultLimit is used when a result query does not specify a limit.
+const DefaultExampleResultLimit = 50
+
+// StoredExampleResult is an ExampleResult consumed from the result queue together
+// with the correlation ID of the message that carried it. The consumer derives the
+// ID from the message, so a redelivered result maps onto the stored one.
+type StoredExampleResult struct {
+	ID            string     `bson:"_id"`
+	RecordID      string     `bson:"record_id"`
+	CorrelationID string     `bson:"correlation_id"`
+	Status        string     `bson:"status"`
+	Note          string     `bson:"note"`
+	ProcessedOn   *date.Date `bson:"processed_on,omitempty"`
+	StoredAt      time.Time  `bson:"stored_at"`
+}
+
+// ExampleResultFilter narrows down ListExampleResults. Empty fields match every result.
+type ExampleResultFilter struct {
+	RecordID      string
+	CorrelationID string
+	Limit         int
+}
+
+// ResultStore persists the results of processed example records.
+type ResultStore interface {
+	// StoreExampleResult saves a result. A result whose ID is already stored is ignored.
+	StoreExampleResult(ctx context.Context, result *StoredExampleResult) error
+	// ListExampleResults returns the matching results, newest first. Results stored
+	// at the same time are ordered by descending ID.
+	ListExampleResults(ctx context.Context, filter ExampleResultFilter) ([]*StoredExampleResult, error)
+}
+
+// prepareExampleResult validates a result and fills in its storage time.
+func prepareExampleResult(result *StoredExampleResult) error {
+	if result == nil || result.ID == "" || result.RecordID == "" {
+		return fmt.Errorf("result id and record id are required: %w", domain.ErrorBadRequest)
+	}
+	if result.StoredAt.IsZero() {
+		result.StoredAt = time.Now().UTC()
+	}
+	return nil
+}
+
+func (f ExampleResultFilter) limit() int {
+	if f.Limit <= 0 {
+		return DefaultExampleResultLimit
+	}
+	return f.Limit
+}
+
+// StoreExampleResult inserts the result document unless its ID exists.
+func (db *Database) StoreExampleResult(ctx context.Context, result *StoredExampleResult) error {
+	if err := prepareExampleResult(result); err != nil {
+		return err
+	}
+	_, err := db.DB.Collection(resultCollection).UpdateOne(ctx,
+		bson.M{"_id": result.ID},
+		bson.M{"$setOnInsert": result},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// ListExampleResults returns the matching results, newest first.
+func (db *Database) ListExampleResults(ctx context.Context, filter ExampleResultFilter) ([]*StoredExampleResult, error) {
+	query := bson.M{}
+	if filter.RecordID != "" {
+		query["record_id"] = filter.RecordID
+	}
+	if filter.CorrelationID != "" {
+		query["correlation_id"] = filter.CorrelationID
+	}
+
+	cursor, err := db.DB.Collection(resultCollection).Find(ctx, query,
+		options.Find().SetSort(bson.D{{Key: "stored_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(filter.limit())))
+	if err != nil {
+		return nil, err
+	}
+	results := []*StoredExampleResult{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	date "google.golang.org/genproto/googleapis/type/date"
+)
+
+// assertResultStore stores results of two records and queries them back on store.
+func assertResultStore(t *testing.T, store ResultStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	// The IDs are hashes, so they do not sort in the order the results were stored.
+	stored := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	results := []*StoredExampleResult{
+		{ID: "b2", RecordID: "EX-1", CorrelationID: "corr-1", Status: "completed", Note: "first", ProcessedOn: &date.Date{Year: 2026, Month: 3, Day: 14}, StoredAt: stored},
+		{ID: "c3", RecordID: "EX-2", CorrelationID: "corr-2", Status: "completed", Note: "other record", StoredAt: stored.Add(time.Minute)},
+		{ID: "a1", RecordID: "EX-1", CorrelationID: "corr-3", Status: "completed", Note: "second", StoredAt: stored.Add(2 * time.Minute)},
+		// Redelivered results keep the stored copy.
+		{ID: "b2", RecordID: "EX-1", CorrelationID: "corr-1", Status: "completed", Note: "duplicate", StoredAt: stored.Add(3 * time.Minute)},
+	}
+	for _, result := range results {
+		if err := store.StoreExampleResult(ctx, result); err != nil {
+			t.Fatalf("StoreExampleResult(%s) error = %v", result.ID, err)
+		}
+	}
+
+	got, err := store.ListExampleResults(ctx, ExampleResultFilter{RecordID: "EX-1"})
+	if err != nil {
+		t.Fatalf("ListExampleResults() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "a1" || got[1].ID != "b2" {
+		t.Fatalf("ListExampleResults(EX-1) = %+v, want a1 and b2", got)
+	}
+	first := got[1]
+	if first.Note != "first" || first.CorrelationID != "corr-1" || first.StoredAt.IsZero() {
+		t.Errorf("stored result = %+v", first)
+	}
+	if date := first.ProcessedOn; date.GetYear() != 2026 || date.GetMonth() != 3 || date.GetDay() != 14 {
+		t.Errorf("ProcessedOn = %v, want 2026-03-14", date)
+	}
+	if got[0].ProcessedOn != nil {
+		t.Errorf("ProcessedOn = %v, want none", got[0].ProcessedOn)
+	}
+
+	got, err = store.ListExampleResults(ctx, ExampleResultFilter{RecordID: "EX-1", CorrelationID: "corr-3"})
+	if err != nil || len(got) != 1 || got[0].ID != "a1" {
+		t.Errorf("ListExampleResults(corr-3) = %+v, %v; want a1", got, err)
+	}
+	got, err = store.ListExampleResults(ctx, ExampleResultFilter{Limit: 1})
+	if err != nil || len(got) != 1 || got[0].ID != "a1" {
+		t.Errorf("ListExampleResults(limit 1) = %+v, %v; want the newest result", got, err)
+	}
+	got, err = store.ListExampleResults(ctx, ExampleResultFilter{RecordID: "EX-9"})
+	if err != nil || len(got) != 0 {
+		t.Errorf("ListExampleResults(EX-9) = %+v, %v; want no results", got, err)
+	}
+
+	if err := store.StoreExampleResult(ctx, &StoredExampleResult{ID: "01D"}); err == nil {
+		t.Error("StoreExampleResult() without record id should fail")
+	}
+}
+
+func TestMemoryStoreExampleResults(t *testing.T) {
+	t.Parallel()
+	assertResultStore(t, NewMemoryStore())
+}
+
+func TestSQLiteStoreExampleResults(t *testing.T) {
+	t.Parallel()
+	assertResultStore(t, newTestSQLiteStore(t, SQLiteMemoryPath))
+}
@@ -0,0 +1,1012 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+const exampleRecordColumns = "record_id, data"
+
+const exampleResultColumns = "result_id, record_id, correlation_id, status, note, processed_on, stored_at"
+
+const apiKeyColumns = "key_id, key_hash, owner, scopes, created_at, expires_at, last_used_at, revoked_at"
+
+const outboxColumns = "handler, uuid, payload, topic, metadata, status, attempts, last_error, created_at, next_attempt_at, sent_at"
+
+// sqlMigration is one forward-only schema change of a SQL backend.
+type sqlMigration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// sqlDialect captures what differs between the SQL backends. Queries are written
+// with ? placeholders and rewritten through rebind.
+type sqlDialect struct {
+	name string
+	// numberedPlaceholders switches ? to $1, $2, ... as required by Postgres.
+	numberedPlaceholders bool
+	// lockMigrations runs inside the migration transaction to serialise concurrent starts.
+	lockMigrations string
+	// claimLocking is appended to the outbox claim subquery.
+	claimLocking string
+	// lockRow is appended to reads that precede an update of the same row.
+	lockRow    string
+	migrations []sqlMigration
+}
+
+// rebind rewrites ? placeholders into the dialect's placeholder syntax.
+func (d sqlDialect) rebind(query string) string {
+	if !d.numberedPlaceholders {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteByte('$')
+		b.WriteString(strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// SQLStore is a Repository backed by a database/sql connection. Records are stored
+// as protojson documents next to the columns needed for filtering.
+type SQLStore struct {
+	db             *sql.DB
+	dialect        sqlDialect
+	conflictPolicy ConflictPolicy
+	// stopSweep stops the expiry sweep and sweepDone is closed once it returned.
+	stopSweep context.CancelFunc
+	sweepDone chan struct{}
+}
+
+// newSQLStore verifies the connection and applies pending migrations. The store
+// takes ownership of db and closes it when setup fails.
+func newSQLStore(ctx context.Context, db *sql.DB, dialect sqlDialect, cfg *Config, logger *slog.Logger) (*SQLStore, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("%s ping failed: %w", dialect.name, err)
+	}
+
+	store := &SQLStore{db: db, dialect: dialect, conflictPolicy: cfg.conflictPolicy()}
+	applied, err := store.migrate(ctx)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("%s migrations failed: %w", dialect.name, err)
+	}
+	logger.Info("Database migrations applied", "driver", dialect.name, "applied", applied)
+	store.startExpirySweep(cfg.ExpirySweepInterval, logger)
+	return store, nil
+}
+
+// expiringTables lists the tables whose rows are deleted once their expires_at passed.
+var expiringTables = []string{"inbox_messages", "idempotency_keys"}
+
+// startExpirySweep deletes expired rows every interval until the store is closed.
+// Claims replace an expired row of their own key, so the sweep only keeps the
+// tables from growing.
+func (s *SQLStore) startExpirySweep(interval time.Duration, logger *slog.Logger) {
+	if interval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopSweep = cancel
+	s.sweepDone = make(chan struct{})
+	go func() {
+		defer close(s.sweepDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.deleteExpired(ctx, time.Now()); err != nil && ctx.Err() == nil {
+					logger.Warn("Deleting expired rows failed", "driver", s.dialect.name, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// deleteExpired deletes the rows of expiringTables that expired by now.
+func (s *SQLStore) deleteExpired(ctx context.Context, now time.Time) error {
+	for _, table := range expiringTables {
+		if _, err := s.db.ExecContext(ctx, s.dialect.rebind("DELETE FROM "+table+" WHERE expires_at <= ?"), now.UTC()); err != nil {
+			return fmt.Errorf("delete expired rows of %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// migrate applies all migrations that are not yet recorded in schema_migrations and
+// returns how many were applied.
+func (s *SQLStore) migrate(ctx context.Context) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if s.dialect.lockMigrations != "" {
+		if _, err := tx.ExecContext(ctx, s.dialect.lockMigrations); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`); err != nil {
+		return 0, err
+	}
+
+	current := 0
+	if err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, m := range s.dialect.migrations {
+		if m.Version <= current {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+			return 0, fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			s.dialect.rebind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"),
+			m.Version, m.Name, time.Now().UTC(),
+		); err != nil {
+			return 0, err
+		}
+		applied++
+	}
+	return applied, tx.Commit()
+}
+
+// Ping verifies that the SQL connection is still reachable.
+func (s *SQLStore) Ping(ctx context.Context) error {
+	if s == nil || s.db == nil {
+		return errors.New("database not configured")
+	}
+	return s.db.PingContext(ctx)
+}
+
+// Close closes the underlying connection pool.
+func (s *SQLStore) Close(context.Context) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	if s.stopSweep != nil {
+		s.stopSweep()
+		<-s.sweepDone
+	}
+	return s.db.Close()
+}
+
+// StoreExampleRecord saves a new record. An existing record with the same ID is
+// handled according to the conflict policy.
+func (s *SQLStore) StoreExampleRecord(ctx context.Context, record *domain.ExampleRecord) error {
+	if record == nil {
+		return errors.New("example record is required")
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		return s.storeExampleRecordTx(ctx, tx, record)
+	})
+}
+
+func (s *SQLStore) GetExampleRecordByID(ctx context.Context, id string) (*domain.ExampleRecord, error) {
+	row := s.db.QueryRowContext(ctx,
+		s.dialect.rebind("SELECT "+exampleRecordColumns+" FROM example_records WHERE record_id = ?"), id)
+
+	record, err := scanExampleRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExampleRecordNotFound
+	}
+	return record, err
+}
+
+// ListExampleRecords returns one page of records ordered by record ID together with
+// the cursor of the next page, which is empty once the last page was reached.
+func (s *SQLStore) ListExampleRecords(ctx context.Context, filter ExampleRecordFilter) ([]*domain.ExampleRecord, string, error) {
+	where, args, err := exampleRecordWhere(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultExampleRecordPageSize
+	}
+
+	query := "SELECT " + exampleRecordColumns + " FROM example_records" + where + " ORDER BY record_id LIMIT ?"
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(query), append(args, limit+1)...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = rows.Close() }()
+
+	records := make([]*domain.ExampleRecord, 0, limit+1)
+	for rows.Next() {
+		record, err := scanExampleRecord(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(records) <= limit {
+		return records, "", nil
+	}
+	records = records[:limit]
+	return records, EncodeCursor(records[limit-1].GetRecordId()), nil
+}
+
+// ReplaceExampleRecordVersion overwrites the stored record with the given ID while it
+// still has version. The version lives inside the stored document, so the row is
+// locked while it is compared.
+func (s *SQLStore) ReplaceExampleRecordVersion(ctx context.Context, id string, version int32, record *domain.ExampleRecord) error {
+	if record == nil {
+		return errors.New("example record is required")
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		stored, err := scanExampleRecord(tx.QueryRowContext(ctx,
+			s.dialect.rebind("SELECT "+exampleRecordColumns+" FROM example_records WHERE record_id = ?"+s.dialect.lockRow), id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrExampleRecordNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if stored.GetVersion() != version {
+			return ErrExampleRecordVersionConflict
+		}
+		return s.writeExampleRecordTx(ctx, tx, id, record, false)
+	})
+}
+
+// DeleteExampleRecord removes the stored record with the given ID.
+func (s *SQLStore) DeleteExampleRecord(ctx context.Context, id string) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, s.dialect.rebind("DELETE FROM example_record_tags WHERE record_id = ?"), id); err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, s.dialect.rebind("DELETE FROM example_records WHERE record_id = ?"), id)
+		if err != nil {
+			return err
+		}
+		return requireAffected(result, ErrExampleRecordNotFound)
+	})
+}
+
+// StoreExampleRecordWithOutbox saves the record and the outbox row in one transaction,
+// so the event is only dispatched once the record is committed.
+func (s *SQLStore) StoreExampleRecordWithOutbox(ctx context.Context, record *domain.ExampleRecord, msg *OutboxMessage) error {
+	if record == nil {
+		return errors.New("example record is required")
+	}
+	if err := prepareOutboxMessage(msg); err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.storeExampleRecordTx(ctx, tx, record); err != nil {
+			return err
+		}
+		return s.insertOutboxMessage(ctx, tx, msg)
+	})
+}
+
+// storeExampleRecordTx inserts a new record or resolves the collision with the stored
+// one through the conflict policy. The stored row is locked while it is resolved,
+// and an insert that loses the race against a concurrent one is resolved against
+// the row that won.
+func (s *SQLStore) storeExampleRecordTx(ctx context.Context, tx *sql.Tx, record *domain.ExampleRecord) error {
+	id := record.GetRecordId()
+	for {
+		existing, err := scanExampleRecord(tx.QueryRowContext(ctx,
+			s.dialect.rebind("SELECT "+exampleRecordColumns+" FROM example_records WHERE record_id = ?"+s.dialect.lockRow), id))
+		if errors.Is(err, sql.ErrNoRows) {
+			err := s.writeExampleRecordTx(ctx, tx, id, record, true)
+			if errors.Is(err, ErrExampleRecordExists) && s.conflictPolicy != ConflictReject {
+				continue
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		resolved, err := s.conflictPolicy.resolveConflict(existing, record)
+		if err != nil {
+			return err
+		}
+		return s.writeExampleRecordTx(ctx, tx, id, resolved, false)
+	}
+}
+
+// writeExampleRecordTx inserts or updates the record row and rewrites its tags. An
+// insert that hits an existing row reports ErrExampleRecordExists, an update that
+// matches no row ErrExampleRecordNotFound.
+func (s *SQLStore) writeExampleRecordTx(ctx context.Context, tx *sql.Tx, id string, record *domain.ExampleRecord, insert bool) error {
+	data, err := protojson.Marshal(record)
+	if err != nil {
+		return err
+	}
+	meta := record.GetMeta()
+	args := []any{string(data), meta.GetPriority(), meta.GetRequestedBy(), dateOrdinal(meta.GetDesiredStartDate()), id}
+
+	query := "UPDATE example_records SET data = ?, priority = ?, requested_by = ?, desired_start = ? WHERE record_id = ?"
+	missing := ErrExampleRecordNotFound
+	if insert {
+		query = `INSERT INTO example_records (data, priority, requested_by, desired_start, record_id)
+			VALUES (?, ?, ?, ?, ?) ON CONFLICT (record_id) DO NOTHING`
+		missing = ErrExampleRecordExists
+	}
+	result, err := tx.ExecContext(ctx, s.dialect.rebind(query), args...)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result, missing); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, s.dialect.rebind("DELETE FROM example_record_tags WHERE record_id = ?"), id); err != nil {
+		return err
+	}
+	for _, tag := range uniqueTags(record.GetTags()) {
+		if _, err := tx.ExecContext(ctx,
+			s.dialect.rebind("INSERT INTO example_record_tags (record_id, tag) VALUES (?, ?)"), id, tag,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StoreOutboxMessage inserts a pending row into the outbox_messages table.
+func (s *SQLStore) StoreOutboxMessage(ctx context.Context, msg *OutboxMessage) error {
+	if err := prepareOutboxMessage(msg); err != nil {
+		return err
+	}
+	return s.insertOutboxMessage(ctx, s.db, msg)
+}
+
+// sqlExecer is implemented by both *sql.DB and *sql.Tx.
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (s *SQLStore) insertOutboxMessage(ctx context.Context, exec sqlExecer, msg *OutboxMessage) error {
+	metadata, err := json.Marshal(msg.Metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = exec.ExecContext(ctx,
+		s.dialect.rebind("INSERT INTO outbox_messages ("+outboxColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+		msg.Handler, msg.UUID, msg.Payload, msg.Topic, string(metadata), msg.Status, msg.Attempts, msg.LastError,
+		msg.CreatedAt.UTC(), msg.NextAttemptAt.UTC(), nullTime(msg.SentAt),
+	)
+	return err
+}
+
+// OutboxHandlers lists the handlers that own at least one outbox row.
+func (s *SQLStore) OutboxHandlers(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT handler FROM outbox_messages ORDER BY handler")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var handlers []string
+	for rows.Next() {
+		var handler string
+		if err := rows.Scan(&handler); err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, handler)
+	}
+	return handlers, rows.Err()
+}
+
+// ClaimOutboxMessages leases up to limit due rows of a handler so concurrent relays
+// do not dispatch the same message. A leased row becomes due again once lease expires.
+func (s *SQLStore) ClaimOutboxMessages(
+	ctx context.Context,
+	handler string,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]OutboxMessage, error) {
+	query := `UPDATE outbox_messages SET next_attempt_at = ?
+		WHERE (handler, uuid) IN (
+			SELECT handler, uuid FROM outbox_messages
+			WHERE handler = ? AND status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at LIMIT ?` + s.dialect.claimLocking + `
+		)
+		RETURNING ` + outboxColumns
+
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(query),
+		now.Add(lease).UTC(), handler, OutboxStatusPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	claimed := make([]OutboxMessage, 0, limit)
+	for rows.Next() {
+		row, err := scanOutboxMessage(rows)
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, row)
+	}
+	return claimed, rows.Err()
+}
+
+// MarkOutboxMessageSent deletes the sent row instead of keeping it, so the outbox
+// table does not grow without bound.
+func (s *SQLStore) MarkOutboxMessageSent(ctx context.Context, handler string, uuid string, _ time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		s.dialect.rebind("DELETE FROM outbox_messages WHERE handler = ? AND uuid = ?"),
+		handler, uuid,
+	)
+	return err
+}
+
+// MarkOutboxMessageFailed records a failed dispatch. The row is retried at nextAttemptAt
+// unless final is set, in which case it is parked with status failed.
+func (s *SQLStore) MarkOutboxMessageFailed(
+	ctx context.Context,
+	handler string,
+	uuid string,
+	reason string,
+	nextAttemptAt time.Time,
+	final bool,
+) error {
+	status := OutboxStatusPending
+	if final {
+		status = OutboxStatusFailed
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		s.dialect.rebind(`UPDATE outbox_messages SET status = ?, last_error = ?, next_attempt_at = ?, attempts = attempts + 1
+			WHERE handler = ? AND uuid = ?`),
+		status, reason, nextAttemptAt.UTC(), handler, uuid,
+	)
+	return err
+}
+
+// ClaimInboxMessage leases the message to the caller unless an unexpired entry exists.
+// An expired entry of the message is taken over; the expiry sweep deletes the rest.
+func (s *SQLStore) ClaimInboxMessage(
+	ctx context.Context,
+	handler string,
+	uuid string,
+	now time.Time,
+	lease time.Duration,
+) (InboxClaim, error) {
+	result, err := s.db.ExecContext(ctx,
+		s.dialect.rebind(`INSERT INTO inbox_messages (handler, uuid, status, expires_at) VALUES (?, ?, ?, ?)
+			ON CONFLICT (handler, uuid) DO UPDATE SET status = excluded.status, expires_at = excluded.expires_at
+			WHERE inbox_messages.expires_at <= ?`),
+		handler, uuid, InboxStatusProcessing, now.Add(lease).UTC(), now.UTC(),
+	)
+	if err != nil {
+		return InboxInFlight, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return InboxInFlight, err
+	}
+	if inserted > 0 {
+		return InboxClaimed, nil
+	}
+
+	var status string
+	err = s.db.QueryRowContext(ctx,
+		s.dialect.rebind("SELECT status FROM inbox_messages WHERE handler = ? AND uuid = ?"), handler, uuid,
+	).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released between the two statements; let the redelivery decide.
+		return InboxInFlight, nil
+	}
+	if err != nil {
+		return InboxInFlight, err
+	}
+	return inboxClaimForStatus(status), nil
+}
+
+// CompleteInboxMessage marks the message processed and keeps the entry until expiresAt.
+func (s *SQLStore) CompleteInboxMessage(ctx context.Context, handler string, uuid string, expiresAt time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		s.dialect.rebind("UPDATE inbox_messages SET status = ?, expires_at = ? WHERE handler = ? AND uuid = ?"),
+		InboxStatusProcessed, expiresAt.UTC(), handler, uuid,
+	)
+	return err
+}
+
+// ReleaseInboxMessage drops a lease after failed processing so a redelivery is handled.
+func (s *SQLStore) ReleaseInboxMessage(ctx context.Context, handler string, uuid string) error {
+	_, err := s.db.ExecContext(ctx,
+		s.dialect.rebind("DELETE FROM inbox_messages WHERE handler = ? AND uuid = ? AND status = ?"),
+		handler, uuid, InboxStatusProcessing,
+	)
+	return err
+}
+
+// ClaimIdempotencyKey leases the key unless an unexpired record exists. An expired
+// record of the key is taken over; the expiry sweep deletes the rest.
+func (s *SQLStore) ClaimIdempotencyKey(
+	ctx context.Context,
+	key string,
+	fingerprint string,
+	now time.Time,
+	lease time.Duration,
+) (*IdempotencyRecord, bool, error) {
+	claimed := inFlightIdempotencyRecord(key, fingerprint, now, lease)
+	result, err := s.db.ExecContext(ctx,
+		s.dialect.rebind(`INSERT INTO idempotency_keys (idempotency_key, fingerprint, owner, status, expires_at) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (idempotency_key) DO UPDATE SET fingerprint = excluded.fingerprint, owner = excluded.owner,
+				status = excluded.status, status_code = 0, header = '', body = NULL, expires_at = excluded.expires_at
+			WHERE idempotency_keys.expires_at <= ?`),
+		claimed.Key, claimed.Fingerprint, claimed.Owner, claimed.Status, claimed.ExpiresAt, now.UTC(),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+	if inserted > 0 {
+		return claimed, true, nil
+	}
+
+	existing := &IdempotencyRecord{Key: key}
+	var header []byte
+	err = s.db.QueryRowContext(ctx,
+		s.dialect.rebind(`SELECT fingerprint, status, status_code, header, body, expires_at
+			FROM idempotency_keys WHERE idempotency_key = ?`), key,
+	).Scan(&existing.Fingerprint, &existing.Status, &existing.StatusCode, &header, &existing.Body, &existing.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released between the two statements; the caller retries later.
+		return claimed, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(header) > 0 {
+		if err := json.Unmarshal(header, &existing.Header); err != nil {
+			return nil, false, err
+		}
+	}
+	return existing, false, nil
+}
+
+// CompleteIdempotencyKey stores the response of an in-flight key.
+func (s *SQLStore) CompleteIdempotencyKey(ctx context.Context, record *IdempotencyRecord) error {
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		s.dialect.rebind(`UPDATE idempotency_keys SET status = ?, status_code = ?, header = ?, body = ?, expires_at = ?
+			WHERE idempotency_key = ? AND owner = ? AND status = ?`),
+		IdempotencyStatusCompleted, record.StatusCode, string(header), record.Body, record.ExpiresAt.UTC(),
+		record.Key, record.Owner, IdempotencyStatusInFlight,
+	)
+	return err
+}
+
+// ReleaseIdempotencyKey deletes the record of an in-flight key.
+func (s *SQLStore) ReleaseIdempotencyKey(ctx context.Context, key string, owner string) error {
+	_, err := s.db.ExecContext(ctx,
+		s.dialect.rebind("DELETE FROM idempotency_keys WHERE idempotency_key = ? AND owner = ? AND status = ?"),
+		key, owner, IdempotencyStatusInFlight,
+	)
+	return err
+}
+
+// StartBatch inserts the batch row unless it exists and returns the stored progress.
+func (s *SQLStore) StartBatch(ctx context.Context, progress *BatchProgress) (*BatchProgress, error) {
+	if err := prepareBatchProgress(progress); err != nil {
+		return nil, err
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		s.dialect.rebind(`INSERT INTO example_batches (batch_id, status, total, started_at, updated_at) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (batch_id) DO NOTHING`),
+		progress.BatchID, progress.Status, progress.Total, progress.StartedAt, progress.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetBatch(ctx, progress.BatchID)
+}
+
+// RecordBatchOutcome inserts the outcome and bumps its counter in one transaction.
+func (s *SQLStore) RecordBatchOutcome(ctx context.Context, batchID string, outcome BatchRecordOutcome) error {
+	if err := validBatchOutcome(outcome.Outcome); err != nil {
+		return err
+	}
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			s.dialect.rebind(`INSERT INTO example_batch_outcomes
+				(batch_id, record_id, outcome, status, note, error_code, message, retryable, processed_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT (batch_id, record_id) DO NOTHING`),
+			batchID, outcome.RecordID, outcome.Outcome, outcome.Status, outcome.Note,
+			outcome.ErrorCode, outcome.Message, outcome.Retryable, outcome.ProcessedAt.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+		if inserted, err := result.RowsAffected(); err != nil || inserted == 0 {
+			return err
+		}
+
+		// The outcome was validated above, so it is safe to use as the column name.
+		result, err = tx.ExecContext(ctx,
+			s.dialect.rebind("UPDATE example_batches SET "+outcome.Outcome+" = "+outcome.Outcome+" + 1, updated_at = ? WHERE batch_id = ?"),
+			time.Now().UTC(), batchID,
+		)
+		if err != nil {
+			return err
+		}
+		return requireAffected(result, ErrBatchNotFound)
+	})
+}
+
+// CompleteBatch marks the batch completed.
+func (s *SQLStore) CompleteBatch(ctx context.Context, batchID string, completedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx,
+		s.dialect.rebind("UPDATE example_batches SET status = ?, completed_at = ?, updated_at = ? WHERE batch_id = ?"),
+		BatchStatusCompleted, completedAt.UTC(), completedAt.UTC(), batchID,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, ErrBatchNotFound)
+}
+
+// GetBatch returns the stored progress of a batch with its outcomes in processing order.
+func (s *SQLStore) GetBatch(ctx context.Context, batchID string) (*BatchProgress, error) {
+	progress := &BatchProgress{BatchID: batchID, Outcomes: []BatchRecordOutcome{}}
+	var completedAt sql.NullTime
+	err := s.db.QueryRowContext(ctx,
+		s.dialect.rebind(`SELECT status, total, succeeded, failed, skipped, started_at, updated_at, completed_at
+			FROM example_batches WHERE batch_id = ?`), batchID,
+	).Scan(&progress.Status, &progress.Total, &progress.Succeeded, &progress.Failed, &progress.Skipped,
+		&progress.StartedAt, &progress.UpdatedAt, &completedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrBatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		progress.CompletedAt = &completedAt.Time
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		s.dialect.rebind(`SELECT record_id, outcome, status, note, error_code, message, retryable, processed_at
+			FROM example_batch_outcomes WHERE batch_id = ? ORDER BY processed_at, record_id`), batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var o BatchRecordOutcome
+		if err := rows.Scan(&o.RecordID, &o.Outcome, &o.Status, &o.Note, &o.ErrorCode, &o.Message, &o.Retryable, &o.ProcessedAt); err != nil {
+			return nil, err
+		}
+		progress.Outcomes = append(progress.Outcomes, o)
+	}
+	return progress, rows.Err()
+}
+
+// StoreExampleResult inserts the result row unless its ID exists.
+func (s *SQLStore) StoreExampleResult(ctx context.Context, result *StoredExampleResult) error {
+	if err := prepareExampleResult(result); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx,
+		s.dialect.rebind("INSERT INTO example_results ("+exampleResultColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (result_id) DO NOTHING`),
+		result.ID, result.RecordID, result.CorrelationID, result.Status, result.Note,
+		dateOrdinal(result.ProcessedOn), result.StoredAt.UTC(),
+	)
+	return err
+}
+
+// ListExampleResults returns the matching results, newest first.
+func (s *SQLStore) ListExampleResults(ctx context.Context, filter ExampleResultFilter) ([]*StoredExampleResult, error) {
+	var conditions []string
+	var args []any
+	if filter.RecordID != "" {
+		conditions = append(conditions, "record_id = ?")
+		args = append(args, filter.RecordID)
+	}
+	if filter.CorrelationID != "" {
+		conditions = append(conditions, "correlation_id = ?")
+		args = append(args, filter.CorrelationID)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := "SELECT " + exampleResultColumns + " FROM example_results" + where + " ORDER BY stored_at DESC, result_id DESC LIMIT ?"
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(query), append(args, filter.limit())...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	results := []*StoredExampleResult{}
+	for rows.Next() {
+		var result StoredExampleResult
+		var processedOn int32
+		if err := rows.Scan(&result.ID, &result.RecordID, &result.CorrelationID, &result.Status, &result.Note,
+			&processedOn, &result.StoredAt); err != nil {
+			return nil, err
+		}
+		result.ProcessedOn = dateFromOrdinal(processedOn)
+		results = append(results, &result)
+	}
+	return results, rows.Err()
+}
+
+// StoreAPIKey inserts the key row. Scopes are stored space-separated.
+func (s *SQLStore) StoreAPIKey(ctx context.Context, key *StoredAPIKey) error {
+	if err := prepareAPIKey(key); err != nil {
+		return err
+	}
+	result, err := s.db.ExecContext(ctx,
+		s.dialect.rebind("INSERT INTO api_keys ("+apiKeyColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT DO NOTHING`),
+		key.ID, key.Hash, key.Owner, strings.Join(key.Scopes, " "), key.CreatedAt.UTC(),
+		nullTime(key.ExpiresAt), nullTime(key.LastUsedAt), nullTime(key.RevokedAt),
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, ErrAPIKeyExists)
+}
+
+// GetAPIKeyByHash looks up the key with the given hash.
+func (s *SQLStore) GetAPIKeyByHash(ctx context.Context, hash string) (*StoredAPIKey, error) {
+	row := s.db.QueryRowContext(ctx,
+		s.dialect.rebind("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = ?"), hash)
+	key, err := scanAPIKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, err
+}
+
+// ListAPIKeys returns the keys of owner, or all keys for an empty owner, oldest first.
+func (s *SQLStore) ListAPIKeys(ctx context.Context, owner string) ([]*StoredAPIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys"
+	var args []any
+	if owner != "" {
+		query += " WHERE owner = ?"
+		args = append(args, owner)
+	}
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(query+" ORDER BY key_id"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	keys := []*StoredAPIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey sets the revocation time of a key that is not revoked yet.
+func (s *SQLStore) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx,
+		s.dialect.rebind("UPDATE api_keys SET revoked_at = COALESCE(revoked_at, ?) WHERE key_id = ?"),
+		revokedAt.UTC(), id,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, ErrAPIKeyNotFound)
+}
+
+// TouchAPIKey sets the time the key was last used.
+func (s *SQLStore) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx,
+		s.dialect.rebind("UPDATE api_keys SET last_used_at = ? WHERE key_id = ?"), usedAt.UTC(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, ErrAPIKeyNotFound)
+}
+
+// inTx runs fn in a transaction that is committed when fn succeeds.
+func (s *SQLStore) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// exampleRecordWhere translates the filter into a WHERE clause and its arguments.
+func exampleRecordWhere(filter ExampleRecordFilter) (string, []any, error) {
+	var conditions []string
+	var args []any
+
+	if filter.Cursor != "" {
+		after, err := DecodeCursor(filter.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, "record_id > ?")
+		args = append(args, after)
+	}
+	if tags := uniqueTags(filter.Tags); len(tags) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(tags)), ", ")
+		conditions = append(conditions, "record_id IN (SELECT record_id FROM example_record_tags WHERE tag IN ("+
+			placeholders+") GROUP BY record_id HAVING COUNT(*) = ?)")
+		for _, tag := range tags {
+			args = append(args, tag)
+		}
+		args = append(args, len(tags))
+	}
+	if filter.Priority != 0 {
+		conditions = append(conditions, "priority = ?")
+		args = append(args, filter.Priority)
+	}
+	if filter.RequestedBy != "" {
+		conditions = append(conditions, "requested_by = ?")
+		args = append(args, filter.RequestedBy)
+	}
+	if filter.DesiredStartFrom != nil {
+		conditions = append(conditions, "desired_start >= ?")
+		args = append(args, dateOrdinal(filter.DesiredStartFrom))
+	}
+	if filter.DesiredStartTo != nil {
+		conditions = append(conditions, "desired_start <= ?")
+		args = append(args, dateOrdinal(filter.DesiredStartTo))
+	}
+
+	if len(conditions) == 0 {
+		return "", args, nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanExampleRecord(row rowScanner) (*domain.ExampleRecord, error) {
+	var id string
+	var data []byte
+	if err := row.Scan(&id, &data); err != nil {
+		return nil, err
+	}
+
+	record := &domain.ExampleRecord{}
+	if err := protojson.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("decode example record %q: %w", id, err)
+	}
+	return record, nil
+}
+
+func scanOutboxMessage(row rowScanner) (OutboxMessage, error) {
+	var msg OutboxMessage
+	var metadata []byte
+	var sentAt sql.NullTime
+	if err := row.Scan(
+		&msg.Handler, &msg.UUID, &msg.Payload, &msg.Topic, &metadata, &msg.Status, &msg.Attempts,
+		&msg.LastError, &msg.CreatedAt, &msg.NextAttemptAt, &sentAt,
+	); err != nil {
+		return msg, err
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &msg.Metadata); err != nil {
+			return msg, err
+		}
+	}
+	if sentAt.Valid {
+		msg.SentAt = &sentAt.Time
+	}
+	return msg, nil
+}
+
+func scanAPIKey(row rowScanner) (*StoredAPIKey, error) {
+	var key StoredAPIKey
+	var scopes string
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	if err := row.Scan(&key.ID, &key.Hash, &key.Owner, &scopes, &key.CreatedAt,
+		&expiresAt, &lastUsedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	key.Scopes = strings.Fields(scopes)
+	key.ExpiresAt = timeOrNil(expiresAt)
+	key.LastUsedAt = timeOrNil(lastUsedAt)
+	key.RevokedAt = timeOrNil(revokedAt)
+	return &key, nil
+}
+
+// timeOrNil reverses nullTime.
+func timeOrNil(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// requireAffected returns none when the statement changed no row.
+func requireAffected(result sql.Result, none error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return none
+	}
+	return nil
+}
+
+func uniqueTags(tags []string) []string {
+	seen := make(map[string]struct{}, len(tags))
+	unique := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		unique = append(unique, tag)
+	}
+	return unique
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	date "google.golang.org/genproto/googleapis/type/date"
+)
+
+func TestSQLDialectRebind(t *testing.T) {
+	t.Parallel()
+
+	query := "SELECT a FROM t WHERE b = ? AND c IN (?, ?)"
+	if got := (sqlDialect{}).rebind(query); got != query {
+		t.Errorf("rebind() without numbered placeholders = %q", got)
+	}
+	want := "SELECT a FROM t WHERE b = $1 AND c IN ($2, $3)"
+	if got := postgresDialect.rebind(query); got != want {
+		t.Errorf("rebind() = %q, want %q", got, want)
+	}
+}
+
+func TestExampleRecordWhere(t *testing.T) {
+	t.Parallel()
+
+	where, args, err := exampleRecordWhere(ExampleRecordFilter{})
+	if err != nil || where != "" || len(args) != 0 {
+		t.Errorf("empty filter = %q, %v, %v", where, args, err)
+	}
+
+	where, args, err = exampleRecordWhere(ExampleRecordFilter{
+		Cursor:           EncodeCursor("EX-1"),
+		Tags:             []string{"a", "b", "a"},
+		Priority:         2,
+		RequestedBy:      "bot",
+		DesiredStartFrom: &date.Date{Year: 2025, Month: 1, Day: 1},
+		DesiredStartTo:   &date.Date{Year: 2025, Month: 12, Day: 31},
+	})
+	if err != nil {
+		t.Fatalf("exampleRecordWhere() error = %v", err)
+	}
+
+	wantWhere := " WHERE record_id > ?" +
+		" AND record_id IN (SELECT record_id FROM example_record_tags WHERE tag IN (?, ?) GROUP BY record_id HAVING COUNT(*) = ?)" +
+		" AND priority = ? AND requested_by = ? AND desired_start >= ? AND desired_start <= ?"
+	if where != wantWhere {
+		t.Errorf("where = %q, want %q", where, wantWhere)
+	}
+	wantArgs := []any{"EX-1", "a", "b", 2, int32(2), "bot", int32(20250101), int32(20251231)}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %#v, want %#v", args, wantArgs)
+	}
+
+	// Postgres numbers the placeholders in argument order, the page size last.
+	wantPostgres := " WHERE record_id > $1" +
+		" AND record_id IN (SELECT record_id FROM example_record_tags WHERE tag IN ($2, $3) GROUP BY record_id HAVING COUNT(*) = $4)" +
+		" AND priority = $5 AND requested_by = $6 AND desired_start >= $7 AND desired_start <= $8 ORDER BY record_id LIMIT $9"
+	if got := postgresDialect.rebind(where + " ORDER BY record_id LIMIT ?"); got != wantPostgres {
+		t.Errorf("rebind(where) = %q, want %q", got, wantPostgres)
+	}
+
+	if _, _, err := exampleRecordWhere(ExampleRecordFilter{Cursor: "%%%"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("invalid cursor error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestPostgresMigrationsAreOrdered(t *testing.T) {
+	t.Parallel()
+
+	last := 0
+	for _, m := range postgresDialect.migrations {
+		if m.Version <= last {
+			t.Errorf("migration %q has version %d after %d", m.Name, m.Version, last)
+		}
+		if m.SQL == "" {
+			t.Errorf("migration %d has no SQL", m.Version)
+		}
+		last = m.Version
+	}
+}
+
+func TestSQLStoreNilHandle(t *testing.T) {
+	t.Parallel()
+
+	var store *SQLStore
+	if err := store.Ping(context.Background()); err == nil {
+		t.Error("Ping() on nil store should fail")
+	}
+	if err := store.Close(context.Background()); err != nil {
+		t.Errorf("Close() on nil store error = %v", err)
+	}
+}
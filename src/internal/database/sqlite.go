@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+
+	// Registers the "sqlite3" database/sql driver. It requires a cgo build.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteMemoryPath selects a private in-memory SQLite database.
+const SQLiteMemoryPath = ":memory:"
+
+var sqliteDialect = sqlDialect{
+	name: DriverSQLite,
+	migrations: []sqlMigration{
+		{
+			Version: 1,
+			Name:    "create example records",
+			SQL: `CREATE TABLE IF NOT EXISTS example_records (
+				record_id TEXT PRIMARY KEY,
+				data TEXT NOT NULL,
+				priority INTEGER NOT NULL DEFAULT 0,
+				requested_by TEXT NOT NULL DEFAULT '',
+				desired_start INTEGER NOT NULL DEFAULT 0
+			);
+			CREATE TABLE IF NOT EXISTS example_record_tags (
+				record_id TEXT NOT NULL,
+				tag TEXT NOT NULL,
+				PRIMARY KEY (record_id, tag)
+			);
+			CREATE INDEX IF NOT EXISTS example_record_tags_tag_idx ON example_record_tags (tag);`,
+		},
+		{
+			Version: 2,
+			Name:    "create outbox messages",
+			SQL: `CREATE TABLE IF NOT EXISTS outbox_messages (
+				handler TEXT NOT NULL,
+				uuid TEXT NOT NULL,
+				payload TEXT NOT NULL,
+				topic TEXT NOT NULL DEFAULT '',
+				metadata TEXT,
+				status TEXT NOT NULL,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				last_error TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL,
+				next_attempt_at TIMESTAMP NOT NULL,
+				sent_at TIMESTAMP,
+				PRIMARY KEY (handler, uuid)
+			);
+			CREATE INDEX IF NOT EXISTS outbox_messages_due_idx ON outbox_messages (handler, status, next_attempt_at);`,
+		},
+		{
+			Version: 3,
+			Name:    "create inbox messages",
+			SQL: `CREATE TABLE IF NOT EXISTS inbox_messages (
+				handler TEXT NOT NULL,
+				uuid TEXT NOT NULL,
+				status TEXT NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				PRIMARY KEY (handler, uuid)
+			);
+			CREATE INDEX IF NOT EXISTS inbox_messages_expiry_idx ON inbox_messages (expires_at);`,
+		},
+		{
+			Version: 4,
+			Name:    "create example batches",
+			SQL: `CREATE TABLE IF NOT EXISTS example_batches (
+				batch_id TEXT PRIMARY KEY,
+				status TEXT NOT NULL,
+				total INTEGER NOT NULL DEFAULT 0,
+				succeeded INTEGER NOT NULL DEFAULT 0,
+				failed INTEGER NOT NULL DEFAULT 0,
+				skipped INTEGER NOT NULL DEFAULT 0,
+				started_at TIMESTAMP NOT NULL,
+				updated_at TIMESTAMP NOT NULL,
+				completed_at TIMESTAMP
+			);
+			CREATE TABLE IF NOT EXISTS example_batch_outcomes (
+				batch_id TEXT NOT NULL,
+				record_id TEXT NOT NULL,
+				outcome TEXT NOT NULL,
+				status TEXT NOT NULL DEFAULT '',
+				note TEXT NOT NULL DEFAULT '',
+				error_code TEXT NOT NULL DEFAULT '',
+				message TEXT NOT NULL DEFAULT '',
+				retryable BOOLEAN NOT NULL DEFAULT FALSE,
+				processed_at TIMESTAMP NOT NULL,
+				PRIMARY KEY (batch_id, record_id)
+			);`,
+		},
+		{
+			Version: 5,
+			Name:    "create example results",
+			SQL: `CREATE TABLE IF NOT EXISTS example_results (
+				result_id TEXT PRIMARY KEY,
+				record_id TEXT NOT NULL,
+				correlation_id TEXT NOT NULL DEFAULT '',
+				status TEXT NOT NULL DEFAULT '',
+				note TEXT NOT NULL DEFAULT '',
+				processed_on INTEGER NOT NULL DEFAULT 0,
+				stored_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS example_results_record_idx ON example_results (record_id, result_id);
+			CREATE INDEX IF NOT EXISTS example_results_correlation_idx ON example_results (correlation_id);`,
+		},
+		{
+			Version: 6,
+			Name:    "create api keys",
+			SQL: `CREATE TABLE IF NOT EXISTS api_keys (
+				key_id TEXT PRIMARY KEY,
+				key_hash TEXT NOT NULL UNIQUE,
+				owner TEXT NOT NULL,
+				scopes TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL,
+				expires_at TIMESTAMP,
+				last_used_at TIMESTAMP,
+				revoked_at TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS api_keys_owner_idx ON api_keys (owner, key_id);`,
+		},
+		{
+			Version: 7,
+			Name:    "create idempotency keys",
+			SQL: `CREATE TABLE IF NOT EXISTS idempotency_keys (
+				idempotency_key TEXT PRIMARY KEY,
+				fingerprint TEXT NOT NULL,
+				status TEXT NOT NULL,
+				status_code INTEGER NOT NULL DEFAULT 0,
+				header TEXT NOT NULL DEFAULT '',
+				body BLOB,
+				expires_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS idempotency_keys_expiry_idx ON idempotency_keys (expires_at);`,
+		},
+		{
+			Version: 8,
+			Name:    "order example results by storage time",
+			SQL: `DROP INDEX IF EXISTS example_results_record_idx;
+			CREATE INDEX IF NOT EXISTS example_results_stored_idx ON example_results (record_id, stored_at, result_id);`,
+		},
+		{
+			Version: 9,
+			Name:    "own idempotency key leases",
+			SQL:     `ALTER TABLE idempotency_keys ADD COLUMN owner TEXT NOT NULL DEFAULT '';`,
+		},
+		{
+			Version: 10,
+			Name:    "delete sent outbox rows",
+			SQL:     `DELETE FROM outbox_messages WHERE status = 'sent';`,
+		},
+	},
+}
+
+// NewSQLiteStore opens the SQLite database at cfg.SQLitePath, creating the file if
+// needed, and applies pending schema migrations. ":memory:" keeps everything in
+// process memory.
+func NewSQLiteStore(ctx context.Context, cfg *Config, logger *slog.Logger) (*SQLStore, error) {
+	path := strings.TrimSpace(cfg.SQLitePath)
+	logger.Info("Opening SQLite database", "path", path)
+
+	db, err := sql.Open("sqlite3", sqliteDSN(path))
+	if err != nil {
+		return nil, err
+	}
+	// Every connection to ":memory:" opens a separate database, and SQLite allows a
+	// single writer anyway, so the pool is limited to one connection.
+	db.SetMaxOpenConns(1)
+
+	store, err := newSQLStore(ctx, db, sqliteDialect, cfg, logger)
+	if err != nil {
+		logger.Error("SQLite setup failed", "error", err)
+		return nil, err
+	}
+	return store, nil
+}
+
+// sqliteDSN adds the connection options the store relies on to a file path.
+func sqliteDSN(path string) string {
+	if path == SQLiteMemoryPath {
+		return path
+	}
+	return "file:" + path + "?_busy_timeout=5000&_journal_mode=WAL"
+}
@@ -0,0 +1,366 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+
+	date "google.golang.org/genproto/googleapis/type/date"
+)
+
+func newTestSQLiteStore(t *testing.T, path string) *SQLStore {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store, err := NewSQLiteStore(context.Background(), &Config{Driver: DriverSQLite, SQLitePath: path}, logger)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close(context.Background()) })
+	return store
+}
+
+func TestSQLiteStoreExampleRecordLifecycle(t *testing.T) {
+	t.Parallel()
+	assertSQLExampleRecordLifecycle(t, newTestSQLiteStore(t, SQLiteMemoryPath))
+}
+
+// assertSQLExampleRecordLifecycle stores, replaces and deletes a record in store.
+func assertSQLExampleRecordLifecycle(t *testing.T, store *SQLStore) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	record := &domain.ExampleRecord{RecordId: "EX-1", Title: "first", Tags: []string{"a", "a", "b"}}
+	if err := store.StoreExampleRecord(ctx, record); err != nil {
+		t.Fatalf("StoreExampleRecord() error = %v", err)
+	}
+	got, err := store.GetExampleRecordByID(ctx, "EX-1")
+	if err != nil || got.GetTitle() != "first" {
+		t.Fatalf("GetExampleRecordByID() = %v, %v", got, err)
+	}
+
+	if err := store.ReplaceExampleRecordVersion(ctx, "EX-1", got.GetVersion(), &domain.ExampleRecord{RecordId: "EX-1", Title: "second", Version: got.GetVersion() + 1}); err != nil {
+		t.Fatalf("ReplaceExampleRecordVersion() error = %v", err)
+	}
+	if got, _ := store.GetExampleRecordByID(ctx, "EX-1"); got.GetTitle() != "second" {
+		t.Errorf("Title = %q, want 'second'", got.GetTitle())
+	}
+	if records, _, _ := store.ListExampleRecords(ctx, ExampleRecordFilter{Tags: []string{"a"}}); len(records) != 0 {
+		t.Error("replacing a record must drop its previous tags")
+	}
+
+	if err := store.DeleteExampleRecord(ctx, "EX-1"); err != nil {
+		t.Fatalf("DeleteExampleRecord() error = %v", err)
+	}
+	if _, err := store.GetExampleRecordByID(ctx, "EX-1"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("GetExampleRecordByID() after delete error = %v, want ErrorNotFound", err)
+	}
+	if err := store.DeleteExampleRecord(ctx, "EX-1"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("DeleteExampleRecord() twice error = %v, want ErrorNotFound", err)
+	}
+	if err := store.ReplaceExampleRecordVersion(ctx, "missing", 0, &domain.ExampleRecord{}); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("ReplaceExampleRecordVersion() error = %v, want ErrorNotFound", err)
+	}
+}
+
+func TestSQLiteStoreReplaceExampleRecordVersion(t *testing.T) {
+	t.Parallel()
+	assertReplaceExampleRecordVersion(t, newTestSQLiteStore(t, SQLiteMemoryPath))
+}
+
+func TestSQLiteStoreListExampleRecords(t *testing.T) {
+	t.Parallel()
+	assertSQLListExampleRecords(t, newTestSQLiteStore(t, SQLiteMemoryPath))
+}
+
+// assertSQLListExampleRecords pages and filters records stored in an empty store.
+func assertSQLListExampleRecords(t *testing.T, store *SQLStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	for i := 1; i <= 5; i++ {
+		_ = store.StoreExampleRecord(ctx, &domain.ExampleRecord{
+			RecordId: fmt.Sprintf("EX-%d", i),
+			Tags:     []string{"all", fmt.Sprintf("tag-%d", i%2)},
+			Meta: &domain.ExampleMeta{
+				Priority:         int32(i % 2),
+				RequestedBy:      "bot",
+				DesiredStartDate: &date.Date{Year: 2025, Month: int32(i), Day: 1},
+			},
+		})
+	}
+
+	var ids []string
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		records, next, err := store.ListExampleRecords(ctx, ExampleRecordFilter{Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("ListExampleRecords() error = %v", err)
+		}
+		for _, record := range records {
+			ids = append(ids, record.GetRecordId())
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if want := "[EX-1 EX-2 EX-3 EX-4 EX-5]"; fmt.Sprint(ids) != want {
+		t.Errorf("paged ids = %v, want %s", ids, want)
+	}
+
+	testCases := []struct {
+		name   string
+		filter ExampleRecordFilter
+		want   int
+	}{
+		{"all tags", ExampleRecordFilter{Tags: []string{"all", "tag-1"}}, 3},
+		{"priority", ExampleRecordFilter{Priority: 1}, 3},
+		{"requested by", ExampleRecordFilter{RequestedBy: "nobody"}, 0},
+		{"date range", ExampleRecordFilter{
+			DesiredStartFrom: &date.Date{Year: 2025, Month: 2, Day: 1},
+			DesiredStartTo:   &date.Date{Year: 2025, Month: 3, Day: 31},
+		}, 2},
+	}
+	for _, tc := range testCases {
+		records, _, err := store.ListExampleRecords(ctx, tc.filter)
+		if err != nil {
+			t.Fatalf("%s: ListExampleRecords() error = %v", tc.name, err)
+		}
+		if len(records) != tc.want {
+			t.Errorf("%s: got %d records, want %d", tc.name, len(records), tc.want)
+		}
+	}
+}
+
+func TestSQLiteStoreOutbox(t *testing.T) {
+	t.Parallel()
+	assertSQLOutbox(t, newTestSQLiteStore(t, SQLiteMemoryPath))
+}
+
+// assertSQLOutbox claims, sends and retries outbox rows of an empty store.
+func assertSQLOutbox(t *testing.T, store *SQLStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	if err := store.StoreOutboxMessage(ctx, &OutboxMessage{Handler: "handler", UUID: "id-1", Topic: "topic", Payload: "payload"}); err != nil {
+		t.Fatalf("StoreOutboxMessage() error = %v", err)
+	}
+	_ = store.StoreOutboxMessage(ctx, &OutboxMessage{
+		Handler: "handler", UUID: "id-2", Metadata: map[string]string{"k": "v"}, NextAttemptAt: now.Add(time.Hour),
+	})
+
+	handlers, err := store.OutboxHandlers(ctx)
+	if err != nil || len(handlers) != 1 || handlers[0] != "handler" {
+		t.Fatalf("OutboxHandlers() = %v, %v", handlers, err)
+	}
+
+	claimed, err := store.ClaimOutboxMessages(ctx, "handler", now.Add(time.Second), time.Minute, 10)
+	if err != nil || len(claimed) != 1 || claimed[0].UUID != "id-1" || claimed[0].Payload != "payload" {
+		t.Fatalf("ClaimOutboxMessages() = %+v, %v; want only id-1", claimed, err)
+	}
+	if again, _ := store.ClaimOutboxMessages(ctx, "handler", now.Add(time.Second), time.Minute, 10); len(again) != 0 {
+		t.Error("leased rows must not be claimed twice")
+	}
+
+	if err := store.MarkOutboxMessageSent(ctx, "handler", "id-1", now); err != nil {
+		t.Fatalf("MarkOutboxMessageSent() error = %v", err)
+	}
+	var sent int
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM outbox_messages WHERE uuid = 'id-1'").Scan(&sent); err != nil || sent != 0 {
+		t.Errorf("sent rows = %d, %v; want the sent row deleted", sent, err)
+	}
+	if err := store.MarkOutboxMessageFailed(ctx, "handler", "id-2", "boom", now, false); err != nil {
+		t.Fatalf("MarkOutboxMessageFailed() error = %v", err)
+	}
+
+	retried, err := store.ClaimOutboxMessages(ctx, "handler", now.Add(time.Second), time.Minute, 10)
+	if err != nil || len(retried) != 1 {
+		t.Fatalf("ClaimOutboxMessages() after failure = %+v, %v", retried, err)
+	}
+	if row := retried[0]; row.UUID != "id-2" || row.LastError != "boom" || row.Attempts != 1 || row.Metadata["k"] != "v" {
+		t.Errorf("unexpected retried row: %+v", row)
+	}
+}
+
+func TestSQLiteStoreClaimsKeepOtherExpiredRows(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestSQLiteStore(t, SQLiteMemoryPath)
+	now := time.Now().UTC()
+
+	_, _ = store.ClaimInboxMessage(ctx, "handler", "abandoned", now, time.Minute)
+	_, _ = store.ClaimInboxMessage(ctx, "handler", "done", now, time.Minute)
+	_ = store.CompleteInboxMessage(ctx, "handler", "done", now.Add(time.Hour))
+	_, _, _ = store.ClaimIdempotencyKey(ctx, "abandoned", "fp", now, time.Minute)
+
+	// A claim only touches the row it claims; expired rows are left to the sweep.
+	later := now.Add(2 * time.Minute)
+	_, _ = store.ClaimInboxMessage(ctx, "handler", "new", later, time.Minute)
+	_, _, _ = store.ClaimIdempotencyKey(ctx, "new", "fp", later, time.Minute)
+	assertSQLRowCount(t, store, "inbox_messages", 3)
+	assertSQLRowCount(t, store, "idempotency_keys", 2)
+
+	if err := store.deleteExpired(ctx, later); err != nil {
+		t.Fatalf("deleteExpired() error = %v", err)
+	}
+	assertSQLRowCount(t, store, "inbox_messages", 2)
+	assertSQLRowCount(t, store, "idempotency_keys", 1)
+}
+
+func TestSQLiteStoreSweepsExpiredRows(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store, err := NewSQLiteStore(ctx, &Config{Driver: DriverSQLite, SQLitePath: SQLiteMemoryPath, ExpirySweepInterval: 10 * time.Millisecond}, logger)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	_, _ = store.ClaimInboxMessage(ctx, "handler", "expired", time.Now().UTC().Add(-time.Hour), time.Minute)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for countSQLRows(t, store, "inbox_messages") > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the sweep did not delete the expired inbox entry")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := store.Close(ctx); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func countSQLRows(t *testing.T, store *SQLStore, table string) int {
+	t.Helper()
+	var rows int
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&rows); err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return rows
+}
+
+func assertSQLRowCount(t *testing.T, store *SQLStore, table string, want int) {
+	t.Helper()
+	if got := countSQLRows(t, store, table); got != want {
+		t.Errorf("%s has %d rows, want %d", table, got, want)
+	}
+}
+
+func TestSQLiteStoreFileMigratesOnce(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "records.db")
+	first := newTestSQLiteStore(t, path)
+	_ = first.StoreExampleRecord(context.Background(), &domain.ExampleRecord{RecordId: "EX-1"})
+	_ = first.Close(context.Background())
+
+	second := newTestSQLiteStore(t, path)
+	if _, err := second.GetExampleRecordByID(context.Background(), "EX-1"); err != nil {
+		t.Fatalf("record lost after reopening: %v", err)
+	}
+
+	var versions int
+	if err := second.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&versions); err != nil {
+		t.Fatalf("query schema_migrations: %v", err)
+	}
+	if versions != len(sqliteDialect.migrations) {
+		t.Errorf("schema_migrations has %d rows, want %d", versions, len(sqliteDialect.migrations))
+	}
+}
+
+func TestOpenSQLiteDriver(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo, err := Open(context.Background(), &Config{Driver: "sqlite", SQLitePath: SQLiteMemoryPath}, logger)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer func() { _ = repo.Close(context.Background()) }()
+	if _, ok := repo.(*SQLStore); !ok {
+		t.Errorf("Open() returned %T, want *SQLStore", repo)
+	}
+	if _, err := Open(context.Background(), &Config{Driver: "sqlite"}, logger); err == nil {
+		t.Error("Open() without SQLITE_PATH should fail")
+	}
+}
+
+func TestSQLiteStoreExampleRecordWithOutboxIsAtomic(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestSQLiteStore(t, SQLiteMemoryPath)
+
+	msg := &OutboxMessage{Handler: "handler", UUID: "id-1", Topic: "topic"}
+	if err := store.StoreExampleRecordWithOutbox(ctx, &domain.ExampleRecord{RecordId: "EX-1"}, msg); err != nil {
+		t.Fatalf("StoreExampleRecordWithOutbox() error = %v", err)
+	}
+
+	// Reusing the outbox key fails the second insert, which must roll back the record.
+	duplicate := &OutboxMessage{Handler: "handler", UUID: "id-1", Topic: "topic"}
+	if err := store.StoreExampleRecordWithOutbox(ctx, &domain.ExampleRecord{RecordId: "EX-2"}, duplicate); err == nil {
+		t.Fatal("expected duplicate outbox row to fail")
+	}
+	if _, err := store.GetExampleRecordByID(ctx, "EX-2"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("record of a failed transaction was stored: %v", err)
+	}
+
+	claimed, err := store.ClaimOutboxMessages(ctx, "handler", time.Now().Add(time.Second), time.Minute, 10)
+	if err != nil || len(claimed) != 1 {
+		t.Errorf("ClaimOutboxMessages() = %+v, %v; want the committed row", claimed, err)
+	}
+}
+
+func TestSQLiteStoreConflictPolicy(t *testing.T) {
+	t.Parallel()
+	assertSQLConflictPolicy(t, func() *SQLStore { return newTestSQLiteStore(t, SQLiteMemoryPath) })
+}
+
+// assertSQLConflictPolicy stores a record twice under each conflict policy, using
+// an empty store from newStore for every policy.
+func assertSQLConflictPolicy(t *testing.T, newStore func() *SQLStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	testCases := []struct {
+		policy    ConflictPolicy
+		wantErr   error
+		wantTitle string
+	}{
+		{ConflictReject, domain.ErrorConflict, "first"},
+		{ConflictOverwrite, nil, ""},
+		{ConflictMerge, nil, "first"},
+	}
+
+	for _, tc := range testCases {
+		store := newStore()
+		store.conflictPolicy = tc.policy
+		_ = store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: "EX-1", Title: "first", Tags: []string{"a"}})
+
+		msg := &OutboxMessage{Handler: "handler", UUID: "id-1"}
+		err := store.StoreExampleRecordWithOutbox(ctx, &domain.ExampleRecord{RecordId: "EX-1", Description: "second", Tags: []string{"b"}}, msg)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%q: StoreExampleRecordWithOutbox() error = %v, want %v", tc.policy, err, tc.wantErr)
+		}
+		got, _ := store.GetExampleRecordByID(ctx, "EX-1")
+		if got.GetTitle() != tc.wantTitle {
+			t.Errorf("%q: Title = %q, want %q", tc.policy, got.GetTitle(), tc.wantTitle)
+		}
+		if tc.policy == ConflictMerge {
+			if records, _, _ := store.ListExampleRecords(ctx, ExampleRecordFilter{Tags: []string{"a", "b"}}); len(records) != 1 {
+				t.Error("merged record should carry both tag sets")
+			}
+		}
+	}
+}
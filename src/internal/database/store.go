@@ -2,38 +2,304 @@ package database
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	date "google.golang.org/genproto/googleapis/type/date"
 
 	"drblury/event-driven-service/internal/domain"
 )
 
 const exampleCollection = "example-records"
 
-func (db *Database) StoreOutgoingMessage(ctx context.Context, handler string, uuid string, payload string) error {
-	_, err := db.DB.Collection(handler+"_outbox").InsertOne(ctx, bson.M{
-		"handler": handler,
-		"uuid":    uuid,
-		"payload": payload,
-	})
-	return err
+const recordIDIndexName = "recordid_unique"
+
+// Document keys of stored example records. Proto structs carry no bson tags, so the
+// driver stores every field under its lowercased Go name.
+const (
+	recordIDField         = "recordid"
+	tagsField             = "tags"
+	versionField          = "version"
+	priorityField         = "meta.priority"
+	requestedByField      = "meta.requestedby"
+	desiredStartDateField = "$meta.desiredstartdate"
+)
+
+// DefaultExampleRecordPageSize is used when a list request does not specify a limit.
+const DefaultExampleRecordPageSize = 20
+
+// ErrExampleRecordNotFound is returned when no example record matches the requested ID.
+var ErrExampleRecordNotFound = fmt.Errorf("example record %w", domain.ErrorNotFound)
+
+// ErrExampleRecordVersionConflict is returned when a conditional replace finds that the
+// stored record has a different version than the one that was read.
+var ErrExampleRecordVersionConflict = fmt.Errorf("example record was changed concurrently: %w", domain.ErrorConflict)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = fmt.Errorf("invalid cursor: %w", domain.ErrorBadRequest)
+
+// ExampleRecordFilter narrows down and paginates ListExampleRecords.
+type ExampleRecordFilter struct {
+	Tags             []string
+	Priority         int32
+	RequestedBy      string
+	DesiredStartFrom *date.Date
+	DesiredStartTo   *date.Date
+	Cursor           string
+	Limit            int
 }
 
+// StoreExampleRecord saves a new record. An existing record with the same ID is
+// handled according to the conflict policy.
 func (db *Database) StoreExampleRecord(ctx context.Context, record *domain.ExampleRecord) error {
 	if record == nil {
 		return errors.New("example record is required")
 	}
-	_, err := db.DB.Collection(exampleCollection).InsertOne(ctx, record)
+	return db.storeExampleRecord(ctx, record)
+}
+
+// storeExampleRecord writes through filtered upserts instead of a lookup followed
+// by an unconditional insert or replace, so concurrent writers of the same ID
+// cannot overwrite each other. Upserts also avoid duplicate key errors, which
+// abort a surrounding transaction, except when two first writers race; the loser
+// then resolves the conflict like any later writer.
+func (db *Database) storeExampleRecord(ctx context.Context, record *domain.ExampleRecord) error {
+	collection := db.DB.Collection(exampleCollection)
+	filter := bson.M{recordIDField: record.GetRecordId()}
+
+	policy := db.Cfg.conflictPolicy()
+	for {
+		inserted, err := collection.UpdateOne(ctx, filter, bson.M{"$setOnInsert": record}, options.Update().SetUpsert(true))
+		if mongo.IsDuplicateKeyError(err) {
+			// Another writer inserted the record after this upsert found none.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if inserted.UpsertedCount > 0 {
+			return nil
+		}
+		if policy == ConflictReject {
+			return ErrExampleRecordExists
+		}
+
+		var stored bson.Raw
+		err = collection.FindOne(ctx, filter).Decode(&stored)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var existing domain.ExampleRecord
+		if err := bson.Unmarshal(stored, &existing); err != nil {
+			return err
+		}
+
+		resolved, err := policy.resolveConflict(&existing, record)
+		if err != nil {
+			return err
+		}
+		// Filtering on the document that was resolved against makes the replace miss
+		// when another writer changed it in between; the conflict is then resolved again.
+		replaced, err := collection.ReplaceOne(ctx, stored, resolved)
+		if err != nil {
+			return err
+		}
+		if replaced.MatchedCount > 0 {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// StoreExampleRecordWithOutbox inserts the record and the outbox row in one session
+// transaction. Transactions require MongoDB to run as a replica set.
+func (db *Database) StoreExampleRecordWithOutbox(ctx context.Context, record *domain.ExampleRecord, msg *OutboxMessage) error {
+	if record == nil {
+		return errors.New("example record is required")
+	}
+	if err := prepareOutboxMessage(msg); err != nil {
+		return err
+	}
+
+	session, err := db.DB.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		if err := db.storeExampleRecord(sc, record); err != nil {
+			return nil, err
+		}
+		_, err := db.DB.Collection(msg.Handler+outboxCollectionSuffix).InsertOne(sc, msg)
+		return nil, err
+	})
 	return err
 }
 
 func (db *Database) GetExampleRecordByID(ctx context.Context, id string) (*domain.ExampleRecord, error) {
 	var result domain.ExampleRecord
-	err := db.DB.Collection(exampleCollection).FindOne(ctx, bson.M{"record_id": id}).Decode(&result)
+	err := db.DB.Collection(exampleCollection).FindOne(ctx, bson.M{recordIDField: id}).Decode(&result)
 	if err == mongo.ErrNoDocuments {
-		return nil, errors.New("example record not found")
+		return nil, ErrExampleRecordNotFound
 	}
 	return &result, err
 }
+
+// ListExampleRecords returns one page of records ordered by record ID together with
+// the cursor of the next page, which is empty once the last page was reached.
+func (db *Database) ListExampleRecords(ctx context.Context, filter ExampleRecordFilter) ([]*domain.ExampleRecord, string, error) {
+	query, err := exampleRecordQuery(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultExampleRecordPageSize
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: recordIDField, Value: 1}}).
+		SetLimit(int64(limit) + 1)
+
+	cursor, err := db.DB.Collection(exampleCollection).Find(ctx, query, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	records := make([]*domain.ExampleRecord, 0, limit+1)
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, "", err
+	}
+
+	if len(records) <= limit {
+		return records, "", nil
+	}
+	records = records[:limit]
+	return records, EncodeCursor(records[limit-1].GetRecordId()), nil
+}
+
+// ReplaceExampleRecordVersion overwrites the stored record with the given ID while it
+// still has version.
+func (db *Database) ReplaceExampleRecordVersion(ctx context.Context, id string, version int32, record *domain.ExampleRecord) error {
+	if record == nil {
+		return errors.New("example record is required")
+	}
+	collection := db.DB.Collection(exampleCollection)
+	result, err := collection.ReplaceOne(ctx, bson.M{recordIDField: id, versionField: versionQuery(version)}, record)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+	if err := collection.FindOne(ctx, bson.M{recordIDField: id}).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrExampleRecordNotFound
+		}
+		return err
+	}
+	return ErrExampleRecordVersionConflict
+}
+
+// versionQuery matches the stored version. Records stored before they were versioned
+// have no version field and are read as version 0, so version 0 also matches them.
+func versionQuery(version int32) any {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// DeleteExampleRecord removes the stored record with the given ID.
+func (db *Database) DeleteExampleRecord(ctx context.Context, id string) error {
+	result, err := db.DB.Collection(exampleCollection).DeleteOne(ctx, bson.M{recordIDField: id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrExampleRecordNotFound
+	}
+	return nil
+}
+
+// exampleRecordQuery translates the filter into a MongoDB query document.
+func exampleRecordQuery(filter ExampleRecordFilter) (bson.M, error) {
+	query := bson.M{}
+
+	if filter.Cursor != "" {
+		after, err := DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query[recordIDField] = bson.M{"$gt": after}
+	}
+	if len(filter.Tags) > 0 {
+		query[tagsField] = bson.M{"$all": filter.Tags}
+	}
+	if filter.Priority != 0 {
+		query[priorityField] = filter.Priority
+	}
+	if filter.RequestedBy != "" {
+		query[requestedByField] = filter.RequestedBy
+	}
+
+	var dateBounds bson.A
+	if filter.DesiredStartFrom != nil {
+		dateBounds = append(dateBounds, bson.M{"$gte": bson.A{desiredStartDateOrdinal(), dateOrdinal(filter.DesiredStartFrom)}})
+	}
+	if filter.DesiredStartTo != nil {
+		dateBounds = append(dateBounds, bson.M{"$lte": bson.A{desiredStartDateOrdinal(), dateOrdinal(filter.DesiredStartTo)}})
+	}
+	if len(dateBounds) > 0 {
+		query["$expr"] = bson.M{"$and": dateBounds}
+	}
+
+	return query, nil
+}
+
+// desiredStartDateOrdinal folds the stored date into a sortable YYYYMMDD number.
+func desiredStartDateOrdinal() bson.M {
+	return bson.M{"$add": bson.A{
+		bson.M{"$multiply": bson.A{desiredStartDateField + ".year", 10000}},
+		bson.M{"$multiply": bson.A{desiredStartDateField + ".month", 100}},
+		desiredStartDateField + ".day",
+	}}
+}
+
+// dateOrdinal returns the YYYYMMDD representation of the date.
+func dateOrdinal(d *date.Date) int32 {
+	return d.GetYear()*10000 + d.GetMonth()*100 + d.GetDay()
+}
+
+// dateFromOrdinal reverses dateOrdinal. The zero ordinal stands for no date.
+func dateFromOrdinal(ordinal int32) *date.Date {
+	if ordinal == 0 {
+		return nil
+	}
+	return &date.Date{Year: ordinal / 10000, Month: ordinal / 100 % 100, Day: ordinal % 100}
+}
+
+// EncodeCursor turns the last record ID of a page into an opaque pagination cursor.
+func EncodeCursor(recordID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(recordID))
+}
+
+// DecodeCursor reverses EncodeCursor.
+func DecodeCursor(cursor string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(decoded) == 0 {
+		return "", ErrInvalidCursor
+	}
+	return string(decoded), nil
+}
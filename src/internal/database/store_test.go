@@ -2,9 +2,14 @@ package database
 
 import (
 	"context"
+	"errors"
+	"reflect"
 	"testing"
 
 	"drblury/event-driven-service/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	date "google.golang.org/genproto/googleapis/type/date"
 )
 
 func TestStoreExampleRecordNilRecord(t *testing.T) {
@@ -111,7 +116,7 @@ func runStoreExampleRecordTest(t *testing.T, db *Database, record *domain.Exampl
 	}()
 }
 
-func TestStoreOutgoingMessageNilDB(t *testing.T) {
+func TestStoreOutboxMessageNilDB(t *testing.T) {
 	t.Parallel()
 
 	db := &Database{
@@ -119,18 +124,18 @@ func TestStoreOutgoingMessageNilDB(t *testing.T) {
 		Cfg: &Config{},
 	}
 
-	// StoreOutgoingMessage will panic when DB is nil
+	// StoreOutboxMessage will panic when DB is nil
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Log("StoreOutgoingMessage did not panic with nil DB")
+				t.Log("StoreOutboxMessage did not panic with nil DB")
 			}
 		}()
-		_ = db.StoreOutgoingMessage(context.Background(), "handler", "uuid", "payload")
+		_ = db.StoreOutboxMessage(context.Background(), &OutboxMessage{Handler: "handler", UUID: "uuid", Payload: "payload", Topic: "topic"})
 	}()
 }
 
-func TestStoreOutgoingMessageVariousInputs(t *testing.T) {
+func TestStoreOutboxMessageVariousInputs(t *testing.T) {
 	t.Parallel()
 
 	db := &Database{
@@ -143,12 +148,14 @@ func TestStoreOutgoingMessageVariousInputs(t *testing.T) {
 		handler string
 		uuid    string
 		payload string
+		wantErr bool
 	}{
 		{
 			name:    "empty values",
 			handler: "",
 			uuid:    "",
 			payload: "",
+			wantErr: true,
 		},
 		{
 			name:    "normal values",
@@ -177,7 +184,10 @@ func TestStoreOutgoingMessageVariousInputs(t *testing.T) {
 				defer func() {
 					_ = recover()
 				}()
-				_ = db.StoreOutgoingMessage(context.Background(), tc.handler, tc.uuid, tc.payload)
+				err := db.StoreOutboxMessage(context.Background(), &OutboxMessage{Handler: tc.handler, UUID: tc.uuid, Payload: tc.payload, Topic: "topic"})
+				if tc.wantErr && err == nil {
+					t.Error("Expected error for a message without handler")
+				}
 			}()
 		})
 	}
@@ -251,7 +261,7 @@ func TestStoreExampleRecordWithMeta(t *testing.T) {
 			RequestedBy:      "test-automation",
 			RequiresFollowUp: true,
 			Priority:         10,
-			DesiredStartDate: &domain.Date{
+			DesiredStartDate: &date.Date{
 				Year:  2024,
 				Month: 12,
 				Day:   25,
@@ -355,7 +365,7 @@ func TestGetExampleRecordByIDCancelledContext(t *testing.T) {
 	}()
 }
 
-func TestStoreOutgoingMessageCancelledContext(t *testing.T) {
+func TestStoreOutboxMessageCancelledContext(t *testing.T) {
 	t.Parallel()
 
 	db := &Database{
@@ -370,7 +380,7 @@ func TestStoreOutgoingMessageCancelledContext(t *testing.T) {
 		defer func() {
 			_ = recover()
 		}()
-		_ = db.StoreOutgoingMessage(ctx, "handler", "uuid", "payload")
+		_ = db.StoreOutboxMessage(ctx, &OutboxMessage{Handler: "handler", UUID: "uuid", Payload: "payload", Topic: "topic"})
 	}()
 }
 
@@ -391,7 +401,7 @@ func TestDatabaseMethodsWithNilDatabase(t *testing.T) {
 		}()
 	})
 
-	t.Run("StoreOutgoingMessage on nil database", func(t *testing.T) {
+	t.Run("StoreOutboxMessage on nil database", func(t *testing.T) {
 		t.Parallel()
 		func() {
 			defer func() {
@@ -399,7 +409,7 @@ func TestDatabaseMethodsWithNilDatabase(t *testing.T) {
 					t.Log("Did not panic as expected")
 				}
 			}()
-			_ = db.StoreOutgoingMessage(context.Background(), "h", "u", "p")
+			_ = db.StoreOutboxMessage(context.Background(), &OutboxMessage{Handler: "h", UUID: "u", Payload: "p", Topic: "t"})
 		}()
 	})
 
@@ -415,3 +425,80 @@ func TestDatabaseMethodsWithNilDatabase(t *testing.T) {
 		}()
 	})
 }
+
+func TestCursorRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	cursor := EncodeCursor("EX-0042")
+	decoded, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if decoded != "EX-0042" {
+		t.Errorf("DecodeCursor() = %q, want 'EX-0042'", decoded)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, cursor := range []string{"!!!", ""} {
+		if _, err := DecodeCursor(cursor); !errors.Is(err, domain.ErrorBadRequest) {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrorBadRequest", cursor, err)
+		}
+	}
+}
+
+func TestExampleRecordQuery(t *testing.T) {
+	t.Parallel()
+
+	query, err := exampleRecordQuery(ExampleRecordFilter{
+		Tags:             []string{"demo"},
+		Priority:         2,
+		RequestedBy:      "bot",
+		DesiredStartFrom: &date.Date{Year: 2025, Month: 1, Day: 1},
+		Cursor:           EncodeCursor("EX-1"),
+	})
+	if err != nil {
+		t.Fatalf("exampleRecordQuery() error = %v", err)
+	}
+
+	for _, key := range []string{recordIDField, tagsField, priorityField, requestedByField, "$expr"} {
+		if _, ok := query[key]; !ok {
+			t.Errorf("expected query to contain %q, got %v", key, query)
+		}
+	}
+}
+
+func TestExampleRecordQueryEmptyFilter(t *testing.T) {
+	t.Parallel()
+
+	query, err := exampleRecordQuery(ExampleRecordFilter{})
+	if err != nil {
+		t.Fatalf("exampleRecordQuery() error = %v", err)
+	}
+	if len(query) != 0 {
+		t.Errorf("expected empty query, got %v", query)
+	}
+}
+
+func TestVersionQuery(t *testing.T) {
+	t.Parallel()
+
+	if got := versionQuery(3); got != int32(3) {
+		t.Errorf("versionQuery(3) = %v, want 3", got)
+	}
+	// Records stored before versioning have no version field.
+	unversioned, ok := versionQuery(0).(bson.M)
+	if !ok || !reflect.DeepEqual(unversioned["$in"], bson.A{0, nil}) {
+		t.Errorf("versionQuery(0) = %v, want a match on 0 or a missing version", versionQuery(0))
+	}
+}
+
+func TestDateOrdinal(t *testing.T) {
+	t.Parallel()
+
+	if got := dateOrdinal(&date.Date{Year: 2025, Month: 4, Day: 18}); got != 20250418 {
+		t.Errorf("dateOrdinal() = %d, want 20250418", got)
+	}
+}
@@ -0,0 +1,301 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: domain/v1/batch.proto
+
+package domain
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// BatchRequest asks the service to process several stored example records.
+type BatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId" json:"batch_id,omitempty"`
+	RecordIds     []string               `protobuf:"bytes,2,rep,name=record_ids,json=recordIds" json:"record_ids,omitempty"`
+	Options       *BatchOptions          `protobuf:"bytes,3,opt,name=options" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRequest) Reset() {
+	*x = BatchRequest{}
+	mi := &file_domain_v1_batch_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRequest) ProtoMessage() {}
+
+func (x *BatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_domain_v1_batch_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRequest.ProtoReflect.Descriptor instead.
+func (*BatchRequest) Descriptor() ([]byte, []int) {
+	return file_domain_v1_batch_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BatchRequest) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *BatchRequest) GetRecordIds() []string {
+	if x != nil {
+		return x.RecordIds
+	}
+	return nil
+}
+
+func (x *BatchRequest) GetOptions() *BatchOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// BatchOptions controls how the records of a batch are processed.
+type BatchOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// parallel processes the records concurrently instead of in request order.
+	Parallel bool `protobuf:"varint,1,opt,name=parallel" json:"parallel,omitempty"`
+	// stop_on_error skips the remaining records after the first failure.
+	StopOnError   bool `protobuf:"varint,2,opt,name=stop_on_error,json=stopOnError" json:"stop_on_error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchOptions) Reset() {
+	*x = BatchOptions{}
+	mi := &file_domain_v1_batch_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchOptions) ProtoMessage() {}
+
+func (x *BatchOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_domain_v1_batch_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchOptions.ProtoReflect.Descriptor instead.
+func (*BatchOptions) Descriptor() ([]byte, []int) {
+	return file_domain_v1_batch_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BatchOptions) GetParallel() bool {
+	if x != nil {
+		return x.Parallel
+	}
+	return false
+}
+
+func (x *BatchOptions) GetStopOnError() bool {
+	if x != nil {
+		return x.StopOnError
+	}
+	return false
+}
+
+// BatchResult is emitted once every record of a batch was processed or skipped.
+type BatchResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BatchId   string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId" json:"batch_id,omitempty"`
+	Total     int32                  `protobuf:"varint,2,opt,name=total" json:"total,omitempty"`
+	Succeeded int32                  `protobuf:"varint,3,opt,name=succeeded" json:"succeeded,omitempty"`
+	Failed    int32                  `protobuf:"varint,4,opt,name=failed" json:"failed,omitempty"`
+	// results holds the outcome of every processed or skipped record in request order.
+	Results       []*ExampleResult   `protobuf:"bytes,5,rep,name=results" json:"results,omitempty"`
+	Errors        []*ProcessingError `protobuf:"bytes,6,rep,name=errors" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_domain_v1_batch_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_domain_v1_batch_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_domain_v1_batch_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchResult) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *BatchResult) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *BatchResult) GetSucceeded() int32 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *BatchResult) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *BatchResult) GetResults() []*ExampleResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchResult) GetErrors() []*ProcessingError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+var File_domain_v1_batch_proto protoreflect.FileDescriptor
+
+const file_domain_v1_batch_proto_rawDesc = "" +
+	"\n" +
+	"\x15domain/v1/batch.proto\x12\tdomain.v1\x1a\x17domain/v1/example.proto\"{\n" +
+	"\fBatchRequest\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x12\x1d\n" +
+	"\n" +
+	"record_ids\x18\x02 \x03(\tR\trecordIds\x121\n" +
+	"\aoptions\x18\x03 \x01(\v2\x17.domain.v1.BatchOptionsR\aoptions\"N\n" +
+	"\fBatchOptions\x12\x1a\n" +
+	"\bparallel\x18\x01 \x01(\bR\bparallel\x12\"\n" +
+	"\rstop_on_error\x18\x02 \x01(\bR\vstopOnError\"\xdc\x01\n" +
+	"\vBatchResult\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x1c\n" +
+	"\tsucceeded\x18\x03 \x01(\x05R\tsucceeded\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x05R\x06failed\x122\n" +
+	"\aresults\x18\x05 \x03(\v2\x18.domain.v1.ExampleResultR\aresults\x122\n" +
+	"\x06errors\x18\x06 \x03(\v2\x1a.domain.v1.ProcessingErrorR\x06errorsB3Z,drblury/event-driven-service/internal/domain\x92\x03\x02\b\x02b\beditionsp\xe8\a"
+
+var (
+	file_domain_v1_batch_proto_rawDescOnce sync.Once
+	file_domain_v1_batch_proto_rawDescData []byte
+)
+
+func file_domain_v1_batch_proto_rawDescGZIP() []byte {
+	file_domain_v1_batch_proto_rawDescOnce.Do(func() {
+		file_domain_v1_batch_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_domain_v1_batch_proto_rawDesc), len(file_domain_v1_batch_proto_rawDesc)))
+	})
+	return file_domain_v1_batch_proto_rawDescData
+}
+
+var file_domain_v1_batch_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_domain_v1_batch_proto_goTypes = []any{
+	(*BatchRequest)(nil),    // 0: domain.v1.BatchRequest
+	(*BatchOptions)(nil),    // 1: domain.v1.BatchOptions
+	(*BatchResult)(nil),     // 2: domain.v1.BatchResult
+	(*ExampleResult)(nil),   // 3: domain.v1.ExampleResult
+	(*ProcessingError)(nil), // 4: domain.v1.ProcessingError
+}
+var file_domain_v1_batch_proto_depIdxs = []int32{
+	1, // 0: domain.v1.BatchRequest.options:type_name -> domain.v1.BatchOptions
+	3, // 1: domain.v1.BatchResult.results:type_name -> domain.v1.ExampleResult
+	4, // 2: domain.v1.BatchResult.errors:type_name -> domain.v1.ProcessingError
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_domain_v1_batch_proto_init() }
+func file_domain_v1_batch_proto_init() {
+	if File_domain_v1_batch_proto != nil {
+		return
+	}
+	file_domain_v1_example_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_domain_v1_batch_proto_rawDesc), len(file_domain_v1_batch_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_domain_v1_batch_proto_goTypes,
+		DependencyIndexes: file_domain_v1_batch_proto_depIdxs,
+		MessageInfos:      file_domain_v1_batch_proto_msgTypes,
+	}.Build()
+	File_domain_v1_batch_proto = out.File
+	file_domain_v1_batch_proto_goTypes = nil
+	file_domain_v1_batch_proto_depIdxs = nil
+}
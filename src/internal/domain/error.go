@@ -12,18 +12,42 @@ import (
 var (
 	ErrorNotFound        = errors.New("not found")
 	ErrorBadRequest      = errors.New("something you provided was wrong")
+	ErrorConflict        = errors.New("conflicts with the current state")
+	ErrorUnauthorized    = errors.New("authentication required")
+	ErrorForbidden       = errors.New("not allowed")
+	ErrorUnprocessable   = errors.New("cannot be processed")
+	ErrorTooManyRequests = errors.New("too many requests")
 	ErrorUpstreamService = errors.New("upstream service error")
 	ErrorNotImplemented  = errors.New("not implemented")
 	ErrorInternal        = errors.New("internal error")
 )
 
+// Violation describes one validation rule a payload violates.
+type Violation struct {
+	// Field is the path of the offending field, for example meta.priority or tags[2].
+	Field string
+	// Rule identifies the violated rule, for example string.min_len.
+	Rule    string
+	Message string
+	// Value holds the offending scalar value, or nil for messages, lists and maps.
+	Value any
+}
+
+func (v Violation) String() string {
+	if v.Field == "" {
+		return v.Message
+	}
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+// ErrValidations lists the violations of a payload that failed validation.
 type ErrValidations struct {
-	Errors []string
+	Errors []Violation
 }
 
 func (e ErrValidations) Error() string {
 	// Join the errors with a separator and number them
-	numbered := lo.Map(e.Errors, func(v string, i int) string {
+	numbered := lo.Map(e.Errors, func(v Violation, i int) string {
 		return fmt.Sprintf("%d: %s", i+1, v)
 	})
 	return strings.Join(numbered, " - ")
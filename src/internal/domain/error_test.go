@@ -14,6 +14,11 @@ func TestPredefinedErrors(t *testing.T) {
 	}{
 		{"ErrorNotFound", ErrorNotFound, "not found"},
 		{"ErrorBadRequest", ErrorBadRequest, "something you provided was wrong"},
+		{"ErrorConflict", ErrorConflict, "conflicts with the current state"},
+		{"ErrorUnauthorized", ErrorUnauthorized, "authentication required"},
+		{"ErrorForbidden", ErrorForbidden, "not allowed"},
+		{"ErrorUnprocessable", ErrorUnprocessable, "cannot be processed"},
+		{"ErrorTooManyRequests", ErrorTooManyRequests, "too many requests"},
 		{"ErrorUpstreamService", ErrorUpstreamService, "upstream service error"},
 		{"ErrorNotImplemented", ErrorNotImplemented, "not implemented"},
 		{"ErrorInternal", ErrorInternal, "internal error"},
@@ -31,29 +36,34 @@ func TestPredefinedErrors(t *testing.T) {
 func TestErrValidationsError(t *testing.T) {
 	tests := []struct {
 		name   string
-		errors []string
+		errors []Violation
 		want   string
 	}{
 		{
 			name:   "single error",
-			errors: []string{"field is required"},
+			errors: []Violation{{Message: "field is required"}},
 			want:   "1: field is required",
 		},
 		{
 			name:   "multiple errors",
-			errors: []string{"field1 is required", "field2 must be positive"},
+			errors: []Violation{{Message: "field1 is required"}, {Message: "field2 must be positive"}},
 			want:   "1: field1 is required - 2: field2 must be positive",
 		},
+		{
+			name:   "field paths",
+			errors: []Violation{{Field: "meta.priority", Rule: "int32.lte", Message: "value must be less than or equal to 5", Value: int32(7)}},
+			want:   "1: meta.priority: value must be less than or equal to 5",
+		},
 		{
 			name:   "empty errors",
-			errors: []string{},
+			errors: []Violation{},
 			want:   "",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			errsCopy := make([]string, len(tt.errors))
+			errsCopy := make([]Violation, len(tt.errors))
 			copy(errsCopy, tt.errors)
 
 			e := ErrValidations{Errors: errsCopy}
@@ -66,7 +76,7 @@ func TestErrValidationsError(t *testing.T) {
 }
 
 func TestErrValidationsImplementsError(t *testing.T) {
-	var _ error = ErrValidations{Errors: []string{"test"}}
+	var _ error = ErrValidations{Errors: []Violation{{Message: "test"}}}
 	// If this compiles, ErrValidations implements error interface
 }
 
@@ -84,10 +94,10 @@ func TestErrorsIs(t *testing.T) {
 
 func TestErrValidationsMultipleErrors(t *testing.T) {
 	e := ErrValidations{
-		Errors: []string{
-			"name is required",
-			"age must be positive",
-			"email format invalid",
+		Errors: []Violation{
+			{Message: "name is required"},
+			{Message: "age must be positive"},
+			{Message: "email format invalid"},
 		},
 	}
 
@@ -109,7 +119,7 @@ func TestErrValidationsMultipleErrors(t *testing.T) {
 }
 
 func TestErrValidationsAsError(t *testing.T) {
-	originalErr := ErrValidations{Errors: []string{"test error"}}
+	originalErr := ErrValidations{Errors: []Violation{{Message: "test error"}}}
 
 	// Wrap it
 	wrappedErr := errors.Join(errors.New("context"), originalErr)
@@ -129,6 +139,10 @@ func TestErrorsAreDistinct(t *testing.T) {
 	errs := []error{
 		ErrorNotFound,
 		ErrorBadRequest,
+		ErrorUnauthorized,
+		ErrorForbidden,
+		ErrorUnprocessable,
+		ErrorTooManyRequests,
 		ErrorUpstreamService,
 		ErrorNotImplemented,
 		ErrorInternal,
@@ -145,10 +159,10 @@ func TestErrorsAreDistinct(t *testing.T) {
 
 func TestErrValidationsWithSpecialCharacters(t *testing.T) {
 	e := ErrValidations{
-		Errors: []string{
-			"field 'name' is required",
-			`value must not contain "quotes"`,
-			"special: chars & symbols < > \" '",
+		Errors: []Violation{
+			{Message: "field 'name' is required"},
+			{Message: `value must not contain "quotes"`},
+			{Message: "special: chars & symbols < > \" '"},
 		},
 	}
 
@@ -163,7 +177,7 @@ func TestErrValidationsWithSpecialCharacters(t *testing.T) {
 }
 
 func TestErrValidationsEmptySlice(t *testing.T) {
-	e := ErrValidations{Errors: []string{}}
+	e := ErrValidations{Errors: []Violation{}}
 	result := e.Error()
 	if result != "" {
 		t.Errorf("Empty ErrValidations should return empty string, got %q", result)
@@ -171,7 +185,7 @@ func TestErrValidationsEmptySlice(t *testing.T) {
 }
 
 func TestErrValidationsSingleError(t *testing.T) {
-	e := ErrValidations{Errors: []string{"validation failed"}}
+	e := ErrValidations{Errors: []Violation{{Message: "validation failed"}}}
 	result := e.Error()
 	expected := "1: validation failed"
 	if result != expected {
@@ -214,7 +228,7 @@ func TestErrorsIsWithPredefinedErrors(t *testing.T) {
 
 // Test that ErrValidations can be wrapped and unwrapped
 func TestErrValidationsWrapped(t *testing.T) {
-	original := ErrValidations{Errors: []string{"error1", "error2"}}
+	original := ErrValidations{Errors: []Violation{{Message: "error1"}, {Message: "error2"}}}
 	wrapped := errors.Join(errors.New("wrapper"), original)
 
 	var unwrapped ErrValidations
@@ -7,10 +7,13 @@
 package domain
 
 import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	date "google.golang.org/genproto/googleapis/type/date"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	_ "google.golang.org/protobuf/types/descriptorpb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,14 +26,83 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// ExampleRecord demonstrates the payload this sample service works with.
+// ExampleRecordStatus is the lifecycle state of a stored record. Records start
+// QUEUED, move to IN_PROGRESS while a handler processes them and end up
+// COMPLETED or FAILED. Completed and failed records are queued again when an
+// example.record.updated event changes them; PUT and PATCH keep the status.
+type ExampleRecordStatus int32
+
+const (
+	ExampleRecordStatus_EXAMPLE_RECORD_STATUS_UNSPECIFIED ExampleRecordStatus = 0
+	ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED      ExampleRecordStatus = 1
+	ExampleRecordStatus_EXAMPLE_RECORD_STATUS_IN_PROGRESS ExampleRecordStatus = 2
+	ExampleRecordStatus_EXAMPLE_RECORD_STATUS_COMPLETED   ExampleRecordStatus = 3
+	ExampleRecordStatus_EXAMPLE_RECORD_STATUS_FAILED      ExampleRecordStatus = 4
+)
+
+// Enum value maps for ExampleRecordStatus.
+var (
+	ExampleRecordStatus_name = map[int32]string{
+		0: "EXAMPLE_RECORD_STATUS_UNSPECIFIED",
+		1: "EXAMPLE_RECORD_STATUS_QUEUED",
+		2: "EXAMPLE_RECORD_STATUS_IN_PROGRESS",
+		3: "EXAMPLE_RECORD_STATUS_COMPLETED",
+		4: "EXAMPLE_RECORD_STATUS_FAILED",
+	}
+	ExampleRecordStatus_value = map[string]int32{
+		"EXAMPLE_RECORD_STATUS_UNSPECIFIED": 0,
+		"EXAMPLE_RECORD_STATUS_QUEUED":      1,
+		"EXAMPLE_RECORD_STATUS_IN_PROGRESS": 2,
+		"EXAMPLE_RECORD_STATUS_COMPLETED":   3,
+		"EXAMPLE_RECORD_STATUS_FAILED":      4,
+	}
+)
+
+func (x ExampleRecordStatus) Enum() *ExampleRecordStatus {
+	p := new(ExampleRecordStatus)
+	*p = x
+	return p
+}
+
+func (x ExampleRecordStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExampleRecordStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_domain_v1_example_proto_enumTypes[0].Descriptor()
+}
+
+func (ExampleRecordStatus) Type() protoreflect.EnumType {
+	return &file_domain_v1_example_proto_enumTypes[0]
+}
+
+func (x ExampleRecordStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExampleRecordStatus.Descriptor instead.
+func (ExampleRecordStatus) EnumDescriptor() ([]byte, []int) {
+	return file_domain_v1_example_proto_rawDescGZIP(), []int{0}
+}
+
+// ExampleRecord demonstrates the payload this sample service works with. Its
+// protovalidate rules are enforced by the HTTP API and by the event consumers.
 type ExampleRecord struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RecordId      string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId" json:"record_id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
-	Meta          *ExampleMeta           `protobuf:"bytes,4,opt,name=meta" json:"meta,omitempty"`
-	Tags          []string               `protobuf:"bytes,5,rep,name=tags" json:"tags,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	RecordId    string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId" json:"record_id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
+	Meta        *ExampleMeta           `protobuf:"bytes,4,opt,name=meta" json:"meta,omitempty"`
+	Tags        []string               `protobuf:"bytes,5,rep,name=tags" json:"tags,omitempty"`
+	// version starts at zero and is incremented whenever the record is replaced,
+	// patched, updated or changes its status. These writes only succeed while the
+	// stored version is still the one they read, so concurrent writers cannot
+	// overwrite each other.
+	Version int32 `protobuf:"varint,6,opt,name=version" json:"version,omitempty"`
+	// status and status_history are maintained by the service. Values sent by
+	// clients are ignored.
+	Status        ExampleRecordStatus `protobuf:"varint,7,opt,name=status,enum=domain.v1.ExampleRecordStatus" json:"status,omitempty"`
+	StatusHistory []*StatusTransition `protobuf:"bytes,8,rep,name=status_history,json=statusHistory" json:"status_history,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -100,20 +172,112 @@ func (x *ExampleRecord) GetTags() []string {
 	return nil
 }
 
+func (x *ExampleRecord) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ExampleRecord) GetStatus() ExampleRecordStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ExampleRecordStatus_EXAMPLE_RECORD_STATUS_UNSPECIFIED
+}
+
+func (x *ExampleRecord) GetStatusHistory() []*StatusTransition {
+	if x != nil {
+		return x.StatusHistory
+	}
+	return nil
+}
+
+// StatusTransition records one change of an ExampleRecord status.
+type StatusTransition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          ExampleRecordStatus    `protobuf:"varint,1,opt,name=from,enum=domain.v1.ExampleRecordStatus" json:"from,omitempty"`
+	To            ExampleRecordStatus    `protobuf:"varint,2,opt,name=to,enum=domain.v1.ExampleRecordStatus" json:"to,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=occurred_at,json=occurredAt" json:"occurred_at,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusTransition) Reset() {
+	*x = StatusTransition{}
+	mi := &file_domain_v1_example_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusTransition) ProtoMessage() {}
+
+func (x *StatusTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_domain_v1_example_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusTransition.ProtoReflect.Descriptor instead.
+func (*StatusTransition) Descriptor() ([]byte, []int) {
+	return file_domain_v1_example_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StatusTransition) GetFrom() ExampleRecordStatus {
+	if x != nil {
+		return x.From
+	}
+	return ExampleRecordStatus_EXAMPLE_RECORD_STATUS_UNSPECIFIED
+}
+
+func (x *StatusTransition) GetTo() ExampleRecordStatus {
+	if x != nil {
+		return x.To
+	}
+	return ExampleRecordStatus_EXAMPLE_RECORD_STATUS_UNSPECIFIED
+}
+
+func (x *StatusTransition) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *StatusTransition) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 // ExampleMeta groups together additional sample fields.
 type ExampleMeta struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	RequestedBy      string                 `protobuf:"bytes,1,opt,name=requested_by,json=requestedBy" json:"requested_by,omitempty"`
-	DesiredStartDate *date.Date             `protobuf:"bytes,2,opt,name=desired_start_date,json=desiredStartDate" json:"desired_start_date,omitempty"`
-	RequiresFollowUp bool                   `protobuf:"varint,3,opt,name=requires_follow_up,json=requiresFollowUp" json:"requires_follow_up,omitempty"`
-	Priority         int32                  `protobuf:"varint,4,opt,name=priority" json:"priority,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	RequestedBy string                 `protobuf:"bytes,1,opt,name=requested_by,json=requestedBy" json:"requested_by,omitempty"`
+	// desired_start_date must be a complete calendar date when set.
+	DesiredStartDate *date.Date `protobuf:"bytes,2,opt,name=desired_start_date,json=desiredStartDate" json:"desired_start_date,omitempty"`
+	RequiresFollowUp bool       `protobuf:"varint,3,opt,name=requires_follow_up,json=requiresFollowUp" json:"requires_follow_up,omitempty"`
+	// priority ranges from 1 to 5; zero means no priority was given.
+	Priority      int32 `protobuf:"varint,4,opt,name=priority" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ExampleMeta) Reset() {
 	*x = ExampleMeta{}
-	mi := &file_domain_v1_example_proto_msgTypes[1]
+	mi := &file_domain_v1_example_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -125,7 +289,7 @@ func (x *ExampleMeta) String() string {
 func (*ExampleMeta) ProtoMessage() {}
 
 func (x *ExampleMeta) ProtoReflect() protoreflect.Message {
-	mi := &file_domain_v1_example_proto_msgTypes[1]
+	mi := &file_domain_v1_example_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -138,7 +302,7 @@ func (x *ExampleMeta) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExampleMeta.ProtoReflect.Descriptor instead.
 func (*ExampleMeta) Descriptor() ([]byte, []int) {
-	return file_domain_v1_example_proto_rawDescGZIP(), []int{1}
+	return file_domain_v1_example_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ExampleMeta) GetRequestedBy() string {
@@ -169,6 +333,70 @@ func (x *ExampleMeta) GetPriority() int32 {
 	return 0
 }
 
+// ExampleRecordUpdate patches a stored ExampleRecord. Only the fields named in
+// update_mask are copied from updates; a named field that is unset in updates is
+// cleared. Without a mask, every populated field of updates is applied.
+type ExampleRecordUpdate struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	RecordId string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId" json:"record_id,omitempty"`
+	// updates is partial, so the record rules are checked once it has been applied.
+	Updates       *ExampleRecord         `protobuf:"bytes,2,opt,name=updates" json:"updates,omitempty"`
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExampleRecordUpdate) Reset() {
+	*x = ExampleRecordUpdate{}
+	mi := &file_domain_v1_example_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExampleRecordUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExampleRecordUpdate) ProtoMessage() {}
+
+func (x *ExampleRecordUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_domain_v1_example_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExampleRecordUpdate.ProtoReflect.Descriptor instead.
+func (*ExampleRecordUpdate) Descriptor() ([]byte, []int) {
+	return file_domain_v1_example_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExampleRecordUpdate) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *ExampleRecordUpdate) GetUpdates() *ExampleRecord {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+func (x *ExampleRecordUpdate) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
 // ExampleResult is emitted by event handlers once the record was processed.
 type ExampleResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -182,7 +410,7 @@ type ExampleResult struct {
 
 func (x *ExampleResult) Reset() {
 	*x = ExampleResult{}
-	mi := &file_domain_v1_example_proto_msgTypes[2]
+	mi := &file_domain_v1_example_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -194,7 +422,7 @@ func (x *ExampleResult) String() string {
 func (*ExampleResult) ProtoMessage() {}
 
 func (x *ExampleResult) ProtoReflect() protoreflect.Message {
-	mi := &file_domain_v1_example_proto_msgTypes[2]
+	mi := &file_domain_v1_example_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -207,7 +435,7 @@ func (x *ExampleResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExampleResult.ProtoReflect.Descriptor instead.
 func (*ExampleResult) Descriptor() ([]byte, []int) {
-	return file_domain_v1_example_proto_rawDescGZIP(), []int{2}
+	return file_domain_v1_example_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ExampleResult) GetRecordId() string {
@@ -238,27 +466,139 @@ func (x *ExampleResult) GetProcessedOn() *date.Date {
 	return nil
 }
 
+// ProcessingError describes why an example record could not be processed.
+type ProcessingError struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	RecordId string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId" json:"record_id,omitempty"`
+	// error_code is one of VALIDATION_ERROR, NOT_FOUND, PROCESSING_ERROR, TIMEOUT or INTERNAL_ERROR.
+	ErrorCode string `protobuf:"bytes,2,opt,name=error_code,json=errorCode" json:"error_code,omitempty"`
+	Message   string `protobuf:"bytes,3,opt,name=message" json:"message,omitempty"`
+	Retryable bool   `protobuf:"varint,4,opt,name=retryable" json:"retryable,omitempty"`
+	// details carries context such as the failed handler and message UUID.
+	Details       map[string]string `protobuf:"bytes,5,rep,name=details" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessingError) Reset() {
+	*x = ProcessingError{}
+	mi := &file_domain_v1_example_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessingError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessingError) ProtoMessage() {}
+
+func (x *ProcessingError) ProtoReflect() protoreflect.Message {
+	mi := &file_domain_v1_example_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessingError.ProtoReflect.Descriptor instead.
+func (*ProcessingError) Descriptor() ([]byte, []int) {
+	return file_domain_v1_example_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProcessingError) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *ProcessingError) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *ProcessingError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ProcessingError) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+func (x *ProcessingError) GetDetails() map[string]string {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
 var File_domain_v1_example_proto protoreflect.FileDescriptor
 
 const file_domain_v1_example_proto_rawDesc = "" +
 	"\n" +
-	"\x17domain/v1/example.proto\x12\tdomain.v1\x1a google/protobuf/descriptor.proto\x1a\x16google/type/date.proto\"\xa4\x01\n" +
-	"\rExampleRecord\x12\x1b\n" +
-	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12*\n" +
-	"\x04meta\x18\x04 \x01(\v2\x16.domain.v1.ExampleMetaR\x04meta\x12\x12\n" +
-	"\x04tags\x18\x05 \x03(\tR\x04tags\"\xbb\x01\n" +
-	"\vExampleMeta\x12!\n" +
-	"\frequested_by\x18\x01 \x01(\tR\vrequestedBy\x12?\n" +
-	"\x12desired_start_date\x18\x02 \x01(\v2\x11.google.type.DateR\x10desiredStartDate\x12,\n" +
-	"\x12requires_follow_up\x18\x03 \x01(\bR\x10requiresFollowUp\x12\x1a\n" +
-	"\bpriority\x18\x04 \x01(\x05R\bpriority\"\x8e\x01\n" +
+	"\x17domain/v1/example.proto\x12\tdomain.v1\x1a\x1bbuf/validate/validate.proto\x1a google/protobuf/descriptor.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x16google/type/date.proto\"\xa1\x03\n" +
+	"\rExampleRecord\x12E\n" +
+	"\trecord_id\x18\x01 \x01(\tB(\xbaH%\xc8\x01\x01r \x18@2\x1c^[A-Za-z0-9][A-Za-z0-9._-]*$R\brecordId\x12 \n" +
+	"\x05title\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xc8\x01R\x05title\x12*\n" +
+	"\vdescription\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\xd0\x0fR\vdescription\x12*\n" +
+	"\x04meta\x18\x04 \x01(\v2\x16.domain.v1.ExampleMetaR\x04meta\x12&\n" +
+	"\x04tags\x18\x05 \x03(\tB\x12\xbaH\x0f\x92\x01\f\x10\x14\x18\x01\"\x06r\x04\x10\x01\x18 R\x04tags\x12!\n" +
+	"\aversion\x18\x06 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\aversion\x12@\n" +
+	"\x06status\x18\a \x01(\x0e2\x1e.domain.v1.ExampleRecordStatusB\b\xbaH\x05\x82\x01\x02\x10\x01R\x06status\x12B\n" +
+	"\x0estatus_history\x18\b \x03(\v2\x1b.domain.v1.StatusTransitionR\rstatusHistory\"\xcb\x01\n" +
+	"\x10StatusTransition\x122\n" +
+	"\x04from\x18\x01 \x01(\x0e2\x1e.domain.v1.ExampleRecordStatusR\x04from\x12.\n" +
+	"\x02to\x18\x02 \x01(\x0e2\x1e.domain.v1.ExampleRecordStatusR\x02to\x12;\n" +
+	"\voccurred_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"\x84\x04\n" +
+	"\vExampleMeta\x12+\n" +
+	"\frequested_by\x18\x01 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x01R\vrequestedBy\x12\xef\x02\n" +
+	"\x12desired_start_date\x18\x02 \x01(\v2\x11.google.type.DateB\xad\x02\xbaH\xa9\x02\xba\x01\xa5\x02\n" +
+	"\n" +
+	"date.valid\x12\x1dmust be a valid calendar date\x1a\xf7\x01this.year >= 1 && this.year <= 9999 && this.month >= 1 && this.month <= 12 && this.day >= 1 && this.day <= [31, (this.year % 4 == 0 && this.year % 100 != 0) || this.year % 400 == 0 ? 29 : 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31][this.month - 1]R\x10desiredStartDate\x12,\n" +
+	"\x12requires_follow_up\x18\x03 \x01(\bR\x10requiresFollowUp\x12(\n" +
+	"\bpriority\x18\x04 \x01(\x05B\f\xbaH\t\xd8\x01\x01\x1a\x04\x18\x05(\x01R\bpriority\"\xb3\x01\n" +
+	"\x13ExampleRecordUpdate\x12#\n" +
+	"\trecord_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\brecordId\x12:\n" +
+	"\aupdates\x18\x02 \x01(\v2\x18.domain.v1.ExampleRecordB\x06\xbaH\x03\xd8\x01\x03R\aupdates\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"\x8e\x01\n" +
 	"\rExampleResult\x12\x1b\n" +
 	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
 	"\x04note\x18\x03 \x01(\tR\x04note\x124\n" +
-	"\fprocessed_on\x18\x04 \x01(\v2\x11.google.type.DateR\vprocessedOnB3Z,drblury/event-driven-service/internal/domain\x92\x03\x02\b\x02b\beditionsp\xe8\a"
+	"\fprocessed_on\x18\x04 \x01(\v2\x11.google.type.DateR\vprocessedOn\"\x84\x02\n" +
+	"\x0fProcessingError\x12\x1b\n" +
+	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x02 \x01(\tR\terrorCode\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1c\n" +
+	"\tretryable\x18\x04 \x01(\bR\tretryable\x12A\n" +
+	"\adetails\x18\x05 \x03(\v2'.domain.v1.ProcessingError.DetailsEntryR\adetails\x1a:\n" +
+	"\fDetailsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\xcc\x01\n" +
+	"\x13ExampleRecordStatus\x12%\n" +
+	"!EXAMPLE_RECORD_STATUS_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cEXAMPLE_RECORD_STATUS_QUEUED\x10\x01\x12%\n" +
+	"!EXAMPLE_RECORD_STATUS_IN_PROGRESS\x10\x02\x12#\n" +
+	"\x1fEXAMPLE_RECORD_STATUS_COMPLETED\x10\x03\x12 \n" +
+	"\x1cEXAMPLE_RECORD_STATUS_FAILED\x10\x04B3Z,drblury/event-driven-service/internal/domain\x92\x03\x02\b\x02b\beditionsp\xe8\a"
 
 var (
 	file_domain_v1_example_proto_rawDescOnce sync.Once
@@ -272,22 +612,38 @@ func file_domain_v1_example_proto_rawDescGZIP() []byte {
 	return file_domain_v1_example_proto_rawDescData
 }
 
-var file_domain_v1_example_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_domain_v1_example_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_domain_v1_example_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_domain_v1_example_proto_goTypes = []any{
-	(*ExampleRecord)(nil), // 0: domain.v1.ExampleRecord
-	(*ExampleMeta)(nil),   // 1: domain.v1.ExampleMeta
-	(*ExampleResult)(nil), // 2: domain.v1.ExampleResult
-	(*date.Date)(nil),     // 3: google.type.Date
+	(ExampleRecordStatus)(0),      // 0: domain.v1.ExampleRecordStatus
+	(*ExampleRecord)(nil),         // 1: domain.v1.ExampleRecord
+	(*StatusTransition)(nil),      // 2: domain.v1.StatusTransition
+	(*ExampleMeta)(nil),           // 3: domain.v1.ExampleMeta
+	(*ExampleRecordUpdate)(nil),   // 4: domain.v1.ExampleRecordUpdate
+	(*ExampleResult)(nil),         // 5: domain.v1.ExampleResult
+	(*ProcessingError)(nil),       // 6: domain.v1.ProcessingError
+	nil,                           // 7: domain.v1.ProcessingError.DetailsEntry
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*date.Date)(nil),             // 9: google.type.Date
+	(*fieldmaskpb.FieldMask)(nil), // 10: google.protobuf.FieldMask
 }
 var file_domain_v1_example_proto_depIdxs = []int32{
-	1, // 0: domain.v1.ExampleRecord.meta:type_name -> domain.v1.ExampleMeta
-	3, // 1: domain.v1.ExampleMeta.desired_start_date:type_name -> google.type.Date
-	3, // 2: domain.v1.ExampleResult.processed_on:type_name -> google.type.Date
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	3,  // 0: domain.v1.ExampleRecord.meta:type_name -> domain.v1.ExampleMeta
+	0,  // 1: domain.v1.ExampleRecord.status:type_name -> domain.v1.ExampleRecordStatus
+	2,  // 2: domain.v1.ExampleRecord.status_history:type_name -> domain.v1.StatusTransition
+	0,  // 3: domain.v1.StatusTransition.from:type_name -> domain.v1.ExampleRecordStatus
+	0,  // 4: domain.v1.StatusTransition.to:type_name -> domain.v1.ExampleRecordStatus
+	8,  // 5: domain.v1.StatusTransition.occurred_at:type_name -> google.protobuf.Timestamp
+	9,  // 6: domain.v1.ExampleMeta.desired_start_date:type_name -> google.type.Date
+	1,  // 7: domain.v1.ExampleRecordUpdate.updates:type_name -> domain.v1.ExampleRecord
+	10, // 8: domain.v1.ExampleRecordUpdate.update_mask:type_name -> google.protobuf.FieldMask
+	9,  // 9: domain.v1.ExampleResult.processed_on:type_name -> google.type.Date
+	7,  // 10: domain.v1.ProcessingError.details:type_name -> domain.v1.ProcessingError.DetailsEntry
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_domain_v1_example_proto_init() }
@@ -300,13 +656,14 @@ func file_domain_v1_example_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_domain_v1_example_proto_rawDesc), len(file_domain_v1_example_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   3,
+			NumEnums:      1,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_domain_v1_example_proto_goTypes,
 		DependencyIndexes: file_domain_v1_example_proto_depIdxs,
+		EnumInfos:         file_domain_v1_example_proto_enumTypes,
 		MessageInfos:      file_domain_v1_example_proto_msgTypes,
 	}.Build()
 	File_domain_v1_example_proto = out.File
@@ -0,0 +1,262 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/drblury/protoflow"
+)
+
+const defaultBatchParallelism = 4
+
+const skippedResultStatus = "skipped"
+
+// BatchStore is the persistence contract used by the batch handler.
+type BatchStore interface {
+	StartBatch(ctx context.Context, progress *database.BatchProgress) (*database.BatchProgress, error)
+	RecordBatchOutcome(ctx context.Context, batchID string, outcome database.BatchRecordOutcome) error
+	CompleteBatch(ctx context.Context, batchID string, completedAt time.Time) error
+}
+
+// ExampleRecordReader loads stored example records.
+type ExampleRecordReader interface {
+	GetExampleRecordByID(ctx context.Context, id string) (*domain.ExampleRecord, error)
+}
+
+// recordProcessor processes a single example record.
+type recordProcessor func(ctx context.Context, record *domain.ExampleRecord) (*domain.ExampleResult, error)
+
+// batchRunner processes the records of a batch request and tracks each outcome in
+// the store, so a redelivered request only processes the records that are left.
+type batchRunner struct {
+	store       BatchStore
+	records     ExampleRecordReader
+	process     recordProcessor
+	parallelism int
+}
+
+func newBatchRunner(store BatchStore, records ExampleRecordReader, process recordProcessor, parallelism int) *batchRunner {
+	if parallelism <= 0 {
+		parallelism = defaultBatchParallelism
+	}
+	return &batchRunner{store: store, records: records, process: process, parallelism: parallelism}
+}
+
+// exampleBatchHandler runs a batch request and emits a single BatchResult.
+func exampleBatchHandler(runner *batchRunner) protoflow.ProtoMessageHandler[*domain.BatchRequest] {
+	return func(ctx context.Context, e protoflow.ProtoMessageContext[*domain.BatchRequest]) ([]protoflow.ProtoMessageOutput, error) {
+		result, err := runner.run(ctx, e.Payload)
+		if err != nil {
+			if errors.Is(err, domain.ErrorBadRequest) {
+				return nil, fmt.Errorf("%w: %w", protoflow.ErrUnprocessable, err)
+			}
+			return nil, err
+		}
+
+		metadata := e.Metadata.WithAll(
+			protoflow.Metadata{
+				"handler":      "exampleBatchHandler",
+				"batch_id":     result.GetBatchId(),
+				"completed_at": time.Now().Format(time.RFC3339),
+			},
+		)
+
+		return []protoflow.ProtoMessageOutput{{Message: result, Metadata: metadata}}, nil
+	}
+}
+
+// run processes the records that have no outcome yet and completes the batch.
+func (r *batchRunner) run(ctx context.Context, req *domain.BatchRequest) (*domain.BatchResult, error) {
+	batchID := req.GetBatchId()
+	if batchID == "" {
+		return nil, fmt.Errorf("batch id is required: %w", domain.ErrorBadRequest)
+	}
+	ids := uniqueRecordIDs(req.GetRecordIds())
+
+	progress, err := r.store.StartBatch(ctx, &database.BatchProgress{BatchID: batchID, Total: len(ids)})
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make(map[string]database.BatchRecordOutcome, len(ids))
+	for _, outcome := range progress.Outcomes {
+		outcomes[outcome.RecordID] = outcome
+	}
+	pending := slices.DeleteFunc(slices.Clone(ids), func(id string) bool {
+		_, ok := outcomes[id]
+		return ok
+	})
+
+	stopOnError := req.GetOptions().GetStopOnError()
+	stopped := stopOnError && progress.Failed > 0
+	if req.GetOptions().GetParallel() {
+		err = r.runParallel(ctx, batchID, pending, stopOnError, stopped, outcomes)
+	} else {
+		err = r.runSequential(ctx, batchID, pending, stopOnError, stopped, outcomes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.store.CompleteBatch(ctx, batchID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return newBatchResult(batchID, ids, outcomes), nil
+}
+
+func (r *batchRunner) runSequential(
+	ctx context.Context,
+	batchID string,
+	ids []string,
+	stopOnError bool,
+	stopped bool,
+	outcomes map[string]database.BatchRecordOutcome,
+) error {
+	for _, id := range ids {
+		outcome := skippedOutcome(id)
+		if !stopped {
+			outcome = r.processOne(ctx, id)
+		}
+		if err := r.store.RecordBatchOutcome(ctx, batchID, outcome); err != nil {
+			return err
+		}
+		outcomes[id] = outcome
+		stopped = stopped || (stopOnError && outcome.Outcome == database.BatchOutcomeFailed)
+	}
+	return nil
+}
+
+// runParallel processes up to parallelism records at once. With stopOnError, records
+// that have not started when the first failure is seen are skipped.
+func (r *batchRunner) runParallel(
+	ctx context.Context,
+	batchID string,
+	ids []string,
+	stopOnError bool,
+	stopped bool,
+	outcomes map[string]database.BatchRecordOutcome,
+) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		stop     atomic.Bool
+	)
+	stop.Store(stopped)
+	slots := make(chan struct{}, r.parallelism)
+
+	for _, id := range ids {
+		slots <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-slots }()
+
+			outcome := skippedOutcome(id)
+			if !stop.Load() {
+				outcome = r.processOne(ctx, id)
+			}
+			if stopOnError && outcome.Outcome == database.BatchOutcomeFailed {
+				stop.Store(true)
+			}
+			err := r.store.RecordBatchOutcome(ctx, batchID, outcome)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			outcomes[id] = outcome
+		})
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// processOne loads and processes a record and converts the result into an outcome.
+func (r *batchRunner) processOne(ctx context.Context, id string) database.BatchRecordOutcome {
+	record, err := r.records.GetExampleRecordByID(ctx, id)
+	var result *domain.ExampleResult
+	if err == nil {
+		result, err = r.process(ctx, record)
+	}
+
+	outcome := database.BatchRecordOutcome{RecordID: id, ProcessedAt: time.Now().UTC()}
+	if err != nil {
+		processingErr := classifyProcessingError(id, err)
+		outcome.Outcome = database.BatchOutcomeFailed
+		outcome.ErrorCode = processingErr.GetErrorCode()
+		outcome.Message = processingErr.GetMessage()
+		outcome.Retryable = processingErr.GetRetryable()
+		return outcome
+	}
+
+	outcome.Outcome = database.BatchOutcomeSucceeded
+	outcome.Status = result.GetStatus()
+	outcome.Note = result.GetNote()
+	return outcome
+}
+
+// newBatchResult builds the completion event with the outcomes in request order.
+func newBatchResult(batchID string, ids []string, outcomes map[string]database.BatchRecordOutcome) *domain.BatchResult {
+	result := &domain.BatchResult{
+		BatchId: batchID,
+		Total:   int32(len(ids)), // #nosec G115 -- batch sizes are far below the int32 range
+	}
+
+	for _, id := range ids {
+		outcome := outcomes[id]
+		switch outcome.Outcome {
+		case database.BatchOutcomeSucceeded:
+			result.Succeeded++
+			result.Results = append(result.Results, &domain.ExampleResult{
+				RecordId:    id,
+				Status:      outcome.Status,
+				Note:        outcome.Note,
+				ProcessedOn: dateOf(outcome.ProcessedAt),
+			})
+		case database.BatchOutcomeFailed:
+			result.Failed++
+			result.Errors = append(result.Errors, &domain.ProcessingError{
+				RecordId:  id,
+				ErrorCode: outcome.ErrorCode,
+				Message:   outcome.Message,
+				Retryable: outcome.Retryable,
+			})
+		case database.BatchOutcomeSkipped:
+			result.Results = append(result.Results, &domain.ExampleResult{
+				RecordId: id,
+				Status:   skippedResultStatus,
+				Note:     "skipped after an earlier record failed",
+			})
+		}
+	}
+	return result
+}
+
+func skippedOutcome(id string) database.BatchRecordOutcome {
+	return database.BatchRecordOutcome{RecordID: id, Outcome: database.BatchOutcomeSkipped, ProcessedAt: time.Now().UTC()}
+}
+
+// uniqueRecordIDs drops empty and repeated IDs while keeping the request order.
+func uniqueRecordIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok || id == "" {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique
+}
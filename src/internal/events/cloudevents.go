@@ -0,0 +1,346 @@
+package events
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+)
+
+// CloudEvents content modes. In binary mode the payload is the event data and the
+// attributes travel as ce_* metadata; in structured mode the payload is the JSON
+// envelope of the event.
+const (
+	CloudEventsModeBinary     = "binary"
+	CloudEventsModeStructured = "structured"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsContentType = "application/cloudevents+json"
+	cloudEventsDataType    = "application/json"
+
+	// cloudEventsMetadataPrefix marks attributes in binary mode, as in the Kafka binding.
+	cloudEventsMetadataPrefix = "ce_"
+	contentTypeMetadataKey    = "content-type"
+)
+
+// Attributes of the CloudEvents v1.0 envelope.
+const (
+	ceSpecVersion     = "specversion"
+	ceType            = "type"
+	ceSource          = "source"
+	ceID              = "id"
+	ceTime            = "time"
+	ceDataContentType = "datacontenttype"
+	ceData            = "data"
+	ceDataBase64      = "data_base64"
+)
+
+// Protoflow extensions listed in the AsyncAPI cloudEventsEnvelope trait.
+const (
+	cePfCorrelationID = "pf_correlation_id"
+	cePfTraceID       = "pf_trace_id"
+	cePfDeadLetter    = "pf_dead_letter"
+)
+
+// cloudEventsExtensionMetadata maps extensions onto the protoflow metadata keys the
+// middlewares read, in both directions.
+var cloudEventsExtensionMetadata = map[string]string{
+	cePfCorrelationID: protoflow.MetadataKeyCorrelationID,
+	cePfTraceID:       protoflow.MetadataKeyTraceID,
+}
+
+// cloudEventsTypedExtensions are written as JSON numbers or booleans in structured mode.
+var cloudEventsTypedExtensions = map[string]func(string) (any, error){
+	"pf_attempt":      func(v string) (any, error) { return strconv.Atoi(v) },
+	"pf_max_attempts": func(v string) (any, error) { return strconv.Atoi(v) },
+	"pf_delay_ms":     func(v string) (any, error) { return strconv.Atoi(v) },
+	cePfDeadLetter:    func(v string) (any, error) { return strconv.ParseBool(v) },
+}
+
+// ErrInvalidCloudEvent is returned for messages that claim to be CloudEvents but
+// violate the specification.
+var ErrInvalidCloudEvent = errors.New("invalid CloudEvent")
+
+// CloudEventsConfig configures how outgoing messages are wrapped.
+type CloudEventsConfig struct {
+	Mode   string
+	Source string
+	// Types maps topics onto event types; other topics use the topic name as type.
+	Types map[string]string
+	// DeadLetterTopic receives messages flagged with pf_dead_letter.
+	DeadLetterTopic string
+}
+
+// cloudEventsCodec converts Watermill messages from and to CloudEvents v1.0.
+type cloudEventsCodec struct {
+	cfg CloudEventsConfig
+	now func() time.Time
+}
+
+func newCloudEventsCodec(cfg CloudEventsConfig) (*cloudEventsCodec, error) {
+	if cfg.Mode == "" {
+		cfg.Mode = CloudEventsModeBinary
+	}
+	if cfg.Mode != CloudEventsModeBinary && cfg.Mode != CloudEventsModeStructured {
+		return nil, fmt.Errorf("unknown CloudEvents mode %q, want %s or %s", cfg.Mode, CloudEventsModeBinary, CloudEventsModeStructured)
+	}
+	if cfg.Source == "" {
+		return nil, errors.New("CloudEvents source is required")
+	}
+	return &cloudEventsCodec{cfg: cfg, now: time.Now}, nil
+}
+
+// encode returns a copy of msg wrapped as a CloudEvent for topic. Attributes of a
+// consumed event that were copied into the metadata are replaced, since the copy is a
+// new event: its ID is the message UUID, so redelivered outbox rows keep their ID.
+func (c *cloudEventsCodec) encode(topic string, msg *message.Message) (*message.Message, error) {
+	attrs := map[string]string{
+		ceSpecVersion:     cloudEventsSpecVersion,
+		ceType:            c.eventType(topic),
+		ceSource:          c.cfg.Source,
+		ceID:              msg.UUID,
+		ceTime:            c.now().UTC().Format(time.RFC3339Nano),
+		ceDataContentType: cloudEventsDataType,
+	}
+	for extension, key := range cloudEventsExtensionMetadata {
+		if value := msg.Metadata.Get(key); value != "" {
+			attrs[extension] = value
+		}
+	}
+	if topic != "" && topic == c.cfg.DeadLetterTopic {
+		attrs[cePfDeadLetter] = "true"
+	}
+
+	out := message.NewMessage(msg.UUID, msg.Payload)
+	out.SetContext(msg.Context())
+	for key, value := range msg.Metadata {
+		if !strings.HasPrefix(key, cloudEventsMetadataPrefix) && key != contentTypeMetadataKey {
+			out.Metadata.Set(key, value)
+		}
+	}
+
+	if c.cfg.Mode == CloudEventsModeBinary {
+		for name, value := range attrs {
+			out.Metadata.Set(cloudEventsMetadataPrefix+name, value)
+		}
+		return out, nil
+	}
+
+	payload, err := marshalStructuredCloudEvent(attrs, msg.Payload)
+	if err != nil {
+		return nil, err
+	}
+	out.Payload = payload
+	out.Metadata.Set(contentTypeMetadataKey, cloudEventsContentType)
+	return out, nil
+}
+
+func (c *cloudEventsCodec) eventType(topic string) string {
+	if eventType := c.cfg.Types[topic]; eventType != "" {
+		return eventType
+	}
+	return topic
+}
+
+// decodeCloudEvent normalises a consumed message to binary mode in place: the payload
+// becomes the event data and every attribute is stored as ce_<name> metadata. The
+// pf_correlation_id and pf_trace_id extensions also fill the protoflow metadata keys.
+// Messages that are not CloudEvents are left untouched.
+func decodeCloudEvent(msg *message.Message) error {
+	structured := isStructuredCloudEvent(msg)
+	if !structured && msg.Metadata.Get(cloudEventsMetadataPrefix+ceSpecVersion) == "" {
+		return nil
+	}
+
+	var (
+		attrs = make(map[string]string)
+		data  = msg.Payload
+		err   error
+	)
+	if structured {
+		if attrs, data, err = unmarshalStructuredCloudEvent(msg.Payload); err != nil {
+			return err
+		}
+	} else {
+		for key, value := range msg.Metadata {
+			if name, ok := strings.CutPrefix(key, cloudEventsMetadataPrefix); ok {
+				attrs[name] = value
+			}
+		}
+		// Protocol bindings carry datacontenttype in the content-type header.
+		if contentType := msg.Metadata.Get(contentTypeMetadataKey); contentType != "" && attrs[ceDataContentType] == "" {
+			attrs[ceDataContentType] = contentType
+		}
+	}
+	if err := validateCloudEventAttributes(attrs); err != nil {
+		return err
+	}
+
+	msg.Payload = data
+	if structured {
+		delete(msg.Metadata, contentTypeMetadataKey)
+	}
+	for name, value := range attrs {
+		msg.Metadata.Set(cloudEventsMetadataPrefix+name, value)
+	}
+	for extension, key := range cloudEventsExtensionMetadata {
+		if value := attrs[extension]; value != "" && msg.Metadata.Get(key) == "" {
+			msg.Metadata.Set(key, value)
+		}
+	}
+	return nil
+}
+
+// isStructuredCloudEvent reports whether msg carries a JSON envelope, either announced
+// by its content type or recognisable by its specversion member.
+func isStructuredCloudEvent(msg *message.Message) bool {
+	if mediaType, _, err := mime.ParseMediaType(msg.Metadata.Get(contentTypeMetadataKey)); err == nil {
+		return mediaType == cloudEventsContentType
+	}
+	if !json.Valid(msg.Payload) || !bytes.HasPrefix(bytes.TrimSpace(msg.Payload), []byte("{")) {
+		return false
+	}
+	var probe struct {
+		SpecVersion *json.RawMessage `json:"specversion"`
+	}
+	return json.Unmarshal(msg.Payload, &probe) == nil && probe.SpecVersion != nil
+}
+
+func validateCloudEventAttributes(attrs map[string]string) error {
+	if version := attrs[ceSpecVersion]; version != cloudEventsSpecVersion {
+		return fmt.Errorf("%w: unsupported specversion %q", ErrInvalidCloudEvent, version)
+	}
+	for _, name := range []string{ceID, ceSource, ceType} {
+		if attrs[name] == "" {
+			return fmt.Errorf("%w: %s is required", ErrInvalidCloudEvent, name)
+		}
+	}
+	if value, ok := attrs[ceTime]; ok {
+		if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+			return fmt.Errorf("%w: time %q is not RFC 3339", ErrInvalidCloudEvent, value)
+		}
+	}
+	return nil
+}
+
+// marshalStructuredCloudEvent builds the JSON envelope. JSON data is embedded as is,
+// any other data is base64 encoded into data_base64.
+func marshalStructuredCloudEvent(attrs map[string]string, data []byte) ([]byte, error) {
+	envelope := make(map[string]any, len(attrs)+1)
+	for name, value := range attrs {
+		envelope[name] = value
+		if convert, ok := cloudEventsTypedExtensions[name]; ok {
+			if typed, err := convert(value); err == nil {
+				envelope[name] = typed
+			}
+		}
+	}
+
+	switch {
+	case len(data) == 0:
+	case isJSONContentType(attrs[ceDataContentType]) && json.Valid(data):
+		envelope[ceData] = json.RawMessage(data)
+	default:
+		envelope[ceDataBase64] = base64.StdEncoding.EncodeToString(data)
+	}
+	return json.Marshal(envelope)
+}
+
+// unmarshalStructuredCloudEvent splits a JSON envelope into its attributes, with
+// extension values in their canonical string form, and the event data.
+func unmarshalStructuredCloudEvent(payload []byte) (map[string]string, []byte, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &members); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCloudEvent, err)
+	}
+	rawData, hasData := members[ceData]
+	rawBase64, hasBase64 := members[ceDataBase64]
+	if hasData && hasBase64 {
+		return nil, nil, fmt.Errorf("%w: data and data_base64 are mutually exclusive", ErrInvalidCloudEvent)
+	}
+
+	attrs := make(map[string]string, len(members))
+	for name, raw := range members {
+		if name == ceData || name == ceDataBase64 {
+			continue
+		}
+		value, ok, err := cloudEventAttributeValue(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: attribute %s: %w", ErrInvalidCloudEvent, name, err)
+		}
+		if ok {
+			attrs[name] = value
+		}
+	}
+
+	var data []byte
+	switch {
+	case hasBase64:
+		var encoded string
+		if err := json.Unmarshal(rawBase64, &encoded); err != nil {
+			return nil, nil, fmt.Errorf("%w: data_base64 must be a string", ErrInvalidCloudEvent)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: data_base64: %w", ErrInvalidCloudEvent, err)
+		}
+		data = decoded
+	case hasData && !isJSONContentType(attrs[ceDataContentType]):
+		// Non-JSON data is carried as a JSON string holding the data itself.
+		var text string
+		if err := json.Unmarshal(rawData, &text); err != nil {
+			data = []byte(rawData)
+		} else {
+			data = []byte(text)
+		}
+	case hasData:
+		data = []byte(rawData)
+	}
+	return attrs, data, nil
+}
+
+// cloudEventAttributeValue returns the canonical string of a scalar attribute value.
+// Null values are treated as absent.
+func cloudEventAttributeValue(raw json.RawMessage) (string, bool, error) {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return "", false, err
+	}
+	switch v := value.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, true, nil
+	case json.Number:
+		return v.String(), true, nil
+	case bool:
+		return strconv.FormatBool(v), true, nil
+	default:
+		return "", false, errors.New("value must be a string, number or boolean")
+	}
+}
+
+// isJSONContentType reports whether data of contentType is JSON. An absent content
+// type means application/json.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
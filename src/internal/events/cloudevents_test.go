@@ -0,0 +1,302 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"github.com/drblury/protoflow"
+)
+
+// The structured examples are taken from the CloudEvents v1.0 JSON event format.
+const (
+	specExampleXMLData = `{
+		"specversion" : "1.0",
+		"type" : "com.example.someevent",
+		"source" : "/mycontext",
+		"subject": null,
+		"id" : "C234-1234-1234",
+		"time" : "2018-04-05T17:31:00Z",
+		"comexampleextension1" : "value",
+		"comexampleothervalue" : 5,
+		"datacontenttype" : "application/xml",
+		"data" : "<much wow=\"xml\"/>"
+	}`
+	specExampleBinaryData = `{
+		"specversion" : "1.0",
+		"type" : "com.example.someevent",
+		"source" : "/mycontext",
+		"id" : "A234-1234-1234",
+		"time" : "2018-04-05T17:31:00Z",
+		"comexampleextension1" : "value",
+		"comexampleothervalue" : 5,
+		"datacontenttype" : "application/vnd.apache.thrift.binary",
+		"data_base64" : "YWJj"
+	}`
+	specExampleJSONData = `{
+		"specversion" : "1.0",
+		"type" : "com.example.someevent",
+		"source" : "/mycontext",
+		"id" : "B234-1234-1234",
+		"time" : "2018-04-05T17:31:00Z",
+		"datacontenttype" : "application/json",
+		"data" : {"appinfoA" : "abc", "appinfoB" : 123, "appinfoC" : true}
+	}`
+	// asyncAPIExampleSubmitted is the example-record-submitted envelope of the AsyncAPI spec.
+	asyncAPIExampleSubmitted = `{
+		"specversion": "1.0",
+		"type": "example.record.submitted",
+		"source": "example-service",
+		"id": "01HWXYZ123456789ABCDEF",
+		"time": "2024-01-15T10:30:00Z",
+		"datacontenttype": "application/json",
+		"pf_correlation_id": "corr-123",
+		"data": {"record_id": "EX-001", "title": "Example"}
+	}`
+)
+
+func TestDecodeCloudEventSpecExamples(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		attrs   map[string]string
+		data    string
+	}{
+		{
+			name:    "string data",
+			payload: specExampleXMLData,
+			attrs: map[string]string{
+				ceID: "C234-1234-1234", ceType: "com.example.someevent", ceSource: "/mycontext",
+				ceDataContentType: "application/xml", "comexampleextension1": "value", "comexampleothervalue": "5",
+			},
+			data: `<much wow="xml"/>`,
+		},
+		{
+			name:    "base64 data",
+			payload: specExampleBinaryData,
+			attrs: map[string]string{
+				ceID: "A234-1234-1234", ceDataContentType: "application/vnd.apache.thrift.binary", "comexampleothervalue": "5",
+			},
+			data: "abc",
+		},
+		{
+			name:    "json data",
+			payload: specExampleJSONData,
+			attrs:   map[string]string{ceID: "B234-1234-1234", ceTime: "2018-04-05T17:31:00Z"},
+			data:    `{"appinfoA" : "abc", "appinfoB" : 123, "appinfoC" : true}`,
+		},
+		{
+			name:    "asyncapi envelope",
+			payload: asyncAPIExampleSubmitted,
+			attrs:   map[string]string{ceType: "example.record.submitted", cePfCorrelationID: "corr-123"},
+			data:    `{"record_id": "EX-001", "title": "Example"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := message.NewMessage("msg-1", []byte(tt.payload))
+			AssertNoError(t, decodeCloudEvent(msg), "decode")
+
+			AssertEqual(t, string(msg.Payload), tt.data, "data")
+			AssertEqual(t, msg.Metadata.Get("ce_specversion"), "1.0", "specversion")
+			for name, want := range tt.attrs {
+				AssertEqual(t, msg.Metadata.Get(cloudEventsMetadataPrefix+name), want, name)
+			}
+			if _, ok := msg.Metadata["ce_subject"]; ok {
+				t.Error("null attributes must be treated as absent")
+			}
+		})
+	}
+}
+
+func TestDecodeCloudEventBinaryMode(t *testing.T) {
+	msg := message.NewMessage("msg-1", []byte(`{"record_id":"EX-001"}`))
+	msg.Metadata.Set("ce_specversion", "1.0")
+	msg.Metadata.Set("ce_type", "example.record.submitted")
+	msg.Metadata.Set("ce_source", "example-service")
+	msg.Metadata.Set("ce_id", "01HWXYZ123456789ABCDEF")
+	msg.Metadata.Set("ce_pf_correlation_id", "corr-123")
+	msg.Metadata.Set("ce_pf_trace_id", "trace-1")
+	msg.Metadata.Set(contentTypeMetadataKey, "application/json")
+
+	AssertNoError(t, decodeCloudEvent(msg), "decode")
+	AssertEqual(t, string(msg.Payload), `{"record_id":"EX-001"}`, "payload")
+	AssertEqual(t, msg.Metadata.Get("ce_datacontenttype"), "application/json", "datacontenttype")
+	AssertEqual(t, msg.Metadata.Get(protoflow.MetadataKeyCorrelationID), "corr-123", "correlation id")
+	AssertEqual(t, msg.Metadata.Get(protoflow.MetadataKeyTraceID), "trace-1", "trace id")
+}
+
+func TestDecodeCloudEventLeavesPlainMessages(t *testing.T) {
+	payloads := []string{`{"record_id":"EX-001","title":"Example"}`, `not json`, ``}
+	for _, payload := range payloads {
+		msg := message.NewMessage("msg-1", []byte(payload))
+		AssertNoError(t, decodeCloudEvent(msg), "decode")
+		AssertEqual(t, string(msg.Payload), payload, "payload")
+		AssertEqual(t, len(msg.Metadata), 0, "metadata entries")
+	}
+}
+
+func TestDecodeCloudEventRejectsInvalidEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{"missing id", `{"specversion":"1.0","type":"t","source":"/s"}`},
+		{"missing source", `{"specversion":"1.0","type":"t","id":"1"}`},
+		{"old specversion", `{"specversion":"0.3","type":"t","source":"/s","id":"1"}`},
+		{"data and data_base64", `{"specversion":"1.0","type":"t","source":"/s","id":"1","data":{},"data_base64":"YWJj"}`},
+		{"bad time", `{"specversion":"1.0","type":"t","source":"/s","id":"1","time":"yesterday"}`},
+		{"bad base64", `{"specversion":"1.0","type":"t","source":"/s","id":"1","data_base64":"%%%"}`},
+		{"object attribute", `{"specversion":"1.0","type":"t","source":"/s","id":"1","ext":{"a":1}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := message.NewMessage("msg-1", []byte(tt.payload))
+			err := decodeCloudEvent(msg)
+			if !errors.Is(err, ErrInvalidCloudEvent) {
+				t.Fatalf("error = %v, want ErrInvalidCloudEvent", err)
+			}
+			AssertEqual(t, string(msg.Payload), tt.payload, "payload")
+		})
+	}
+}
+
+func newTestCloudEventsCodec(t *testing.T, mode string) *cloudEventsCodec {
+	t.Helper()
+	codec, err := newCloudEventsCodec(CloudEventsConfig{
+		Mode:            mode,
+		Source:          "event-driven-service",
+		Types:           map[string]string{"example-records-processed": "example.record.processed"},
+		DeadLetterTopic: "poison",
+	})
+	AssertNoError(t, err, "new codec")
+	codec.now = func() time.Time { return time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC) }
+	return codec
+}
+
+func TestCloudEventsCodecRoundTrip(t *testing.T) {
+	for _, mode := range []string{CloudEventsModeBinary, CloudEventsModeStructured} {
+		t.Run(mode, func(t *testing.T) {
+			codec := newTestCloudEventsCodec(t, mode)
+			msg := message.NewMessage("01HWXYZ123456789ABCDEF", []byte(`{"record_id":"EX-001"}`))
+			msg.Metadata.Set(protoflow.MetadataKeyCorrelationID, "corr-123")
+			msg.Metadata.Set(protoflow.MetadataKeyEventSchema, "*domain.ExampleRecord")
+
+			encoded, err := codec.encode("example-records-processed", msg)
+			AssertNoError(t, err, "encode")
+			if mode == CloudEventsModeStructured {
+				AssertEqual(t, encoded.Metadata.Get(contentTypeMetadataKey), cloudEventsContentType, "content type")
+				var envelope map[string]any
+				AssertNoError(t, json.Unmarshal(encoded.Payload, &envelope), "unmarshal envelope")
+				if _, ok := envelope[ceData].(map[string]any); !ok {
+					t.Errorf("data = %v, want the embedded JSON object", envelope[ceData])
+				}
+			}
+
+			// Consumers only receive payload and metadata.
+			consumed := message.NewMessage(encoded.UUID, encoded.Payload)
+			consumed.Metadata = encoded.Metadata
+			AssertNoError(t, decodeCloudEvent(consumed), "decode")
+
+			AssertEqual(t, string(consumed.Payload), `{"record_id":"EX-001"}`, "payload")
+			AssertEqual(t, consumed.Metadata.Get("ce_type"), "example.record.processed", "type")
+			AssertEqual(t, consumed.Metadata.Get("ce_source"), "event-driven-service", "source")
+			AssertEqual(t, consumed.Metadata.Get("ce_id"), "01HWXYZ123456789ABCDEF", "id")
+			AssertEqual(t, consumed.Metadata.Get("ce_time"), "2024-01-15T10:30:00Z", "time")
+			AssertEqual(t, consumed.Metadata.Get("ce_pf_correlation_id"), "corr-123", "correlation extension")
+			AssertEqual(t, consumed.Metadata.Get(protoflow.MetadataKeyEventSchema), "*domain.ExampleRecord", "schema")
+		})
+	}
+}
+
+func TestCloudEventsCodecEncodesNonJSONDataAsBase64(t *testing.T) {
+	codec := newTestCloudEventsCodec(t, CloudEventsModeStructured)
+	encoded, err := codec.encode("other", message.NewMessage("msg-1", []byte("abc")))
+	AssertNoError(t, err, "encode")
+
+	var envelope map[string]any
+	AssertNoError(t, json.Unmarshal(encoded.Payload, &envelope), "unmarshal envelope")
+	AssertEqual(t, envelope[ceDataBase64], any("YWJj"), "data_base64")
+	AssertEqual(t, envelope[ceType], any("other"), "type falls back to the topic")
+}
+
+func TestCloudEventsCodecMarksDeadLetters(t *testing.T) {
+	codec := newTestCloudEventsCodec(t, CloudEventsModeStructured)
+	msg := message.NewMessage("msg-1", []byte(`{}`))
+	// Attributes of the consumed event are replaced by those of the new one.
+	msg.Metadata.Set("ce_type", "example.record.submitted")
+
+	encoded, err := codec.encode("poison", msg)
+	AssertNoError(t, err, "encode")
+
+	var envelope map[string]any
+	AssertNoError(t, json.Unmarshal(encoded.Payload, &envelope), "unmarshal envelope")
+	AssertEqual(t, envelope[cePfDeadLetter], any(true), "pf_dead_letter")
+	AssertEqual(t, envelope[ceType], any("poison"), "type")
+	AssertEqual(t, encoded.Metadata.Get("ce_type"), "", "stale binary attributes")
+}
+
+func TestNewCloudEventsCodecValidatesConfig(t *testing.T) {
+	if _, err := newCloudEventsCodec(CloudEventsConfig{Mode: "batched", Source: "svc"}); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+	if _, err := newCloudEventsCodec(CloudEventsConfig{}); err == nil {
+		t.Error("expected an error without a source")
+	}
+	codec, err := newCloudEventsCodec(CloudEventsConfig{Source: "svc"})
+	AssertNoError(t, err, "defaults")
+	AssertEqual(t, codec.cfg.Mode, CloudEventsModeBinary, "default mode")
+}
+
+type recordingPublisher struct {
+	message.Publisher
+	topic string
+	msgs  []*message.Message
+}
+
+func (p *recordingPublisher) Publish(topic string, msgs ...*message.Message) error {
+	p.topic = topic
+	p.msgs = append(p.msgs, msgs...)
+	return nil
+}
+
+func TestCloudEventsPublisherKeepsOriginalMessage(t *testing.T) {
+	inner := &recordingPublisher{}
+	publisher := cloudEventsPublisher{Publisher: inner, codec: newTestCloudEventsCodec(t, CloudEventsModeStructured)}
+	msg := message.NewMessage("msg-1", []byte(`{"record_id":"EX-001"}`))
+
+	AssertNoError(t, publisher.Publish("example-records-processed", msg), "publish")
+	AssertResultCount(t, len(inner.msgs), 1)
+	AssertEqual(t, inner.topic, "example-records-processed", "topic")
+	AssertEqual(t, string(msg.Payload), `{"record_id":"EX-001"}`, "original payload")
+	AssertEqual(t, msg.Metadata.Get(contentTypeMetadataKey), "", "original content type")
+	AssertEqual(t, inner.msgs[0].Metadata.Get(contentTypeMetadataKey), cloudEventsContentType, "published content type")
+}
+
+func TestCloudEventsMiddlewarePoisonsInvalidEvents(t *testing.T) {
+	var poisoned []*message.Message
+	mw := newCloudEventsMiddleware(func(msg *message.Message) error {
+		poisoned = append(poisoned, msg)
+		return nil
+	})
+	calls := 0
+	handler := mw(countingHandler(&calls, nil))
+
+	_, err := handler(message.NewMessage("msg-1", []byte(asyncAPIExampleSubmitted)))
+	AssertNoError(t, err, "valid event")
+
+	_, err = handler(message.NewMessage("msg-2", []byte(`{"specversion":"1.0","type":"t","source":"/s"}`)))
+	AssertNoError(t, err, "invalid event")
+	AssertEqual(t, calls, 1, "handler calls")
+	AssertResultCount(t, len(poisoned), 1)
+	AssertEqual(t, poisoned[0].UUID, "msg-2", "poisoned message")
+	AssertMetadataHasKey(t, protoflow.Metadata(poisoned[0].Metadata), middleware.ReasonForPoisonedKey)
+
+	failing := newCloudEventsMiddleware(func(*message.Message) error { return errors.New("broker down") })
+	if _, err := failing(countingHandler(&calls, nil))(message.NewMessage("msg-3", []byte(`{"specversion":"2.0"}`))); err == nil {
+		t.Error("expected the message to be nacked when the poison queue is unavailable")
+	}
+}
@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"github.com/drblury/protoflow"
+)
+
+// cloudEventsTransportFactory builds the configured protoflow transport, along with
+// the transports of routes, and wraps its publisher, so every published message
+// becomes a CloudEvent: handler outputs, PublishProto calls, relayed outbox rows and
+// poison queue messages alike.
+type cloudEventsTransportFactory struct {
+	codec  *cloudEventsCodec
+	queues queueTransports
+}
+
+func (f cloudEventsTransportFactory) Build(ctx context.Context, conf *protoflow.Config, logger watermill.LoggerAdapter) (protoflow.Transport, error) {
+	transport, err := f.queues.build(ctx, conf, logger, buildProtoflowTransport)
+	if err != nil {
+		return protoflow.Transport{}, err
+	}
+	return protoflow.Transport{
+		Publisher:  cloudEventsPublisher{Publisher: transport.Publisher, codec: f.codec},
+		Subscriber: transport.Subscriber,
+	}, nil
+}
+
+// buildProtoflowTransport builds the transport selected by conf.
+func buildProtoflowTransport(ctx context.Context, conf *protoflow.Config, logger watermill.LoggerAdapter) (protoflow.Transport, error) {
+	transport, err := protoflow.BuildTransport(ctx, conf, logger)
+	if err != nil {
+		return protoflow.Transport{}, err
+	}
+	return protoflow.Transport{Publisher: transport.Publisher, Subscriber: transport.Subscriber}, nil
+}
+
+// cloudEventsPublisher encodes messages before handing them to the wrapped publisher.
+// The given messages are not modified.
+type cloudEventsPublisher struct {
+	message.Publisher
+	codec *cloudEventsCodec
+}
+
+func (p cloudEventsPublisher) Publish(topic string, msgs ...*message.Message) error {
+	encoded := make([]*message.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		out, err := p.codec.encode(topic, msg)
+		if err != nil {
+			return fmt.Errorf("encode CloudEvent %s: %w", msg.UUID, err)
+		}
+		encoded = append(encoded, out)
+	}
+	return p.Publisher.Publish(topic, encoded...)
+}
+
+// cloudEventsMiddleware decodes consumed CloudEvents before any other middleware
+// looks at the payload. It runs ahead of the poison queue middleware, so messages
+// that violate the specification are moved to the poison queue here and acked.
+func cloudEventsMiddleware() protoflow.MiddlewareRegistration {
+	return protoflow.MiddlewareRegistration{
+		Name: "cloudevents",
+		Builder: func(s *protoflow.Service) (message.HandlerMiddleware, error) {
+			return newCloudEventsMiddleware(func(msg *message.Message) error {
+				return s.Publish(msg.Context(), s.Conf.PoisonQueue, msg)
+			}), nil
+		},
+	}
+}
+
+func newCloudEventsMiddleware(poison func(*message.Message) error) message.HandlerMiddleware {
+	return func(h message.HandlerFunc) message.HandlerFunc {
+		return func(msg *message.Message) ([]*message.Message, error) {
+			err := decodeCloudEvent(msg)
+			if err == nil {
+				return h(msg)
+			}
+
+			// Same metadata as the Watermill poison queue middleware.
+			msg.Metadata.Set(middleware.ReasonForPoisonedKey, err.Error())
+			msg.Metadata.Set(middleware.PoisonedTopicKey, message.SubscribeTopicFromCtx(msg.Context()))
+			msg.Metadata.Set(middleware.PoisonedHandlerKey, message.HandlerNameFromCtx(msg.Context()))
+			msg.Metadata.Set(middleware.PoisonedSubscriberKey, message.SubscriberNameFromCtx(msg.Context()))
+			if poisonErr := poison(msg); poisonErr != nil {
+				return nil, errors.Join(err, fmt.Errorf("publish to poison queue: %w", poisonErr))
+			}
+			return nil, nil
+		}
+	}
+}
@@ -1,8 +1,107 @@
 package events
 
+import (
+	"fmt"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
 type Config struct {
-	DemoConsumeQueue    string
-	DemoPublishQueue    string
-	ExampleConsumeQueue string
-	ExamplePublishQueue string
+	DemoConsumeQueue          string
+	DemoPublishQueue          string
+	ExampleConsumeQueue       string
+	ExamplePublishQueue       string
+	ExampleUpdateConsumeQueue string
+	ExampleFailedQueue        string
+	ExampleBatchConsumeQueue  string
+	ExampleBatchPublishQueue  string
+	BatchParallelism          int
+	// MaxConcurrency bounds the messages a handler processes at once, unless its
+	// route sets max_concurrency.
+	MaxConcurrency int
+
+	OutboxPollInterval         time.Duration
+	OutboxBatchSize            int
+	OutboxMaxAttempts          int
+	OutboxRetryInitialInterval time.Duration
+	OutboxRetryMaxInterval     time.Duration
+	OutboxLease                time.Duration
+
+	InboxEnabled bool
+	InboxTTL     time.Duration
+
+	CloudEventsMode   string
+	CloudEventsSource string
+
+	// Routes replaces the handler registrations built from the queue settings above.
+	Routes []Route
+}
+
+// routes returns the configured routes. Without any, the routes are built from the
+// queue settings; the batch handler keeps its progress and the result handler the
+// processing results in the database, so both are only enabled with one.
+func (c *Config) routes(withDatabase bool) []Route {
+	if len(c.Routes) > 0 {
+		return c.Routes
+	}
+	return []Route{
+		{Handler: demoHandlerName, ConsumeQueue: c.DemoConsumeQueue, PublishQueue: c.DemoPublishQueue},
+		{Handler: exampleRecordHandlerName, ConsumeQueue: c.ExampleConsumeQueue, PublishQueue: c.ExamplePublishQueue},
+		// Updated records are published to the example queue so they are processed again.
+		{Handler: exampleRecordUpdateHandlerName, ConsumeQueue: c.ExampleUpdateConsumeQueue, PublishQueue: c.ExampleConsumeQueue},
+		{Handler: exampleBatchHandlerName, ConsumeQueue: c.ExampleBatchConsumeQueue, PublishQueue: c.ExampleBatchPublishQueue, Enabled: &withDatabase},
+		{Handler: exampleResultHandlerName, ConsumeQueue: c.ExamplePublishQueue, Enabled: &withDatabase},
+	}
+}
+
+// handlerEventTypes lists the event types of the AsyncAPI channels a handler
+// consumes from and publishes to.
+var handlerEventTypes = map[string]struct{ consume, publish string }{
+	exampleRecordHandlerName:       {"example.record.submitted", "example.record.processed"},
+	exampleRecordUpdateHandlerName: {"example.record.updated", "example.record.submitted"},
+	exampleBatchHandlerName:        {"example.batch.requested", "example.batch.completed"},
+	exampleResultHandlerName:       {"example.record.processed", ""},
+}
+
+// cloudEventTypes maps the queues of routes onto the event types of the AsyncAPI channels.
+func (c *Config) cloudEventTypes(routes []Route) map[string]string {
+	types := map[string]string{c.ExampleFailedQueue: "example.record.failed"}
+	for _, route := range routes {
+		eventTypes, ok := handlerEventTypes[route.Handler]
+		if !ok {
+			continue
+		}
+		if route.ConsumeQueue != "" {
+			types[route.ConsumeQueue] = eventTypes.consume
+		}
+		if route.PublishQueue != "" {
+			types[route.PublishQueue] = eventTypes.publish
+		}
+	}
+	return types
+}
+
+// legacyOutboxSchemas lists the event_message_schema of the messages published by the
+// handlers that ran before the outbox relay. protoflow's outbox middleware stored
+// their outputs without a topic, in rows named after that schema.
+var legacyOutboxSchemas = map[string]string{
+	demoHandlerName:          fmt.Sprintf("%T", &processedDemoEvent{}),
+	exampleRecordHandlerName: fmt.Sprintf("%T", &domain.ExampleResult{}),
+}
+
+// legacyOutboxTopics maps the handlers of legacy outbox rows onto the publish queue
+// of the first route that produced them.
+func legacyOutboxTopics(routes []Route) map[string]string {
+	topics := make(map[string]string)
+	for _, route := range routes {
+		schema, ok := legacyOutboxSchemas[route.Handler]
+		if !ok || route.PublishQueue == "" {
+			continue
+		}
+		if _, seen := topics[schema]; !seen {
+			topics[schema] = route.PublishQueue
+		}
+	}
+	return topics
 }
@@ -5,9 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"drblury/event-driven-service/internal/domain"
-
 	"github.com/drblury/protoflow"
+	date "google.golang.org/genproto/googleapis/type/date"
 )
 
 // =============================================================================
@@ -92,7 +91,7 @@ func testDemoHandlerDateHandling(t *testing.T, handler demoHandlerFunc, ctx cont
 	t.Helper()
 
 	t.Run("varying dates", func(t *testing.T) {
-		dates := []*domain.Date{{Year: 2020, Month: 1, Day: 1}, {Year: 2024, Month: 6, Day: 15}, {Year: 2030, Month: 12, Day: 31}}
+		dates := []*date.Date{{Year: 2020, Month: 1, Day: 1}, {Year: 2024, Month: 6, Day: 15}, {Year: 2030, Month: 12, Day: 31}}
 		for i, date := range dates {
 			evt := protoflow.JSONMessageContext[*demoEvent]{Payload: &demoEvent{ID: i, Date: date}}
 			result, err := handler(ctx, evt)
@@ -102,7 +101,7 @@ func testDemoHandlerDateHandling(t *testing.T, handler demoHandlerFunc, ctx cont
 	})
 
 	t.Run("zero date values", func(t *testing.T) {
-		evt := protoflow.JSONMessageContext[*demoEvent]{Payload: &demoEvent{ID: 0, Date: &domain.Date{Year: 0, Month: 0, Day: 0}}}
+		evt := protoflow.JSONMessageContext[*demoEvent]{Payload: &demoEvent{ID: 0, Date: &date.Date{Year: 0, Month: 0, Day: 0}}}
 		result, err := handler(ctx, evt)
 		AssertNoError(t, err, "handler returned error")
 		AssertResultCount(t, len(result), 1)
@@ -151,7 +150,7 @@ func TestDemoEventStruct(t *testing.T) {
 	t.Run("with values", func(t *testing.T) {
 		evt := &demoEvent{
 			ID:   42,
-			Date: &domain.Date{Year: 2024, Month: 1, Day: 15},
+			Date: &date.Date{Year: 2024, Month: 1, Day: 15},
 		}
 		AssertEqual(t, evt.ID, 42, "ID mismatch")
 		AssertEqual(t, evt.Date.Year, int32(2024), "Year mismatch")
@@ -166,7 +165,7 @@ func TestDemoEventStruct(t *testing.T) {
 	})
 
 	t.Run("various date values", func(t *testing.T) {
-		dates := []*domain.Date{
+		dates := []*date.Date{
 			nil,
 			{Year: 0, Month: 0, Day: 0},
 			{Year: 2024, Month: 1, Day: 1},
@@ -203,7 +202,7 @@ func TestProcessedDemoEventStruct(t *testing.T) {
 		evt := &processedDemoEvent{
 			ID:   123,
 			Time: now,
-			Date: &domain.Date{Year: 2024, Month: 6, Day: 15},
+			Date: &date.Date{Year: 2024, Month: 6, Day: 15},
 		}
 		AssertEqual(t, evt.ID, 123, "ID mismatch")
 		if evt.Time != now {
@@ -2,41 +2,137 @@ package events
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log/slog"
 	"math/rand/v2"
+	"strconv"
 	"time"
 
+	"drblury/event-driven-service/internal/database"
 	"drblury/event-driven-service/internal/domain"
+	"drblury/event-driven-service/internal/usecase"
 
 	"github.com/drblury/protoflow"
+	date "google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/protobuf/proto"
 )
 
-// registerAppEventHandlers wires the demo handlers used by this application.
-// In your own code base you can register entirely different handlers against
-// the shared protoflow.Service instance.
-func registerAppEventHandlers(svc *protoflow.Service, cfg *Config) error {
-
-	if err := protoflow.RegisterJSONHandler(svc, protoflow.JSONHandlerRegistration[*demoEvent, *processedDemoEvent]{
-		Name:         "demoHandler",
-		ConsumeQueue: cfg.DemoConsumeQueue,
-		PublishQueue: cfg.DemoPublishQueue,
-		Handler:      demoHandler(),
-	}); err != nil {
+// Status values written by the record handlers.
+const (
+	statusReasonProcessing = "processing"
+	statusReasonProcessed  = "processed"
+	exampleResultStatus    = "completed"
+)
+
+// ExampleRecordUpdater applies partial updates to stored example records.
+// *usecase.AppLogic satisfies it.
+type ExampleRecordUpdater interface {
+	ApplyExampleRecordUpdate(ctx context.Context, update *domain.ExampleRecordUpdate) (*domain.ExampleRecord, error)
+}
+
+// ExampleRecordLifecycle moves stored example records through their statuses.
+// *usecase.AppLogic satisfies it.
+type ExampleRecordLifecycle interface {
+	GetExample(ctx context.Context, id string) (*domain.ExampleRecord, error)
+	TransitionExampleStatus(ctx context.Context, id string, to domain.ExampleRecordStatus, reason string) (*domain.ExampleRecord, error)
+}
+
+// registerAppEventHandlers wires the demo handlers used by this application along
+// the configured routes. In your own code base you can register entirely different
+// handlers against the shared protoflow.Service instance.
+func registerAppEventHandlers(svc *protoflow.Service, cfg *Config, updater ExampleRecordUpdater, lifecycle ExampleRecordLifecycle, db database.Repository, ordering *orderedProcessing) error {
+	registry, err := newAppHandlerRegistry(cfg, updater, lifecycle, db, ordering)
+	if err != nil {
 		return err
 	}
+	return registry.RegisterRoutes(svc, cfg.routes(db != nil))
+}
 
-	if err := protoflow.RegisterProtoHandler(svc, protoflow.ProtoHandlerRegistration[*domain.ExampleRecord]{
-		Name:         "exampleRecordHandler",
-		ConsumeQueue: cfg.ExampleConsumeQueue,
-		PublishQueue: cfg.ExamplePublishQueue,
-		Handler:      exampleRecordHandler(),
-	}); err != nil {
-		return err
+// newAppHandlerRegistry returns the factories of the handlers routes can refer to.
+// Record messages are ordered by record_id and batches by batch_id unless the route
+// sets another ordering_key. Without a lifecycle, record statuses are not tracked.
+func newAppHandlerRegistry(cfg *Config, updater ExampleRecordUpdater, lifecycle ExampleRecordLifecycle, db database.Repository, ordering *orderedProcessing) (*HandlerRegistry, error) {
+	factories := map[string]HandlerFactory{
+		demoHandlerName: func(svc *protoflow.Service, route Route) error {
+			if err := route.checkOptions(routeOrderingOptions...); err != nil {
+				return err
+			}
+			if err := ordering.configureRoute(route, orderingKeyNone); err != nil {
+				return err
+			}
+			return protoflow.RegisterJSONHandler(svc, protoflow.JSONHandlerRegistration[*demoEvent, *processedDemoEvent]{
+				Name:         route.RouteName(),
+				ConsumeQueue: route.ConsumeQueue,
+				PublishQueue: route.PublishQueue,
+				Handler:      demoHandler(),
+			})
+		},
+		exampleRecordHandlerName: func(svc *protoflow.Service, route Route) error {
+			if err := ordering.configureRoute(route, "payload:record_id"); err != nil {
+				return err
+			}
+			return registerProtoRoute(svc, route, exampleRecordHandler(lifecycle))
+		},
+		exampleRecordUpdateHandlerName: func(svc *protoflow.Service, route Route) error {
+			if err := ordering.configureRoute(route, "payload:record_id"); err != nil {
+				return err
+			}
+			return registerProtoRoute(svc, route, exampleRecordUpdateHandler(updater))
+		},
+		exampleBatchHandlerName: func(svc *protoflow.Service, route Route) error {
+			if db == nil {
+				return errors.New("the batch handler requires a database")
+			}
+			if err := ordering.configureRoute(route, "payload:batch_id"); err != nil {
+				return err
+			}
+			parallelism, err := route.intOption("parallelism", cfg.BatchParallelism)
+			if err != nil {
+				return err
+			}
+			return registerProtoRoute(svc, route, exampleBatchHandler(newBatchRunner(db, db, processExampleRecord, parallelism)), "parallelism")
+		},
+		exampleResultHandlerName: func(svc *protoflow.Service, route Route) error {
+			if db == nil {
+				return errors.New("the result handler requires a database")
+			}
+			if err := ordering.configureRoute(route, "payload:record_id"); err != nil {
+				return err
+			}
+			return registerProtoRoute(svc, route, exampleResultHandler(db))
+		},
 	}
 
-	return nil
+	registry := NewHandlerRegistry()
+	for name, factory := range factories {
+		if err := registry.Register(name, factory); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// registerProtoRoute registers a proto handler for route. Besides the handler
+// specific and the ordering options, every proto route accepts validate_outgoing,
+// which validates published messages against their protovalidate rules.
+func registerProtoRoute[T proto.Message](svc *protoflow.Service, route Route, handler protoflow.ProtoMessageHandler[T], options ...string) error {
+	if err := route.checkOptions(append(append(options, "validate_outgoing"), routeOrderingOptions...)...); err != nil {
+		return err
+	}
+	validateOutgoing, err := route.boolOption("validate_outgoing")
+	if err != nil {
+		return err
+	}
+	return protoflow.RegisterProtoHandler(svc, protoflow.ProtoHandlerRegistration[T]{
+		Name:             route.RouteName(),
+		ConsumeQueue:     route.ConsumeQueue,
+		PublishQueue:     route.PublishQueue,
+		Handler:          handler,
+		ValidateOutgoing: validateOutgoing,
+	})
 }
 
 func RunExampleSimulation(ctx context.Context, svc *protoflow.Service, cfg *Config) {
@@ -74,7 +170,7 @@ func runSomeSimulation(ctx context.Context, svc *protoflow.Service, queueName st
 					RequestedBy:      "simulation-bot",
 					RequiresFollowUp: followUp,
 					Priority:         int32(rand.IntN(5) + 1), // #nosec G404 G115 -- non-security simulation data with bounded values
-					DesiredStartDate: &domain.Date{
+					DesiredStartDate: &date.Date{
 						Year:  int32(rand.IntN(5) + 2020), // #nosec G404 G115 -- non-security simulation data with bounded values
 						Month: int32(i%12 + 1),            // #nosec G115 -- bounded value 1-12
 						Day:   int32((i % 28) + 1),        // #nosec G115 -- bounded value 1-28
@@ -93,14 +189,14 @@ func runSomeSimulation(ctx context.Context, svc *protoflow.Service, queueName st
 }
 
 type demoEvent struct {
-	ID   int          `json:"id"`
-	Date *domain.Date `json:"date"`
+	ID   int        `json:"id"`
+	Date *date.Date `json:"date"`
 }
 
 type processedDemoEvent struct {
-	ID   int          `json:"id"`
-	Time time.Time    `json:"time"`
-	Date *domain.Date `json:"date"`
+	ID   int        `json:"id"`
+	Time time.Time  `json:"time"`
+	Date *date.Date `json:"date"`
 }
 
 func demoHandler() protoflow.JSONMessageHandler[*demoEvent, *processedDemoEvent] {
@@ -137,35 +233,157 @@ func demoHandler() protoflow.JSONMessageHandler[*demoEvent, *processedDemoEvent]
 	}
 }
 
-func exampleRecordHandler() protoflow.ProtoMessageHandler[*domain.ExampleRecord] {
+// exampleRecordHandler processes a record and moves the stored record from
+// IN_PROGRESS to COMPLETED. Records that are not stored, such as the simulated ones,
+// are processed without status changes.
+func exampleRecordHandler(lifecycle ExampleRecordLifecycle) protoflow.ProtoMessageHandler[*domain.ExampleRecord] {
 	return func(ctx context.Context, e protoflow.ProtoMessageContext[*domain.ExampleRecord]) ([]protoflow.ProtoMessageOutput, error) {
-		// #nosec G404 -- non-security simulation for random failures
-		if rand.IntN(10) == 0 {
-			return nil, errors.New("fatal error processing example event")
+		tracked, completed, err := startProcessing(ctx, lifecycle, e.Payload.GetRecordId())
+		if err != nil {
+			return nil, err
+		}
+		if completed {
+			slog.InfoContext(ctx, "Skipping redelivered record that is already completed", "record_id", e.Payload.GetRecordId())
+			return nil, nil
 		}
 
-		statuses := []string{"queued", "in-progress", "completed"}
-		status := statuses[rand.IntN(len(statuses))] // #nosec G404 -- non-security simulation data
-
-		now := time.Now()
-		result := &domain.ExampleResult{
-			RecordId: e.Payload.GetRecordId(),
-			Status:   status,
-			Note:     fmt.Sprintf("processed %s", e.Payload.GetTitle()),
-			ProcessedOn: &domain.Date{
-				Year:  int32(now.Year()),  // #nosec G115 -- year is bounded to reasonable values
-				Month: int32(now.Month()), // #nosec G115 -- month is bounded 1-12
-				Day:   int32(now.Day()),   // #nosec G115 -- day is bounded 1-31
-			},
+		result, err := processExampleRecord(ctx, e.Payload)
+		if err != nil {
+			return nil, err
+		}
+		if tracked {
+			_, err := lifecycle.TransitionExampleStatus(ctx, e.Payload.GetRecordId(), domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_COMPLETED, statusReasonProcessed)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		metadata := e.Metadata.WithAll(
 			protoflow.Metadata{
 				"handler":      "exampleRecordHandler",
-				"processed_at": now.Format(time.RFC3339),
+				"processed_at": time.Now().Format(time.RFC3339),
 			},
 		)
 
 		return []protoflow.ProtoMessageOutput{{Message: result, Metadata: metadata}}, nil
 	}
 }
+
+// startProcessing moves the stored record to IN_PROGRESS and reports whether the
+// record is stored and whether it is already completed. Delivery is at least once,
+// so a completed record is a redelivery that needs no processing. A record whose
+// status does not allow processing otherwise is unprocessable.
+func startProcessing(ctx context.Context, lifecycle ExampleRecordLifecycle, recordID string) (tracked, completed bool, err error) {
+	if lifecycle == nil {
+		return false, false, nil
+	}
+	_, err = lifecycle.TransitionExampleStatus(ctx, recordID, domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_IN_PROGRESS, statusReasonProcessing)
+	switch {
+	case err == nil:
+		return true, false, nil
+	case errors.Is(err, domain.ErrorNotFound):
+		return false, false, nil
+	case errors.Is(err, usecase.ErrInvalidStatusTransition):
+		record, getErr := lifecycle.GetExample(ctx, recordID)
+		if getErr != nil {
+			return false, false, getErr
+		}
+		if record.GetStatus() == domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_COMPLETED {
+			return true, true, nil
+		}
+		return false, false, fmt.Errorf("%w: %w", protoflow.ErrUnprocessable, err)
+	default:
+		return false, false, err
+	}
+}
+
+// processExampleRecord simulates the processing of a record. It is shared by the
+// single-record and the batch handler.
+func processExampleRecord(_ context.Context, record *domain.ExampleRecord) (*domain.ExampleResult, error) {
+	// #nosec G404 -- non-security simulation for random failures
+	if rand.IntN(10) == 0 {
+		return nil, errors.New("fatal error processing example event")
+	}
+
+	return &domain.ExampleResult{
+		RecordId:    record.GetRecordId(),
+		Status:      exampleResultStatus,
+		Note:        fmt.Sprintf("processed %s", record.GetTitle()),
+		ProcessedOn: dateOf(time.Now()),
+	}, nil
+}
+
+// dateOf converts t to the calendar date used in event payloads.
+func dateOf(t time.Time) *date.Date {
+	return &date.Date{
+		Year:  int32(t.Year()),  // #nosec G115 -- year is bounded to reasonable values
+		Month: int32(t.Month()), // #nosec G115 -- month is bounded 1-12
+		Day:   int32(t.Day()),   // #nosec G115 -- day is bounded 1-31
+	}
+}
+
+// exampleResultHandler stores the results emitted by the record handler under their
+// record ID and correlation ID, so the outcome of a record can be looked up later.
+// It publishes nothing.
+func exampleResultHandler(store database.ResultStore) protoflow.ProtoMessageHandler[*domain.ExampleResult] {
+	return func(ctx context.Context, e protoflow.ProtoMessageContext[*domain.ExampleResult]) ([]protoflow.ProtoMessageOutput, error) {
+		correlationID := e.Metadata[protoflow.MetadataKeyCorrelationID]
+		received, ok := receivedMessageFromContext(ctx)
+		if !ok {
+			received.CorrelationID = correlationID
+		}
+		err := store.StoreExampleResult(ctx, &database.StoredExampleResult{
+			ID:            exampleResultID(received, e.Payload.GetRecordId()),
+			RecordID:      e.Payload.GetRecordId(),
+			CorrelationID: correlationID,
+			Status:        e.Payload.GetStatus(),
+			Note:          e.Payload.GetNote(),
+			ProcessedOn:   e.Payload.GetProcessedOn(),
+		})
+		if errors.Is(err, domain.ErrorBadRequest) {
+			return nil, fmt.Errorf("%w: %w", protoflow.ErrUnprocessable, err)
+		}
+		return nil, err
+	}
+}
+
+// exampleResultID derives the ID of a result from the correlation ID and record ID of
+// its message. The record handler emits one result per record and correlation ID,
+// so a redelivered result gets the ID of the stored one and is ignored. Results that
+// arrived without correlation ID are identified by their message UUID instead, which
+// redeliveries keep as well.
+func exampleResultID(received receivedMessage, recordID string) string {
+	key := received.CorrelationID
+	if key == "" {
+		key = "\x00message\x00" + received.UUID
+	}
+	sum := sha256.Sum256([]byte(key + "\x00" + recordID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// exampleRecordUpdateHandler patches the stored record and emits the updated record.
+// Updates that can never succeed, such as an unknown record, an invalid mask or a
+// result that violates the record rules, are marked unprocessable so they go to the
+// poison queue instead of being retried.
+func exampleRecordUpdateHandler(updater ExampleRecordUpdater) protoflow.ProtoMessageHandler[*domain.ExampleRecordUpdate] {
+	return func(ctx context.Context, e protoflow.ProtoMessageContext[*domain.ExampleRecordUpdate]) ([]protoflow.ProtoMessageOutput, error) {
+		record, err := updater.ApplyExampleRecordUpdate(ctx, e.Payload)
+		if err != nil {
+			var validationErr domain.ErrValidations
+			if errors.Is(err, domain.ErrorBadRequest) || errors.Is(err, domain.ErrorNotFound) || errors.As(err, &validationErr) {
+				return nil, fmt.Errorf("%w: %w", protoflow.ErrUnprocessable, err)
+			}
+			return nil, err
+		}
+
+		metadata := e.Metadata.WithAll(
+			protoflow.Metadata{
+				"handler":        "exampleRecordUpdateHandler",
+				"record_version": strconv.Itoa(int(record.GetVersion())),
+				"updated_at":     time.Now().Format(time.RFC3339),
+			},
+		)
+
+		return []protoflow.ProtoMessageOutput{{Message: record, Metadata: metadata}}, nil
+	}
+}
@@ -17,7 +17,7 @@ func BuildEventService(
 	ctx context.Context,
 	cfg *Config,
 	logger *slog.Logger,
-	db *database.Database,
+	db database.Repository,
 	appLogic *usecase.AppLogic,
 	protoflowCfg *protoflow.Config,
 ) (*protoflow.Service, error) {
@@ -26,7 +26,40 @@ func BuildEventService(
 		return nil, errors.New("events configuration is required")
 	}
 
-	middlewares := composeEventMiddlewares(protoflowCfg)
+	routes := cfg.routes(db != nil)
+	queues, err := routeQueueTransports(routes)
+	if err != nil {
+		logger.Error("invalid route transports", "error", err)
+		return nil, err
+	}
+	codec, err := newCloudEventsCodec(CloudEventsConfig{
+		Mode:            cfg.CloudEventsMode,
+		Source:          cfg.CloudEventsSource,
+		Types:           cfg.cloudEventTypes(routes),
+		DeadLetterTopic: protoflowCfg.PoisonQueue,
+	})
+	if err != nil {
+		logger.Error("invalid CloudEvents configuration", "error", err)
+		return nil, err
+	}
+
+	// Consumed CloudEvents are decoded before any other middleware reads the payload,
+	// and recorded as received before a correlation ID is assigned to them. Ordering
+	// wraps the rest of the chain, so a message and its retries finish before the next
+	// message with the same key starts.
+	ordering := newOrderedProcessing(cfg.MaxConcurrency)
+	lifecycle := recordLifecycle(db, appLogic)
+	middlewares := append(
+		[]protoflow.MiddlewareRegistration{cloudEventsMiddleware(), receivedMessageMiddleware(), ordering.middleware()},
+		composeEventMiddlewares(
+			protoflowCfg,
+			outboxStore(db),
+			inboxStore(db, cfg),
+			InboxConfig{TTL: cfg.InboxTTL},
+			cfg.ExampleFailedQueue,
+			lifecycle,
+		)...,
+	)
 
 	validator, err := NewValidator()
 	if err != nil {
@@ -39,14 +72,14 @@ func BuildEventService(
 		protoflow.NewSlogServiceLogger(logger),
 		ctx,
 		protoflow.ServiceDependencies{
-			Outbox:                    db,
 			Validator:                 validator,
 			DisableDefaultMiddlewares: true,
 			Middlewares:               middlewares,
+			TransportFactory:          cloudEventsTransportFactory{codec: codec, queues: queues},
 		},
 	)
 
-	if err := registerAppEventHandlers(svc, cfg); err != nil {
+	if err := registerAppEventHandlers(svc, cfg, appLogic, lifecycle, db, ordering); err != nil {
 		logger.Error("failed to register event handlers", "error", err)
 		return nil, err
 	}
@@ -54,24 +87,104 @@ func BuildEventService(
 	return svc, nil
 }
 
+// outboxStore returns db as an OutboxStore, or nil when no database is configured.
+func outboxStore(db database.Repository) OutboxStore {
+	if db == nil {
+		return nil
+	}
+	return db
+}
+
+// recordLifecycle returns appLogic as an ExampleRecordLifecycle, or nil when no
+// database is configured, since only stored records have a status.
+func recordLifecycle(db database.Repository, appLogic *usecase.AppLogic) ExampleRecordLifecycle {
+	if db == nil || appLogic == nil {
+		return nil
+	}
+	return appLogic
+}
+
+// inboxStore returns db as an InboxStore, or nil when no database is configured or
+// deduplication is disabled.
+func inboxStore(db database.Repository, cfg *Config) InboxStore {
+	if db == nil || !cfg.InboxEnabled {
+		return nil
+	}
+	return db
+}
+
+// BuildOutboxRelay creates the relay that dispatches persisted outbox rows through publisher.
+// It returns nil when no database is configured, since there is nothing to relay.
+func BuildOutboxRelay(
+	cfg *Config,
+	db database.Repository,
+	publisher OutboxPublisher,
+	logger *slog.Logger,
+) (*OutboxRelay, error) {
+	if cfg == nil {
+		return nil, errors.New("events configuration is required")
+	}
+	if db == nil {
+		return nil, nil
+	}
+
+	return NewOutboxRelay(db, publisher, OutboxRelayConfig{
+		PollInterval:         cfg.OutboxPollInterval,
+		BatchSize:            cfg.OutboxBatchSize,
+		MaxAttempts:          cfg.OutboxMaxAttempts,
+		RetryInitialInterval: cfg.OutboxRetryInitialInterval,
+		RetryMaxInterval:     cfg.OutboxRetryMaxInterval,
+		Lease:                cfg.OutboxLease,
+		LegacyTopics:         legacyOutboxTopics(cfg.routes(true)),
+	}, logger)
+}
+
 // composeEventMiddlewares returns the middleware chain enforced by this application.
-func composeEventMiddlewares(cfg *protoflow.Config) []protoflow.MiddlewareRegistration {
+// The inbox middleware is only added when an inbox store is given; it wraps retries
+// and the poison queue so that only the final outcome of a message is recorded.
+// Errors matching the poison queue filter are not retried. With a failed topic, the
+// failure event middleware sends messages whose retries ran out to the poison queue
+// as well, after publishing an example.record.failed event for them. Payloads are
+// validated inside both, so a message breaking its protovalidate rules gets a
+// failure event and is poisoned instead of being redelivered.
+func composeEventMiddlewares(
+	cfg *protoflow.Config,
+	outbox OutboxStore,
+	inbox InboxStore,
+	inboxCfg InboxConfig,
+	failedTopic string,
+	lifecycle ExampleRecordLifecycle,
+) []protoflow.MiddlewareRegistration {
+	poisonFilter := poisonQueueFilter()
 	retryConfig := protoflow.RetryMiddlewareConfig{
 		MaxRetries:      cfg.RetryMaxRetries,
 		InitialInterval: cfg.RetryInitialInterval,
 		MaxInterval:     cfg.RetryMaxInterval,
+		RetryIf: func(err error) bool {
+			return !poisonFilter(err)
+		},
 	}
 
-	return []protoflow.MiddlewareRegistration{
+	middlewares := []protoflow.MiddlewareRegistration{
 		protoflow.CorrelationIDMiddleware(),
 		protoflow.LogMessagesMiddleware(nil),
-		protoflow.ProtoValidateMiddleware(),
-		protoflow.OutboxMiddleware(),
+	}
+	if inbox != nil {
+		middlewares = append(middlewares, inboxMiddleware(inbox, inboxCfg))
+	}
+	middlewares = append(middlewares,
+		outboxMiddleware(outbox),
 		protoflow.TracerMiddleware(),
+		protoflow.PoisonQueueMiddleware(poisonFilter),
+	)
+	if failedTopic != "" {
+		middlewares = append(middlewares, failureEventMiddleware(outbox, failedTopic, lifecycle))
+	}
+	return append(middlewares,
+		protoflow.ProtoValidateMiddleware(),
 		protoflow.RetryMiddleware(retryConfig),
-		protoflow.PoisonQueueMiddleware(poisonQueueFilter()),
 		protoflow.RecovererMiddleware(),
-	}
+	)
 }
 
 // poisonQueueFilter decides when an event should be redirected to the poison queue.
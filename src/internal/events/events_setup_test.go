@@ -10,7 +10,13 @@ import (
 
 	"drblury/event-driven-service/internal/domain"
 
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
 	"github.com/drblury/protoflow"
+	"github.com/drblury/protoflow/transport/channel"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 func TestBuildEventService(t *testing.T) {
@@ -42,7 +48,7 @@ func TestComposeEventMiddlewares(t *testing.T) {
 	})
 
 	t.Run("with default config", func(t *testing.T) {
-		middlewares := composeEventMiddlewares(&protoflow.Config{})
+		middlewares := composeEventMiddlewares(&protoflow.Config{}, nil, nil, InboxConfig{}, "", nil)
 		if len(middlewares) == 0 {
 			t.Error("expected at least one middleware")
 		}
@@ -60,8 +66,8 @@ func TestComposeEventMiddlewares(t *testing.T) {
 
 	t.Run("middleware order is consistent", func(t *testing.T) {
 		cfg := &protoflow.Config{RetryMaxRetries: 3, RetryInitialInterval: 100, RetryMaxInterval: 1000}
-		middlewares1 := composeEventMiddlewares(cfg)
-		middlewares2 := composeEventMiddlewares(cfg)
+		middlewares1 := composeEventMiddlewares(cfg, nil, nil, InboxConfig{}, "", nil)
+		middlewares2 := composeEventMiddlewares(cfg, nil, nil, InboxConfig{}, "", nil)
 		if len(middlewares1) != len(middlewares2) {
 			t.Error("middleware count should be consistent")
 		}
@@ -70,7 +76,7 @@ func TestComposeEventMiddlewares(t *testing.T) {
 
 func assertMiddlewareCount(t *testing.T, cfg *protoflow.Config, expected int) {
 	t.Helper()
-	if got := len(composeEventMiddlewares(cfg)); got != expected {
+	if got := len(composeEventMiddlewares(cfg, nil, nil, InboxConfig{}, "", nil)); got != expected {
 		t.Errorf("expected %d middlewares, got %d", expected, got)
 	}
 }
@@ -129,18 +135,22 @@ func testPoisonQueueFilterUnprocessable(t *testing.T, filter func(error) bool) {
 func testPoisonQueueFilterValidation(t *testing.T, filter func(error) bool) {
 	t.Helper()
 
-	validationErr := domain.ErrValidations{Errors: []string{"test: invalid"}}
+	validationErr := domain.ErrValidations{Errors: []domain.Violation{{Field: "test", Message: "invalid"}}}
 	if !filter(validationErr) {
 		t.Error("expected filter to return true for ErrValidations")
 	}
 
-	emptyValidationErr := domain.ErrValidations{Errors: []string{}}
+	emptyValidationErr := domain.ErrValidations{Errors: []domain.Violation{}}
 	if !filter(emptyValidationErr) {
 		t.Error("expected filter to return true for empty ErrValidations")
 	}
 
 	multiValidationErr := domain.ErrValidations{
-		Errors: []string{"field1: required", "field2: invalid", "field3: too long"},
+		Errors: []domain.Violation{
+			{Field: "field1", Message: "required"},
+			{Field: "field2", Message: "invalid"},
+			{Field: "field3", Message: "too long"},
+		},
 	}
 	if !filter(multiValidationErr) {
 		t.Error("expected filter to return true for ErrValidations with multiple errors")
@@ -154,7 +164,7 @@ func testPoisonQueueFilterValidation(t *testing.T, filter func(error) bool) {
 func testPoisonQueueFilterWrapped(t *testing.T, filter func(error) bool) {
 	t.Helper()
 
-	validationErr := domain.ErrValidations{Errors: []string{"field: required"}}
+	validationErr := domain.ErrValidations{Errors: []domain.Violation{{Field: "field", Message: "required"}}}
 	wrappedErr := errors.Join(errors.New("validation failed"), validationErr)
 	if !filter(wrappedErr) {
 		t.Error("expected filter to return true for wrapped ErrValidations")
@@ -334,10 +344,10 @@ func TestPoisonQueueFilterEdgeCases(t *testing.T) {
 
 	t.Run("ErrValidations with special characters", func(t *testing.T) {
 		err := domain.ErrValidations{
-			Errors: []string{
-				"field: contains 'quotes'",
-				`field: contains "double quotes"`,
-				"field: contains <>&",
+			Errors: []domain.Violation{
+				{Field: "field", Message: "contains 'quotes'"},
+				{Field: "field", Message: `contains "double quotes"`},
+				{Field: "field", Message: "contains <>&"},
 			},
 		}
 		if !filter(err) {
@@ -347,7 +357,7 @@ func TestPoisonQueueFilterEdgeCases(t *testing.T) {
 
 	t.Run("ErrValidations with empty strings", func(t *testing.T) {
 		err := domain.ErrValidations{
-			Errors: []string{"", "", ""},
+			Errors: []domain.Violation{{Message: ""}, {Message: ""}, {Message: ""}},
 		}
 		if !filter(err) {
 			t.Error("should return true for ErrValidations with empty strings")
@@ -355,7 +365,7 @@ func TestPoisonQueueFilterEdgeCases(t *testing.T) {
 	})
 
 	t.Run("multiple wrapped errors", func(t *testing.T) {
-		innerErr := domain.ErrValidations{Errors: []string{"inner"}}
+		innerErr := domain.ErrValidations{Errors: []domain.Violation{{Message: "inner"}}}
 		midErr := errors.Join(errors.New("mid"), innerErr)
 		outerErr := errors.Join(errors.New("outer"), midErr)
 
@@ -414,10 +424,71 @@ func TestComposeEventMiddlewaresVariousConfigs(t *testing.T) {
 				RetryMaxInterval:     tc.retryMax,
 			}
 
-			middlewares := composeEventMiddlewares(cfg)
+			middlewares := composeEventMiddlewares(cfg, nil, nil, InboxConfig{}, "", nil)
 			if len(middlewares) != tc.expectedCount {
 				t.Errorf("expected %d middlewares, got %d", tc.expectedCount, len(middlewares))
 			}
 		})
 	}
 }
+
+func TestEventServicePoisonsInvalidRecords(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Persistent, so messages published before the router subscribes are kept.
+	pubSub := gochannel.NewGoChannel(gochannel.Config{Persistent: true}, watermill.NopLogger{})
+	channel.Register()
+	defaultFactory := channel.Factory
+	channel.Factory = func(gochannel.Config, watermill.LoggerAdapter) (message.Publisher, message.Subscriber) {
+		return pubSub, pubSub
+	}
+	t.Cleanup(func() { channel.Factory = defaultFactory })
+
+	cfg := &Config{
+		ExampleFailedQueue: "example-records-failed",
+		CloudEventsSource:  "event-driven-service",
+		Routes:             []Route{{Handler: exampleRecordHandlerName, ConsumeQueue: "example-records", PublishQueue: "example-records-processed"}},
+	}
+	protoflowCfg := &protoflow.Config{
+		PubSubSystem:         "channel",
+		PoisonQueue:          "poison",
+		RetryMaxRetries:      1,
+		RetryInitialInterval: time.Millisecond,
+		RetryMaxInterval:     time.Millisecond,
+	}
+	svc, err := BuildEventService(ctx, cfg, logger, nil, nil, protoflowCfg)
+	AssertNoError(t, err, "build event service")
+
+	poisoned, err := pubSub.Subscribe(ctx, "poison")
+	AssertNoError(t, err, "subscribe poison queue")
+	failed, err := pubSub.Subscribe(ctx, "example-records-failed")
+	AssertNoError(t, err, "subscribe failed queue")
+	go func() { _ = svc.Start(ctx) }()
+
+	// An empty title breaks the protovalidate rules of ExampleRecord.
+	invalid := &domain.ExampleRecord{RecordId: "EX-INVALID"}
+	AssertNoError(t, svc.PublishProto(ctx, "example-records", invalid, nil), "publish invalid record")
+
+	select {
+	case msg := <-poisoned:
+		msg.Ack()
+		if msg.Metadata.Get(middleware.ReasonForPoisonedKey) == "" {
+			t.Error("poisoned message has no reason")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalid record did not reach the poison queue")
+	}
+
+	select {
+	case msg := <-failed:
+		msg.Ack()
+		var event domain.ProcessingError
+		AssertNoError(t, protojson.Unmarshal(msg.Payload, &event), "unmarshal failure event")
+		AssertEqual(t, event.GetErrorCode(), errorCodeValidation, "error code")
+		AssertEqual(t, event.GetRecordId(), "EX-INVALID", "record id")
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalid record produced no failure event")
+	}
+}
@@ -0,0 +1,161 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/drblury/protoflow"
+)
+
+// =============================================================================
+// EXAMPLE BATCH HANDLER TESTS
+// =============================================================================
+
+// newBatchTestStore returns a memory store holding records EX-1 to EX-3.
+func newBatchTestStore(t *testing.T) *database.MemoryStore {
+	t.Helper()
+	store := database.NewMemoryStore()
+	fixtures := NewTestFixtures()
+	for _, id := range []string{"EX-1", "EX-2", "EX-3"} {
+		if err := store.StoreExampleRecord(context.Background(), fixtures.ExampleRecord(id, "Record "+id)); err != nil {
+			t.Fatalf("StoreExampleRecord(%s) error = %v", id, err)
+		}
+	}
+	return store
+}
+
+// failingProcessor fails for the given record ID and completes every other record.
+func failingProcessor(failID string, calls *atomic.Int32) recordProcessor {
+	return func(_ context.Context, record *domain.ExampleRecord) (*domain.ExampleResult, error) {
+		calls.Add(1)
+		if record.GetRecordId() == failID {
+			return nil, errors.New("processing failed")
+		}
+		return &domain.ExampleResult{RecordId: record.GetRecordId(), Status: "completed", Note: "ok"}, nil
+	}
+}
+
+func runBatch(t *testing.T, runner *batchRunner, req *domain.BatchRequest) *domain.BatchResult {
+	t.Helper()
+	evt := protoflow.ProtoMessageContext[*domain.BatchRequest]{
+		Payload:            req,
+		MessageContextBase: protoflow.MessageContextBase{Metadata: protoflow.Metadata{"source": "test"}},
+	}
+	outputs, err := exampleBatchHandler(runner)(context.Background(), evt)
+	AssertNoError(t, err, "handler")
+	AssertResultCount(t, len(outputs), 1)
+	AssertMetadataContains(t, outputs[0].Metadata, "batch_id", req.GetBatchId())
+	AssertMetadataContains(t, outputs[0].Metadata, "source", "test")
+
+	result, ok := outputs[0].Message.(*domain.BatchResult)
+	if !ok {
+		t.Fatalf("output message type = %T, want *domain.BatchResult", outputs[0].Message)
+	}
+	return result
+}
+
+func TestExampleBatchHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		parallel bool
+	}{
+		{"sequential", false},
+		{"parallel", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newBatchTestStore(t)
+			var calls atomic.Int32
+			runner := newBatchRunner(store, store, failingProcessor("EX-2", &calls), 2)
+
+			result := runBatch(t, runner, &domain.BatchRequest{
+				BatchId:   "batch-1",
+				RecordIds: []string{"EX-1", "EX-2", "EX-3", "EX-1", "EX-404"},
+				Options:   &domain.BatchOptions{Parallel: tt.parallel},
+			})
+
+			AssertEqual(t, result.GetTotal(), int32(4), "total")
+			AssertEqual(t, result.GetSucceeded(), int32(2), "succeeded")
+			AssertEqual(t, result.GetFailed(), int32(2), "failed")
+			AssertEqual(t, calls.Load(), int32(3), "processed records")
+			AssertResultCount(t, len(result.GetResults()), 2)
+			AssertEqual(t, result.GetResults()[0].GetRecordId(), "EX-1", "first result")
+			AssertEqual(t, result.GetResults()[1].GetRecordId(), "EX-3", "second result")
+
+			AssertResultCount(t, len(result.GetErrors()), 2)
+			AssertEqual(t, result.GetErrors()[0].GetErrorCode(), errorCodeProcessing, "EX-2 error code")
+			AssertEqual(t, result.GetErrors()[0].GetRetryable(), true, "EX-2 retryable")
+			AssertEqual(t, result.GetErrors()[1].GetErrorCode(), errorCodeNotFound, "EX-404 error code")
+			AssertEqual(t, result.GetErrors()[1].GetRetryable(), false, "EX-404 retryable")
+
+			progress, err := store.GetBatch(context.Background(), "batch-1")
+			AssertNoError(t, err, "GetBatch")
+			AssertEqual(t, progress.Status, database.BatchStatusCompleted, "batch status")
+			AssertResultCount(t, len(progress.Outcomes), 4)
+		})
+	}
+}
+
+func TestExampleBatchHandlerStopOnError(t *testing.T) {
+	store := newBatchTestStore(t)
+	var calls atomic.Int32
+	runner := newBatchRunner(store, store, failingProcessor("EX-1", &calls), 1)
+
+	result := runBatch(t, runner, &domain.BatchRequest{
+		BatchId:   "batch-stop",
+		RecordIds: []string{"EX-1", "EX-2", "EX-3"},
+		Options:   &domain.BatchOptions{StopOnError: true},
+	})
+
+	AssertEqual(t, calls.Load(), int32(1), "processed records")
+	AssertEqual(t, result.GetFailed(), int32(1), "failed")
+	AssertEqual(t, result.GetSucceeded(), int32(0), "succeeded")
+	AssertResultCount(t, len(result.GetResults()), 2)
+	for _, skipped := range result.GetResults() {
+		AssertEqual(t, skipped.GetStatus(), skippedResultStatus, skipped.GetRecordId()+" status")
+	}
+}
+
+func TestExampleBatchHandlerResumesRedeliveredBatch(t *testing.T) {
+	store := newBatchTestStore(t)
+	ctx := context.Background()
+
+	// A previous delivery processed EX-1 before it was interrupted.
+	if _, err := store.StartBatch(ctx, &database.BatchProgress{BatchID: "batch-resume", Total: 3}); err != nil {
+		t.Fatalf("StartBatch() error = %v", err)
+	}
+	err := store.RecordBatchOutcome(ctx, "batch-resume", database.BatchRecordOutcome{
+		RecordID:    "EX-1",
+		Outcome:     database.BatchOutcomeSucceeded,
+		Status:      "completed",
+		ProcessedAt: time.Now().UTC(),
+	})
+	AssertNoError(t, err, "RecordBatchOutcome")
+
+	var calls atomic.Int32
+	runner := newBatchRunner(store, store, failingProcessor("", &calls), 0)
+	result := runBatch(t, runner, &domain.BatchRequest{BatchId: "batch-resume", RecordIds: []string{"EX-1", "EX-2", "EX-3"}})
+
+	AssertEqual(t, calls.Load(), int32(2), "processed records")
+	AssertEqual(t, result.GetSucceeded(), int32(3), "succeeded")
+	AssertResultCount(t, len(result.GetResults()), 3)
+}
+
+func TestExampleBatchHandlerRejectsMissingBatchID(t *testing.T) {
+	store := newBatchTestStore(t)
+	var calls atomic.Int32
+	handler := exampleBatchHandler(newBatchRunner(store, store, failingProcessor("", &calls), 0))
+
+	_, err := handler(context.Background(), protoflow.ProtoMessageContext[*domain.BatchRequest]{
+		Payload: &domain.BatchRequest{RecordIds: []string{"EX-1"}},
+	})
+	if !errors.Is(err, protoflow.ErrUnprocessable) {
+		t.Errorf("handler error = %v, want ErrUnprocessable", err)
+	}
+}
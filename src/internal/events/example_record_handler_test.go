@@ -2,10 +2,13 @@ package events
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"drblury/event-driven-service/internal/database"
 	"drblury/event-driven-service/internal/domain"
+	"drblury/event-driven-service/internal/usecase"
 
 	"github.com/drblury/protoflow"
 )
@@ -21,7 +24,7 @@ import (
 // =============================================================================
 
 func TestExampleRecordHandler(t *testing.T) {
-	handler := exampleRecordHandler()
+	handler := exampleRecordHandler(nil)
 	ctx := context.Background()
 	fixtures := NewTestFixtures()
 
@@ -248,7 +251,7 @@ func TestRegisterAppEventHandlers(t *testing.T) {
 					t.Log("registerAppEventHandlers did not panic with nil service")
 				}
 			}()
-			_ = registerAppEventHandlers(nil, cfg)
+			_ = registerAppEventHandlers(nil, cfg, nil, nil, nil, newOrderedProcessing(0))
 		}()
 	})
 
@@ -260,7 +263,56 @@ func TestRegisterAppEventHandlers(t *testing.T) {
 					t.Log("registerAppEventHandlers did not panic with nil config")
 				}
 			}()
-			_ = registerAppEventHandlers(nil, nil)
+			_ = registerAppEventHandlers(nil, nil, nil, nil, nil, nil)
 		}()
 	})
 }
+
+func TestExampleRecordHandlerTracksStatus(t *testing.T) {
+	ctx := context.Background()
+	store := database.NewMemoryStore()
+	logic, _ := usecase.NewAppLogic(store, nil)
+	logic.SetExampleTopic("examples")
+	AssertNoError(t, logic.HandleExample(ctx, NewTestFixtures().ExampleRecord("EX-1", "Tracked")), "submit record")
+	handler := exampleRecordHandler(logic)
+
+	evt := protoflow.ProtoMessageContext[*domain.ExampleRecord]{Payload: NewTestFixtures().ExampleRecord("EX-1", "Tracked")}
+	// Processing fails randomly; retries find the record in progress already.
+	var err error
+	for range 50 {
+		if _, err = handler(ctx, evt); err == nil {
+			break
+		}
+	}
+	AssertNoError(t, err, "handler")
+
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	AssertEqual(t, stored.GetStatus(), domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_COMPLETED, "status")
+	AssertResultCount(t, len(stored.GetStatusHistory()), 3)
+	AssertEqual(t, stored.GetStatusHistory()[1].GetReason(), statusReasonProcessing, "processing reason")
+
+	// A redelivered record that was completed already is acknowledged without work.
+	outputs, err := handler(ctx, evt)
+	AssertNoError(t, err, "redelivered completed record")
+	AssertResultCount(t, len(outputs), 0)
+	redelivered, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	AssertEqual(t, redelivered.GetVersion(), stored.GetVersion(), "version after redelivery")
+
+	// A failed record cannot be processed again without being queued first.
+	AssertNoError(t, logic.HandleExample(ctx, NewTestFixtures().ExampleRecord("EX-2", "Failed")), "submit record")
+	_, err = logic.TransitionExampleStatus(ctx, "EX-2", domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_FAILED, "test")
+	AssertNoError(t, err, "fail record")
+	_, err = handler(ctx, protoflow.ProtoMessageContext[*domain.ExampleRecord]{Payload: NewTestFixtures().ExampleRecord("EX-2", "Failed")})
+	if !errors.Is(err, protoflow.ErrUnprocessable) || !errors.Is(err, usecase.ErrInvalidStatusTransition) {
+		t.Errorf("error = %v, want an unprocessable status transition", err)
+	}
+
+	// Records that are not stored are processed without a status.
+	evt.Payload = NewTestFixtures().ExampleRecord("EX-UNSTORED", "Simulated")
+	for range 50 {
+		if _, err = handler(ctx, evt); err == nil {
+			break
+		}
+	}
+	AssertNoError(t, err, "unstored record")
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+	"drblury/event-driven-service/internal/usecase"
+
+	"github.com/drblury/protoflow"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// =============================================================================
+// EXAMPLE RECORD UPDATE HANDLER TESTS
+// =============================================================================
+
+type fakeExampleRecordUpdater struct {
+	err error
+}
+
+func (f fakeExampleRecordUpdater) ApplyExampleRecordUpdate(context.Context, *domain.ExampleRecordUpdate) (*domain.ExampleRecord, error) {
+	return nil, f.err
+}
+
+func TestExampleRecordUpdateHandler(t *testing.T) {
+	ctx := context.Background()
+	store := database.NewMemoryStore()
+	if err := store.StoreExampleRecord(ctx, NewTestFixtures().ExampleRecord("EX-1", "Original")); err != nil {
+		t.Fatalf("StoreExampleRecord() error = %v", err)
+	}
+	logic, _ := usecase.NewAppLogic(store, nil)
+	handler := exampleRecordUpdateHandler(logic)
+
+	evt := protoflow.ProtoMessageContext[*domain.ExampleRecordUpdate]{
+		Payload: &domain.ExampleRecordUpdate{
+			RecordId:   "EX-1",
+			Updates:    &domain.ExampleRecord{Title: "Renamed"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+		},
+		MessageContextBase: protoflow.MessageContextBase{Metadata: protoflow.Metadata{"source": "test"}},
+	}
+
+	outputs, err := handler(ctx, evt)
+	AssertNoError(t, err, "handler")
+	AssertResultCount(t, len(outputs), 1)
+
+	record, ok := outputs[0].Message.(*domain.ExampleRecord)
+	if !ok {
+		t.Fatalf("output message type = %T, want *domain.ExampleRecord", outputs[0].Message)
+	}
+	AssertEqual(t, record.GetTitle(), "Renamed", "title")
+	AssertEqual(t, record.GetVersion(), int32(1), "version")
+	AssertMetadataContains(t, outputs[0].Metadata, "record_version", "1")
+	AssertMetadataContains(t, outputs[0].Metadata, "source", "test")
+
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	AssertEqual(t, stored.GetVersion(), int32(1), "stored version")
+}
+
+func TestExampleRecordUpdateHandlerErrors(t *testing.T) {
+	evt := protoflow.ProtoMessageContext[*domain.ExampleRecordUpdate]{Payload: &domain.ExampleRecordUpdate{RecordId: "EX-1"}}
+
+	testCases := map[string]struct {
+		err           error
+		unprocessable bool
+	}{
+		"unknown record":    {err: database.ErrExampleRecordNotFound, unprocessable: true},
+		"invalid mask":      {err: domain.ErrorBadRequest, unprocessable: true},
+		"transient failure": {err: errors.New("connection reset"), unprocessable: false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := exampleRecordUpdateHandler(fakeExampleRecordUpdater{err: tc.err})(context.Background(), evt)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("error = %v, want it to wrap %v", err, tc.err)
+			}
+			if got := poisonQueueFilter()(err); got != tc.unprocessable {
+				t.Errorf("poison queue filter = %v, want %v", got, tc.unprocessable)
+			}
+		})
+	}
+}
+
+func TestExampleRecordUpdateHandlerRejectsInvalidResult(t *testing.T) {
+	ctx := context.Background()
+	store := database.NewMemoryStore()
+	_ = store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: "EX-1", Title: "Valid"})
+	validator, err := NewValidator()
+	AssertNoError(t, err, "NewValidator")
+	logic, _ := usecase.NewAppLogic(store, nil)
+	logic.SetValidator(validator)
+
+	// The update itself is well-formed, only the record it produces is invalid.
+	_, err = exampleRecordUpdateHandler(logic)(ctx, protoflow.ProtoMessageContext[*domain.ExampleRecordUpdate]{
+		Payload: &domain.ExampleRecordUpdate{
+			RecordId:   "EX-1",
+			Updates:    &domain.ExampleRecord{Meta: &domain.ExampleMeta{Priority: 9}},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title", "meta.priority"}},
+		},
+	})
+	var validationErr domain.ErrValidations
+	if !errors.As(err, &validationErr) || !poisonQueueFilter()(err) || !errors.Is(err, protoflow.ErrUnprocessable) {
+		t.Fatalf("error = %v, want unprocessable validation errors", err)
+	}
+	if len(validationErr.Errors) != 2 {
+		t.Errorf("violations = %v, want title and meta.priority", validationErr.Errors)
+	}
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	AssertEqual(t, stored.GetTitle(), "Valid", "stored title")
+	AssertEqual(t, stored.GetVersion(), int32(0), "stored version")
+}
@@ -0,0 +1,136 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+	date "google.golang.org/genproto/googleapis/type/date"
+)
+
+// =============================================================================
+// EXAMPLE RESULT HANDLER TESTS
+// =============================================================================
+
+func TestExampleResultHandlerStoresResult(t *testing.T) {
+	ctx := context.Background()
+	store := database.NewMemoryStore()
+	handler := exampleResultHandler(store)
+
+	evt := protoflow.ProtoMessageContext[*domain.ExampleResult]{
+		Payload: &domain.ExampleResult{
+			RecordId:    "EX-1",
+			Status:      exampleResultStatus,
+			Note:        "processed Example",
+			ProcessedOn: &date.Date{Year: 2026, Month: 5, Day: 4},
+		},
+		MessageContextBase: protoflow.MessageContextBase{Metadata: protoflow.Metadata{protoflow.MetadataKeyCorrelationID: "corr-1"}},
+	}
+
+	outputs, err := handler(ctx, evt)
+	AssertNoError(t, err, "handler")
+	AssertResultCount(t, len(outputs), 0)
+
+	results, err := store.ListExampleResults(ctx, database.ExampleResultFilter{RecordID: "EX-1"})
+	AssertNoError(t, err, "list results")
+	AssertResultCount(t, len(results), 1)
+	AssertEqual(t, results[0].CorrelationID, "corr-1", "correlation id")
+	AssertEqual(t, results[0].Note, "processed Example", "note")
+	AssertEqual(t, results[0].ProcessedOn.GetDay(), int32(4), "processed on")
+	if results[0].ID == "" {
+		t.Error("stored result has no ID")
+	}
+}
+
+func TestExampleResultHandlerIgnoresRedeliveredResult(t *testing.T) {
+	ctx := context.Background()
+	store := database.NewMemoryStore()
+	handler := exampleResultHandler(store)
+
+	deliver := func(correlationID, note string) {
+		t.Helper()
+		_, err := handler(ctx, protoflow.ProtoMessageContext[*domain.ExampleResult]{
+			Payload:            &domain.ExampleResult{RecordId: "EX-1", Status: exampleResultStatus, Note: note},
+			MessageContextBase: protoflow.MessageContextBase{Metadata: protoflow.Metadata{protoflow.MetadataKeyCorrelationID: correlationID}},
+		})
+		AssertNoError(t, err, "handler")
+	}
+	deliver("corr-1", "first")
+	deliver("corr-1", "redelivered")
+	deliver("corr-2", "reprocessed")
+
+	results, err := store.ListExampleResults(ctx, database.ExampleResultFilter{RecordID: "EX-1"})
+	AssertNoError(t, err, "list results")
+	AssertResultCount(t, len(results), 2)
+	for _, result := range results {
+		if result.CorrelationID == "corr-1" && result.Note != "first" {
+			t.Errorf("redelivered result replaced the stored one, note = %q", result.Note)
+		}
+	}
+}
+
+func TestExampleResultHandlerKeysResultsWithoutCorrelationIDByMessage(t *testing.T) {
+	store := database.NewMemoryStore()
+	handler := exampleResultHandler(store)
+
+	deliver := func(uuid, note string) {
+		t.Helper()
+		// The correlation middleware has assigned a random ID to the message by now.
+		ctx := context.WithValue(context.Background(), receivedMessageKey{}, receivedMessage{UUID: uuid})
+		_, err := handler(ctx, protoflow.ProtoMessageContext[*domain.ExampleResult]{
+			Payload:            &domain.ExampleResult{RecordId: "EX-1", Status: exampleResultStatus, Note: note},
+			MessageContextBase: protoflow.MessageContextBase{Metadata: protoflow.Metadata{protoflow.MetadataKeyCorrelationID: protoflow.CreateULID()}},
+		})
+		AssertNoError(t, err, "handler")
+	}
+	deliver("msg-1", "first")
+	deliver("msg-1", "redelivered")
+	deliver("msg-2", "reprocessed")
+
+	results, err := store.ListExampleResults(context.Background(), database.ExampleResultFilter{RecordID: "EX-1"})
+	AssertNoError(t, err, "list results")
+	AssertResultCount(t, len(results), 2)
+	for _, result := range results {
+		if result.Note == "redelivered" {
+			t.Error("redelivered result without correlation ID was stored again")
+		}
+	}
+}
+
+func TestReceivedMessageMiddleware(t *testing.T) {
+	var received receivedMessage
+	var ok bool
+	handler := receivedMessageMiddleware().Middleware(func(msg *message.Message) ([]*message.Message, error) {
+		received, ok = receivedMessageFromContext(msg.Context())
+		return nil, nil
+	})
+
+	msg := message.NewMessage("msg-1", nil)
+	_, err := handler(msg)
+	AssertNoError(t, err, "handler")
+	if !ok || received.UUID != "msg-1" || received.CorrelationID != "" {
+		t.Errorf("received message = %+v, %v; want msg-1 without correlation ID", received, ok)
+	}
+
+	msg = message.NewMessage("msg-2", nil)
+	msg.Metadata.Set(protoflow.MetadataKeyCorrelationID, "corr-1")
+	_, err = handler(msg)
+	AssertNoError(t, err, "handler")
+	AssertEqual(t, received.CorrelationID, "corr-1", "correlation id")
+}
+
+func TestExampleResultHandlerRejectsResultWithoutRecord(t *testing.T) {
+	handler := exampleResultHandler(database.NewMemoryStore())
+
+	_, err := handler(context.Background(), protoflow.ProtoMessageContext[*domain.ExampleResult]{
+		Payload: &domain.ExampleResult{Status: exampleResultStatus},
+	})
+	if !errors.Is(err, protoflow.ErrUnprocessable) {
+		t.Errorf("error = %v, want an unprocessable result", err)
+	}
+}
@@ -0,0 +1,193 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"github.com/drblury/protoflow"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Error codes of ProcessingError, as listed in the AsyncAPI processing-error schema.
+const (
+	errorCodeValidation = "VALIDATION_ERROR"
+	errorCodeNotFound   = "NOT_FOUND"
+	errorCodeProcessing = "PROCESSING_ERROR"
+	errorCodeTimeout    = "TIMEOUT"
+	errorCodeInternal   = "INTERNAL_ERROR"
+)
+
+// recordMessage is a handler payload that refers to an example record.
+type recordMessage interface {
+	proto.Message
+	GetRecordId() string
+}
+
+// failureEventPayloads lists the payloads, keyed by their event_message_schema, whose
+// terminal failures are reported as example.record.failed events.
+var failureEventPayloads = map[string]func() recordMessage{
+	exampleRecordSchema: func() recordMessage { return &domain.ExampleRecord{} },
+	fmt.Sprintf("%T", &domain.ExampleRecordUpdate{}): func() recordMessage { return &domain.ExampleRecordUpdate{} },
+}
+
+// failureOutboxHandler names the outbox rows of failure events. The MongoDB store
+// keeps them in the <handler>_outbox collection, so the name must not change
+// between releases.
+const failureOutboxHandler = "example_record_failures"
+
+// failurePublishFunc emits a failure event.
+type failurePublishFunc func(ctx context.Context, event *domain.ProcessingError, metadata protoflow.Metadata) error
+
+// failureStatusFunc marks the stored record with the given ID as failed.
+type failureStatusFunc func(ctx context.Context, recordID string, reason string) error
+
+// exampleRecordSchema is the event_message_schema of ExampleRecord messages, whose
+// terminal failures also fail the stored record.
+var exampleRecordSchema = fmt.Sprintf("%T", &domain.ExampleRecord{})
+
+// failureEventMiddleware publishes an example.record.failed event to topic when a
+// handler gives up on a record message. It sits between the poison queue and the
+// retry middleware, so it only sees the final error: either one that is never
+// retried or the last one after the retries ran out. The error is then marked
+// unprocessable so the poison queue takes the message. With an outbox store the
+// event is written to the outbox, otherwise it is published directly. With a
+// lifecycle, a failed ExampleRecord message also moves the stored record to FAILED.
+func failureEventMiddleware(store OutboxStore, topic string, lifecycle ExampleRecordLifecycle) protoflow.MiddlewareRegistration {
+	return protoflow.MiddlewareRegistration{
+		Name: "failure_events",
+		Builder: func(s *protoflow.Service) (message.HandlerMiddleware, error) {
+			publish := func(ctx context.Context, event *domain.ProcessingError, metadata protoflow.Metadata) error {
+				return s.PublishProto(ctx, topic, event, metadata)
+			}
+			if store != nil {
+				publish = func(ctx context.Context, event *domain.ProcessingError, metadata protoflow.Metadata) error {
+					msg, err := newProtoOutboxMessage(failureOutboxHandler, topic, event, metadata)
+					if err != nil {
+						return err
+					}
+					return store.StoreOutboxMessage(ctx, msg)
+				}
+			}
+			var markFailed failureStatusFunc
+			if lifecycle != nil {
+				markFailed = func(ctx context.Context, recordID string, reason string) error {
+					_, err := lifecycle.TransitionExampleStatus(ctx, recordID, domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_FAILED, reason)
+					return err
+				}
+			}
+			return newFailureEventMiddleware(publish, markFailed), nil
+		},
+	}
+}
+
+func newFailureEventMiddleware(publish failurePublishFunc, markFailed failureStatusFunc) message.HandlerMiddleware {
+	return func(h message.HandlerFunc) message.HandlerFunc {
+		return func(msg *message.Message) ([]*message.Message, error) {
+			outgoing, err := h(msg)
+			if err == nil {
+				return outgoing, nil
+			}
+
+			newPayload, ok := failureEventPayloads[msg.Metadata.Get(protoflow.MetadataKeyEventSchema)]
+			if !ok {
+				return outgoing, err
+			}
+
+			handler := message.HandlerNameFromCtx(msg.Context())
+			event := classifyProcessingError(recordIDOf(msg.Payload, newPayload()), err)
+			if markFailed != nil && event.GetRecordId() != "" && msg.Metadata.Get(protoflow.MetadataKeyEventSchema) == exampleRecordSchema {
+				// Records that are not stored have no status, and a status that cannot
+				// fail must not hold back the failure event.
+				if statusErr := markFailed(msg.Context(), event.GetRecordId(), event.GetErrorCode()); statusErr != nil && !errors.Is(statusErr, domain.ErrorNotFound) {
+					slog.Warn("could not mark record as failed", "record_id", event.GetRecordId(), "error", statusErr)
+				}
+			}
+			event.Details = map[string]string{
+				"handler":      handler,
+				"message_uuid": msg.UUID,
+			}
+			metadata := protoflow.Metadata{
+				"source":  "events.failures",
+				"handler": handler,
+			}
+			if correlationID := msg.Metadata.Get(protoflow.MetadataKeyCorrelationID); correlationID != "" {
+				metadata[protoflow.MetadataKeyCorrelationID] = correlationID
+			}
+
+			// Without the failure event the message must not be dropped, so it stays
+			// retryable and is redelivered.
+			if publishErr := publish(msg.Context(), event, metadata); publishErr != nil {
+				return nil, errors.Join(err, fmt.Errorf("publish failure event: %w", publishErr))
+			}
+			if errors.Is(err, protoflow.ErrUnprocessable) {
+				return outgoing, err
+			}
+			return outgoing, fmt.Errorf("%w: %w", protoflow.ErrUnprocessable, err)
+		}
+	}
+}
+
+// classifyProcessingError maps a processing error onto the AsyncAPI error codes.
+func classifyProcessingError(recordID string, err error) *domain.ProcessingError {
+	processingErr := &domain.ProcessingError{RecordId: recordID, Message: err.Error()}
+
+	var (
+		validationErr domain.ErrValidations
+		unprocessable *protoflow.UnprocessableEventError
+		panicErr      middleware.RecoveredPanicError
+	)
+	switch {
+	case errors.Is(err, domain.ErrorNotFound):
+		processingErr.ErrorCode = errorCodeNotFound
+	case errors.As(err, &validationErr), errors.Is(err, domain.ErrorBadRequest), errors.As(err, &unprocessable):
+		processingErr.ErrorCode = errorCodeValidation
+	case errors.Is(err, context.DeadlineExceeded):
+		processingErr.ErrorCode = errorCodeTimeout
+		processingErr.Retryable = true
+	case errors.As(err, &panicErr):
+		processingErr.ErrorCode = errorCodeInternal
+		processingErr.Message = fmt.Sprintf("panic: %v", panicErr.V)
+	default:
+		processingErr.ErrorCode = errorCodeProcessing
+		processingErr.Retryable = !errors.Is(err, protoflow.ErrUnprocessable)
+	}
+	return processingErr
+}
+
+// recordIDOf decodes payload into msg and returns its record ID, or "" when the
+// payload cannot be decoded.
+func recordIDOf(payload []byte, msg recordMessage) string {
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(payload, msg); err != nil {
+		return ""
+	}
+	return msg.GetRecordId()
+}
+
+// newProtoOutboxMessage encodes event as an outbox row of handler for topic, using the
+// same envelope as protoflow's PublishProto.
+func newProtoOutboxMessage(handler string, topic string, event proto.Message, metadata protoflow.Metadata) (*database.OutboxMessage, error) {
+	payload, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	rowMetadata := map[string]string{protoflow.MetadataKeyEventSchema: fmt.Sprintf("%T", event)}
+	for key, value := range metadata {
+		rowMetadata[key] = value
+	}
+	return &database.OutboxMessage{
+		Handler:  handler,
+		UUID:     protoflow.CreateULID(),
+		Payload:  string(payload),
+		Topic:    topic,
+		Metadata: rowMetadata,
+	}, nil
+}
@@ -0,0 +1,205 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"github.com/drblury/protoflow"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// newRecordMessage returns a message carrying record as the protoflow envelope does.
+func newRecordMessage(t *testing.T, record *domain.ExampleRecord) *message.Message {
+	t.Helper()
+	payload, err := protojson.Marshal(record)
+	AssertNoError(t, err, "marshal record")
+	msg := message.NewMessage("msg-1", payload)
+	msg.Metadata.Set(protoflow.MetadataKeyEventSchema, fmt.Sprintf("%T", record))
+	msg.Metadata.Set(protoflow.MetadataKeyCorrelationID, "corr-1")
+	return msg
+}
+
+// recordingFailurePublisher collects published failure events.
+type recordingFailurePublisher struct {
+	events   []*domain.ProcessingError
+	metadata []protoflow.Metadata
+	err      error
+}
+
+func (p *recordingFailurePublisher) publish(_ context.Context, event *domain.ProcessingError, metadata protoflow.Metadata) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, event)
+	p.metadata = append(p.metadata, metadata)
+	return nil
+}
+
+func TestFailureEventMiddlewarePublishesTerminalFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      string
+		retryable bool
+	}{
+		{"retries ran out", errors.New("fatal error processing example event"), errorCodeProcessing, true},
+		{"unknown record", fmt.Errorf("%w: %w", protoflow.ErrUnprocessable, database.ErrExampleRecordNotFound), errorCodeNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingFailurePublisher{}
+			calls := 0
+			handler := newFailureEventMiddleware(publisher.publish, nil)(countingHandler(&calls, tt.err))
+
+			msg := newRecordMessage(t, NewTestFixtures().ExampleRecord("EX-1", "Failing"))
+			_, err := handler(msg)
+			if !poisonQueueFilter()(err) {
+				t.Errorf("error = %v, want it to go to the poison queue", err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("error = %v, want it to wrap %v", err, tt.err)
+			}
+
+			AssertResultCount(t, len(publisher.events), 1)
+			event := publisher.events[0]
+			AssertEqual(t, event.GetRecordId(), "EX-1", "record id")
+			AssertEqual(t, event.GetErrorCode(), tt.code, "error code")
+			AssertEqual(t, event.GetRetryable(), tt.retryable, "retryable")
+			AssertEqual(t, event.GetDetails()["message_uuid"], "msg-1", "message uuid detail")
+			AssertMetadataContains(t, publisher.metadata[0], protoflow.MetadataKeyCorrelationID, "corr-1")
+		})
+	}
+}
+
+func TestFailureEventMiddlewareIgnoresOtherMessages(t *testing.T) {
+	publisher := &recordingFailurePublisher{}
+	calls := 0
+	boom := errors.New("boom")
+
+	// Successful record messages do not emit anything.
+	_, err := newFailureEventMiddleware(publisher.publish, nil)(countingHandler(&calls, nil))(
+		newRecordMessage(t, NewTestFixtures().ExampleRecord("EX-1", "Fine")),
+	)
+	AssertNoError(t, err, "handler")
+
+	// Failures of other payloads keep their error untouched.
+	msg := message.NewMessage("msg-2", []byte(`{"batch_id":"batch-1"}`))
+	msg.Metadata.Set(protoflow.MetadataKeyEventSchema, fmt.Sprintf("%T", &domain.BatchRequest{}))
+	_, err = newFailureEventMiddleware(publisher.publish, nil)(countingHandler(&calls, boom))(msg)
+	if err != boom {
+		t.Errorf("error = %v, want the handler error", err)
+	}
+
+	AssertResultCount(t, len(publisher.events), 0)
+}
+
+func TestFailureEventMiddlewareKeepsMessageWhenPublishFails(t *testing.T) {
+	publisher := &recordingFailurePublisher{err: errors.New("broker down")}
+	calls := 0
+	handler := newFailureEventMiddleware(publisher.publish, nil)(countingHandler(&calls, errors.New("boom")))
+
+	_, err := handler(newRecordMessage(t, NewTestFixtures().ExampleRecord("EX-1", "Failing")))
+	if err == nil || poisonQueueFilter()(err) {
+		t.Errorf("error = %v, want a retryable error", err)
+	}
+}
+
+func TestFailureEventMiddlewareStoresEventsInOutbox(t *testing.T) {
+	store := database.NewMemoryStore()
+	mw, err := failureEventMiddleware(store, "example-records-failed", nil).Builder(nil)
+	AssertNoError(t, err, "build middleware")
+
+	calls := 0
+	_, err = mw(countingHandler(&calls, errors.New("boom")))(newRecordMessage(t, NewTestFixtures().ExampleRecord("EX-1", "Failing")))
+	if err == nil {
+		t.Fatal("expected the handler error")
+	}
+
+	rows := store.OutboxMessages(failureOutboxHandler)
+	AssertResultCount(t, len(rows), 1)
+	AssertEqual(t, rows[0].Topic, "example-records-failed", "topic")
+	AssertEqual(t, rows[0].Metadata[protoflow.MetadataKeyCorrelationID], "corr-1", "correlation id")
+
+	var event domain.ProcessingError
+	AssertNoError(t, protojson.Unmarshal([]byte(rows[0].Payload), &event), "unmarshal payload")
+	AssertEqual(t, event.GetRecordId(), "EX-1", "record id")
+}
+
+func TestComposeEventMiddlewaresWithFailureEvents(t *testing.T) {
+	middlewares := composeEventMiddlewares(&protoflow.Config{}, nil, nil, InboxConfig{}, "example-records-failed", nil)
+
+	names := make([]string, 0, len(middlewares))
+	for _, mw := range middlewares {
+		names = append(names, mw.Name)
+	}
+	want := []string{"poison_queue", "failure_events", "proto_validate", "retry", "recoverer"}
+	got := names[len(names)-len(want):]
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("middleware order = %v, want it to end with %v", names, want)
+		}
+	}
+}
+
+func TestClassifyProcessingError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      string
+		retryable bool
+	}{
+		{"not found", database.ErrExampleRecordNotFound, errorCodeNotFound, false},
+		{"validation", domain.ErrValidations{}, errorCodeValidation, false},
+		{"bad request", domain.ErrorBadRequest, errorCodeValidation, false},
+		{"timeout", context.DeadlineExceeded, errorCodeTimeout, true},
+		{"panic", middleware.RecoveredPanicError{V: "nil map"}, errorCodeInternal, false},
+		{"unprocessable", protoflow.ErrUnprocessable, errorCodeProcessing, false},
+		{"other", errors.New("boom"), errorCodeProcessing, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyProcessingError("EX-1", tt.err)
+			AssertEqual(t, got.GetErrorCode(), tt.code, "error code")
+			AssertEqual(t, got.GetRetryable(), tt.retryable, "retryable")
+			AssertEqual(t, got.GetRecordId(), "EX-1", "record id")
+		})
+	}
+}
+
+func TestFailureEventMiddlewareMarksRecordFailed(t *testing.T) {
+	var failed []string
+	markFailed := func(_ context.Context, recordID string, reason string) error {
+		failed = append(failed, recordID+":"+reason)
+		if recordID == "EX-UNSTORED" {
+			return database.ErrExampleRecordNotFound
+		}
+		return nil
+	}
+	publisher := &recordingFailurePublisher{}
+	calls := 0
+	handler := newFailureEventMiddleware(publisher.publish, markFailed)(countingHandler(&calls, errors.New("boom")))
+
+	_, err := handler(newRecordMessage(t, &domain.ExampleRecord{RecordId: "EX-1"}))
+	if !errors.Is(err, protoflow.ErrUnprocessable) {
+		t.Errorf("error = %v, want ErrUnprocessable", err)
+	}
+	_, _ = handler(newRecordMessage(t, &domain.ExampleRecord{RecordId: "EX-UNSTORED"}))
+
+	// Failed updates leave the status of the stored record alone.
+	update, err := protojson.Marshal(&domain.ExampleRecordUpdate{RecordId: "EX-2"})
+	AssertNoError(t, err, "marshal update")
+	msg := message.NewMessage("msg-2", update)
+	msg.Metadata.Set(protoflow.MetadataKeyEventSchema, fmt.Sprintf("%T", &domain.ExampleRecordUpdate{}))
+	_, _ = handler(msg)
+
+	AssertResultCount(t, len(publisher.events), 3)
+	AssertResultCount(t, len(failed), 2)
+	AssertEqual(t, failed[0], "EX-1:"+errorCodeProcessing, "marked record")
+}
@@ -0,0 +1,118 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	defaultInboxTTL   = 24 * time.Hour
+	defaultInboxLease = 5 * time.Minute
+
+	unknownInboxHandler = "unknown_handler"
+
+	eventsMeterName          = "drblury/event-driven-service/internal/events"
+	inboxDuplicatesMetric    = "events.inbox.duplicates_dropped"
+	inboxDuplicatesMetricDoc = "Consumed events dropped because the handler already processed them."
+)
+
+// ErrInboxMessageInFlight is returned for a message that another consumer is still
+// processing. The message is nacked so the broker redelivers it later.
+var ErrInboxMessageInFlight = errors.New("event is already being processed")
+
+// InboxStore is the persistence contract used by the inbox middleware.
+type InboxStore interface {
+	ClaimInboxMessage(ctx context.Context, handler string, uuid string, now time.Time, lease time.Duration) (database.InboxClaim, error)
+	CompleteInboxMessage(ctx context.Context, handler string, uuid string, expiresAt time.Time) error
+	ReleaseInboxMessage(ctx context.Context, handler string, uuid string) error
+}
+
+// InboxConfig controls consumer-side deduplication.
+type InboxConfig struct {
+	// TTL is how long a processed message UUID is remembered.
+	TTL time.Duration
+	// Lease bounds how long a crashed consumer blocks redeliveries of its message.
+	Lease time.Duration
+}
+
+// inboxMiddleware records the UUID of every message a handler processed and drops
+// redelivered copies, counting them in the events.inbox.duplicates_dropped metric.
+// A message is claimed before the handler runs and released again when it fails, so
+// broker retries still reach the handler.
+func inboxMiddleware(store InboxStore, cfg InboxConfig) protoflow.MiddlewareRegistration {
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = defaultInboxTTL
+	}
+	lease := cfg.Lease
+	if lease <= 0 {
+		lease = defaultInboxLease
+	}
+	duplicates := newInboxDuplicatesCounter()
+
+	return protoflow.MiddlewareRegistration{
+		Name: "inbox",
+		Middleware: func(h message.HandlerFunc) message.HandlerFunc {
+			return func(msg *message.Message) ([]*message.Message, error) {
+				ctx := msg.Context()
+				handler := handlerNameOf(ctx)
+
+				claim, err := store.ClaimInboxMessage(ctx, handler, msg.UUID, time.Now().UTC(), lease)
+				if err != nil {
+					return nil, fmt.Errorf("claim inbox message: %w", err)
+				}
+				switch claim {
+				case database.InboxDuplicate:
+					duplicates.Add(ctx, 1, metric.WithAttributes(attribute.String("handler", handler)))
+					return nil, nil
+				case database.InboxInFlight:
+					return nil, ErrInboxMessageInFlight
+				}
+
+				outgoing, err := h(msg)
+				if err != nil {
+					if releaseErr := store.ReleaseInboxMessage(ctx, handler, msg.UUID); releaseErr != nil {
+						return nil, errors.Join(err, releaseErr)
+					}
+					return nil, err
+				}
+				if err := store.CompleteInboxMessage(ctx, handler, msg.UUID, time.Now().UTC().Add(ttl)); err != nil {
+					return nil, fmt.Errorf("complete inbox message: %w", err)
+				}
+				return outgoing, nil
+			}
+		},
+	}
+}
+
+func handlerNameOf(ctx context.Context) string {
+	if name := message.HandlerNameFromCtx(ctx); name != "" {
+		return name
+	}
+	return unknownInboxHandler
+}
+
+// newInboxDuplicatesCounter registers the counter on the global meter provider. The
+// no-op counter is used if registration fails, so deduplication never depends on metrics.
+func newInboxDuplicatesCounter() metric.Int64Counter {
+	counter, err := otel.Meter(eventsMeterName).Int64Counter(
+		inboxDuplicatesMetric,
+		metric.WithDescription(inboxDuplicatesMetricDoc),
+	)
+	if err != nil {
+		otel.Handle(err)
+		return noop.Int64Counter{}
+	}
+	return counter
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func countingHandler(calls *int, err error) message.HandlerFunc {
+	return func(*message.Message) ([]*message.Message, error) {
+		*calls++
+		return nil, err
+	}
+}
+
+func TestInboxMiddlewareDropsDuplicates(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	calls := 0
+	handler := inboxMiddleware(database.NewMemoryStore(), InboxConfig{}).Middleware(countingHandler(&calls, nil))
+
+	for range 3 {
+		_, err := handler(message.NewMessage("msg-1", nil))
+		AssertNoError(t, err, "handler")
+	}
+	AssertEqual(t, calls, 1, "handler calls")
+
+	var metrics metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &metrics); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if got := inboxDuplicatesDropped(metrics); got != 2 {
+		t.Errorf("%s = %d, want 2", inboxDuplicatesMetric, got)
+	}
+}
+
+func TestInboxMiddlewareReleasesFailedMessages(t *testing.T) {
+	store := database.NewMemoryStore()
+	calls := 0
+	failing := inboxMiddleware(store, InboxConfig{}).Middleware(countingHandler(&calls, errors.New("boom")))
+
+	if _, err := failing(message.NewMessage("msg-1", nil)); err == nil {
+		t.Fatal("expected handler error to be returned")
+	}
+
+	succeeding := inboxMiddleware(store, InboxConfig{}).Middleware(countingHandler(&calls, nil))
+	_, err := succeeding(message.NewMessage("msg-1", nil))
+	AssertNoError(t, err, "redelivery")
+	AssertEqual(t, calls, 2, "handler calls")
+}
+
+func TestInboxMiddlewareNacksMessagesInFlight(t *testing.T) {
+	store := database.NewMemoryStore()
+	if _, err := store.ClaimInboxMessage(context.Background(), unknownInboxHandler, "msg-1", time.Now().UTC(), time.Minute); err != nil {
+		t.Fatalf("ClaimInboxMessage() error = %v", err)
+	}
+
+	calls := 0
+	handler := inboxMiddleware(store, InboxConfig{}).Middleware(countingHandler(&calls, nil))
+	if _, err := handler(message.NewMessage("msg-1", nil)); !errors.Is(err, ErrInboxMessageInFlight) {
+		t.Errorf("error = %v, want ErrInboxMessageInFlight", err)
+	}
+	AssertEqual(t, calls, 0, "handler calls")
+}
+
+func TestComposeEventMiddlewaresWithInbox(t *testing.T) {
+	cfg := &protoflow.Config{}
+	without := composeEventMiddlewares(cfg, nil, nil, InboxConfig{}, "", nil)
+	with := composeEventMiddlewares(cfg, nil, database.NewMemoryStore(), InboxConfig{}, "", nil)
+
+	if len(with) != len(without)+1 {
+		t.Fatalf("expected the inbox middleware to be added, got %d and %d middlewares", len(with), len(without))
+	}
+	if with[2].Name != "inbox" {
+		t.Errorf("middleware[2] = %q, want inbox before outbox and retries", with[2].Name)
+	}
+}
+
+func TestInboxStoreHonoursEnabledFlag(t *testing.T) {
+	db := database.NewMemoryStore()
+	if inboxStore(db, &Config{InboxEnabled: true}) == nil {
+		t.Error("expected an inbox store when enabled")
+	}
+	if inboxStore(db, &Config{}) != nil {
+		t.Error("expected no inbox store when disabled")
+	}
+	if inboxStore(nil, &Config{InboxEnabled: true}) != nil {
+		t.Error("expected no inbox store without a database")
+	}
+}
+
+func inboxDuplicatesDropped(metrics metricdata.ResourceMetrics) int64 {
+	var total int64
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != inboxDuplicatesMetric {
+				continue
+			}
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, point := range sum.DataPoints {
+					total += point.Value
+				}
+			}
+		}
+	}
+	return total
+}
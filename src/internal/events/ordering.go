@@ -0,0 +1,247 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	defaultMaxConcurrency = 8
+
+	// Route options understood by every route.
+	orderingKeyOption    = "ordering_key"
+	maxConcurrencyOption = "max_concurrency"
+
+	orderingKeyNone       = "none"
+	orderingQueueDepth    = "events.ordering.queue_depth"
+	orderingQueueDepthDoc = "Messages waiting for or being processed by a handler partition."
+)
+
+// routeOrderingOptions are accepted by every route.
+var routeOrderingOptions = []string{orderingKeyOption, maxConcurrencyOption}
+
+// orderingKeyFunc extracts the ordering key of a message, or "" when it has none.
+type orderingKeyFunc func(msg *message.Message) string
+
+// parseOrderingKey parses "metadata:<key>", "payload:<field>[.<field>...]" or "none".
+// Payload fields may be given by their proto name or their proto JSON name.
+func parseOrderingKey(spec string) (orderingKeyFunc, error) {
+	if spec == "" || spec == orderingKeyNone {
+		return nil, nil
+	}
+	source, name, ok := strings.Cut(spec, ":")
+	if !ok || name == "" {
+		return nil, fmt.Errorf("ordering key %q: want metadata:<key>, payload:<field> or none", spec)
+	}
+	switch source {
+	case "metadata":
+		return func(msg *message.Message) string { return msg.Metadata.Get(name) }, nil
+	case "payload":
+		path := strings.Split(name, ".")
+		return func(msg *message.Message) string { return jsonField(msg.Payload, path) }, nil
+	default:
+		return nil, fmt.Errorf("ordering key %q: unknown source %q", spec, source)
+	}
+}
+
+// jsonField returns the scalar at path in the JSON object payload, or "" when there
+// is none. Each segment matches the field under its own name or, as protojson writes
+// payloads by default, under its lowerCamelCase JSON name.
+func jsonField(payload []byte, path []string) string {
+	raw := json.RawMessage(payload)
+	for _, name := range path {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return ""
+		}
+		if raw = object[name]; raw == nil {
+			if raw = object[protoJSONName(name)]; raw == nil {
+				return ""
+			}
+		}
+	}
+	value, ok, err := cloudEventAttributeValue(raw)
+	if err != nil || !ok {
+		return ""
+	}
+	return value
+}
+
+// protoJSONName converts a proto field name to the JSON name protojson derives for
+// it: underscores are dropped and the letter after each is upper-cased.
+func protoJSONName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && 'a' <= r && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+			upper = false
+		default:
+			b.WriteRune(r)
+			upper = false
+		}
+	}
+	return b.String()
+}
+
+// orderedProcessing runs the messages of each handler on a fixed number of
+// partitions. Messages with the same key always land on the same partition, which
+// processes them one at a time in arrival order, so the partition count bounds the
+// handler's concurrency. Messages without a key are spread across the partitions.
+type orderedProcessing struct {
+	defaultMaxConcurrency int
+	depth                 metric.Int64UpDownCounter
+
+	mu        sync.RWMutex
+	executors map[string]*partitionedExecutor
+}
+
+func newOrderedProcessing(defaultMax int) *orderedProcessing {
+	if defaultMax <= 0 {
+		defaultMax = defaultMaxConcurrency
+	}
+	return &orderedProcessing{
+		defaultMaxConcurrency: defaultMax,
+		depth:                 newOrderingQueueDepthCounter(),
+		executors:             make(map[string]*partitionedExecutor),
+	}
+}
+
+// configureRoute applies the ordering_key and max_concurrency options of route,
+// falling back to defaultKey and the configured concurrency.
+func (o *orderedProcessing) configureRoute(route Route, defaultKey string) error {
+	spec := defaultKey
+	if value, ok := route.Options[orderingKeyOption]; ok {
+		spec = value
+	}
+	key, err := parseOrderingKey(spec)
+	if err != nil {
+		return fmt.Errorf("route %s: %w", route.RouteName(), err)
+	}
+	maxConcurrency, err := route.intOption(maxConcurrencyOption, o.defaultMaxConcurrency)
+	if err != nil {
+		return err
+	}
+	if maxConcurrency <= 0 {
+		return fmt.Errorf("route %s: %s must be positive", route.RouteName(), maxConcurrencyOption)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.executors[route.RouteName()] = &partitionedExecutor{
+		handler:    route.RouteName(),
+		key:        key,
+		depth:      o.depth,
+		partitions: make([]chan struct{}, maxConcurrency),
+	}
+	return nil
+}
+
+// middleware runs every message through the executor of its handler. Handlers
+// without one are not limited.
+func (o *orderedProcessing) middleware() protoflow.MiddlewareRegistration {
+	return protoflow.MiddlewareRegistration{
+		Name: "ordering",
+		Middleware: func(h message.HandlerFunc) message.HandlerFunc {
+			return func(msg *message.Message) ([]*message.Message, error) {
+				o.mu.RLock()
+				executor := o.executors[handlerNameOf(msg.Context())]
+				o.mu.RUnlock()
+				if executor == nil {
+					return h(msg)
+				}
+				return executor.run(msg, h)
+			}
+		},
+	}
+}
+
+// partitionedExecutor chains the messages of each partition: a message starts once
+// its predecessor on the partition is done.
+type partitionedExecutor struct {
+	handler string
+	key     orderingKeyFunc
+	depth   metric.Int64UpDownCounter
+	next    atomic.Uint64
+
+	mu sync.Mutex
+	// partitions holds the done channel of the last message queued on each partition.
+	partitions []chan struct{}
+}
+
+func (e *partitionedExecutor) run(msg *message.Message, h message.HandlerFunc) ([]*message.Message, error) {
+	ctx := msg.Context()
+	partition := e.partitionOf(msg)
+	attrs := metric.WithAttributes(
+		attribute.String("handler", e.handler),
+		attribute.Int("partition", partition),
+	)
+
+	done := make(chan struct{})
+	e.mu.Lock()
+	previous := e.partitions[partition]
+	e.partitions[partition] = done
+	e.mu.Unlock()
+
+	e.depth.Add(ctx, 1, attrs)
+	release := func() {
+		close(done)
+		e.depth.Add(ctx, -1, attrs)
+	}
+
+	if previous != nil {
+		select {
+		case <-previous:
+		case <-ctx.Done():
+			// Successors wait for this message, so it only gives up its turn once its
+			// own predecessor is done.
+			go func() {
+				<-previous
+				release()
+			}()
+			return nil, ctx.Err()
+		}
+	}
+	defer release()
+	return h(msg)
+}
+
+func (e *partitionedExecutor) partitionOf(msg *message.Message) int {
+	partitions := uint64(len(e.partitions))
+	if e.key != nil {
+		if key := e.key(msg); key != "" {
+			hash := fnv.New64a()
+			_, _ = hash.Write([]byte(key))
+			return int(hash.Sum64() % partitions) // #nosec G115 -- bounded by the partition count
+		}
+	}
+	return int(e.next.Add(1) % partitions) // #nosec G115 -- bounded by the partition count
+}
+
+// newOrderingQueueDepthCounter registers the gauge on the global meter provider,
+// falling back to a no-op counter like the inbox metrics.
+func newOrderingQueueDepthCounter() metric.Int64UpDownCounter {
+	counter, err := otel.Meter(eventsMeterName).Int64UpDownCounter(
+		orderingQueueDepth,
+		metric.WithDescription(orderingQueueDepthDoc),
+	)
+	if err != nil {
+		otel.Handle(err)
+		return noop.Int64UpDownCounter{}
+	}
+	return counter
+}
@@ -0,0 +1,261 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// protoMsg encodes payload with protojson, as protoflow and the outbox do.
+func protoMsg(t *testing.T, uuid string, payload proto.Message) *message.Message {
+	t.Helper()
+	data, err := protojson.Marshal(payload)
+	AssertNoError(t, err, "marshal payload")
+	return message.NewMessage(uuid, data)
+}
+
+func TestParseOrderingKey(t *testing.T) {
+	record := protoMsg(t, "msg-1", &domain.ExampleRecord{
+		RecordId: "EX-1",
+		Meta:     &domain.ExampleMeta{RequestedBy: "alice", Priority: 3},
+	})
+	record.Metadata.Set("correlation_id", "corr-1")
+	batch := protoMsg(t, "msg-2", &domain.BatchRequest{BatchId: "B-1"})
+
+	tests := []struct {
+		spec string
+		msg  *message.Message
+		want string
+	}{
+		{"payload:record_id", record, "EX-1"},
+		{"payload:recordId", record, "EX-1"},
+		{"payload:meta.requested_by", record, "alice"},
+		{"payload:meta.priority", record, "3"},
+		{"payload:batch_id", batch, "B-1"},
+		{"payload:missing", record, ""},
+		{"payload:record_id.nested", record, ""},
+		{"metadata:correlation_id", record, "corr-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			key, err := parseOrderingKey(tt.spec)
+			AssertNoError(t, err, "parse")
+			AssertEqual(t, key(tt.msg), tt.want, "key")
+		})
+	}
+
+	for _, spec := range []string{"", orderingKeyNone} {
+		key, err := parseOrderingKey(spec)
+		AssertNoError(t, err, "parse "+spec)
+		if key != nil {
+			t.Errorf("parseOrderingKey(%q) should not extract a key", spec)
+		}
+	}
+	for _, spec := range []string{"record_id", "payload:", "header:id"} {
+		if _, err := parseOrderingKey(spec); err == nil {
+			t.Errorf("parseOrderingKey(%q): expected an error", spec)
+		}
+	}
+}
+
+func newTestExecutor(t *testing.T, keySpec string, maxConcurrency string) *partitionedExecutor {
+	t.Helper()
+	ordering := newOrderedProcessing(0)
+	route := Route{Handler: unknownInboxHandler, Options: map[string]string{maxConcurrencyOption: maxConcurrency}}
+	AssertNoError(t, ordering.configureRoute(route, keySpec), "configure route")
+	return ordering.executors[unknownInboxHandler]
+}
+
+func recordMsg(t *testing.T, uuid, recordID string) *message.Message {
+	t.Helper()
+	return protoMsg(t, uuid, &domain.ExampleRecord{RecordId: recordID})
+}
+
+func TestOrderedProcessingKeepsOrderPerKey(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	executor := newTestExecutor(t, "payload:record_id", "4")
+	release := make(chan struct{})
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	handler := func(msg *message.Message) ([]*message.Message, error) {
+		if msg.UUID == "first" {
+			<-release
+		}
+		mu.Lock()
+		order = append(order, msg.UUID)
+		mu.Unlock()
+		return nil, nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Go(func() { _, _ = executor.run(recordMsg(t, "first", "EX-1"), handler) })
+	waitForQueueDepth(t, reader, 1)
+	wg.Go(func() { _, _ = executor.run(recordMsg(t, "second", "EX-1"), handler) })
+	waitForQueueDepth(t, reader, 2)
+
+	close(release)
+	wg.Wait()
+	AssertResultCount(t, len(order), 2)
+	AssertEqual(t, order[0], "first", "first processed")
+	AssertEqual(t, order[1], "second", "second processed")
+	waitForQueueDepth(t, reader, 0)
+}
+
+func TestOrderedProcessingBoundsConcurrency(t *testing.T) {
+	executor := newTestExecutor(t, orderingKeyNone, "2")
+	var running, peak atomic.Int32
+	handler := func(*message.Message) ([]*message.Message, error) {
+		current := running.Add(1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return nil, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() { _, _ = executor.run(message.NewMessage("msg", nil), handler) })
+	}
+	wg.Wait()
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", got)
+	}
+}
+
+func TestOrderedProcessingCancelledMessageKeepsItsTurn(t *testing.T) {
+	executor := newTestExecutor(t, "payload:record_id", "1")
+	release := make(chan struct{})
+	var third atomic.Bool
+	handler := func(msg *message.Message) ([]*message.Message, error) {
+		switch msg.UUID {
+		case "first":
+			<-release
+		case "third":
+			third.Store(true)
+		}
+		return nil, nil
+	}
+
+	var wg sync.WaitGroup
+	started := make(chan struct{})
+	wg.Go(func() {
+		close(started)
+		_, _ = executor.run(recordMsg(t, "first", "EX-1"), handler)
+	})
+	<-started
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := recordMsg(t, "second", "EX-1")
+	cancelled.SetContext(ctx)
+	errs := make(chan error, 1)
+	wg.Go(func() {
+		_, err := executor.run(cancelled, handler)
+		errs <- err
+	})
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-errs; err == nil {
+		t.Fatal("expected the cancelled message to return an error")
+	}
+
+	wg.Go(func() { _, _ = executor.run(recordMsg(t, "third", "EX-1"), handler) })
+	time.Sleep(20 * time.Millisecond)
+	if third.Load() {
+		t.Fatal("third message ran before the first one finished")
+	}
+	close(release)
+	wg.Wait()
+	if !third.Load() {
+		t.Error("third message did not run")
+	}
+}
+
+func TestOrderedProcessingMiddleware(t *testing.T) {
+	ordering := newOrderedProcessing(0)
+	calls := 0
+	handler := ordering.middleware().Middleware(countingHandler(&calls, nil))
+
+	// Handlers without a route are passed through.
+	_, err := handler(recordMsg(t, "msg-1", "EX-1"))
+	AssertNoError(t, err, "unconfigured handler")
+
+	AssertNoError(t, ordering.configureRoute(Route{Handler: unknownInboxHandler}, "payload:record_id"), "configure route")
+	_, err = handler(recordMsg(t, "msg-2", "EX-1"))
+	AssertNoError(t, err, "configured handler")
+	AssertEqual(t, calls, 2, "handler calls")
+	AssertEqual(t, len(ordering.executors[unknownInboxHandler].partitions), defaultMaxConcurrency, "partitions")
+}
+
+func TestOrderedProcessingRejectsInvalidOptions(t *testing.T) {
+	ordering := newOrderedProcessing(4)
+	invalid := []map[string]string{
+		{orderingKeyOption: "body:record_id"},
+		{maxConcurrencyOption: "0"},
+		{maxConcurrencyOption: "many"},
+	}
+	for _, options := range invalid {
+		if err := ordering.configureRoute(Route{Handler: "records", Options: options}, orderingKeyNone); err == nil {
+			t.Errorf("configureRoute(%v): expected an error", options)
+		}
+	}
+}
+
+// waitForQueueDepth polls the queue depth gauge until it sums to want.
+func waitForQueueDepth(t *testing.T, reader *sdkmetric.ManualReader, want int64) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		var metrics metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &metrics); err != nil {
+			t.Fatalf("Collect() error = %v", err)
+		}
+		got := orderingQueueDepthTotal(metrics)
+		if got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s = %d, want %d", orderingQueueDepth, got, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func orderingQueueDepthTotal(metrics metricdata.ResourceMetrics) int64 {
+	var total int64
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != orderingQueueDepth {
+				continue
+			}
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, point := range sum.DataPoints {
+					total += point.Value
+				}
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"maps"
+
+	"drblury/event-driven-service/internal/database"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+)
+
+const unknownOutboxHandler = "unknown_event"
+
+// outboxMiddleware persists handler outputs as pending outbox rows instead of letting
+// the router publish them. The OutboxRelay delivers the rows once they are committed,
+// so outgoing events are not lost. Delivery is at least once: a row whose publish
+// succeeded but was not marked sent is published again, so consumers must tolerate
+// duplicates. The rows are named after the route, which unlike the Go type of an
+// output does not change between releases. Without a store it falls back to the
+// protoflow middleware, which publishes directly.
+func outboxMiddleware(store OutboxStore) protoflow.MiddlewareRegistration {
+	if store == nil {
+		return protoflow.OutboxMiddleware()
+	}
+
+	return protoflow.MiddlewareRegistration{
+		Name: "outbox",
+		Middleware: func(h message.HandlerFunc) message.HandlerFunc {
+			return func(msg *message.Message) ([]*message.Message, error) {
+				outgoing, err := h(msg)
+				if err != nil || len(outgoing) == 0 {
+					return outgoing, err
+				}
+
+				handler := message.HandlerNameFromCtx(msg.Context())
+				topic := message.PublishTopicFromCtx(msg.Context())
+				for _, out := range outgoing {
+					if err := store.StoreOutboxMessage(msg.Context(), newOutboxMessage(handler, topic, out)); err != nil {
+						return nil, err
+					}
+				}
+				return nil, nil
+			}
+		},
+	}
+}
+
+// newOutboxMessage maps an outgoing Watermill message of handler onto an outbox row.
+func newOutboxMessage(handler string, topic string, msg *message.Message) *database.OutboxMessage {
+	if handler == "" {
+		handler = unknownOutboxHandler
+	}
+
+	return &database.OutboxMessage{
+		Handler:  handler,
+		UUID:     msg.UUID,
+		Payload:  string(msg.Payload),
+		Topic:    topic,
+		Metadata: maps.Clone(map[string]string(msg.Metadata)),
+	}
+}
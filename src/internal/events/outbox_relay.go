@@ -0,0 +1,222 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+)
+
+const (
+	defaultOutboxPollInterval         = time.Second
+	defaultOutboxBatchSize            = 100
+	defaultOutboxMaxAttempts          = 10
+	defaultOutboxRetryInitialInterval = time.Second
+	defaultOutboxRetryMaxInterval     = 5 * time.Minute
+	defaultOutboxLease                = 30 * time.Second
+)
+
+// OutboxStore is the persistence contract used by the outbox middleware and relay.
+type OutboxStore interface {
+	StoreOutboxMessage(ctx context.Context, msg *database.OutboxMessage) error
+	OutboxHandlers(ctx context.Context) ([]string, error)
+	ClaimOutboxMessages(ctx context.Context, handler string, now time.Time, lease time.Duration, limit int) ([]database.OutboxMessage, error)
+	MarkOutboxMessageSent(ctx context.Context, handler string, uuid string, sentAt time.Time) error
+	MarkOutboxMessageFailed(ctx context.Context, handler string, uuid string, reason string, nextAttemptAt time.Time, final bool) error
+}
+
+// OutboxPublisher delivers raw messages to the broker. *protoflow.Service satisfies it.
+type OutboxPublisher interface {
+	Publish(ctx context.Context, topic string, msgs ...*message.Message) error
+}
+
+// OutboxRelayConfig tunes how often and how aggressively pending outbox rows are dispatched.
+type OutboxRelayConfig struct {
+	PollInterval         time.Duration
+	BatchSize            int
+	MaxAttempts          int
+	RetryInitialInterval time.Duration
+	RetryMaxInterval     time.Duration
+	Lease                time.Duration
+	// LegacyTopics are the topics of rows stored without one, keyed by handler. Rows
+	// written by protoflow's outbox middleware before the relay existed carry none.
+	LegacyTopics map[string]string
+}
+
+// withDefaults fills unset or invalid values with sensible defaults.
+func (c OutboxRelayConfig) withDefaults() OutboxRelayConfig {
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultOutboxPollInterval
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultOutboxBatchSize
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultOutboxMaxAttempts
+	}
+	if c.RetryInitialInterval <= 0 {
+		c.RetryInitialInterval = defaultOutboxRetryInitialInterval
+	}
+	if c.RetryMaxInterval < c.RetryInitialInterval {
+		c.RetryMaxInterval = max(defaultOutboxRetryMaxInterval, c.RetryInitialInterval)
+	}
+	if c.Lease <= 0 {
+		c.Lease = defaultOutboxLease
+	}
+	return c
+}
+
+// OutboxRelay polls the outbox collections and publishes pending rows.
+type OutboxRelay struct {
+	store     OutboxStore
+	publisher OutboxPublisher
+	cfg       OutboxRelayConfig
+	log       *slog.Logger
+	now       func() time.Time
+}
+
+// NewOutboxRelay builds a relay that dispatches rows from store through publisher.
+func NewOutboxRelay(store OutboxStore, publisher OutboxPublisher, cfg OutboxRelayConfig, logger *slog.Logger) (*OutboxRelay, error) {
+	if store == nil {
+		return nil, errors.New("outbox store is required")
+	}
+	if publisher == nil {
+		return nil, errors.New("outbox publisher is required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &OutboxRelay{
+		store:     store,
+		publisher: publisher,
+		cfg:       cfg.withDefaults(),
+		log:       logger,
+		now:       func() time.Time { return time.Now().UTC() },
+	}, nil
+}
+
+// Run dispatches pending rows every poll interval until the context is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.DispatchPending(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			r.log.Error("outbox relay iteration failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending performs a single relay pass over every outbox collection.
+func (r *OutboxRelay) DispatchPending(ctx context.Context) error {
+	handlers, err := r.store.OutboxHandlers(ctx)
+	if err != nil {
+		return fmt.Errorf("list outbox handlers: %w", err)
+	}
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := r.dispatchHandler(ctx, handler); err != nil {
+			errs = append(errs, fmt.Errorf("outbox %s: %w", handler, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// dispatchHandler claims a batch of due rows for one handler and publishes them.
+func (r *OutboxRelay) dispatchHandler(ctx context.Context, handler string) error {
+	rows, err := r.store.ClaimOutboxMessages(ctx, handler, r.now(), r.cfg.Lease, r.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range rows {
+		if err := r.dispatchRow(ctx, handler, &rows[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// dispatchRow publishes a single row and records the outcome on the outbox entry.
+func (r *OutboxRelay) dispatchRow(ctx context.Context, handler string, row *database.OutboxMessage) error {
+	publishErr := r.publish(ctx, handler, row)
+	if publishErr == nil {
+		return r.store.MarkOutboxMessageSent(ctx, handler, row.UUID, r.now())
+	}
+
+	attempts := row.Attempts + 1
+	final := attempts >= r.cfg.MaxAttempts
+	nextAttemptAt := r.now().Add(r.backoff(attempts))
+
+	r.log.Warn("outbox dispatch failed",
+		"handler", handler,
+		"uuid", row.UUID,
+		"attempts", attempts,
+		"final", final,
+		"error", publishErr,
+	)
+
+	return r.store.MarkOutboxMessageFailed(ctx, handler, row.UUID, publishErr.Error(), nextAttemptAt, final)
+}
+
+// publish sends the stored payload to the topic captured when the row was written,
+// or to the legacy topic of its handler.
+func (r *OutboxRelay) publish(ctx context.Context, handler string, row *database.OutboxMessage) error {
+	topic := row.Topic
+	if topic == "" {
+		topic = r.cfg.LegacyTopics[handler]
+	}
+	if topic == "" {
+		return errors.New("outbox row has no topic")
+	}
+
+	msg := message.NewMessage(row.UUID, []byte(row.Payload))
+	for key, value := range row.Metadata {
+		msg.Metadata.Set(key, value)
+	}
+	if msg.Metadata.Get(protoflow.MetadataKeyEventSchema) == "" {
+		msg.Metadata.Set(protoflow.MetadataKeyEventSchema, handler)
+	}
+
+	return r.publisher.Publish(ctx, topic, msg)
+}
+
+// backoff returns the exponential delay before the given attempt is retried.
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := r.cfg.RetryInitialInterval
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= r.cfg.RetryMaxInterval {
+			return r.cfg.RetryMaxInterval
+		}
+	}
+	return delay
+}
+
+// StartOutboxRelay runs the relay loop until the context is cancelled.
+func StartOutboxRelay(ctx context.Context, relay *OutboxRelay, logger *slog.Logger) {
+	if relay == nil {
+		return
+	}
+	logger.Info("starting outbox relay",
+		"poll_interval", relay.cfg.PollInterval,
+		"batch_size", relay.cfg.BatchSize,
+		"max_attempts", relay.cfg.MaxAttempts,
+	)
+	relay.Run(ctx)
+}
@@ -0,0 +1,394 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+)
+
+type outboxOutcome struct {
+	handler       string
+	uuid          string
+	reason        string
+	nextAttemptAt time.Time
+	final         bool
+}
+
+type fakeOutboxStore struct {
+	mu       sync.Mutex
+	stored   []*database.OutboxMessage
+	rows     map[string][]database.OutboxMessage
+	sent     []outboxOutcome
+	failed   []outboxOutcome
+	claimErr error
+	storeErr error
+}
+
+func newFakeOutboxStore() *fakeOutboxStore {
+	return &fakeOutboxStore{rows: map[string][]database.OutboxMessage{}}
+}
+
+func (s *fakeOutboxStore) StoreOutboxMessage(_ context.Context, msg *database.OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.storeErr != nil {
+		return s.storeErr
+	}
+	s.stored = append(s.stored, msg)
+	return nil
+}
+
+func (s *fakeOutboxStore) OutboxHandlers(context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	handlers := make([]string, 0, len(s.rows))
+	for handler := range s.rows {
+		handlers = append(handlers, handler)
+	}
+	return handlers, nil
+}
+
+func (s *fakeOutboxStore) ClaimOutboxMessages(_ context.Context, handler string, _ time.Time, _ time.Duration, limit int) ([]database.OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.claimErr != nil {
+		return nil, s.claimErr
+	}
+	rows := s.rows[handler]
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	s.rows[handler] = s.rows[handler][len(rows):]
+	return rows, nil
+}
+
+func (s *fakeOutboxStore) MarkOutboxMessageSent(_ context.Context, handler, uuid string, sentAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, outboxOutcome{handler: handler, uuid: uuid, nextAttemptAt: sentAt})
+	return nil
+}
+
+func (s *fakeOutboxStore) MarkOutboxMessageFailed(_ context.Context, handler, uuid, reason string, nextAttemptAt time.Time, final bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed = append(s.failed, outboxOutcome{handler: handler, uuid: uuid, reason: reason, nextAttemptAt: nextAttemptAt, final: final})
+	return nil
+}
+
+type publishedMessage struct {
+	topic string
+	msg   *message.Message
+}
+
+type fakeOutboxPublisher struct {
+	mu        sync.Mutex
+	published []publishedMessage
+	err       error
+}
+
+func (p *fakeOutboxPublisher) Publish(_ context.Context, topic string, msgs ...*message.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	for _, msg := range msgs {
+		p.published = append(p.published, publishedMessage{topic: topic, msg: msg})
+	}
+	return nil
+}
+
+func newTestOutboxRelay(t *testing.T, store OutboxStore, publisher OutboxPublisher, cfg OutboxRelayConfig) *OutboxRelay {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	relay, err := NewOutboxRelay(store, publisher, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewOutboxRelay() error = %v", err)
+	}
+	return relay
+}
+
+func TestNewOutboxRelay(t *testing.T) {
+	t.Run("nil store returns error", func(t *testing.T) {
+		if _, err := NewOutboxRelay(nil, &fakeOutboxPublisher{}, OutboxRelayConfig{}, nil); err == nil {
+			t.Error("expected error when store is nil")
+		}
+	})
+
+	t.Run("nil publisher returns error", func(t *testing.T) {
+		if _, err := NewOutboxRelay(newFakeOutboxStore(), nil, OutboxRelayConfig{}, nil); err == nil {
+			t.Error("expected error when publisher is nil")
+		}
+	})
+
+	t.Run("applies defaults", func(t *testing.T) {
+		relay := newTestOutboxRelay(t, newFakeOutboxStore(), &fakeOutboxPublisher{}, OutboxRelayConfig{})
+		if relay.cfg.PollInterval != defaultOutboxPollInterval {
+			t.Errorf("PollInterval = %v, want %v", relay.cfg.PollInterval, defaultOutboxPollInterval)
+		}
+		if relay.cfg.BatchSize != defaultOutboxBatchSize {
+			t.Errorf("BatchSize = %d, want %d", relay.cfg.BatchSize, defaultOutboxBatchSize)
+		}
+		if relay.cfg.MaxAttempts != defaultOutboxMaxAttempts {
+			t.Errorf("MaxAttempts = %d, want %d", relay.cfg.MaxAttempts, defaultOutboxMaxAttempts)
+		}
+	})
+}
+
+func TestOutboxRelayDispatchPending(t *testing.T) {
+	t.Run("publishes pending rows and marks them sent", func(t *testing.T) {
+		store := newFakeOutboxStore()
+		store.rows["*domain.ExampleResult"] = []database.OutboxMessage{
+			{UUID: "id-1", Payload: `{"a":1}`, Topic: "results", Metadata: map[string]string{"source": "test"}},
+			{UUID: "id-2", Payload: `{"a":2}`, Topic: "results"},
+		}
+		publisher := &fakeOutboxPublisher{}
+		relay := newTestOutboxRelay(t, store, publisher, OutboxRelayConfig{})
+
+		AssertNoError(t, relay.DispatchPending(context.Background()), "DispatchPending")
+
+		if len(publisher.published) != 2 {
+			t.Fatalf("expected 2 published messages, got %d", len(publisher.published))
+		}
+		first := publisher.published[0]
+		if first.topic != "results" || first.msg.UUID != "id-1" {
+			t.Errorf("unexpected first message: topic=%q uuid=%q", first.topic, first.msg.UUID)
+		}
+		if first.msg.Metadata.Get("source") != "test" {
+			t.Errorf("expected stored metadata to be forwarded, got %v", first.msg.Metadata)
+		}
+		if first.msg.Metadata.Get(protoflow.MetadataKeyEventSchema) != "*domain.ExampleResult" {
+			t.Errorf("expected event schema metadata to default to the handler, got %v", first.msg.Metadata)
+		}
+		if len(store.sent) != 2 || len(store.failed) != 0 {
+			t.Errorf("expected 2 sent and 0 failed, got %d sent and %d failed", len(store.sent), len(store.failed))
+		}
+	})
+
+	t.Run("respects batch size", func(t *testing.T) {
+		store := newFakeOutboxStore()
+		store.rows["handler"] = []database.OutboxMessage{
+			{UUID: "1", Topic: "t"}, {UUID: "2", Topic: "t"}, {UUID: "3", Topic: "t"},
+		}
+		publisher := &fakeOutboxPublisher{}
+		relay := newTestOutboxRelay(t, store, publisher, OutboxRelayConfig{BatchSize: 2})
+
+		AssertNoError(t, relay.DispatchPending(context.Background()), "DispatchPending")
+		if len(publisher.published) != 2 {
+			t.Errorf("expected 2 published messages, got %d", len(publisher.published))
+		}
+	})
+
+	t.Run("legacy rows without topic go to the topic of their handler", func(t *testing.T) {
+		store := newFakeOutboxStore()
+		store.rows["*domain.ExampleResult"] = []database.OutboxMessage{{UUID: "legacy-1", Payload: `{"recordId":"EX-1"}`}}
+		publisher := &fakeOutboxPublisher{}
+		relay := newTestOutboxRelay(t, store, publisher, OutboxRelayConfig{
+			LegacyTopics: legacyOutboxTopics([]Route{{Handler: exampleRecordHandlerName, ConsumeQueue: "records", PublishQueue: "results"}}),
+		})
+
+		AssertNoError(t, relay.DispatchPending(context.Background()), "DispatchPending")
+
+		if len(publisher.published) != 1 || publisher.published[0].topic != "results" {
+			t.Fatalf("expected the legacy row to be published to results, got %+v", publisher.published)
+		}
+		if len(store.sent) != 1 {
+			t.Errorf("expected the legacy row to be marked sent, got %d sent", len(store.sent))
+		}
+	})
+
+	t.Run("claim errors are reported", func(t *testing.T) {
+		store := newFakeOutboxStore()
+		store.rows["handler"] = nil
+		store.claimErr = errors.New("boom")
+		relay := newTestOutboxRelay(t, store, &fakeOutboxPublisher{}, OutboxRelayConfig{})
+
+		if err := relay.DispatchPending(context.Background()); err == nil {
+			t.Error("expected claim error to be returned")
+		}
+	})
+}
+
+func TestOutboxRelayFailures(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cfg := OutboxRelayConfig{
+		MaxAttempts:          3,
+		RetryInitialInterval: time.Second,
+		RetryMaxInterval:     10 * time.Second,
+	}
+
+	t.Run("failed publish schedules a retry with backoff", func(t *testing.T) {
+		store := newFakeOutboxStore()
+		store.rows["handler"] = []database.OutboxMessage{{UUID: "id-1", Topic: "t", Attempts: 1}}
+		relay := newTestOutboxRelay(t, store, &fakeOutboxPublisher{err: errors.New("broker down")}, cfg)
+		relay.now = func() time.Time { return now }
+
+		AssertNoError(t, relay.DispatchPending(context.Background()), "DispatchPending")
+
+		if len(store.failed) != 1 {
+			t.Fatalf("expected 1 failed row, got %d", len(store.failed))
+		}
+		outcome := store.failed[0]
+		if outcome.final {
+			t.Error("expected row to remain retryable")
+		}
+		if want := now.Add(2 * time.Second); !outcome.nextAttemptAt.Equal(want) {
+			t.Errorf("nextAttemptAt = %v, want %v", outcome.nextAttemptAt, want)
+		}
+		if outcome.reason != "broker down" {
+			t.Errorf("reason = %q, want 'broker down'", outcome.reason)
+		}
+	})
+
+	t.Run("row is parked after max attempts", func(t *testing.T) {
+		store := newFakeOutboxStore()
+		store.rows["handler"] = []database.OutboxMessage{{UUID: "id-1", Topic: "t", Attempts: 2}}
+		relay := newTestOutboxRelay(t, store, &fakeOutboxPublisher{err: errors.New("broker down")}, cfg)
+
+		AssertNoError(t, relay.DispatchPending(context.Background()), "DispatchPending")
+
+		if len(store.failed) != 1 || !store.failed[0].final {
+			t.Errorf("expected row to be marked as finally failed, got %+v", store.failed)
+		}
+	})
+
+	t.Run("row without topic fails", func(t *testing.T) {
+		store := newFakeOutboxStore()
+		store.rows["handler"] = []database.OutboxMessage{{UUID: "id-1"}}
+		publisher := &fakeOutboxPublisher{}
+		relay := newTestOutboxRelay(t, store, publisher, cfg)
+
+		AssertNoError(t, relay.DispatchPending(context.Background()), "DispatchPending")
+
+		if len(publisher.published) != 0 {
+			t.Error("expected nothing to be published")
+		}
+		if len(store.failed) != 1 {
+			t.Errorf("expected 1 failed row, got %d", len(store.failed))
+		}
+	})
+}
+
+func TestOutboxRelayBackoff(t *testing.T) {
+	relay := newTestOutboxRelay(t, newFakeOutboxStore(), &fakeOutboxPublisher{}, OutboxRelayConfig{
+		RetryInitialInterval: time.Second,
+		RetryMaxInterval:     5 * time.Second,
+	})
+
+	testCases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 4, want: 5 * time.Second},
+		{attempts: 50, want: 5 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		if got := relay.backoff(tc.attempts); got != tc.want {
+			t.Errorf("backoff(%d) = %v, want %v", tc.attempts, got, tc.want)
+		}
+	}
+}
+
+func TestOutboxRelayRunStopsOnCancel(t *testing.T) {
+	relay := newTestOutboxRelay(t, newFakeOutboxStore(), &fakeOutboxPublisher{}, OutboxRelayConfig{PollInterval: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop after context cancellation")
+	}
+}
+
+func TestOutboxMiddleware(t *testing.T) {
+	t.Run("stores outputs instead of publishing", func(t *testing.T) {
+		store := newFakeOutboxStore()
+		mw := outboxMiddleware(store)
+		if mw.Middleware == nil {
+			t.Fatal("expected a concrete middleware when a store is configured")
+		}
+
+		out := message.NewMessage("out-1", []byte(`{"ok":true}`))
+		out.Metadata.Set(protoflow.MetadataKeyEventSchema, "*domain.ExampleResult")
+		handler := mw.Middleware(func(*message.Message) ([]*message.Message, error) {
+			return []*message.Message{out}, nil
+		})
+
+		msgs, err := handler(message.NewMessage("in-1", nil))
+		AssertNoError(t, err, "handler")
+		if len(msgs) != 0 {
+			t.Errorf("expected no messages to be returned to the router, got %d", len(msgs))
+		}
+		if len(store.stored) != 1 {
+			t.Fatalf("expected 1 stored row, got %d", len(store.stored))
+		}
+		row := store.stored[0]
+		// Outside a router the message has no handler name.
+		if row.Handler != unknownOutboxHandler || row.UUID != "out-1" || row.Payload != `{"ok":true}` {
+			t.Errorf("unexpected stored row: %+v", row)
+		}
+	})
+
+	t.Run("store errors fail the handler", func(t *testing.T) {
+		store := newFakeOutboxStore()
+		store.storeErr = errors.New("write failed")
+		handler := outboxMiddleware(store).Middleware(func(*message.Message) ([]*message.Message, error) {
+			return []*message.Message{message.NewMessage("out-1", nil)}, nil
+		})
+
+		if _, err := handler(message.NewMessage("in-1", nil)); err == nil {
+			t.Error("expected store error to be returned")
+		}
+	})
+
+	t.Run("nil store falls back to protoflow middleware", func(t *testing.T) {
+		mw := outboxMiddleware(nil)
+		if mw.Builder == nil {
+			t.Error("expected protoflow builder registration")
+		}
+	})
+}
+
+func TestNewOutboxMessageNamedAfterRoute(t *testing.T) {
+	out := message.NewMessage("id", nil)
+	out.Metadata.Set(protoflow.MetadataKeyEventSchema, "*domain.ExampleResult")
+
+	row := newOutboxMessage(exampleRecordHandlerName, "topic", out)
+	if row.Handler != exampleRecordHandlerName {
+		t.Errorf("Handler = %q, want %q", row.Handler, exampleRecordHandlerName)
+	}
+	if row.Metadata[protoflow.MetadataKeyEventSchema] != "*domain.ExampleResult" {
+		t.Errorf("expected the event schema to stay in the metadata, got %v", row.Metadata)
+	}
+}
+
+func TestNewOutboxMessageUnknownHandler(t *testing.T) {
+	row := newOutboxMessage("", "topic", message.NewMessage("id", nil))
+	if row.Handler != unknownOutboxHandler {
+		t.Errorf("Handler = %q, want %q", row.Handler, unknownOutboxHandler)
+	}
+	if row.Topic != "topic" {
+		t.Errorf("Topic = %q, want 'topic'", row.Topic)
+	}
+}
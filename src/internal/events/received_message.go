@@ -0,0 +1,43 @@
+package events
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+)
+
+// receivedMessageKey is the context key of the receivedMessage of a consumed message.
+type receivedMessageKey struct{}
+
+// receivedMessage identifies a consumed message as it arrived, for handlers that only
+// get its payload and metadata. CorrelationID is empty when the message carried none,
+// since the correlation middleware later assigns a random one.
+type receivedMessage struct {
+	UUID          string
+	CorrelationID string
+}
+
+// receivedMessageMiddleware records the receivedMessage of consumed messages in their
+// context. It must run before the correlation middleware.
+func receivedMessageMiddleware() protoflow.MiddlewareRegistration {
+	return protoflow.MiddlewareRegistration{
+		Name: "received_message",
+		Middleware: func(h message.HandlerFunc) message.HandlerFunc {
+			return func(msg *message.Message) ([]*message.Message, error) {
+				received := receivedMessage{
+					UUID:          msg.UUID,
+					CorrelationID: msg.Metadata.Get(protoflow.MetadataKeyCorrelationID),
+				}
+				msg.SetContext(context.WithValue(msg.Context(), receivedMessageKey{}, received))
+				return h(msg)
+			}
+		},
+	}
+}
+
+// receivedMessageFromContext returns the receivedMessage recorded in ctx, if any.
+func receivedMessageFromContext(ctx context.Context) (receivedMessage, bool) {
+	received, ok := ctx.Value(receivedMessageKey{}).(receivedMessage)
+	return received, ok
+}
@@ -0,0 +1,191 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+)
+
+// RouteTransport overrides the broker settings of the service for the queues of a
+// route. Unset fields keep the value of the service configuration; AWS credentials
+// are never read from the routes file.
+type RouteTransport struct {
+	PubSubSystem       string   `mapstructure:"pubsub_system"`
+	KafkaBrokers       []string `mapstructure:"kafka_brokers"`
+	KafkaClientID      string   `mapstructure:"kafka_client_id"`
+	KafkaConsumerGroup string   `mapstructure:"kafka_consumer_group"`
+	RabbitMQURL        string   `mapstructure:"rabbitmq_url"`
+	NATSURL            string   `mapstructure:"nats_url"`
+	HTTPServerAddress  string   `mapstructure:"http_server_address"`
+	HTTPPublisherURL   string   `mapstructure:"http_publisher_url"`
+	IOFile             string   `mapstructure:"io_file"`
+	SQLiteFile         string   `mapstructure:"sqlite_file"`
+	PostgresURL        string   `mapstructure:"postgres_url"`
+	AWSRegion          string   `mapstructure:"aws_region"`
+	AWSEndpoint        string   `mapstructure:"aws_endpoint"`
+}
+
+// config returns a copy of base with the settings of t applied.
+func (t RouteTransport) config(base *protoflow.Config) *protoflow.Config {
+	conf := *base
+	override := func(dst *string, value string) {
+		if value != "" {
+			*dst = value
+		}
+	}
+	override(&conf.PubSubSystem, t.PubSubSystem)
+	if len(t.KafkaBrokers) > 0 {
+		conf.KafkaBrokers = t.KafkaBrokers
+	}
+	override(&conf.KafkaClientID, t.KafkaClientID)
+	override(&conf.KafkaConsumerGroup, t.KafkaConsumerGroup)
+	override(&conf.RabbitMQURL, t.RabbitMQURL)
+	override(&conf.NATSURL, t.NATSURL)
+	override(&conf.HTTPServerAddress, t.HTTPServerAddress)
+	override(&conf.HTTPPublisherURL, t.HTTPPublisherURL)
+	override(&conf.IOFile, t.IOFile)
+	override(&conf.SQLiteFile, t.SQLiteFile)
+	override(&conf.PostgresURL, t.PostgresURL)
+	override(&conf.AWSRegion, t.AWSRegion)
+	override(&conf.AWSEndpoint, t.AWSEndpoint)
+	return &conf
+}
+
+// queueTransports assigns queues to the transports of the routes that use them.
+// Queues without an entry are served by the transport of the service.
+type queueTransports struct {
+	transports []RouteTransport
+	// queues maps a queue onto its index in transports.
+	queues map[string]int
+}
+
+// routeQueueTransports collects the transports of the enabled routes. The consume
+// and publish queue of a route with a transport are served by it for every
+// publisher and subscriber, so outbox rows and other routes reach them as well. A
+// queue can only live on one broker, so routes that put it on different transports
+// are rejected. Routes with equal settings share one connection.
+func routeQueueTransports(routes []Route) (queueTransports, error) {
+	qt := queueTransports{queues: make(map[string]int)}
+	var errs []error
+	for _, route := range routes {
+		if !route.IsEnabled() || route.Transport == nil {
+			continue
+		}
+		index := -1
+		for i, transport := range qt.transports {
+			if reflect.DeepEqual(transport, *route.Transport) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			index = len(qt.transports)
+			qt.transports = append(qt.transports, *route.Transport)
+		}
+		for _, queue := range []string{route.ConsumeQueue, route.PublishQueue} {
+			if queue == "" {
+				continue
+			}
+			if current, ok := qt.queues[queue]; ok && current != index {
+				errs = append(errs, fmt.Errorf("route %s: queue %s is already served by another transport", route.RouteName(), queue))
+				continue
+			}
+			qt.queues[queue] = index
+		}
+	}
+	return qt, errors.Join(errs...)
+}
+
+// buildTransportFunc builds a protoflow transport; protoflow.BuildTransport in production.
+type buildTransportFunc func(ctx context.Context, conf *protoflow.Config, logger watermill.LoggerAdapter) (protoflow.Transport, error)
+
+// build returns the transport of the service. With route transports, its publisher
+// and subscriber dispatch every queue to the transport serving it.
+func (qt queueTransports) build(ctx context.Context, conf *protoflow.Config, logger watermill.LoggerAdapter, build buildTransportFunc) (protoflow.Transport, error) {
+	base, err := build(ctx, conf, logger)
+	if err != nil || len(qt.transports) == 0 {
+		return base, err
+	}
+
+	built := []protoflow.Transport{base}
+	for _, transport := range qt.transports {
+		routed, err := build(ctx, transport.config(conf), logger)
+		if err != nil {
+			_ = (&routedPublisher{transports: built}).Close()
+			_ = (&routedSubscriber{transports: built}).Close()
+			return protoflow.Transport{}, fmt.Errorf("build %s transport of routes: %w", transport.config(conf).PubSubSystem, err)
+		}
+		built = append(built, routed)
+	}
+
+	publisher := &routedPublisher{transports: built, byQueue: make(map[string]message.Publisher)}
+	subscriber := &routedSubscriber{transports: built, byQueue: make(map[string]message.Subscriber)}
+	for queue, index := range qt.queues {
+		publisher.byQueue[queue] = built[index+1].Publisher
+		subscriber.byQueue[queue] = built[index+1].Subscriber
+	}
+	return protoflow.Transport{Publisher: publisher, Subscriber: subscriber}, nil
+}
+
+// routedPublisher publishes to the transport serving a queue, falling back to the
+// first transport.
+type routedPublisher struct {
+	transports []protoflow.Transport
+	byQueue    map[string]message.Publisher
+}
+
+func (p *routedPublisher) Publish(topic string, msgs ...*message.Message) error {
+	publisher, ok := p.byQueue[topic]
+	if !ok {
+		publisher = p.transports[0].Publisher
+	}
+	if publisher == nil {
+		return fmt.Errorf("transport of queue %s cannot publish", topic)
+	}
+	return publisher.Publish(topic, msgs...)
+}
+
+// Close closes the publishers of every transport.
+func (p *routedPublisher) Close() error {
+	var errs []error
+	for _, transport := range p.transports {
+		if transport.Publisher != nil {
+			errs = append(errs, transport.Publisher.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// routedSubscriber subscribes through the transport serving a queue, falling back
+// to the first transport.
+type routedSubscriber struct {
+	transports []protoflow.Transport
+	byQueue    map[string]message.Subscriber
+}
+
+func (s *routedSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	subscriber, ok := s.byQueue[topic]
+	if !ok {
+		subscriber = s.transports[0].Subscriber
+	}
+	if subscriber == nil {
+		return nil, fmt.Errorf("transport of queue %s cannot subscribe", topic)
+	}
+	return subscriber.Subscribe(ctx, topic)
+}
+
+// Close closes the subscribers of every transport.
+func (s *routedSubscriber) Close() error {
+	var errs []error
+	for _, transport := range s.transports {
+		if transport.Subscriber != nil {
+			errs = append(errs, transport.Subscriber.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
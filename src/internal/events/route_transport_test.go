@@ -0,0 +1,144 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/drblury/protoflow"
+)
+
+// fakeBroker records the topics published to and subscribed on one transport.
+type fakeBroker struct {
+	system     string
+	published  []string
+	subscribed []string
+	closed     int
+}
+
+func (b *fakeBroker) Publish(topic string, _ ...*message.Message) error {
+	b.published = append(b.published, topic)
+	return nil
+}
+
+func (b *fakeBroker) Subscribe(_ context.Context, topic string) (<-chan *message.Message, error) {
+	b.subscribed = append(b.subscribed, topic)
+	return make(chan *message.Message), nil
+}
+
+func (b *fakeBroker) Close() error {
+	b.closed++
+	return nil
+}
+
+// fakeBrokers builds a fakeBroker per transport and fails for the system "broken".
+func fakeBrokers(brokers *[]*fakeBroker) buildTransportFunc {
+	return func(_ context.Context, conf *protoflow.Config, _ watermill.LoggerAdapter) (protoflow.Transport, error) {
+		if conf.PubSubSystem == "broken" {
+			return protoflow.Transport{}, errors.New("unreachable broker")
+		}
+		broker := &fakeBroker{system: conf.PubSubSystem}
+		*brokers = append(*brokers, broker)
+		return protoflow.Transport{Publisher: broker, Subscriber: broker}, nil
+	}
+}
+
+func TestRouteQueueTransports(t *testing.T) {
+	disabled := false
+	queues, err := routeQueueTransports([]Route{
+		{Handler: "records", ConsumeQueue: "records-in", PublishQueue: "records-out"},
+		{Name: "legacy", Handler: "records", ConsumeQueue: "legacy-in", PublishQueue: "legacy-out", Transport: &RouteTransport{PubSubSystem: "rabbitmq"}},
+		{Name: "legacy-updates", Handler: "updates", ConsumeQueue: "legacy-updates", PublishQueue: "legacy-in", Transport: &RouteTransport{PubSubSystem: "rabbitmq"}},
+		{Name: "off", Handler: "records", ConsumeQueue: "records-in", Transport: &RouteTransport{PubSubSystem: "nats"}, Enabled: &disabled},
+	})
+	AssertNoError(t, err, "route transports")
+
+	AssertResultCount(t, len(queues.transports), 1)
+	AssertResultCount(t, len(queues.queues), 3)
+	for _, queue := range []string{"legacy-in", "legacy-out", "legacy-updates"} {
+		if index, ok := queues.queues[queue]; !ok || index != 0 {
+			t.Errorf("queue %s = %d, %v; want the rabbitmq transport", queue, index, ok)
+		}
+	}
+	if _, ok := queues.queues["records-in"]; ok {
+		t.Error("queue of a route without a transport should use the service transport")
+	}
+}
+
+func TestRouteQueueTransportsRejectsConflicts(t *testing.T) {
+	_, err := routeQueueTransports([]Route{
+		{Name: "kafka", Handler: "records", ConsumeQueue: "records-in", Transport: &RouteTransport{PubSubSystem: "kafka"}},
+		{Name: "nats", Handler: "records", ConsumeQueue: "nats-in", PublishQueue: "records-in", Transport: &RouteTransport{PubSubSystem: "nats"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "queue records-in is already served by another transport") {
+		t.Fatalf("error = %v, want a transport conflict for records-in", err)
+	}
+}
+
+func TestRouteTransportConfig(t *testing.T) {
+	base := &protoflow.Config{PubSubSystem: "kafka", KafkaBrokers: []string{"kafka:9092"}, KafkaConsumerGroup: "service", AWSSecretAccessKey: "secret"}
+	conf := RouteTransport{PubSubSystem: "rabbitmq", RabbitMQURL: "amqp://legacy:5672/"}.config(base)
+
+	AssertEqual(t, conf.PubSubSystem, "rabbitmq", "pubsub system")
+	AssertEqual(t, conf.RabbitMQURL, "amqp://legacy:5672/", "rabbitmq url")
+	AssertEqual(t, conf.KafkaConsumerGroup, "service", "inherited consumer group")
+	AssertEqual(t, conf.AWSSecretAccessKey, "secret", "inherited credentials")
+	AssertEqual(t, base.PubSubSystem, "kafka", "base pubsub system")
+}
+
+func TestQueueTransportsBuildRoutesQueues(t *testing.T) {
+	queues, err := routeQueueTransports([]Route{
+		{Name: "legacy", Handler: "records", ConsumeQueue: "legacy-in", PublishQueue: "legacy-out", Transport: &RouteTransport{PubSubSystem: "rabbitmq"}},
+	})
+	AssertNoError(t, err, "route transports")
+
+	var brokers []*fakeBroker
+	transport, err := queues.build(context.Background(), &protoflow.Config{PubSubSystem: "kafka"}, nil, fakeBrokers(&brokers))
+	AssertNoError(t, err, "build")
+	AssertResultCount(t, len(brokers), 2)
+	service, legacy := brokers[0], brokers[1]
+	AssertEqual(t, legacy.system, "rabbitmq", "route transport")
+
+	AssertNoError(t, transport.Publisher.Publish("legacy-out", message.NewMessage("m-1", nil)), "publish legacy-out")
+	AssertNoError(t, transport.Publisher.Publish("records-out", message.NewMessage("m-2", nil)), "publish records-out")
+	_, err = transport.Subscriber.Subscribe(context.Background(), "legacy-in")
+	AssertNoError(t, err, "subscribe legacy-in")
+	_, err = transport.Subscriber.Subscribe(context.Background(), "records-in")
+	AssertNoError(t, err, "subscribe records-in")
+
+	AssertEqual(t, strings.Join(legacy.published, ","), "legacy-out", "published on the route transport")
+	AssertEqual(t, strings.Join(legacy.subscribed, ","), "legacy-in", "subscribed on the route transport")
+	AssertEqual(t, strings.Join(service.published, ","), "records-out", "published on the service transport")
+	AssertEqual(t, strings.Join(service.subscribed, ","), "records-in", "subscribed on the service transport")
+
+	AssertNoError(t, transport.Publisher.Close(), "close publisher")
+	AssertNoError(t, transport.Subscriber.Close(), "close subscriber")
+	AssertEqual(t, service.closed, 2, "service transport closes")
+	AssertEqual(t, legacy.closed, 2, "route transport closes")
+}
+
+func TestQueueTransportsBuildFailureClosesTransports(t *testing.T) {
+	queues, err := routeQueueTransports([]Route{
+		{Name: "legacy", Handler: "records", ConsumeQueue: "legacy-in", Transport: &RouteTransport{PubSubSystem: "broken"}},
+	})
+	AssertNoError(t, err, "route transports")
+
+	var brokers []*fakeBroker
+	if _, err := queues.build(context.Background(), &protoflow.Config{PubSubSystem: "kafka"}, nil, fakeBrokers(&brokers)); err == nil {
+		t.Fatal("build with an unreachable route transport should fail")
+	}
+	AssertResultCount(t, len(brokers), 1)
+	AssertEqual(t, brokers[0].closed, 2, "service transport closes")
+}
+
+func TestQueueTransportsBuildWithoutRouteTransports(t *testing.T) {
+	var brokers []*fakeBroker
+	transport, err := queueTransports{}.build(context.Background(), &protoflow.Config{PubSubSystem: "kafka"}, nil, fakeBrokers(&brokers))
+	AssertNoError(t, err, "build")
+	if transport.Publisher != brokers[0] {
+		t.Error("without route transports the service transport should be used directly")
+	}
+}
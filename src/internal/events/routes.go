@@ -0,0 +1,153 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/drblury/protoflow"
+)
+
+// Names of the handlers provided by this application.
+const (
+	demoHandlerName                = "demoHandler"
+	exampleRecordHandlerName       = "exampleRecordHandler"
+	exampleRecordUpdateHandlerName = "exampleRecordUpdateHandler"
+	exampleBatchHandlerName        = "exampleBatchHandler"
+	exampleResultHandlerName       = "exampleResultHandler"
+)
+
+// Route binds a named handler to the queues it consumes from and publishes to.
+type Route struct {
+	// Name identifies the registration and defaults to Handler. Routes that run the
+	// same handler against several queues need distinct names.
+	Name         string            `mapstructure:"name"`
+	Handler      string            `mapstructure:"handler"`
+	ConsumeQueue string            `mapstructure:"consume_queue"`
+	PublishQueue string            `mapstructure:"publish_queue"`
+	Options      map[string]string `mapstructure:"options"`
+	// Transport serves the queues of the route from another broker than the service.
+	Transport *RouteTransport `mapstructure:"transport"`
+	// Enabled defaults to true when unset.
+	Enabled *bool `mapstructure:"enabled"`
+}
+
+// RouteName returns the registration name of the route.
+func (r Route) RouteName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Handler
+}
+
+// IsEnabled reports whether the route should be registered.
+func (r Route) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// checkOptions rejects options the handler does not understand, so typos fail at startup.
+func (r Route) checkOptions(known ...string) error {
+	for _, key := range slices.Sorted(maps.Keys(r.Options)) {
+		if !slices.Contains(known, key) {
+			return fmt.Errorf("route %s: unknown option %q", r.RouteName(), key)
+		}
+	}
+	return nil
+}
+
+func (r Route) boolOption(key string) (bool, error) {
+	value, ok := r.Options[key]
+	if !ok {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("route %s: option %s: %w", r.RouteName(), key, err)
+	}
+	return parsed, nil
+}
+
+func (r Route) intOption(key string, fallback int) (int, error) {
+	value, ok := r.Options[key]
+	if !ok {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("route %s: option %s: %w", r.RouteName(), key, err)
+	}
+	return parsed, nil
+}
+
+// HandlerFactory registers the handler of route on svc.
+type HandlerFactory func(svc *protoflow.Service, route Route) error
+
+// HandlerRegistry resolves routes against named handler factories.
+type HandlerRegistry struct {
+	factories map[string]HandlerFactory
+}
+
+// NewHandlerRegistry returns an empty registry.
+func NewHandlerRegistry() *HandlerRegistry {
+	return &HandlerRegistry{factories: make(map[string]HandlerFactory)}
+}
+
+// Register adds the factory for handler. Each handler name can be registered once.
+func (r *HandlerRegistry) Register(handler string, factory HandlerFactory) error {
+	if handler == "" || factory == nil {
+		return errors.New("handler name and factory are required")
+	}
+	if _, ok := r.factories[handler]; ok {
+		return fmt.Errorf("handler %s is already registered", handler)
+	}
+	r.factories[handler] = factory
+	return nil
+}
+
+// Handlers returns the registered handler names in sorted order.
+func (r *HandlerRegistry) Handlers() []string {
+	return slices.Sorted(maps.Keys(r.factories))
+}
+
+// RegisterRoutes registers every enabled route on svc. All routes are validated
+// before the first one is registered, so a bad configuration registers nothing.
+func (r *HandlerRegistry) RegisterRoutes(svc *protoflow.Service, routes []Route) error {
+	if err := r.ValidateRoutes(routes); err != nil {
+		return err
+	}
+	for _, route := range routes {
+		if !route.IsEnabled() {
+			continue
+		}
+		if err := r.factories[route.Handler](svc, route); err != nil {
+			return fmt.Errorf("register route %s: %w", route.RouteName(), err)
+		}
+	}
+	return nil
+}
+
+// ValidateRoutes checks that every enabled route names a known handler, has a
+// consume queue and a unique name.
+func (r *HandlerRegistry) ValidateRoutes(routes []Route) error {
+	var errs []error
+	names := make(map[string]bool, len(routes))
+	for i, route := range routes {
+		if !route.IsEnabled() {
+			continue
+		}
+		if _, ok := r.factories[route.Handler]; !ok {
+			errs = append(errs, fmt.Errorf("route %d: unknown handler %q, known handlers are %v", i, route.Handler, r.Handlers()))
+			continue
+		}
+		if route.ConsumeQueue == "" {
+			errs = append(errs, fmt.Errorf("route %s: consume queue is required", route.RouteName()))
+		}
+		if names[route.RouteName()] {
+			errs = append(errs, fmt.Errorf("route %s: duplicate route name, set a distinct name", route.RouteName()))
+		}
+		names[route.RouteName()] = true
+	}
+	return errors.Join(errs...)
+}
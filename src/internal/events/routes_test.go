@@ -0,0 +1,178 @@
+package events
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"drblury/event-driven-service/internal/database"
+
+	"github.com/drblury/protoflow"
+)
+
+// recordingRegistry returns a registry whose factories record the routes they get.
+func recordingRegistry(t *testing.T, handlers ...string) (*HandlerRegistry, *[]Route) {
+	t.Helper()
+	var registered []Route
+	registry := NewHandlerRegistry()
+	for _, handler := range handlers {
+		err := registry.Register(handler, func(_ *protoflow.Service, route Route) error {
+			registered = append(registered, route)
+			return nil
+		})
+		AssertNoError(t, err, "register "+handler)
+	}
+	return registry, &registered
+}
+
+func TestHandlerRegistryRegistersEnabledRoutes(t *testing.T) {
+	registry, registered := recordingRegistry(t, "records", "updates")
+	disabled := false
+
+	err := registry.RegisterRoutes(nil, []Route{
+		{Handler: "records", ConsumeQueue: "records-in", PublishQueue: "records-out"},
+		{Name: "records-priority", Handler: "records", ConsumeQueue: "records-priority"},
+		{Handler: "updates", ConsumeQueue: "updates-in", Enabled: &disabled},
+	})
+	AssertNoError(t, err, "register routes")
+
+	AssertResultCount(t, len(*registered), 2)
+	AssertEqual(t, (*registered)[0].RouteName(), "records", "first route name")
+	AssertEqual(t, (*registered)[1].RouteName(), "records-priority", "second route name")
+	AssertEqual(t, (*registered)[1].ConsumeQueue, "records-priority", "second consume queue")
+}
+
+func TestHandlerRegistryRejectsInvalidRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []Route
+		want   string
+	}{
+		{"unknown handler", []Route{{Handler: "missing", ConsumeQueue: "in"}}, `unknown handler "missing"`},
+		{"missing consume queue", []Route{{Handler: "records"}}, "consume queue is required"},
+		{"duplicate name", []Route{
+			{Handler: "records", ConsumeQueue: "a"},
+			{Handler: "records", ConsumeQueue: "b"},
+		}, "duplicate route name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, registered := recordingRegistry(t, "records")
+			err := registry.RegisterRoutes(nil, tt.routes)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want it to mention %q", err, tt.want)
+			}
+			AssertResultCount(t, len(*registered), 0)
+		})
+	}
+}
+
+func TestHandlerRegistryIgnoresDisabledInvalidRoutes(t *testing.T) {
+	registry, _ := recordingRegistry(t, "records")
+	disabled := false
+	err := registry.ValidateRoutes([]Route{{Handler: "missing", Enabled: &disabled}})
+	AssertNoError(t, err, "validate routes")
+}
+
+func TestHandlerRegistryRegister(t *testing.T) {
+	registry, _ := recordingRegistry(t, "records")
+	if err := registry.Register("records", func(*protoflow.Service, Route) error { return nil }); err == nil {
+		t.Error("expected an error for a duplicate handler")
+	}
+	if err := registry.Register("other", nil); err == nil {
+		t.Error("expected an error without a factory")
+	}
+
+	boom := errors.New("boom")
+	AssertNoError(t, registry.Register("failing", func(*protoflow.Service, Route) error { return boom }), "register failing")
+	if err := registry.RegisterRoutes(nil, []Route{{Handler: "failing", ConsumeQueue: "in"}}); !errors.Is(err, boom) {
+		t.Errorf("error = %v, want the factory error", err)
+	}
+}
+
+func TestRouteOptions(t *testing.T) {
+	route := Route{Handler: "records", Options: map[string]string{"parallelism": "8", "validate_outgoing": "yes"}}
+
+	AssertNoError(t, route.checkOptions("parallelism", "validate_outgoing"), "known options")
+	if err := route.checkOptions("parallelism"); err == nil {
+		t.Error("expected an error for an unknown option")
+	}
+
+	parallelism, err := route.intOption("parallelism", 4)
+	AssertNoError(t, err, "parallelism")
+	AssertEqual(t, parallelism, 8, "parallelism")
+	fallback, err := route.intOption("missing", 4)
+	AssertNoError(t, err, "missing option")
+	AssertEqual(t, fallback, 4, "fallback")
+	if _, err := route.boolOption("validate_outgoing"); err == nil {
+		t.Error("expected an error for a malformed boolean")
+	}
+}
+
+func TestConfigRoutesDefaults(t *testing.T) {
+	cfg := &Config{
+		DemoConsumeQueue:          "messages",
+		ExampleConsumeQueue:       "example-records",
+		ExamplePublishQueue:       "example-records-processed",
+		ExampleUpdateConsumeQueue: "example-record-updates",
+		ExampleBatchConsumeQueue:  "example-batches",
+	}
+
+	routes := cfg.routes(false)
+	AssertResultCount(t, len(routes), 5)
+	AssertEqual(t, routes[2].Handler, exampleRecordUpdateHandlerName, "update handler")
+	AssertEqual(t, routes[2].PublishQueue, "example-records", "updates are processed again")
+	AssertEqual(t, routes[3].Handler, exampleBatchHandlerName, "batch handler")
+	AssertEqual(t, routes[3].IsEnabled(), false, "batch handler without database")
+	AssertEqual(t, cfg.routes(true)[3].IsEnabled(), true, "batch handler with database")
+	AssertEqual(t, routes[4].Handler, exampleResultHandlerName, "result handler")
+	AssertEqual(t, routes[4].ConsumeQueue, "example-records-processed", "results are consumed from the publish queue")
+	AssertEqual(t, routes[4].IsEnabled(), false, "result handler without database")
+
+	cfg.Routes = []Route{{Handler: exampleRecordHandlerName, ConsumeQueue: "rewired"}}
+	AssertResultCount(t, len(cfg.routes(true)), 1)
+}
+
+func TestConfigCloudEventTypesFollowRoutes(t *testing.T) {
+	cfg := &Config{ExampleFailedQueue: "failed"}
+	types := cfg.cloudEventTypes([]Route{
+		{Handler: exampleRecordHandlerName, ConsumeQueue: "records-priority", PublishQueue: "records-done"},
+		{Handler: demoHandlerName, ConsumeQueue: "messages"},
+	})
+
+	AssertEqual(t, types["records-priority"], "example.record.submitted", "consume type")
+	AssertEqual(t, types["records-done"], "example.record.processed", "publish type")
+	AssertEqual(t, types["failed"], "example.record.failed", "failed type")
+	if _, ok := types["messages"]; ok {
+		t.Error("demo queues have no AsyncAPI channel")
+	}
+}
+
+func TestAppHandlerRegistry(t *testing.T) {
+	registry, err := newAppHandlerRegistry(&Config{}, nil, nil, nil, newOrderedProcessing(0))
+	AssertNoError(t, err, "new registry")
+	want := []string{demoHandlerName, exampleBatchHandlerName, exampleRecordHandlerName, exampleRecordUpdateHandlerName, exampleResultHandlerName}
+	got := registry.Handlers()
+	AssertResultCount(t, len(got), len(want))
+	for i := range want {
+		AssertEqual(t, got[i], want[i], "handler")
+	}
+
+	// Failing factories return before they touch the service.
+	if err := registry.RegisterRoutes(nil, []Route{{Handler: exampleBatchHandlerName, ConsumeQueue: "batches"}}); err == nil {
+		t.Error("expected the batch handler to require a database")
+	}
+	if err := registry.RegisterRoutes(nil, []Route{{Handler: exampleResultHandlerName, ConsumeQueue: "results"}}); err == nil {
+		t.Error("expected the result handler to require a database")
+	}
+	withDB, err := newAppHandlerRegistry(&Config{}, nil, nil, database.NewMemoryStore(), newOrderedProcessing(0))
+	AssertNoError(t, err, "new registry with database")
+	err = withDB.RegisterRoutes(nil, []Route{{
+		Handler:      exampleRecordHandlerName,
+		ConsumeQueue: "records",
+		Options:      map[string]string{"parallelism": "2"},
+	}})
+	if err == nil || !strings.Contains(err.Error(), `unknown option "parallelism"`) {
+		t.Errorf("error = %v, want parallelism to be rejected for the record handler", err)
+	}
+}
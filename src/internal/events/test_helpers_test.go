@@ -9,6 +9,7 @@ import (
 	"drblury/event-driven-service/internal/domain"
 
 	"github.com/drblury/protoflow"
+	date "google.golang.org/genproto/googleapis/type/date"
 )
 
 // =============================================================================
@@ -27,7 +28,7 @@ func NewTestFixtures() *TestFixtures {
 func (f *TestFixtures) DemoEvent(id int, year, month, day int32) *demoEvent {
 	return &demoEvent{
 		ID: id,
-		Date: &domain.Date{
+		Date: &date.Date{
 			Year:  year,
 			Month: month,
 			Day:   day,
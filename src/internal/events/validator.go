@@ -2,12 +2,15 @@ package events
 
 import (
 	"drblury/event-driven-service/internal/domain"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 
 	"buf.build/go/protovalidate"
 	"github.com/bytedance/gopkg/util/logger"
 	"github.com/samber/lo"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
@@ -26,14 +29,74 @@ func NewValidator() (*Validator, error) {
 	}, nil
 }
 
+// Validate checks a against its protovalidate rules and reports violations as
+// domain.ErrValidations. Values that are not proto messages, such as the payloads
+// of JSON handlers, are validated by their own Validate method if they have one
+// and accepted otherwise.
 func (v Validator) Validate(a any) error {
-	if err := v.validator.Validate(a.(protoreflect.ProtoMessage)); err != nil {
-		// log the error
-		slog.With("error", err).Error("validation error")
-		errMessages := lo.Map(err.(*protovalidate.ValidationError).Violations, func(violation *protovalidate.Violation, _ int) string {
-			return fmt.Sprintf("%s %s", violation.Proto.GetField(), violation.Proto.GetMessage())
-		})
-		return domain.ErrValidations{Errors: errMessages}
-	}
-	return nil
+	switch value := a.(type) {
+	case nil:
+		return nil
+	case proto.Message:
+		if !value.ProtoReflect().IsValid() {
+			return nil
+		}
+		return v.validateProto(value)
+	case interface{ Validate() error }:
+		return value.Validate()
+	default:
+		return nil
+	}
+}
+
+func (v Validator) validateProto(msg proto.Message) error {
+	err := v.validator.Validate(msg)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *protovalidate.ValidationError
+	if !errors.As(err, &validationErr) {
+		// Compilation and runtime errors mean the rules themselves are broken.
+		return fmt.Errorf("validate %s: %w", msg.ProtoReflect().Descriptor().FullName(), err)
+	}
+	// log the error
+	slog.With("error", err).Error("validation error")
+	return domain.ErrValidations{
+		Errors: lo.Map(validationErr.Violations, func(violation *protovalidate.Violation, _ int) domain.Violation {
+			return domain.Violation{
+				Field:   protovalidate.FieldPathString(violation.Proto.GetField()),
+				Rule:    violation.Proto.GetRuleId(),
+				Message: violation.Proto.GetMessage(),
+				Value:   violationValue(violation.FieldValue),
+			}
+		}),
+	}
+}
+
+// violationValue returns the scalar held by value, or nil for absent values,
+// messages, lists, maps and non-finite floats, which have no plain JSON form.
+func violationValue(value protoreflect.Value) any {
+	if !value.IsValid() {
+		return nil
+	}
+	switch scalar := value.Interface().(type) {
+	case protoreflect.Message, protoreflect.List, protoreflect.Map:
+		return nil
+	case protoreflect.EnumNumber:
+		return int32(scalar)
+	case float32:
+		return finiteOrNil(float64(scalar))
+	case float64:
+		return finiteOrNil(scalar)
+	default:
+		return scalar
+	}
+}
+
+func finiteOrNil(f float64) any {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	return f
 }
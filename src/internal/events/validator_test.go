@@ -1,9 +1,16 @@
 package events
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 
 	"drblury/event-driven-service/internal/domain"
+
+	date "google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 func TestNewValidator(t *testing.T) {
@@ -98,7 +105,7 @@ func TestValidatorValidateExampleResult(t *testing.T) {
 		RecordId: "result-123",
 		Status:   "completed",
 		Note:     "Processed successfully",
-		ProcessedOn: &domain.Date{
+		ProcessedOn: &date.Date{
 			Year:  2024,
 			Month: 6,
 			Day:   15,
@@ -117,7 +124,7 @@ func TestValidatorValidateDate(t *testing.T) {
 		t.Fatalf("NewValidator() error = %v", err)
 	}
 
-	date := &domain.Date{
+	date := &date.Date{
 		Year:  2024,
 		Month: 12,
 		Day:   31,
@@ -170,8 +177,8 @@ func TestValidatorValidateExampleMeta(t *testing.T) {
 	meta := &domain.ExampleMeta{
 		RequestedBy:      "test-user",
 		RequiresFollowUp: true,
-		Priority:         10,
-		DesiredStartDate: &domain.Date{
+		Priority:         5,
+		DesiredStartDate: &date.Date{
 			Year:  2025,
 			Month: 1,
 			Day:   1,
@@ -213,7 +220,7 @@ func TestValidatorValidateExampleResultVariants(t *testing.T) {
 	results := []*domain.ExampleResult{
 		{RecordId: "r1", Status: "pending"},
 		{RecordId: "r2", Status: "completed", Note: "Done"},
-		{RecordId: "r3", Status: "failed", Note: "Error occurred", ProcessedOn: &domain.Date{Year: 2024, Month: 1, Day: 1}},
+		{RecordId: "r3", Status: "failed", Note: "Error occurred", ProcessedOn: &date.Date{Year: 2024, Month: 1, Day: 1}},
 	}
 
 	for i, result := range results {
@@ -230,7 +237,7 @@ func TestValidatorValidateDateVariants(t *testing.T) {
 		t.Fatalf("NewValidator() error = %v", err)
 	}
 
-	dates := []*domain.Date{
+	dates := []*date.Date{
 		{Year: 2020, Month: 1, Day: 1},
 		{Year: 2024, Month: 6, Day: 15},
 		{Year: 2030, Month: 12, Day: 31},
@@ -281,7 +288,7 @@ func TestValidatorValidateRecordWithAllFields(t *testing.T) {
 			RequestedBy:      "test-automation",
 			RequiresFollowUp: true,
 			Priority:         5,
-			DesiredStartDate: &domain.Date{
+			DesiredStartDate: &date.Date{
 				Year:  2024,
 				Month: 12,
 				Day:   25,
@@ -375,7 +382,7 @@ func TestValidatorValidateDateZeroValues(t *testing.T) {
 		t.Fatalf("NewValidator() error = %v", err)
 	}
 
-	date := &domain.Date{
+	date := &date.Date{
 		Year:  0,
 		Month: 0,
 		Day:   0,
@@ -393,17 +400,20 @@ func TestValidatorValidateMetaPriorityValues(t *testing.T) {
 		t.Fatalf("NewValidator() error = %v", err)
 	}
 
-	priorities := []int32{0, 1, 5, 10, 100}
-	for _, priority := range priorities {
-		meta := &domain.ExampleMeta{
-			RequestedBy: "test",
-			Priority:    priority,
-		}
-		err = v.Validate(meta)
-		if err != nil {
+	// Zero means no priority was given.
+	for _, priority := range []int32{0, 1, 5} {
+		meta := &domain.ExampleMeta{RequestedBy: "test", Priority: priority}
+		if err := v.Validate(meta); err != nil {
 			t.Errorf("Validate() error = %v for priority %d", err, priority)
 		}
 	}
+	for _, priority := range []int32{-1, 6, 100} {
+		meta := &domain.ExampleMeta{RequestedBy: "test", Priority: priority}
+		violation := singleViolation(t, v.Validate(meta))
+		if violation.Field != "priority" || violation.Rule != "int32.gte_lte" || violation.Value != priority {
+			t.Errorf("violation = %+v for priority %d", violation, priority)
+		}
+	}
 }
 
 func TestValidatorStruct_CheckFields(t *testing.T) {
@@ -429,7 +439,7 @@ func TestValidatorValidateMultipleTypes(t *testing.T) {
 		&domain.ExampleRecord{RecordId: "test-1", Title: "Title 1"},
 		&domain.ExampleResult{RecordId: "result-1", Status: "pending"},
 		&domain.ExampleMeta{RequestedBy: "user1", Priority: 1},
-		&domain.Date{Year: 2024, Month: 6, Day: 15},
+		&date.Date{Year: 2024, Month: 6, Day: 15},
 		&domain.Info{Version: "1.0.0"},
 	}
 
@@ -447,20 +457,18 @@ func TestValidatorValidateRecordWithLongDescription(t *testing.T) {
 		t.Fatalf("NewValidator() error = %v", err)
 	}
 
-	longDescription := ""
-	for i := 0; i < 1000; i++ {
-		longDescription += "This is a very long description. "
-	}
-
 	record := &domain.ExampleRecord{
 		RecordId:    "long-desc-test",
 		Title:       "Long Description Test",
-		Description: longDescription,
+		Description: strings.Repeat("x", 2000),
+	}
+	if err := v.Validate(record); err != nil {
+		t.Errorf("Validate() error = %v for description at the limit", err)
 	}
 
-	err = v.Validate(record)
-	if err != nil {
-		t.Errorf("Validate() error = %v for record with long description", err)
+	record.Description += "x"
+	if violation := singleViolation(t, v.Validate(record)); violation.Rule != "string.max_len" {
+		t.Errorf("violation = %+v, want string.max_len", violation)
 	}
 }
 
@@ -470,23 +478,112 @@ func TestValidatorValidateManyTags(t *testing.T) {
 		t.Fatalf("NewValidator() error = %v", err)
 	}
 
-	tags := make([]string, 100)
-	for i := 0; i < 100; i++ {
-		tags[i] = "tag" + string(rune('A'+i%26))
+	tags := make([]string, 21)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag%d", i)
 	}
-
 	record := &domain.ExampleRecord{
 		RecordId: "many-tags",
 		Title:    "Many Tags Test",
-		Tags:     tags,
+		Tags:     tags[:20],
+	}
+	if err := v.Validate(record); err != nil {
+		t.Errorf("Validate() error = %v for 20 tags", err)
 	}
 
-	err = v.Validate(record)
+	tests := []struct {
+		name string
+		tags []string
+		rule string
+	}{
+		{"too many", tags, "repeated.max_items"},
+		{"duplicates", []string{"demo", "demo"}, "repeated.unique"},
+		{"empty tag", []string{""}, "string.min_len"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record.Tags = tt.tags
+			if violation := singleViolation(t, v.Validate(record)); violation.Rule != tt.rule {
+				t.Errorf("violation = %+v, want %s", violation, tt.rule)
+			}
+		})
+	}
+}
+
+func TestValidatorValidateRecordRules(t *testing.T) {
+	v, err := NewValidator()
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*domain.ExampleRecord)
+		field  string
+		rule   string
+	}{
+		{"missing record id", func(r *domain.ExampleRecord) { r.RecordId = "" }, "record_id", "required"},
+		{"record id pattern", func(r *domain.ExampleRecord) { r.RecordId = "EX 1" }, "record_id", "string.pattern"},
+		{"missing title", func(r *domain.ExampleRecord) { r.Title = "" }, "title", "string.min_len"},
+		{"long title", func(r *domain.ExampleRecord) { r.Title = strings.Repeat("t", 201) }, "title", "string.max_len"},
+		{"negative version", func(r *domain.ExampleRecord) { r.Version = -1 }, "version", "int32.gte"},
+		{"invalid month", func(r *domain.ExampleRecord) { r.Meta.DesiredStartDate.Month = 13 }, "meta.desired_start_date", "date.valid"},
+		{"invalid day", func(r *domain.ExampleRecord) { r.Meta.DesiredStartDate.Day = 31 }, "meta.desired_start_date", "date.valid"},
+		{"not a leap year", func(r *domain.ExampleRecord) { *r.Meta.DesiredStartDate = date.Date{Year: 2100, Month: 2, Day: 29} }, "meta.desired_start_date", "date.valid"},
+		{"partial date", func(r *domain.ExampleRecord) { *r.Meta.DesiredStartDate = date.Date{Year: 2025} }, "meta.desired_start_date", "date.valid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := NewTestFixtures().ExampleRecordFull("EX-1", "Rules", "", []string{"demo"}, 3)
+			record.Meta.DesiredStartDate = &date.Date{Year: 2024, Month: 4, Day: 30}
+			if err := v.Validate(record); err != nil {
+				t.Fatalf("Validate() error = %v for the fixture", err)
+			}
+			tt.mutate(record)
+			violation := singleViolation(t, v.Validate(record))
+			if violation.Field != tt.field || violation.Rule != tt.rule {
+				t.Errorf("violation = %+v, want %s on %s", violation, tt.rule, tt.field)
+			}
+		})
+	}
+
+	leapDay := NewTestFixtures().ExampleRecordFull("EX-1", "Rules", "", nil, 3)
+	leapDay.Meta.DesiredStartDate = &date.Date{Year: 2024, Month: 2, Day: 29}
+	if err := v.Validate(leapDay); err != nil {
+		t.Errorf("Validate() error = %v for a leap day", err)
+	}
+}
+
+func TestValidatorValidateExampleRecordUpdate(t *testing.T) {
+	v, err := NewValidator()
 	if err != nil {
-		t.Errorf("Validate() error = %v for record with many tags", err)
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+
+	// The updates are partial, so only the record ID of the update is required.
+	update := &domain.ExampleRecordUpdate{RecordId: "EX-1", Updates: &domain.ExampleRecord{Title: ""}}
+	if err := v.Validate(update); err != nil {
+		t.Errorf("Validate() error = %v for a partial update", err)
+	}
+	update.RecordId = ""
+	if violation := singleViolation(t, v.Validate(update)); violation.Field != "record_id" {
+		t.Errorf("violation = %+v, want record_id", violation)
 	}
 }
 
+// singleViolation asserts that err reports exactly one violation and returns it.
+func singleViolation(t *testing.T, err error) domain.Violation {
+	t.Helper()
+	var validationErr domain.ErrValidations
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Validate() error = %v, want domain.ErrValidations", err)
+	}
+	if len(validationErr.Errors) != 1 {
+		t.Fatalf("violations = %+v, want exactly one", validationErr.Errors)
+	}
+	return validationErr.Errors[0]
+}
+
 func TestValidatorValidateSequential(t *testing.T) {
 	v, err := NewValidator()
 	if err != nil {
@@ -506,3 +603,51 @@ func TestValidatorValidateSequential(t *testing.T) {
 		}
 	}
 }
+
+type selfValidating struct{ err error }
+
+func (s selfValidating) Validate() error { return s.err }
+
+func TestValidatorValidateNonProtoValues(t *testing.T) {
+	v, err := NewValidator()
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+
+	var nilRecord *domain.ExampleRecord
+	for _, value := range []any{nil, nilRecord, map[string]any{"record_id": "json"}, []byte(`{}`), "text"} {
+		if err := v.Validate(value); err != nil {
+			t.Errorf("Validate(%T) error = %v", value, err)
+		}
+	}
+
+	invalid := domain.ErrValidations{Errors: []domain.Violation{{Field: "name", Message: "value is required"}}}
+	if err := v.Validate(selfValidating{err: invalid}); err == nil || err.Error() != invalid.Error() {
+		t.Errorf("Validate() error = %v, want the value's own error", err)
+	}
+}
+
+func TestViolationValue(t *testing.T) {
+	record := &domain.ExampleRecord{RecordId: "EX-1"}
+	tests := []struct {
+		name  string
+		value protoreflect.Value
+		want  any
+	}{
+		{"invalid", protoreflect.Value{}, nil},
+		{"string", protoreflect.ValueOfString("EX-1"), "EX-1"},
+		{"int32", protoreflect.ValueOfInt32(7), int32(7)},
+		{"enum", protoreflect.ValueOfEnum(2), int32(2)},
+		{"float", protoreflect.ValueOfFloat64(1.5), 1.5},
+		{"nan", protoreflect.ValueOfFloat64(math.NaN()), nil},
+		{"infinity", protoreflect.ValueOfFloat32(float32(math.Inf(1))), nil},
+		{"message", protoreflect.ValueOfMessage(record.ProtoReflect()), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violationValue(tt.value); got != tt.want {
+				t.Errorf("violationValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
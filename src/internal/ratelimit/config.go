@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stores keeping the token buckets.
+const (
+	// StoreMemory keeps buckets in process memory, so every replica limits on its own.
+	StoreMemory = "memory"
+	// StoreMongo keeps buckets in MongoDB, so limits hold across replicas.
+	StoreMongo = "mongo"
+)
+
+// Limit allows Requests requests per Period. Clients may use the whole quota at
+// once; it refills evenly over the period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// String formats the limit as ParseLimit reads it, for example 10/1s.
+func (l Limit) String() string {
+	return strconv.Itoa(l.Requests) + "/" + l.Period.String()
+}
+
+// perSecond is the refill rate in tokens per second.
+func (l Limit) perSecond() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+func (l Limit) validate() error {
+	if l.Requests <= 0 || l.Period <= 0 {
+		return fmt.Errorf("rate limit %s must allow a positive number of requests per positive period", l)
+	}
+	// The shared store keeps bucket times in milliseconds.
+	if l.Period < time.Millisecond {
+		return fmt.Errorf("rate limit %s must have a period of at least 1ms", l)
+	}
+	return nil
+}
+
+// ParseLimit parses a limit written as requests/period, for example 600/1m.
+func ParseLimit(value string) (Limit, error) {
+	requests, period, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return Limit{}, fmt.Errorf("rate limit %q is not requests/period", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil {
+		return Limit{}, fmt.Errorf("rate limit %q: %w", value, err)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil {
+		return Limit{}, fmt.Errorf("rate limit %q: %w", value, err)
+	}
+	limit := Limit{Requests: n, Period: d}
+	return limit, limit.validate()
+}
+
+// ParseRouteLimits parses limits written as comma-separated Operation=limit
+// entries, for example "CreateExampleRecord=10/1s,ListExampleRecords=100/1m".
+func ParseRouteLimits(value string) (map[string]Limit, error) {
+	routes := map[string]Limit{}
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		operation, limit, found := strings.Cut(entry, "=")
+		operation = strings.TrimSpace(operation)
+		if !found || operation == "" {
+			return nil, fmt.Errorf("route limit entry %q is not Operation=requests/period", entry)
+		}
+		parsed, err := ParseLimit(limit)
+		if err != nil {
+			return nil, err
+		}
+		routes[operation] = parsed
+	}
+	return routes, nil
+}
+
+// Config enables rate limiting of the HTTP API. Every client gets a token bucket
+// per route limit and one shared by all other operations.
+type Config struct {
+	Enabled bool
+	// Default applies to operations without a route limit.
+	Default Limit
+	// Routes maps operation IDs in the form of the generated ServerInterface
+	// methods, e.g. CreateExampleRecord, onto their limits.
+	Routes map[string]Limit
+	// Store selects where the buckets are kept: StoreMemory or StoreMongo.
+	Store string
+	// TrustForwardedFor identifies anonymous clients by the last X-Forwarded-For
+	// address, as seen by the proxy in front of the service, instead of the
+	// address of the connection.
+	TrustForwardedFor bool
+}
+
+// Validate checks the limits and the store of an enabled configuration.
+func (c *Config) Validate() error {
+	if c == nil || !c.Enabled {
+		return nil
+	}
+	if err := c.Default.validate(); err != nil {
+		return err
+	}
+	for operation, limit := range c.Routes {
+		if err := limit.validate(); err != nil {
+			return fmt.Errorf("%s: %w", operation, err)
+		}
+	}
+	switch c.StoreName() {
+	case StoreMemory, StoreMongo:
+		return nil
+	default:
+		return errors.New("RATE_LIMIT_STORE must be memory or mongo")
+	}
+}
+
+// StoreName returns the configured store, StoreMemory when unset.
+func (c *Config) StoreName() string {
+	if store := strings.ToLower(strings.TrimSpace(c.Store)); store != "" {
+		return store
+	}
+	return StoreMemory
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	got, err := ParseLimit(" 600 / 1m ")
+	if err != nil || got != (Limit{Requests: 600, Period: time.Minute}) {
+		t.Fatalf("ParseLimit() = %v, %v; want 600/1m", got, err)
+	}
+	if got.String() != "600/1m0s" {
+		t.Errorf("String() = %q", got.String())
+	}
+
+	for _, value := range []string{"", "600", "x/1m", "600/minute", "0/1m", "10/0s", "-1/1s", "10/500us"} {
+		if _, err := ParseLimit(value); err == nil {
+			t.Errorf("ParseLimit(%q) expected error", value)
+		}
+	}
+}
+
+func TestParseRouteLimits(t *testing.T) {
+	got, err := ParseRouteLimits("CreateExampleRecord=10/1s, ListExampleRecords=100/1m,")
+	if err != nil {
+		t.Fatalf("ParseRouteLimits() error = %v", err)
+	}
+	want := map[string]Limit{
+		"CreateExampleRecord": {Requests: 10, Period: time.Second},
+		"ListExampleRecords":  {Requests: 100, Period: time.Minute},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRouteLimits() = %v, want %v", got, want)
+	}
+
+	for _, value := range []string{"CreateExampleRecord", "=10/1s", "CreateExampleRecord=10"} {
+		if _, err := ParseRouteLimits(value); err == nil {
+			t.Errorf("ParseRouteLimits(%q) expected error", value)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{Enabled: true, Default: Limit{Requests: 10, Period: time.Second}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if valid.StoreName() != StoreMemory {
+		t.Errorf("StoreName() = %q, want %q", valid.StoreName(), StoreMemory)
+	}
+	if err := (&Config{Default: Limit{}}).Validate(); err != nil {
+		t.Errorf("disabled config must not be validated, error = %v", err)
+	}
+
+	tests := map[string]Config{
+		"no default":    {Enabled: true},
+		"invalid route": {Enabled: true, Default: valid.Default, Routes: map[string]Limit{"GetStatus": {}}},
+		"unknown store": {Enabled: true, Default: valid.Default, Store: "redis"},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := cfg.Validate(); err == nil {
+				t.Error("Validate() expected error")
+			}
+		})
+	}
+}
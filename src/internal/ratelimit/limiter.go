@@ -0,0 +1,89 @@
+// Package ratelimit limits the request rate of API clients with token buckets.
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"drblury/event-driven-service/internal/auth"
+)
+
+// defaultScope names the bucket shared by the operations without a route limit.
+const defaultScope = "*"
+
+// Limiter applies the configured limits per client and operation.
+type Limiter struct {
+	cfg   Config
+	store Store
+	now   func() time.Time
+}
+
+// New returns a limiter keeping its buckets in store, or nil when rate limiting
+// is disabled.
+func New(cfg *Config, store Store) (*Limiter, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Limiter{cfg: *cfg, store: store, now: time.Now}, nil
+}
+
+// Take takes a token for r from the bucket of its client for operation. Operations
+// with a route limit have buckets of their own, all others share one.
+func (l *Limiter) Take(r *http.Request, operation string) (Decision, error) {
+	scope, limit := operation, l.cfg.Routes[operation]
+	if _, ok := l.cfg.Routes[operation]; !ok {
+		scope, limit = defaultScope, l.cfg.Default
+	}
+	return l.store.Take(r.Context(), scope+"|"+Client(r, l.cfg.TrustForwardedFor), limit, l.now())
+}
+
+// Client identifies the caller of r: by API key ID for API keys, by issuer and
+// subject for other principals and by IP address for anonymous requests.
+func Client(r *http.Request, trustForwardedFor bool) string {
+	if principal, ok := auth.PrincipalFrom(r.Context()); ok {
+		if id, ok := principal.Claims["key_id"].(string); ok && principal.Issuer == auth.APIKeyIssuer {
+			return "key:" + id
+		}
+		return "sub:" + principal.Issuer + "|" + principal.Subject
+	}
+	return "ip:" + clientIP(r, trustForwardedFor)
+}
+
+func clientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		forwarded := r.Header.Values("X-Forwarded-For")
+		if len(forwarded) > 0 {
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// SetHeaders describes the decision in the RateLimit-* headers of the IETF
+// RateLimit header fields draft and, for denied requests, in Retry-After.
+func (d Decision) SetHeaders(h http.Header) {
+	h.Set("RateLimit-Limit", strconv.Itoa(d.Limit.Requests))
+	h.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+	h.Set("RateLimit-Policy", strconv.Itoa(d.Limit.Requests)+";w="+strconv.Itoa(ceilSeconds(d.Limit.Period)))
+	if !d.Allowed {
+		h.Set("Retry-After", strconv.Itoa(max(ceilSeconds(d.RetryAfter), 1)))
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/auth"
+)
+
+func TestNewDisabled(t *testing.T) {
+	if limiter, err := New(nil, NewMemoryStore()); limiter != nil || err != nil {
+		t.Errorf("New(nil) = %v, %v; want nil", limiter, err)
+	}
+	if limiter, err := New(&Config{}, NewMemoryStore()); limiter != nil || err != nil {
+		t.Errorf("New(disabled) = %v, %v; want nil", limiter, err)
+	}
+	if _, err := New(&Config{Enabled: true}, NewMemoryStore()); err == nil {
+		t.Error("New() accepted a config without default limit")
+	}
+}
+
+func TestLimiterTake(t *testing.T) {
+	limiter, err := New(&Config{
+		Enabled: true,
+		Default: Limit{Requests: 2, Period: time.Minute},
+		Routes:  map[string]Limit{"CreateExampleRecord": {Requests: 1, Period: time.Minute}},
+	}, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	take := func(r *http.Request, operation string) Decision {
+		t.Helper()
+		decision, err := limiter.Take(r, operation)
+		if err != nil {
+			t.Fatalf("Take() error = %v", err)
+		}
+		return decision
+	}
+	req := httptest.NewRequest(http.MethodPost, "/examples", nil)
+
+	if d := take(req, "CreateExampleRecord"); !d.Allowed || d.Limit.Requests != 1 {
+		t.Errorf("first create = %+v, want the route limit", d)
+	}
+	if d := take(req, "CreateExampleRecord"); d.Allowed {
+		t.Errorf("second create = %+v, want denied", d)
+	}
+	// Other operations share the default bucket.
+	if d := take(req, "ListExampleRecords"); !d.Allowed || d.Limit.Requests != 2 {
+		t.Errorf("list = %+v, want the default limit", d)
+	}
+	if d := take(req, "GetStatus"); !d.Allowed || d.Remaining != 0 {
+		t.Errorf("status = %+v, want the last token of the default bucket", d)
+	}
+
+	other := httptest.NewRequest(http.MethodPost, "/examples", nil)
+	other.RemoteAddr = "192.0.2.99:1234"
+	if d := take(other, "CreateExampleRecord"); !d.Allowed {
+		t.Errorf("create from another address = %+v, want allowed", d)
+	}
+}
+
+func TestClient(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/examples", nil)
+	req.RemoteAddr = "192.0.2.1:4321"
+	req.Header.Add("X-Forwarded-For", "203.0.113.9, 198.51.100.7")
+
+	if got := Client(req, false); got != "ip:192.0.2.1" {
+		t.Errorf("Client() = %q, want the remote address", got)
+	}
+	if got := Client(req, true); got != "ip:198.51.100.7" {
+		t.Errorf("Client() trusting X-Forwarded-For = %q, want the last hop", got)
+	}
+
+	withKey := req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{
+		Issuer: auth.APIKeyIssuer, Subject: "billing", Claims: map[string]any{"key_id": "01K"},
+	}))
+	if got := Client(withKey, true); got != "key:01K" {
+		t.Errorf("Client() with API key = %q, want the key ID", got)
+	}
+	withToken := req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Issuer: "https://issuer", Subject: "user-1"}))
+	if got := Client(withToken, true); got != "sub:https://issuer|user-1" {
+		t.Errorf("Client() with token = %q, want issuer and subject", got)
+	}
+}
+
+func TestDecisionSetHeaders(t *testing.T) {
+	limit := Limit{Requests: 10, Period: time.Minute}
+
+	h := http.Header{}
+	Decision{Allowed: true, Limit: limit, Remaining: 7, Reset: 17500 * time.Millisecond}.SetHeaders(h)
+	want := map[string]string{
+		"RateLimit-Limit": "10", "RateLimit-Remaining": "7", "RateLimit-Reset": "18", "RateLimit-Policy": "10;w=60", "Retry-After": "",
+	}
+	for name, value := range want {
+		if got := h.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+
+	h = http.Header{}
+	Decision{Limit: limit, RetryAfter: 100 * time.Millisecond, Reset: time.Minute}.SetHeaders(h)
+	if got := h.Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want at least 1 second", got)
+	}
+}
@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops buckets that refilled completely.
+const sweepInterval = time.Minute
+
+// Decision is the outcome of taking a token for a request.
+type Decision struct {
+	Allowed bool
+	Limit   Limit
+	// Remaining is the number of requests the client may send right away.
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, zero when allowed.
+	RetryAfter time.Duration
+}
+
+// decide describes the bucket of limit holding tokens after a request.
+func decide(limit Limit, tokens float64, allowed bool) Decision {
+	rate := limit.perSecond()
+	decision := Decision{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Requests) - tokens) / rate),
+	}
+	if !allowed {
+		decision.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	return decision
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Max(s, 0) * float64(time.Second))
+}
+
+// refill returns the tokens of a bucket of limit that held tokens elapsed ago.
+func refill(limit Limit, tokens float64, elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(limit.Requests), tokens+elapsed.Seconds()*limit.perSecond())
+}
+
+// Store takes tokens from the bucket of a client. A missing bucket is full.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error)
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket has refilled completely and can be forgotten.
+	full time.Time
+}
+
+// MemoryStore keeps the buckets in process memory.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+// Take takes a token from the bucket key if one is left.
+func (m *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		m.sweep(now)
+	}
+
+	tokens := float64(limit.Requests)
+	if b, ok := m.buckets[key]; ok {
+		tokens = refill(limit, b.tokens, now.Sub(b.updated))
+	}
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	decision := decide(limit, tokens, allowed)
+	m.buckets[key] = &bucket{tokens: tokens, updated: now, full: now.Add(decision.Reset)}
+	return decision, nil
+}
+
+// sweep drops the buckets that are full by now; they equal missing buckets.
+func (m *MemoryStore) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if !b.full.After(now) {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastSweep = now
+}
+
+// SharedCounter keeps token buckets in a database shared by all replicas. It is
+// satisfied by *database.Database.
+type SharedCounter interface {
+	// TakeRateLimitToken refills the bucket key, holding at most capacity tokens
+	// and refilling completely within period, and takes a token if one is left. It
+	// returns the tokens left and whether a token was taken.
+	TakeRateLimitToken(ctx context.Context, key string, capacity int, period time.Duration, now time.Time) (float64, bool, error)
+}
+
+// SharedStore keeps the buckets in a SharedCounter, so limits hold across replicas.
+type SharedStore struct {
+	counter SharedCounter
+}
+
+// NewSharedStore returns a store keeping its buckets in counter.
+func NewSharedStore(counter SharedCounter) *SharedStore {
+	return &SharedStore{counter: counter}
+}
+
+// Take takes a token from the bucket key if one is left.
+func (s *SharedStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	tokens, allowed, err := s.counter.TakeRateLimitToken(ctx, key, limit.Requests, limit.Period, now)
+	if err != nil {
+		return Decision{}, err
+	}
+	return decide(limit, tokens, allowed), nil
+}
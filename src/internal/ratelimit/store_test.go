@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// assertTokenBucket drains and refills a bucket of 2 requests per second in store.
+func assertTokenBucket(t *testing.T, store Store) {
+	t.Helper()
+
+	ctx := context.Background()
+	limit := Limit{Requests: 2, Period: time.Second}
+	now := time.Now()
+	take := func(key string, at time.Time) Decision {
+		t.Helper()
+		decision, err := store.Take(ctx, key, limit, at)
+		if err != nil {
+			t.Fatalf("Take(%s) error = %v", key, err)
+		}
+		return decision
+	}
+
+	if d := take("a", now); !d.Allowed || d.Remaining != 1 || d.Reset != 500*time.Millisecond {
+		t.Errorf("first request = %+v, want allowed with 1 remaining", d)
+	}
+	if d := take("a", now); !d.Allowed || d.Remaining != 0 {
+		t.Errorf("second request = %+v, want allowed with 0 remaining", d)
+	}
+	d := take("a", now.Add(100*time.Millisecond))
+	if d.Allowed || d.Remaining != 0 || d.RetryAfter != 400*time.Millisecond {
+		t.Errorf("third request = %+v, want denied for 400ms", d)
+	}
+	if d := take("b", now); !d.Allowed {
+		t.Errorf("other client = %+v, want its own bucket", d)
+	}
+	if d := take("a", now.Add(500*time.Millisecond)); !d.Allowed || d.Remaining != 0 {
+		t.Errorf("request after refill = %+v, want allowed", d)
+	}
+	if d := take("a", now.Add(time.Hour)); !d.Allowed || d.Remaining != 1 {
+		t.Errorf("request after idling = %+v, want a full bucket", d)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+	assertTokenBucket(t, NewMemoryStore())
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	t.Parallel()
+	store := NewMemoryStore()
+	now := time.Now()
+	limit := Limit{Requests: 1, Period: time.Second}
+
+	_, _ = store.Take(context.Background(), "a", limit, now)
+	_, _ = store.Take(context.Background(), "b", Limit{Requests: 1, Period: time.Hour}, now)
+	_, _ = store.Take(context.Background(), "c", limit, now.Add(sweepInterval))
+	if _, ok := store.buckets["a"]; ok {
+		t.Error("full bucket a was not swept")
+	}
+	if _, ok := store.buckets["b"]; !ok {
+		t.Error("bucket b refilling for an hour was swept")
+	}
+}
+
+// counterFunc adapts a function to SharedCounter.
+type counterFunc func(key string, capacity int, period time.Duration, now time.Time) (float64, bool, error)
+
+func (f counterFunc) TakeRateLimitToken(_ context.Context, key string, capacity int, period time.Duration, now time.Time) (float64, bool, error) {
+	return f(key, capacity, period, now)
+}
+
+func TestSharedStore(t *testing.T) {
+	t.Parallel()
+
+	// The shared counter keeps the same buckets MemoryStore does.
+	buckets := NewMemoryStore()
+	assertTokenBucket(t, NewSharedStore(counterFunc(func(key string, capacity int, period time.Duration, now time.Time) (float64, bool, error) {
+		limit := Limit{Requests: capacity, Period: period}
+		d, err := buckets.Take(context.Background(), key, limit, now)
+		return float64(limit.Requests) - d.Reset.Seconds()*limit.perSecond(), d.Allowed, err
+	})))
+
+	failing := NewSharedStore(counterFunc(func(string, int, time.Duration, time.Time) (float64, bool, error) {
+		return 0, false, errors.New("unreachable")
+	}))
+	if _, err := failing.Take(context.Background(), "a", Limit{Requests: 1, Period: time.Second}, time.Now()); err == nil {
+		t.Error("Take() should return the counter error")
+	}
+}
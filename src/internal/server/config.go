@@ -1,7 +1,12 @@
 package server
 
+import "time"
+
 type Config struct {
 	Address          string
 	BaseURL          string
 	DocsTemplatePath string
+	// IdempotencyTTL is how long responses of requests sent with an Idempotency-Key
+	// are replayed.
+	IdempotencyTTL time.Duration
 }
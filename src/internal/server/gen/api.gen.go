@@ -20,13 +20,178 @@ import (
 	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oapi-codegen/runtime"
 	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 const (
+	ApiKeyAuthScopes = "apiKeyAuth.Scopes"
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
+// Defines values for ExampleRecordStatus.
+const (
+	EXAMPLERECORDSTATUSCOMPLETED   ExampleRecordStatus = "EXAMPLE_RECORD_STATUS_COMPLETED"
+	EXAMPLERECORDSTATUSFAILED      ExampleRecordStatus = "EXAMPLE_RECORD_STATUS_FAILED"
+	EXAMPLERECORDSTATUSINPROGRESS  ExampleRecordStatus = "EXAMPLE_RECORD_STATUS_IN_PROGRESS"
+	EXAMPLERECORDSTATUSQUEUED      ExampleRecordStatus = "EXAMPLE_RECORD_STATUS_QUEUED"
+	EXAMPLERECORDSTATUSUNSPECIFIED ExampleRecordStatus = "EXAMPLE_RECORD_STATUS_UNSPECIFIED"
+)
+
+// Defines values for OperationStatus.
+const (
+	Failed    OperationStatus = "failed"
+	Pending   OperationStatus = "pending"
+	Succeeded OperationStatus = "succeeded"
+)
+
+// APIKey Metadata of an issued API key. The key itself is never stored.
+type APIKey struct {
+	// CreatedAt Time at which the key was issued.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// ExpiresAt Time after which the key is rejected.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Id Identifier of the key. Identifiers sort in issue order.
+	Id string `json:"id"`
+
+	// LastUsedAt Time the key last authenticated a request, recorded at most once per minute.
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+
+	// Owner Integrator the key is issued to.
+	Owner string `json:"owner"`
+
+	// RevokedAt Time at which the key was revoked.
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+
+	// Scopes Scopes granted to the key.
+	Scopes []string `json:"scopes"`
+}
+
+// APIKeyList Issued API keys, oldest first.
+type APIKeyList struct {
+	// Items Issued keys.
+	Items []APIKey `json:"items"`
+}
+
+// APIKeyRequest The owner, scopes and lifetime of an API key to issue.
+type APIKeyRequest struct {
+	// ExpiresAt Time after which the key is rejected. Keys without expiry stay valid until revoked.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Owner Integrator the key is issued to. Becomes the subject of its requests.
+	Owner string `json:"owner"`
+
+	// Scopes Scopes granted to the key.
+	Scopes *[]string `json:"scopes,omitempty"`
+}
+
+// ExampleOperation The asynchronous processing of a submitted example record. It follows the
+// record lifecycle, so updating a record starts its operation over.
+type ExampleOperation struct {
+	// Reason Reason of the latest status change; the error code of failed operations.
+	Reason *string `json:"reason,omitempty"`
+
+	// RecordId Identifier of the submitted example record.
+	RecordId string `json:"recordId"`
+
+	// RecordStatus Lifecycle state of a stored example record.
+	RecordStatus ExampleRecordStatus `json:"recordStatus"`
+
+	// Result The outcome of one processing run of an example record.
+	Result *ExampleResult `json:"result,omitempty"`
+
+	// Status State of an asynchronous operation.
+	Status OperationStatus `json:"status"`
+
+	// UpdatedAt Time of the latest status change.
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// ExampleRecord defines model for ExampleRecord.
+type ExampleRecord struct {
+	// Description Optional text with more context for the example.
+	Description *string `json:"description,omitempty"`
+	Meta        struct {
+		// DesiredStartDate Requested date for the example to start.
+		DesiredStartDate struct {
+			Day   int32 `json:"day"`
+			Month int32 `json:"month"`
+			Year  int32 `json:"year"`
+		} `json:"desiredStartDate"`
+
+		// Priority Arbitrary priority value to illustrate business rules.
+		Priority int32 `json:"priority"`
+
+		// RequestedBy Identifier of the caller that submitted the record.
+		RequestedBy string `json:"requestedBy"`
+
+		// RequiresFollowUp Flag that indicates whether manual follow-up is needed.
+		RequiresFollowUp *bool `json:"requiresFollowUp,omitempty"`
+	} `json:"meta"`
+
+	// RecordId Unique identifier for this example record.
+	RecordId string `json:"recordId"`
+
+	// Status Lifecycle state of a stored example record.
+	Status *ExampleRecordStatus `json:"status,omitempty"`
+
+	// StatusHistory Status changes, oldest first. Maintained by the service.
+	StatusHistory *[]StatusTransition `json:"statusHistory,omitempty"`
+
+	// Tags Semantic tags attached to the record.
+	Tags *[]string `json:"tags,omitempty"`
+
+	// Title Friendly title that will also appear in downstream logs.
+	Title string `json:"title"`
+}
+
+// ExampleRecordLifecycle Current status of an example record together with its status history.
+// Records start queued, are in progress while a handler processes them and
+// end up completed or failed. Completed and failed records are queued again
+// when an example.record.updated event changes them; PUT and PATCH keep the
+// status.
+type ExampleRecordLifecycle struct {
+	// History Status changes, oldest first.
+	History []StatusTransition `json:"history"`
+
+	// RecordId Identifier of the example record.
+	RecordId string `json:"recordId"`
+
+	// Status Lifecycle state of a stored example record.
+	Status ExampleRecordStatus `json:"status"`
+
+	// UpdatedAt Time of the latest status change. Omitted when the record has no status history.
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// ExampleRecordList A page of example records.
+type ExampleRecordList struct {
+	// Items Example records on this page.
+	Items []ExampleRecord `json:"items"`
+
+	// NextCursor Cursor for the next page. Omitted on the last page.
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// ExampleRecordPatch JSON merge patch (RFC 7396) applied to a stored example record. Only the
+// fields present in the document are changed; `null` clears a field.
+type ExampleRecordPatch struct {
+	// Description New description for the record.
+	Description *string `json:"description,omitempty"`
+
+	// Meta Partial metadata that is merged into the stored metadata.
+	Meta *map[string]interface{} `json:"meta,omitempty"`
+
+	// Tags Replacement list of tags.
+	Tags *[]string `json:"tags,omitempty"`
+
+	// Title New title for the record.
+	Title *string `json:"title,omitempty"`
+}
+
 // ExampleRecordRequest defines model for ExampleRecordRequest.
 type ExampleRecordRequest struct {
 	// Description Optional text with more context for the example.
@@ -59,6 +224,51 @@ type ExampleRecordRequest struct {
 	Title string `json:"title"`
 }
 
+// ExampleRecordStatus Lifecycle state of a stored example record.
+type ExampleRecordStatus string
+
+// ExampleResult The outcome of one processing run of an example record.
+type ExampleResult struct {
+	// CorrelationId Correlation ID of the message that carried the result.
+	CorrelationId *string `json:"correlationId,omitempty"`
+
+	// Id Identifier of the stored result. Identifiers sort in storage order.
+	Id string `json:"id"`
+
+	// Note Notes written by the handler.
+	Note *string `json:"note,omitempty"`
+
+	// ProcessedOn Date on which the record was processed.
+	ProcessedOn *openapi_types.Date `json:"processedOn,omitempty"`
+
+	// RecordId Identifier of the processed example record.
+	RecordId string `json:"recordId"`
+
+	// Status Processing status reported by the handler.
+	Status string `json:"status"`
+
+	// StoredAt Time at which the result was stored.
+	StoredAt time.Time `json:"storedAt"`
+}
+
+// ExampleResultList Results of an example record, newest first.
+type ExampleResultList struct {
+	// Items Stored results.
+	Items []ExampleResult `json:"items"`
+}
+
+// IssuedAPIKey A newly issued API key together with its metadata.
+type IssuedAPIKey struct {
+	// ApiKey Metadata of an issued API key. The key itself is never stored.
+	ApiKey APIKey `json:"apiKey"`
+
+	// Key The API key to send in the `X-API-Key` header. It is shown only once.
+	Key string `json:"key"`
+}
+
+// OperationStatus State of an asynchronous operation.
+type OperationStatus string
+
 // ProbeStatus Outcome payload for service health probes.
 type ProbeStatus struct {
 	// Details Optional messages emitted by individual probe checks.
@@ -73,6 +283,9 @@ type ProblemDetails struct {
 	// Detail Human-readable explanation specific to this occurrence of the problem.
 	Detail *string `json:"detail,omitempty"`
 
+	// Errors Violated validation rules, present when the request payload failed validation.
+	Errors *[]Violation `json:"errors,omitempty"`
+
 	// Instance URI reference that identifies the specific occurrence of the problem.
 	Instance *string `json:"instance,omitempty"`
 
@@ -92,6 +305,21 @@ type ProblemDetails struct {
 	Type *string `json:"type,omitempty"`
 }
 
+// StatusTransition One change of an example record status.
+type StatusTransition struct {
+	// From Lifecycle state of a stored example record.
+	From ExampleRecordStatus `json:"from"`
+
+	// OccurredAt Time at which the status changed.
+	OccurredAt time.Time `json:"occurredAt"`
+
+	// Reason Why the status changed, e.g. submitted, processing, processed, updated or an error code.
+	Reason *string `json:"reason,omitempty"`
+
+	// To Lifecycle state of a stored example record.
+	To ExampleRecordStatus `json:"to"`
+}
+
 // Version Machine-readable build information served by the info endpoints.
 type Version struct {
 	// BuildDate The date the code was built
@@ -110,14 +338,124 @@ type Version struct {
 	Version string `json:"version"`
 }
 
+// Violation A validation rule the request payload violates.
+type Violation struct {
+	// Field Path of the offending field, using the proto field names.
+	Field *string `json:"field,omitempty"`
+
+	// Message Human-readable description of the violation.
+	Message string `json:"message"`
+
+	// Rule Identifier of the violated rule.
+	Rule *string `json:"rule,omitempty"`
+
+	// Value Value of the offending field, when it is a scalar.
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ListAPIKeysParams defines parameters for ListAPIKeys.
+type ListAPIKeysParams struct {
+	// Owner Only return the keys issued to this owner.
+	Owner *string `form:"owner,omitempty" json:"owner,omitempty"`
+}
+
+// ListExampleRecordsParams defines parameters for ListExampleRecords.
+type ListExampleRecordsParams struct {
+	// Cursor Opaque cursor returned as `nextCursor` by the previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Maximum number of records to return.
+	Limit *int32 `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Tag Only return records carrying all of the given tags.
+	Tag *[]string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// Priority Only return records with this priority.
+	Priority *int32 `form:"priority,omitempty" json:"priority,omitempty"`
+
+	// RequestedBy Only return records submitted by this caller.
+	RequestedBy *string `form:"requestedBy,omitempty" json:"requestedBy,omitempty"`
+
+	// DesiredStartFrom Only return records whose desired start date is on or after this date.
+	DesiredStartFrom *openapi_types.Date `form:"desiredStartFrom,omitempty" json:"desiredStartFrom,omitempty"`
+
+	// DesiredStartTo Only return records whose desired start date is on or before this date.
+	DesiredStartTo *openapi_types.Date `form:"desiredStartTo,omitempty" json:"desiredStartTo,omitempty"`
+}
+
+// CreateExampleRecordParams defines parameters for CreateExampleRecord.
+type CreateExampleRecordParams struct {
+	// IdempotencyKey Client-chosen key identifying this submission, e.g. a UUID. Responses
+	// are stored per key and caller and replayed for retries until the
+	// idempotency TTL elapses.
+	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
+}
+
+// GetExampleRecordOperationParams defines parameters for GetExampleRecordOperation.
+type GetExampleRecordOperationParams struct {
+	// Prefer RFC 7240 preferences. `wait=N` long-polls for up to N seconds.
+	Prefer *string `json:"Prefer,omitempty"`
+}
+
+// ListExampleRecordResultsParams defines parameters for ListExampleRecordResults.
+type ListExampleRecordResultsParams struct {
+	// CorrelationId Only return results carried by messages with this correlation ID.
+	CorrelationId *string `form:"correlationId,omitempty" json:"correlationId,omitempty"`
+
+	// Limit Maximum number of results to return.
+	Limit *int32 `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// IssueAPIKeyJSONRequestBody defines body for IssueAPIKey for application/json ContentType.
+type IssueAPIKeyJSONRequestBody = APIKeyRequest
+
 // CreateExampleRecordJSONRequestBody defines body for CreateExampleRecord for application/json ContentType.
 type CreateExampleRecordJSONRequestBody = ExampleRecordRequest
 
+// PatchExampleRecordJSONRequestBody defines body for PatchExampleRecord for application/json ContentType.
+type PatchExampleRecordJSONRequestBody = ExampleRecordPatch
+
+// UpdateExampleRecordJSONRequestBody defines body for UpdateExampleRecord for application/json ContentType.
+type UpdateExampleRecordJSONRequestBody = ExampleRecordRequest
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// List API keys
+	// (GET /admin/api-keys)
+	ListAPIKeys(w http.ResponseWriter, r *http.Request, params ListAPIKeysParams)
+	// Issue an API key
+	// (POST /admin/api-keys)
+	IssueAPIKey(w http.ResponseWriter, r *http.Request)
+	// Revoke an API key
+	// (DELETE /admin/api-keys/{id})
+	RevokeAPIKey(w http.ResponseWriter, r *http.Request, id string)
+	// List example records
+	// (GET /examples)
+	ListExampleRecords(w http.ResponseWriter, r *http.Request, params ListExampleRecordsParams)
 	// Submit example data
 	// (POST /examples)
-	CreateExampleRecord(w http.ResponseWriter, r *http.Request)
+	CreateExampleRecord(w http.ResponseWriter, r *http.Request, params CreateExampleRecordParams)
+	// Delete an example record
+	// (DELETE /examples/{id})
+	DeleteExampleRecord(w http.ResponseWriter, r *http.Request, id string)
+	// Get an example record
+	// (GET /examples/{id})
+	GetExampleRecord(w http.ResponseWriter, r *http.Request, id string)
+	// Partially update an example record
+	// (PATCH /examples/{id})
+	PatchExampleRecord(w http.ResponseWriter, r *http.Request, id string)
+	// Replace an example record
+	// (PUT /examples/{id})
+	UpdateExampleRecord(w http.ResponseWriter, r *http.Request, id string)
+	// Get the processing operation of an example record
+	// (GET /examples/{id}/operation)
+	GetExampleRecordOperation(w http.ResponseWriter, r *http.Request, id string, params GetExampleRecordOperationParams)
+	// List the results of an example record
+	// (GET /examples/{id}/results)
+	ListExampleRecordResults(w http.ResponseWriter, r *http.Request, id string, params ListExampleRecordResultsParams)
+	// Get the status of an example record
+	// (GET /examples/{id}/status)
+	GetExampleRecordStatus(w http.ResponseWriter, r *http.Request, id string)
 	// Kubernetes liveness probe
 	// (GET /healthz)
 	GetHealthz(w http.ResponseWriter, r *http.Request)
@@ -153,17 +491,32 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
-// CreateExampleRecord operation middleware
-func (siw *ServerInterfaceWrapper) CreateExampleRecord(w http.ResponseWriter, r *http.Request) {
+// ListAPIKeys operation middleware
+func (siw *ServerInterfaceWrapper) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+
+	var err error
 
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
 
 	r = r.WithContext(ctx)
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAPIKeysParams
+
+	// ------------- Optional query parameter "owner" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "owner", r.URL.Query(), &params.Owner)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "owner", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateExampleRecord(w, r)
+		siw.Handler.ListAPIKeys(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -173,11 +526,19 @@ func (siw *ServerInterfaceWrapper) CreateExampleRecord(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// GetHealthz operation middleware
-func (siw *ServerInterfaceWrapper) GetHealthz(w http.ResponseWriter, r *http.Request) {
+// IssueAPIKey operation middleware
+func (siw *ServerInterfaceWrapper) IssueAPIKey(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetHealthz(w, r)
+		siw.Handler.IssueAPIKey(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -187,25 +548,30 @@ func (siw *ServerInterfaceWrapper) GetHealthz(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
-// GetAsyncAPIHTML operation middleware
-func (siw *ServerInterfaceWrapper) GetAsyncAPIHTML(w http.ResponseWriter, r *http.Request) {
+// RevokeAPIKey operation middleware
+func (siw *ServerInterfaceWrapper) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetAsyncAPIHTML(w, r)
-	}))
+	var err error
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
 	}
 
-	handler.ServeHTTP(w, r)
-}
+	ctx := r.Context()
 
-// GetAsyncAPIJSON operation middleware
-func (siw *ServerInterfaceWrapper) GetAsyncAPIJSON(w http.ResponseWriter, r *http.Request) {
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetAsyncAPIJSON(w, r)
+		siw.Handler.RevokeAPIKey(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -215,39 +581,80 @@ func (siw *ServerInterfaceWrapper) GetAsyncAPIJSON(w http.ResponseWriter, r *htt
 	handler.ServeHTTP(w, r)
 }
 
-// GetOpenAPIHTML operation middleware
-func (siw *ServerInterfaceWrapper) GetOpenAPIHTML(w http.ResponseWriter, r *http.Request) {
+// ListExampleRecords operation middleware
+func (siw *ServerInterfaceWrapper) ListExampleRecords(w http.ResponseWriter, r *http.Request) {
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetOpenAPIHTML(w, r)
-	}))
+	var err error
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"examples:read"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"examples:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListExampleRecordsParams
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
 	}
 
-	handler.ServeHTTP(w, r)
-}
+	// ------------- Optional query parameter "limit" -------------
 
-// GetOpenAPIJSON operation middleware
-func (siw *ServerInterfaceWrapper) GetOpenAPIJSON(w http.ResponseWriter, r *http.Request) {
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetOpenAPIJSON(w, r)
-	}))
+	// ------------- Optional query parameter "tag" -------------
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
 	}
 
-	handler.ServeHTTP(w, r)
-}
+	// ------------- Optional query parameter "priority" -------------
 
-// GetStatus operation middleware
-func (siw *ServerInterfaceWrapper) GetStatus(w http.ResponseWriter, r *http.Request) {
+	err = runtime.BindQueryParameter("form", true, false, "priority", r.URL.Query(), &params.Priority)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "priority", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "requestedBy" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "requestedBy", r.URL.Query(), &params.RequestedBy)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "requestedBy", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "desiredStartFrom" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "desiredStartFrom", r.URL.Query(), &params.DesiredStartFrom)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "desiredStartFrom", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "desiredStartTo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "desiredStartTo", r.URL.Query(), &params.DesiredStartTo)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "desiredStartTo", Err: err})
+		return
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetStatus(w, r)
+		siw.Handler.ListExampleRecords(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -257,11 +664,45 @@ func (siw *ServerInterfaceWrapper) GetStatus(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
-// GetVersion operation middleware
-func (siw *ServerInterfaceWrapper) GetVersion(w http.ResponseWriter, r *http.Request) {
+// CreateExampleRecord operation middleware
+func (siw *ServerInterfaceWrapper) CreateExampleRecord(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"examples:write"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"examples:write"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateExampleRecordParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetVersion(w, r)
+		siw.Handler.CreateExampleRecord(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -271,11 +712,30 @@ func (siw *ServerInterfaceWrapper) GetVersion(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
-// GetReadyz operation middleware
-func (siw *ServerInterfaceWrapper) GetReadyz(w http.ResponseWriter, r *http.Request) {
+// DeleteExampleRecord operation middleware
+func (siw *ServerInterfaceWrapper) DeleteExampleRecord(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"examples:write"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"examples:write"})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetReadyz(w, r)
+		siw.Handler.DeleteExampleRecord(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -285,175 +745,1245 @@ func (siw *ServerInterfaceWrapper) GetReadyz(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+// GetExampleRecord operation middleware
+func (siw *ServerInterfaceWrapper) GetExampleRecord(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"examples:read"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"examples:read"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetExampleRecord(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PatchExampleRecord operation middleware
+func (siw *ServerInterfaceWrapper) PatchExampleRecord(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"examples:write"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"examples:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchExampleRecord(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateExampleRecord operation middleware
+func (siw *ServerInterfaceWrapper) UpdateExampleRecord(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"examples:write"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"examples:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateExampleRecord(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetExampleRecordOperation operation middleware
+func (siw *ServerInterfaceWrapper) GetExampleRecordOperation(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"examples:read"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"examples:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetExampleRecordOperationParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Prefer" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Prefer")]; found {
+		var Prefer string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Prefer", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Prefer", valueList[0], &Prefer, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Prefer", Err: err})
+			return
+		}
+
+		params.Prefer = &Prefer
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetExampleRecordOperation(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListExampleRecordResults operation middleware
+func (siw *ServerInterfaceWrapper) ListExampleRecordResults(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"examples:read"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"examples:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListExampleRecordResultsParams
+
+	// ------------- Optional query parameter "correlationId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "correlationId", r.URL.Query(), &params.CorrelationId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "correlationId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListExampleRecordResults(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetExampleRecordStatus operation middleware
+func (siw *ServerInterfaceWrapper) GetExampleRecordStatus(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"examples:read"})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"examples:read"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetExampleRecordStatus(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetHealthz operation middleware
+func (siw *ServerInterfaceWrapper) GetHealthz(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetHealthz(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAsyncAPIHTML operation middleware
+func (siw *ServerInterfaceWrapper) GetAsyncAPIHTML(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAsyncAPIHTML(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAsyncAPIJSON operation middleware
+func (siw *ServerInterfaceWrapper) GetAsyncAPIJSON(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAsyncAPIJSON(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetOpenAPIHTML operation middleware
+func (siw *ServerInterfaceWrapper) GetOpenAPIHTML(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetOpenAPIHTML(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetOpenAPIJSON operation middleware
+func (siw *ServerInterfaceWrapper) GetOpenAPIJSON(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetOpenAPIJSON(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatus operation middleware
+func (siw *ServerInterfaceWrapper) GetStatus(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatus(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetVersion operation middleware
+func (siw *ServerInterfaceWrapper) GetVersion(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetVersion(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetReadyz operation middleware
+func (siw *ServerInterfaceWrapper) GetReadyz(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetReadyz(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
 
 func (e *UnescapedCookieParamError) Error() string {
 	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
 }
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("GET "+options.BaseURL+"/admin/api-keys", wrapper.ListAPIKeys)
+	m.HandleFunc("POST "+options.BaseURL+"/admin/api-keys", wrapper.IssueAPIKey)
+	m.HandleFunc("DELETE "+options.BaseURL+"/admin/api-keys/{id}", wrapper.RevokeAPIKey)
+	m.HandleFunc("GET "+options.BaseURL+"/examples", wrapper.ListExampleRecords)
+	m.HandleFunc("POST "+options.BaseURL+"/examples", wrapper.CreateExampleRecord)
+	m.HandleFunc("DELETE "+options.BaseURL+"/examples/{id}", wrapper.DeleteExampleRecord)
+	m.HandleFunc("GET "+options.BaseURL+"/examples/{id}", wrapper.GetExampleRecord)
+	m.HandleFunc("PATCH "+options.BaseURL+"/examples/{id}", wrapper.PatchExampleRecord)
+	m.HandleFunc("PUT "+options.BaseURL+"/examples/{id}", wrapper.UpdateExampleRecord)
+	m.HandleFunc("GET "+options.BaseURL+"/examples/{id}/operation", wrapper.GetExampleRecordOperation)
+	m.HandleFunc("GET "+options.BaseURL+"/examples/{id}/results", wrapper.ListExampleRecordResults)
+	m.HandleFunc("GET "+options.BaseURL+"/examples/{id}/status", wrapper.GetExampleRecordStatus)
+	m.HandleFunc("GET "+options.BaseURL+"/healthz", wrapper.GetHealthz)
+	m.HandleFunc("GET "+options.BaseURL+"/info/asyncapi.html", wrapper.GetAsyncAPIHTML)
+	m.HandleFunc("GET "+options.BaseURL+"/info/asyncapi.json", wrapper.GetAsyncAPIJSON)
+	m.HandleFunc("GET "+options.BaseURL+"/info/openapi.html", wrapper.GetOpenAPIHTML)
+	m.HandleFunc("GET "+options.BaseURL+"/info/openapi.json", wrapper.GetOpenAPIJSON)
+	m.HandleFunc("GET "+options.BaseURL+"/info/status", wrapper.GetStatus)
+	m.HandleFunc("GET "+options.BaseURL+"/info/version", wrapper.GetVersion)
+	m.HandleFunc("GET "+options.BaseURL+"/readyz", wrapper.GetReadyz)
+
+	return m
+}
+
+type ListAPIKeysRequestObject struct {
+	Params ListAPIKeysParams
+}
+
+type ListAPIKeysResponseObject interface {
+	VisitListAPIKeysResponse(w http.ResponseWriter) error
+}
+
+type ListAPIKeys200JSONResponse APIKeyList
+
+func (response ListAPIKeys200JSONResponse) VisitListAPIKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAPIKeys401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response ListAPIKeys401ApplicationProblemPlusJSONResponse) VisitListAPIKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAPIKeys403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response ListAPIKeys403ApplicationProblemPlusJSONResponse) VisitListAPIKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAPIKeysdefaultApplicationProblemPlusJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
+}
+
+func (response ListAPIKeysdefaultApplicationProblemPlusJSONResponse) VisitListAPIKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type IssueAPIKeyRequestObject struct {
+	Body *IssueAPIKeyJSONRequestBody
+}
+
+type IssueAPIKeyResponseObject interface {
+	VisitIssueAPIKeyResponse(w http.ResponseWriter) error
+}
+
+type IssueAPIKey201JSONResponse IssuedAPIKey
+
+func (response IssueAPIKey201JSONResponse) VisitIssueAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type IssueAPIKey400ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response IssueAPIKey400ApplicationProblemPlusJSONResponse) VisitIssueAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type IssueAPIKey401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response IssueAPIKey401ApplicationProblemPlusJSONResponse) VisitIssueAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type IssueAPIKey403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response IssueAPIKey403ApplicationProblemPlusJSONResponse) VisitIssueAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type IssueAPIKeydefaultApplicationProblemPlusJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
+}
+
+func (response IssueAPIKeydefaultApplicationProblemPlusJSONResponse) VisitIssueAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RevokeAPIKeyRequestObject struct {
+	Id string `json:"id"`
+}
+
+type RevokeAPIKeyResponseObject interface {
+	VisitRevokeAPIKeyResponse(w http.ResponseWriter) error
+}
+
+type RevokeAPIKey204Response struct {
+}
+
+func (response RevokeAPIKey204Response) VisitRevokeAPIKeyResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type RevokeAPIKey401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response RevokeAPIKey401ApplicationProblemPlusJSONResponse) VisitRevokeAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAPIKey403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response RevokeAPIKey403ApplicationProblemPlusJSONResponse) VisitRevokeAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAPIKey404ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response RevokeAPIKey404ApplicationProblemPlusJSONResponse) VisitRevokeAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAPIKeydefaultApplicationProblemPlusJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
+}
+
+func (response RevokeAPIKeydefaultApplicationProblemPlusJSONResponse) VisitRevokeAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListExampleRecordsRequestObject struct {
+	Params ListExampleRecordsParams
+}
+
+type ListExampleRecordsResponseObject interface {
+	VisitListExampleRecordsResponse(w http.ResponseWriter) error
+}
+
+type ListExampleRecords200JSONResponse ExampleRecordList
+
+func (response ListExampleRecords200JSONResponse) VisitListExampleRecordsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListExampleRecords400ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response ListExampleRecords400ApplicationProblemPlusJSONResponse) VisitListExampleRecordsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListExampleRecords401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response ListExampleRecords401ApplicationProblemPlusJSONResponse) VisitListExampleRecordsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListExampleRecords403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response ListExampleRecords403ApplicationProblemPlusJSONResponse) VisitListExampleRecordsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListExampleRecordsdefaultApplicationProblemPlusJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
+}
+
+func (response ListExampleRecordsdefaultApplicationProblemPlusJSONResponse) VisitListExampleRecordsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateExampleRecordRequestObject struct {
+	Params CreateExampleRecordParams
+	Body   *CreateExampleRecordJSONRequestBody
+}
+
+type CreateExampleRecordResponseObject interface {
+	VisitCreateExampleRecordResponse(w http.ResponseWriter) error
+}
+
+type CreateExampleRecord202ResponseHeaders struct {
+	IdempotentReplayed string
+	Location           string
+}
+
+type CreateExampleRecord202JSONResponse struct {
+	Body    ExampleOperation
+	Headers CreateExampleRecord202ResponseHeaders
+}
+
+func (response CreateExampleRecord202JSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Idempotent-Replayed", fmt.Sprint(response.Headers.IdempotentReplayed))
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateExampleRecord400JSONResponse ProblemDetails
+
+func (response CreateExampleRecord400JSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateExampleRecord401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response CreateExampleRecord401ApplicationProblemPlusJSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateExampleRecord403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response CreateExampleRecord403ApplicationProblemPlusJSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateExampleRecord409ResponseHeaders struct {
+	RetryAfter int
+}
+
+type CreateExampleRecord409JSONResponse struct {
+	Body    ProblemDetails
+	Headers CreateExampleRecord409ResponseHeaders
+}
+
+func (response CreateExampleRecord409JSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateExampleRecord422JSONResponse ProblemDetails
+
+func (response CreateExampleRecord422JSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateExampleRecord429ResponseHeaders struct {
+	RetryAfter int
+}
+
+type CreateExampleRecord429ApplicationProblemPlusJSONResponse struct {
+	Body    ProblemDetails
+	Headers CreateExampleRecord429ResponseHeaders
+}
+
+func (response CreateExampleRecord429ApplicationProblemPlusJSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateExampleRecorddefaultJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
+}
+
+func (response CreateExampleRecorddefaultJSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteExampleRecordRequestObject struct {
+	Id string `json:"id"`
+}
+
+type DeleteExampleRecordResponseObject interface {
+	VisitDeleteExampleRecordResponse(w http.ResponseWriter) error
+}
+
+type DeleteExampleRecord204Response struct {
+}
+
+func (response DeleteExampleRecord204Response) VisitDeleteExampleRecordResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteExampleRecord401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response DeleteExampleRecord401ApplicationProblemPlusJSONResponse) VisitDeleteExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteExampleRecord403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response DeleteExampleRecord403ApplicationProblemPlusJSONResponse) VisitDeleteExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
+type DeleteExampleRecord404ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response DeleteExampleRecord404ApplicationProblemPlusJSONResponse) VisitDeleteExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+type DeleteExampleRecorddefaultApplicationProblemPlusJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
 }
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
+func (response DeleteExampleRecorddefaultApplicationProblemPlusJSONResponse) VisitDeleteExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type RequiredParamError struct {
-	ParamName string
+type GetExampleRecordRequestObject struct {
+	Id string `json:"id"`
 }
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+type GetExampleRecordResponseObject interface {
+	VisitGetExampleRecordResponse(w http.ResponseWriter) error
 }
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
+type GetExampleRecord200JSONResponse ExampleRecord
+
+func (response GetExampleRecord200JSONResponse) VisitGetExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+type GetExampleRecord401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response GetExampleRecord401ApplicationProblemPlusJSONResponse) VisitGetExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
+type GetExampleRecord403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response GetExampleRecord403ApplicationProblemPlusJSONResponse) VisitGetExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
+type GetExampleRecord404ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response GetExampleRecord404ApplicationProblemPlusJSONResponse) VisitGetExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+type GetExampleRecorddefaultApplicationProblemPlusJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
 }
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
+func (response GetExampleRecorddefaultApplicationProblemPlusJSONResponse) VisitGetExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
+type PatchExampleRecordRequestObject struct {
+	Id   string `json:"id"`
+	Body *PatchExampleRecordJSONRequestBody
 }
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+type PatchExampleRecordResponseObject interface {
+	VisitPatchExampleRecordResponse(w http.ResponseWriter) error
 }
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{})
+type PatchExampleRecord200JSONResponse ExampleRecord
+
+func (response PatchExampleRecord200JSONResponse) VisitPatchExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-// ServeMux is an abstraction of http.ServeMux.
-type ServeMux interface {
-	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
-	ServeHTTP(w http.ResponseWriter, r *http.Request)
+type PatchExampleRecord400ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response PatchExampleRecord400ApplicationProblemPlusJSONResponse) VisitPatchExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-type StdHTTPServerOptions struct {
-	BaseURL          string
-	BaseRouter       ServeMux
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+type PatchExampleRecord401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response PatchExampleRecord401ApplicationProblemPlusJSONResponse) VisitPatchExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseRouter: m,
-	})
+type PatchExampleRecord403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response PatchExampleRecord403ApplicationProblemPlusJSONResponse) VisitPatchExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: m,
-	})
+type PatchExampleRecord404ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response PatchExampleRecord404ApplicationProblemPlusJSONResponse) VisitPatchExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
-	m := options.BaseRouter
+type PatchExampleRecorddefaultApplicationProblemPlusJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
+}
 
-	if m == nil {
-		m = http.NewServeMux()
-	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
-	}
+func (response PatchExampleRecorddefaultApplicationProblemPlusJSONResponse) VisitPatchExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
 
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
-	}
+	return json.NewEncoder(w).Encode(response.Body)
+}
 
-	m.HandleFunc("POST "+options.BaseURL+"/examples", wrapper.CreateExampleRecord)
-	m.HandleFunc("GET "+options.BaseURL+"/healthz", wrapper.GetHealthz)
-	m.HandleFunc("GET "+options.BaseURL+"/info/asyncapi.html", wrapper.GetAsyncAPIHTML)
-	m.HandleFunc("GET "+options.BaseURL+"/info/asyncapi.json", wrapper.GetAsyncAPIJSON)
-	m.HandleFunc("GET "+options.BaseURL+"/info/openapi.html", wrapper.GetOpenAPIHTML)
-	m.HandleFunc("GET "+options.BaseURL+"/info/openapi.json", wrapper.GetOpenAPIJSON)
-	m.HandleFunc("GET "+options.BaseURL+"/info/status", wrapper.GetStatus)
-	m.HandleFunc("GET "+options.BaseURL+"/info/version", wrapper.GetVersion)
-	m.HandleFunc("GET "+options.BaseURL+"/readyz", wrapper.GetReadyz)
+type UpdateExampleRecordRequestObject struct {
+	Id   string `json:"id"`
+	Body *UpdateExampleRecordJSONRequestBody
+}
+
+type UpdateExampleRecordResponseObject interface {
+	VisitUpdateExampleRecordResponse(w http.ResponseWriter) error
+}
+
+type UpdateExampleRecord200JSONResponse ExampleRecord
+
+func (response UpdateExampleRecord200JSONResponse) VisitUpdateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateExampleRecord400ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response UpdateExampleRecord400ApplicationProblemPlusJSONResponse) VisitUpdateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateExampleRecord401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response UpdateExampleRecord401ApplicationProblemPlusJSONResponse) VisitUpdateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateExampleRecord403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response UpdateExampleRecord403ApplicationProblemPlusJSONResponse) VisitUpdateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateExampleRecord404ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response UpdateExampleRecord404ApplicationProblemPlusJSONResponse) VisitUpdateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateExampleRecorddefaultApplicationProblemPlusJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
+}
+
+func (response UpdateExampleRecorddefaultApplicationProblemPlusJSONResponse) VisitUpdateExampleRecordResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetExampleRecordOperationRequestObject struct {
+	Id     string `json:"id"`
+	Params GetExampleRecordOperationParams
+}
+
+type GetExampleRecordOperationResponseObject interface {
+	VisitGetExampleRecordOperationResponse(w http.ResponseWriter) error
+}
+
+type GetExampleRecordOperation200ResponseHeaders struct {
+	PreferenceApplied string
+	RetryAfter        int
+}
+
+type GetExampleRecordOperation200JSONResponse struct {
+	Body    ExampleOperation
+	Headers GetExampleRecordOperation200ResponseHeaders
+}
+
+func (response GetExampleRecordOperation200JSONResponse) VisitGetExampleRecordOperationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Preference-Applied", fmt.Sprint(response.Headers.PreferenceApplied))
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetExampleRecordOperation401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response GetExampleRecordOperation401ApplicationProblemPlusJSONResponse) VisitGetExampleRecordOperationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetExampleRecordOperation403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response GetExampleRecordOperation403ApplicationProblemPlusJSONResponse) VisitGetExampleRecordOperationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetExampleRecordOperation404ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response GetExampleRecordOperation404ApplicationProblemPlusJSONResponse) VisitGetExampleRecordOperationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetExampleRecordOperationdefaultApplicationProblemPlusJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
+}
+
+func (response GetExampleRecordOperationdefaultApplicationProblemPlusJSONResponse) VisitGetExampleRecordOperationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListExampleRecordResultsRequestObject struct {
+	Id     string `json:"id"`
+	Params ListExampleRecordResultsParams
+}
+
+type ListExampleRecordResultsResponseObject interface {
+	VisitListExampleRecordResultsResponse(w http.ResponseWriter) error
+}
+
+type ListExampleRecordResults200JSONResponse ExampleResultList
+
+func (response ListExampleRecordResults200JSONResponse) VisitListExampleRecordResultsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListExampleRecordResults401ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response ListExampleRecordResults401ApplicationProblemPlusJSONResponse) VisitListExampleRecordResultsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListExampleRecordResults403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response ListExampleRecordResults403ApplicationProblemPlusJSONResponse) VisitListExampleRecordResultsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
 
-	return m
+type ListExampleRecordResults404ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response ListExampleRecordResults404ApplicationProblemPlusJSONResponse) VisitListExampleRecordResultsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateExampleRecordRequestObject struct {
-	Body *CreateExampleRecordJSONRequestBody
+type ListExampleRecordResultsdefaultApplicationProblemPlusJSONResponse struct {
+	Body       ProblemDetails
+	StatusCode int
 }
 
-type CreateExampleRecordResponseObject interface {
-	VisitCreateExampleRecordResponse(w http.ResponseWriter) error
+func (response ListExampleRecordResultsdefaultApplicationProblemPlusJSONResponse) VisitListExampleRecordResultsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetExampleRecordStatusRequestObject struct {
+	Id string `json:"id"`
 }
 
-type CreateExampleRecord201JSONResponse struct {
-	Message *string `json:"message,omitempty"`
-	Status  *string `json:"status,omitempty"`
+type GetExampleRecordStatusResponseObject interface {
+	VisitGetExampleRecordStatusResponse(w http.ResponseWriter) error
 }
 
-func (response CreateExampleRecord201JSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
+type GetExampleRecordStatus200JSONResponse ExampleRecordLifecycle
+
+func (response GetExampleRecordStatus200JSONResponse) VisitGetExampleRecordStatusResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(201)
+	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateExampleRecord400JSONResponse ProblemDetails
+type GetExampleRecordStatus401ApplicationProblemPlusJSONResponse ProblemDetails
 
-func (response CreateExampleRecord400JSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+func (response GetExampleRecordStatus401ApplicationProblemPlusJSONResponse) VisitGetExampleRecordStatusResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateExampleRecorddefaultJSONResponse struct {
+type GetExampleRecordStatus403ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response GetExampleRecordStatus403ApplicationProblemPlusJSONResponse) VisitGetExampleRecordStatusResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetExampleRecordStatus404ApplicationProblemPlusJSONResponse ProblemDetails
+
+func (response GetExampleRecordStatus404ApplicationProblemPlusJSONResponse) VisitGetExampleRecordStatusResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetExampleRecordStatusdefaultApplicationProblemPlusJSONResponse struct {
 	Body       ProblemDetails
 	StatusCode int
 }
 
-func (response CreateExampleRecorddefaultJSONResponse) VisitCreateExampleRecordResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
+func (response GetExampleRecordStatusdefaultApplicationProblemPlusJSONResponse) VisitGetExampleRecordStatusResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(response.StatusCode)
 
 	return json.NewEncoder(w).Encode(response.Body)
@@ -708,9 +2238,42 @@ func (response GetReadyz503ApplicationProblemPlusJSONResponse) VisitGetReadyzRes
 
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
+	// List API keys
+	// (GET /admin/api-keys)
+	ListAPIKeys(ctx context.Context, request ListAPIKeysRequestObject) (ListAPIKeysResponseObject, error)
+	// Issue an API key
+	// (POST /admin/api-keys)
+	IssueAPIKey(ctx context.Context, request IssueAPIKeyRequestObject) (IssueAPIKeyResponseObject, error)
+	// Revoke an API key
+	// (DELETE /admin/api-keys/{id})
+	RevokeAPIKey(ctx context.Context, request RevokeAPIKeyRequestObject) (RevokeAPIKeyResponseObject, error)
+	// List example records
+	// (GET /examples)
+	ListExampleRecords(ctx context.Context, request ListExampleRecordsRequestObject) (ListExampleRecordsResponseObject, error)
 	// Submit example data
 	// (POST /examples)
 	CreateExampleRecord(ctx context.Context, request CreateExampleRecordRequestObject) (CreateExampleRecordResponseObject, error)
+	// Delete an example record
+	// (DELETE /examples/{id})
+	DeleteExampleRecord(ctx context.Context, request DeleteExampleRecordRequestObject) (DeleteExampleRecordResponseObject, error)
+	// Get an example record
+	// (GET /examples/{id})
+	GetExampleRecord(ctx context.Context, request GetExampleRecordRequestObject) (GetExampleRecordResponseObject, error)
+	// Partially update an example record
+	// (PATCH /examples/{id})
+	PatchExampleRecord(ctx context.Context, request PatchExampleRecordRequestObject) (PatchExampleRecordResponseObject, error)
+	// Replace an example record
+	// (PUT /examples/{id})
+	UpdateExampleRecord(ctx context.Context, request UpdateExampleRecordRequestObject) (UpdateExampleRecordResponseObject, error)
+	// Get the processing operation of an example record
+	// (GET /examples/{id}/operation)
+	GetExampleRecordOperation(ctx context.Context, request GetExampleRecordOperationRequestObject) (GetExampleRecordOperationResponseObject, error)
+	// List the results of an example record
+	// (GET /examples/{id}/results)
+	ListExampleRecordResults(ctx context.Context, request ListExampleRecordResultsRequestObject) (ListExampleRecordResultsResponseObject, error)
+	// Get the status of an example record
+	// (GET /examples/{id}/status)
+	GetExampleRecordStatus(ctx context.Context, request GetExampleRecordStatusRequestObject) (GetExampleRecordStatusResponseObject, error)
 	// Kubernetes liveness probe
 	// (GET /healthz)
 	GetHealthz(ctx context.Context, request GetHealthzRequestObject) (GetHealthzResponseObject, error)
@@ -766,10 +2329,121 @@ type strictHandler struct {
 	options     StrictHTTPServerOptions
 }
 
+// ListAPIKeys operation middleware
+func (sh *strictHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request, params ListAPIKeysParams) {
+	var request ListAPIKeysRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListAPIKeys(ctx, request.(ListAPIKeysRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListAPIKeys")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListAPIKeysResponseObject); ok {
+		if err := validResponse.VisitListAPIKeysResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// IssueAPIKey operation middleware
+func (sh *strictHandler) IssueAPIKey(w http.ResponseWriter, r *http.Request) {
+	var request IssueAPIKeyRequestObject
+
+	var body IssueAPIKeyJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.IssueAPIKey(ctx, request.(IssueAPIKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "IssueAPIKey")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(IssueAPIKeyResponseObject); ok {
+		if err := validResponse.VisitIssueAPIKeyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RevokeAPIKey operation middleware
+func (sh *strictHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request, id string) {
+	var request RevokeAPIKeyRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RevokeAPIKey(ctx, request.(RevokeAPIKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RevokeAPIKey")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RevokeAPIKeyResponseObject); ok {
+		if err := validResponse.VisitRevokeAPIKeyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListExampleRecords operation middleware
+func (sh *strictHandler) ListExampleRecords(w http.ResponseWriter, r *http.Request, params ListExampleRecordsParams) {
+	var request ListExampleRecordsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListExampleRecords(ctx, request.(ListExampleRecordsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListExampleRecords")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListExampleRecordsResponseObject); ok {
+		if err := validResponse.VisitListExampleRecordsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // CreateExampleRecord operation middleware
-func (sh *strictHandler) CreateExampleRecord(w http.ResponseWriter, r *http.Request) {
+func (sh *strictHandler) CreateExampleRecord(w http.ResponseWriter, r *http.Request, params CreateExampleRecordParams) {
 	var request CreateExampleRecordRequestObject
 
+	request.Params = params
+
 	var body CreateExampleRecordJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
@@ -797,6 +2471,204 @@ func (sh *strictHandler) CreateExampleRecord(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+// DeleteExampleRecord operation middleware
+func (sh *strictHandler) DeleteExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	var request DeleteExampleRecordRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteExampleRecord(ctx, request.(DeleteExampleRecordRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteExampleRecord")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteExampleRecordResponseObject); ok {
+		if err := validResponse.VisitDeleteExampleRecordResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetExampleRecord operation middleware
+func (sh *strictHandler) GetExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetExampleRecordRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetExampleRecord(ctx, request.(GetExampleRecordRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetExampleRecord")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetExampleRecordResponseObject); ok {
+		if err := validResponse.VisitGetExampleRecordResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PatchExampleRecord operation middleware
+func (sh *strictHandler) PatchExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	var request PatchExampleRecordRequestObject
+
+	request.Id = id
+
+	var body PatchExampleRecordJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PatchExampleRecord(ctx, request.(PatchExampleRecordRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PatchExampleRecord")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PatchExampleRecordResponseObject); ok {
+		if err := validResponse.VisitPatchExampleRecordResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateExampleRecord operation middleware
+func (sh *strictHandler) UpdateExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	var request UpdateExampleRecordRequestObject
+
+	request.Id = id
+
+	var body UpdateExampleRecordJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateExampleRecord(ctx, request.(UpdateExampleRecordRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateExampleRecord")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateExampleRecordResponseObject); ok {
+		if err := validResponse.VisitUpdateExampleRecordResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetExampleRecordOperation operation middleware
+func (sh *strictHandler) GetExampleRecordOperation(w http.ResponseWriter, r *http.Request, id string, params GetExampleRecordOperationParams) {
+	var request GetExampleRecordOperationRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetExampleRecordOperation(ctx, request.(GetExampleRecordOperationRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetExampleRecordOperation")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetExampleRecordOperationResponseObject); ok {
+		if err := validResponse.VisitGetExampleRecordOperationResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListExampleRecordResults operation middleware
+func (sh *strictHandler) ListExampleRecordResults(w http.ResponseWriter, r *http.Request, id string, params ListExampleRecordResultsParams) {
+	var request ListExampleRecordResultsRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListExampleRecordResults(ctx, request.(ListExampleRecordResultsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListExampleRecordResults")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListExampleRecordResultsResponseObject); ok {
+		if err := validResponse.VisitListExampleRecordResultsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetExampleRecordStatus operation middleware
+func (sh *strictHandler) GetExampleRecordStatus(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetExampleRecordStatusRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetExampleRecordStatus(ctx, request.(GetExampleRecordStatusRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetExampleRecordStatus")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetExampleRecordStatusResponseObject); ok {
+		if err := validResponse.VisitGetExampleRecordStatusResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // GetHealthz operation middleware
 func (sh *strictHandler) GetHealthz(w http.ResponseWriter, r *http.Request) {
 	var request GetHealthzRequestObject
@@ -992,53 +2864,127 @@ func (sh *strictHandler) GetReadyz(w http.ResponseWriter, r *http.Request) {
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xa3XPbNhL/VzC8e6u+KMlxopt7cNqkca+5emKnvbnEDxC5MtGAAAOAcnQZ/+83iw9+",
-	"y5Ld9NLp3JMpksAudn/72w/6c5TIvJAChNHR6nOkkwxyai9ffKJ5weENJFKlb+BjCdrg/ULJApRhYN9K",
-	"QSeKFYZJ0fsZ/WQvKCcGPhlyy0xGcqmAJFLYOxupiMmAgBM1iUaRv4xW0Vlp5PgGBChqICXa3icpNTQa",
-	"RWZX4DvaKCZuortRlIOhg9oxBemlocp8Rw30VfQHgxR3hq5GxEiicTGq1tma7vBPpW/8dBRtpMqpiVYR",
-	"E2Yxj0ZRTj+xvMyj1SIeRTkT7kdc6c+EgRtQ9gBSmKy14/K+DeP5oQ13QFVrv/lsfjKwZXfl3ShS8LFE",
-	"u0Wrd26boN7IHvu6WiTXv0JiUFqhmFTM7PoWPlNrZhRVOxLeIVvKS2tbxnmpDTqYrEvNBGhNVMlBt6Cw",
-	"uM8QJ4fsoIKHnw8od56CMGzDQBG5sZ5PKOeAIKCG6HKdM4PgwCfKRkIbpJrlJae42XgtzRAwvS31S8m5",
-	"vH1b9HV4yemNk8dEyhJqQJPbDEwGiuRUlJSTjV08LgvCNBEAKbT1MKqESvZaSg5U9DzZNMSoHxoNHw45",
-	"2J3+PO3r/1awjyUQVpvSRRHTVRgNme7Fv8az2SwespmhN31yiS4hp8KwhOBjQo2hSYaukfu88y5KIZcR",
-	"osebD0/GDOR2975Yd4MqRXf2NzN8gDJeKgYi5TtinzvP3TLOCeVaEloUQBVhgqTyVmijgOaEyxvdOb23",
-	"TEF3XNKUDNih5z7vgKCYJ73rStFqU8fZJJD2gDcvlFzDpaGmtKagacocVV80SG5DuYZRl9NLk8i8Vhx9",
-	"rUFtWQIkA8pNRgrcvH3ez5H2wiL5wfFFm6cNZVzfk0Fy0JregCbgI3K9s+GyZSkGiBVJkgySD1bw8U7W",
-	"lRE6aCvzHEnL04LcgqI8CMJFnXSlgKa7g0700ho+s54g3hV7PMUh/6620AOc5RcTv5q4bQnVJIUNE86M",
-	"b15+S54tT047DnM+iVbRlY2ukCYVaFmqBMgt1URITOKlQFAyoQ0VCWpfKrEqS5au4vkClidPTsfw9Nl6",
-	"HM/TxZguT56Ml/MnT+JlfLqczWZR7YPlbImGyUEbmhfRKprP5rNxPB8v4qv5YnXybHXy7N9Rbbs3QZem",
-	"HkbRBJCnoiOle5NnxhR6NZ3av1Yh0BMmp8vZch9g+6h5VeZUjBELdM2xjCg4FTY/EF1AwjZIX9KRo0yS",
-	"UikQCQSQFc5dbWAdaf4e0Gt/9Aj7zTlRsAEn22WeQN7aKlLpeqSOD3V4lc5LxYZ03xeUr66uLoh7SBKZ",
-	"Aqnrw/XOxaliN0xYRgLVUtKC62D108JfV/wVy4Eg22csyZrWsN6odGnbZg+GK02w7hyj0MFMOJyCLjOp",
-	"sDxpUVTQBTdpqzAcJz1ZIXC60r6VSoGrcppZHnGMSxyag3QmBrLdwyKxS9ZH4BerphupmPYAHkTpfRF+",
-	"CJPDPB4c1OHzBuUOUfrPoLTvlx7A5a9pkjEBNbmsS8ZTwoRT3FIMor4KBXxCQKSFZMJ007Fd7Foih9BZ",
-	"PLbFWCLznJnmkz523TuvqM6iVTRL4+VySdP1s/gUaJKcxot5vInnT+Nns+XmNE5OYHH6NKG25vRJLLpC",
-	"yDBNqCBnF+eo2zYYJYons8msT7kNjXtxmYFr32wBj7yA8YgLTD8Uw0F7YGuefK+E0CXYd4+J8z1inPGG",
-	"xGRUZ/eIeYC5e6L3lllnpPE7CD+7OO8kIucz/6zqlT+F+m9I5LbGev+o/uE+gQ4Ih0IxSBg1EFIftWXv",
-	"lo8bMesDkrwGQ9sDhhC0mJMgKbE7ukwyyD0kgSpQZ6Vr3N2vl4FGfvjlCksb+zZ2ZfZpu9yI7u5sot5I",
-	"XJ9IYWhiZyyC2jU/lJxRQZ6D0AlqXyreoLFEq4nOpmHZXZcwQjcQfEUFgS0IQ1LFtiCqqh0b7xtyoaSR",
-	"Gy5vCRUprvkFqE+hnCUgNDQUK0W411RqNZ1WDyZS3UwbGazS5oVV4TunwqVXwY6G9mjQ4gaO3bE9qyxA",
-	"0IJFq2gxiS1MCmoy65aph5D9UUg3tuoAPkmgMIRWbYzNIgoKBRqEscTUbl6tUiA+loC9rp1dvRdU70SS",
-	"KSlkaTNgAhqNOSEukEXKQZECtdfGBU4qkzJHC9jtcoai3osCj74uN95BWjabx0QKXeagNEmoIApoYuzs",
-	"xEzeI+qRIy3925r3WwXUQGt2F1UzkOcy3QWkgbBmoUXBWWLXT3/VLlDdEBCv/qpgE62iv0zrKeHUjwin",
-	"g/PBu3Z0GlWCvaELKbRzyHwWP0iHdhbwfWBrsBWuvPWo9S2k9xeW9Wrr0nSQZ7ossDfGAka8aOtdv+/d",
-	"KFrOZl/M7J1+cECnc7GlnKWhaQgQdwSxoSU3/0NlXiglFQkAaBFptHrXptB313fXo8hXtbYFX+fMVHEY",
-	"mNkOh94F0+voGjedutnDf1DjGzBDg95CKlNP1myb4+nHRy4mN1UKgRHcC6zvwbzyInqAPsa7+0chx5s6",
-	"TGwG7HzVOA7WVZxtYXIYe75O/uZLu/05rfBntTiZLb6CFk2jbCjjkBIkXLSNHTWHeVEHlS0Q/qNcgxJg",
-	"oLHODoEaULS+CUDEdD61iYEWbJKZnN+DSZEir1NBsAFVNDFsC+TV1esfq0ThKvuC3tQfJ85wc8zqoU23",
-	"70zei0uWM05tZ3aZULzCJT8VIM4uzkdVn7ZlKWhCyZbpknI7pJDKj20dhaKfUBs9lGK+BxM0QE0Ph4OB",
-	"T2YaDFH7ttvav/7RHdMWA5CvIU0hrQ/bNsiWwW2znKpIe4AOa8vu2QzFfr1YuWq6NJA2NjA55djbYQ65",
-	"B6Dfg9l3rrfnDZCeXZyfY6U5hNJwpD0oNYrBFtrYW0xmbfw1ShtNfrj86Z+T98K2DG63te/MLcDGvgT1",
-	"2bzGW/3pwMftAQCinN/Ex66oYILl1MGzsutrd7M+ls6Ac6xGKS8tjwfz2RrUtSpJRoUA22I1KvtODTzU",
-	"7Vpc1Igangu4zzydrxEl5zuMYSpa4YL+CapHR1QyVw907p8jXvCQ4Uj3x4rvNw4ReiNUPO9WMjxZ9rDs",
-	"3/tNXFpXs+/L2WyR4Bv2CiaTibs1re8dQ5tu7Iz5CVKXkUL6aZ7r66IgaGLVexwSPDlhB4z2IXJDvKfd",
-	"+Y7CxPH02cWEx90+TDyc3pwBv/mCLHcMiW3jfkseN1ryx9PbJc1rq/kzk3VpbNXCEmb4juRUfQD3JRIN",
-	"RuyEhhIjJUdwd8lvFJUC8HjUQNqVj3o27bkV6URSNvFns4b9mz/43xeT+E9h5E4OCeZeTOL9KeSAFe+N",
-	"V9x4T3qBT4UCrbGF1i1v+vHq5I/BOI8jmyaz4PmshvcyTD2vOMwt/ruYH6bWM9keuVQfmx9RNn25ZnVQ",
-	"26/qXK/S43y7z/r3OLcxGz/s3TArt191Dvv452oq/rs5OYjYY83OcP8P4eGg0+NcfL8Lhh1t/y3kcVMp",
-	"hlCk6Q7beTdLJkbRzYYlg9OpN07SFxtOuX9o+Z3mU9XB3MS0Ptj/J1ZDEys0F3vMyKpeuH9mZXdSCG47",
-	"ke0q5HtxO2xx33qm0d11tVF3wYvwwdnWY8pRGJbXmDZz/4HNzsdbs4poFL4thfi5G33eM2yvvmmTFHIp",
-	"7D+QooTeeOFWqg8bLm/rzauhcX/3htX8/7Ghkh0D6norb8C767v/BgAA///U1PUiRS0AAA==",
+	"H4sIAAAAAAAC/+x9aXMbN7boX0H1u1V35g1JkVq80DUfFFmKlbEtRZLjTEI/E+o+JHHdDXQANGXelP77",
+	"KxwAvTdJyVsm0ZfEIpvAwdk3nP49CEWSCg5cq2D8e6DCBSQU/3l4fvovWJl/0ShimglO43MpUpCagQrG",
+	"Mxor6AURqFCy1HwfjINXoGlENSViRignTKkMInJ4fko+wGpArhZg/kGYVhDPCFOEwxIkUVpIiAZBL0hL",
+	"O/wehBKohuhQmz+qO12xBAjV5GbBwgXRbuEbqtymZjH4SJM0hmAc7A53D/rD/f7oydXw6XhvOB7u/hL0",
+	"gpmQCdXBOIiohr5mCQS9QK9S8xOlJePz4NYskzIJqhuKmQZZA4QpIuF/INQtkDzqj3b7e6Or3b3xwdPx",
+	"wdPtIWFRE4TTCLhmMwbSIN3tPyDFx4ooITVhjhxEyAhkFajh6IfL3R8f//zkX3uv9l8fnD/68fHFk8un",
+	"V8Of2qCIqdJv1BqyeCSYBwnN9MKAEhpKEkok/JaB0j0iITSgRIaKiVCaCB4CSUGShPFMQwcFn16N9sej",
+	"3fH+wfZ4EzccZAvquIa5pFrIMt0c02pRBeCaxTHj874CuWRh6zYSluLDXdnV/ajttAf94ehq+GQ8HI6H",
+	"w+1Pq0KRWvmpwnCJn5O5pFzjAXNuKe/9q/+nGkugUfCuFzANCa7X2Mp9QKWkq+AWUfBbxiREZh0WBR71",
+	"OVC9kky/6wWaaTyv0RBG2+QrimsjPGYLq4deMtWC1dOKflE9IuIIlCYzJpVuqpP8HK2rmBXMb/Kn/kvC",
+	"LBgH/2en0JE7TkHuOO24EQW4VvOgBM/TedoLKyN3VL5GuyK6e8Rim1AekZjNwHCKU8kOV4b6yOhNLH2q",
+	"ujPnU+SG6YXINMHVVkRpuiJLGrOIZFyzuJPt768c7ynk5DsIRQIKv1UZUsLgimnldZXapAkS+vEl8Lle",
+	"BOPR7pNekDCe//3FBbRX/H0jmYaKxJYge7RfByylWoM0O/+/yeTyH//VhtWEfjy1i+0ONzC7JUALs3t2",
+	"buH3Ywv6WQqSWizcmeWpWvFwIQUXmSKpFCEoxfgc+d0QNGHaYNMhyZmdATnVZCbiWNwg5Sfcfo7iEq7C",
+	"GHpECZKlEdVmMep+ZxhZaoXcITzQRCxBDia8IUoSqLJnqoJ9gZ97ex1TbZSW0lRnioQLyufwDL8BKYUk",
+	"oYhQemeUxRAV29bYMj9qu2ky0J9u5T904qyy3fHP/eFwOOre7BLPs0mROga4KP8El1BZrLf+MT5sJGur",
+	"PXNuK/ZDQq8x3WtItcnT3Npy18QpJ1l+rBpmS5LmEEEKOeqWNYtqFLQ4PpsF41/vQCEvyre932u8rj6F",
+	"3PbHL5gJBFYtirGM77qZJ68o45oyDhG5XlkOtpp5a2tu17+SlCvUPJYDaXTG41Uw1jKDFtVXw++7um46",
+	"rggPocqFOS1A1snoSFSn2Uuvm+6oJY8yKYHnbGsdgapsEy3moBfGsDO9QPXmnl5Yogwm3AKhrAokv2WQ",
+	"QdQjVIKJLVIp5hKUMo5BDISSBeVRDNJrZGtgE+OSTDjwiGQpMZSIwSgaIZ12G5Cj/EPjvTidJ93WZjO7",
+	"MaFzyviE3yyAl44zcKrKyTOBpTm4YxyE4Bk5f3OFa58fXh29IB8AUmsB7IHb9PjiXoz5KdxX5ba76e97",
+	"au1PEuBP0Z/kzJkbpKV5zPHkgirCRZ0RP1dgv4W69XR/1yWipBDJTQq3PYI5JCmdI3qqVFNbRy9VNaOI",
+	"MChkCtfdmgWrpqGF/zh81EeZVKLFw7afk5nzsM2jdvecsII76iudw1XQ8OKnuX71/HD16nAjkRrxVIMc",
+	"a91M+9Q51eGieYwfLs9ekwTkHEhqniB/uzg5Io/3nj76O6FpGjPrklOvxusOpTEWVpHMGMSR8UVBGe3D",
+	"7OkjEWaJ+dsoMcv60TMy5VkcT0kYA5WKUIK/bdNBFWDrsL+GG1L6JKdFmwp441RjKLiGj7rV6QdNu22M",
+	"tYdVAM6p1IzGJPEJQL2g2gRaiNCIMO6iGYc8/1wFNONSMCGZXgXj3dsWMmo6V23edBrTEBC3MVMYvZkn",
+	"a1FTBIm4SzYjZ7I2ZONXa9F8AZwmBZu0snYHF1sG3cTGpSTBHTjlLLUEJYb21tgnwjCk5Yb8SN6eVs50",
+	"mGnRnwM3TiZERFmoDR2rQfDucDhcw1YNcI14Xxqv4jnV0EZgPClEZiuog2iEEl2SpsqM6MpmNNwBRk9K",
+	"poFxvbdr4WZJlgTjvREGyPaPIm5nXMMcJB5AcL2orLi/bsHR7qYFV0BlZT1jz1qWrP+yphZxGQ9eD4/9",
+	"roV9CulqmCF5zbSkckX8M2RJ4wxxy+I4U9pQnFxninHj5Mkshqp87a1DxMEmPEhP4e9W23g4IY2Na4k6",
+	"pohWuyRRsSSLMSrqXwvdkq5pCWARt+oEEwRv0iZMJzGdOx3HI8xtG8/XetAJ5RmNXXahn6W2zgFRLdtV",
+	"CSuuhYiB8havpEBMrykqJZq2Ebzba3zD2W8ZEFag1koVU1u6j7XEUimV9Oth/xfa/99h/+m74p+D9/13",
+	"/7c1wdSu0y8hoVyzEBU5oVrTcFGkxFpAc/rdcKFDe1cebG93Y4JuTdKrF2SIOve9IWK3pTiRDHhk3AI0",
+	"F8gvNyyOCY2VME4FUGncg0jccKUl0ITEoma4cuOQ0lUsaERGDV274Tzdjq4F2ynmbq9qc/7uopbxqaIh",
+	"d5LRlQeXmWv1oszJuVETvwbHPx++On95/P7i+Ojs4vn7y6vDqzeX79+8vjw/Pjo9OT1+HvQ6nvnxzfGb",
+	"NV+fvn5/fnH2/cXx5WXnM0dn5sOrNaucHJ6+PH5usFYWj7XgdKD30ocbDT6s5rfuUQ3IdChs9CU4lJOj",
+	"MuOteYCWGqyQEqz6bFMkR8XX5PS5V9AJKGXiGuT4kErJcv1sjtJafXw7Otz9bu9o//nB8aOTx98/efH0",
+	"h/vXQB13ue1aq6HmEYy9PrUeykWb0/JaoE2QxjZxn/NxSZHqbj5DEpEfMyo1yHhFJKRCtvrm+dNnLd7d",
+	"cxQvXqrOuFD6hua58e7aeD1w/tREcnGwT05J1KKNgpFdcsDiq0iutSI6zze172U4ZrvarWUrRGrRuvAl",
+	"khJYQm3LTOTQtmptVBfrtLV5oD0hYb9rTxL2CIebu9dXL8uyqO6ek/DJ/btVWqvY6MwM2OLvvZpdDg06",
+	"4lWty6Ulk1oOdqsYoylz+25XbP4ALf6x0fSlsq4CHvmcw/Tn/uH5af9fsJqSBVCj6MgpxuVqIW44ETxe",
+	"Yd9FlYEhUu9/SZ4u/52crP6dnDwKf/5pP/z5pxF8/3oZJlH8y94Pi+jtx+Evuyer8PuPBxv5+APW9t1p",
+	"S0QqFfA7yv/1Wk1rCtYXtyulwLxOVnYqUuCRAbAXqCwM0Sk3kolZ5qotL570wOagrLHY51JcQwHqHbjp",
+	"zNlq7+YZf9yVCAzpYr0w+vS6FnYVdZdAfHDmoRJba8pitSYN4Cy1IuCiqOsVhjRLFpkgBrck4QLCD1XJ",
+	"3Zg86dLdl1mSmEDT2QixBEljvxH6h1VelECj1UYGU42SGFKiQamCscz3MSTPCwzdgVjux8T9mthlCVUk",
+	"gpmvRF2cHJGn+wePawSzNHFym8d3Rj2KTIaAVoULTWYi44Y5GVea8tBAn0k+zjIWjUe7e7B/8OhxH548",
+	"ve6PdqO9Pt0/eNTf3330aLQ/erw/HA4LczHeH+4bxCSgNE1Sa6GGLY0WHncXHpYyHFrSEIzhD7bc3aF8",
+	"oXWqxjs7+H8ECNSAiZ394X4Xwza55kWWUN43vECvYyDwMY0pt8KoUgjZzISKwgawIgyx4BVCyREx5Koy",
+	"1pbob7YDSilkC2P/xESMaTFsc7GwYZ6kl2eCSwUO3LcQdlvmKn65tZW0u3ZUjQrWacT/F6dEwgwsmmwi",
+	"w/tvrhXGo3VLdN6VN3NvKJPsLr7fi6ur87yAJCIgRT7S+X5Csrnx70Euax4gysHG5FpFVDY7gw4byDg5",
+	"LA2HcHjvvqaO3MLlwsQxqqpNPSxmkXo6uk2kG3t5GV8X5pWSRkbkzE9cycntznhLGuNuSqNuV7bg35Bq",
+	"mAvJXKm5nUvXKaNNPNlucjyBaqanZB3arE+j5ntHZ4H7ElJ7Nd+Vshv+5kyK5J4VXqcGtoyQKhXe6LO1",
+	"yPQ6W6reLlYtG/cIDOaDIkfcK+VAekV82iO+X0BIxGbed3WHFist7oXZGlchhXCxCspL3OV6Dkq808Jf",
+	"P4FUd2erVzRcMA6Fnb3OWGyCCUsbtLZGq+aq1nxDgEepYFzXPVP8sa3oWA04HPUxzg9FkjBd/qapG+0z",
+	"L6haBONgGI329/dpdP109BhoGD4e7e2OZqPdJ6Onw/3Z41F4AHuPn4QUU+TOnwuuFtjn6ZpeDWxLj5Rg",
+	"NBgOhk3vowRxW5CF1SesPxi7Y/S9+YFuqnp/0AaPlE/euYMvcuCz29iRjm0s8tq2WVC1WLPNHdDd2Loz",
+	"4jisFKjd5ofnpzWfzNLMfZeX+j6SNW33y4LXm0d1X3ZtaBlhk6r3O/RKHFIctYLvCo1LUusEkvgbMq1C",
+	"m/tyd01E1FzOVh9zaf3TupRiw0EwxhrAIK8m9QIXGAbjwFYCk0xpcg0kBmXoQ7nRlfCbCRK1IAdBLzA7",
+	"e7dqECOK8KfB+HFD0tyujcQe1TljitnMhuC2J6JHMkz4OdOuhf2YcJrUztQ4Sksh2p1tQ5jRwrFLT6Pq",
+	"ltviqGnPsjbnrplLXfrowvyguncZ4U3hsBRoBCsIbxemMU5hmCeiRIU0plU/+nFdQDxCywyfM3OD0413",
+	"D2FmqHNpTGM5F3aY6Ral5dNbvm1/IUU2X5Dp+dnlFdmhUcL4Dk1Z/wOs1LS4Z4ad9C6cwd76CWe6dEkM",
+	"U3TP7B0F13Do7iXgbRDs1MnvNWBPDjPQ2Exa0AsM7wXjIE+yFUd1qa7bXnANVIL0x7J/nXhX54e3V0Fd",
+	"mn94e0XsY0SLD8DtcdyNjpgpF+kY5vIJqf9WxCOUOLIkwPWEI0PSODZM6a8VONONa/+3IlNceWo4darC",
+	"dErCmLLEnhYdFyxPIzzVmD64vcUQcyZsmYhrGqJv6LDyQxYzysl3wBV2s2QyLjngoZIDtdjxP7vt6p71",
+	"VsB4ZdjLGUm2BJ6nxqxSODcKYRaLG6Th4fnpW6Au+ItZCFxBCbCM+8/KQI13dvIvBkLOd4KWBp1jBOG5",
+	"BeHSgYB53g4IKl6H7X5EZzoFTlMWjIO9wQgNUEr1AsWgxs3mozm0Jup1Jm02ga29BmXv4xhWdsyD8OoF",
+	"MDnheb+W4PHqmb9vYjtlFcRLsL+sXNNE1sh5DzNCL5nSNkut8CySJqBBKmwur4cuWN7Kgcft8us4LoNz",
+	"wy3xUNx+y0CuCmkrbpQZr7olF3n7Du8PpIIrq1l2h0PPosBtHTVNYxYi/Dv/4+KJYr3NuXisJiD/Nz2O",
+	"GjUMufeHozUAuED1H3cDpJa/7ACmrEiMiBeKlCTMhUGM24tZxlZZTWhh3vtGMLvunpiGH6z9Ean1aIoL",
+	"N75Bx+qNGXXl8a8M7DGGiZ7VKnYNGb+s+n8NUK6Dd7e9qqkrvnjXC1w6x0lUwUO+TebX4NCvEqSi80ok",
+	"oYTDTU5spz+tVBGb97Qwu9q88pL4zFaEmFYTjkEC8xVOvA7FNAkp5wKdHAlaMlj6VvwBOYqZQaAtQOV3",
+	"7fiEd1SiWvQIQu/KXXlX2HciWn1m+c2vsVQdGdfNU1Meo8+2eaXYuJX6sJI4/AbMfeq0Qi2MeFBnD+rs",
+	"HurMqaX8/nGLRrvt1X2fnd9ZdGtVXAztjcHGWS8tOyAXZf8dbx17lznTFX+ezKRIDB34hAvufuiveuZr",
+	"EFcmVm3ayu5VUlcVtbG/vlJfuvv/IFNfWKb2LTG+MqCvRY5L+MiUVrnnTeYYQBSFlD+v4DdEtN2XWRcx",
+	"NJMhXtp9hGCCpyJAcE1TZaNejhbu1+Z3a8Dc8TftN0Vlrc2tyrYa2hDcFWoKFhgQ33pFJUx4SueM0zxc",
+	"w2if/pYBCfG21TOSUmXdtmlxN2tqW2tz746qCZ+G7istyAy0q8/YLmmj7vA+VkdIVylYbI7sygC6EA/w",
+	"4msFRJd/SCUsGV7Z9xfVWmI9u9baYK/3e6OIgbcOCM+Sa8syHv1aOKi6touZTYUXu+VCuTtce9PDNWF3",
+	"XnFoglmOgz2AxhtfoQ2KY8/rVlUU95nSWET57YG2Q2g6rxxh++kt24DoVBhWe22CtQuZpQRsAcy974hs",
+	"B11xGQS5jClnKbpgrF6vuAOTtaJmIRTmjDGZaG9JY0GH4a1ME4Th6BSEK3KNTm1Qla95nNh6YAsG2zt0",
+	"Pxeo1zATEu4G65W4E6RfMk3TvHrbYvc6L99++6gLsUxK6vbBRXwIu1qGczW9sPoDzaxSndkLh+zYuzfd",
+	"+aXDMIRUE5qXFbH3RoJrcVMtzSiURwQ4jm0gDG+YTnjH0B6bncpHR4BU6DdXr0+b5RJmtppwrAReZzNX",
+	"HFCifJcqFFxlCUhjBQy1aajxQqOuJsEmPGb8g/KXy8ojhHJWYRw7uacvheWGUhJrwi+Bm1OS6WkESSo0",
+	"8HBVznQ5x0OuiKIziFdj9NHM33lAoGiC+bIJN8e7FhFeAYnpStWus1jHjnGlgUZGdTmT50ujHueYkmtx",
+	"645w/Fr1vv8Gv87m9fqhsRncyr31Wld2U+bsrlJMcNdxQ8mbN6fP0ae1Cn7CTfDtzpGCxHXMWZ2c2tqb",
+	"OTHY3mebXlRuXBjeq2cFesnV1UsCMU0VqDWVuRpBKuapfIfu4GDTHbp3XyYd2T5cZ5us5O7nhqGYHdSm",
+	"pGoijVrAlUztQJag57CP8OWI1/0LR9W2S54oj1NzwGm5OdZzuaqyBHVMETUlbbDefQu83LZ1Er6s3Veq",
+	"SL7j52ke+u24+0o7+SMbNr/d6Ew8pG7/LGmmp1+Ryof53b6KEZnaT9+zaEpojLcnfPqJurpMKPiMzTMJ",
+	"0YSbf8cs1CQVMQtX5G/T59+9Pzp7ffLy9Ojq/fnZy9Ojf//T5kynfycSZhn21wormaGx6L0Jd8Jp2a0G",
+	"T8MoYk2JYVMCJiDya4mox0tK5MLYyP6hCZralEcouA3qbyjTPmBBu4ohtDHa/VnM5gvsbXfqyEPZKrNF",
+	"tIlCu7v7Fclp+K2JqxuqcipmKs8GkYjNsPk5Pw/6DAPkwt2n31Zc4OOCZphzxxmaJqzExAr52/Ti8Or4",
+	"/cvTV6dX7y/O3lwdX07/PiDHS5CrCc/Vvs1hUQ19/JkrhvlK5fSCanhpvujjf6e9CS99dgEJZZzx+bRH",
+	"Kh8r0FOUgNKn58jz0/sxXu6Y+HMndFXUPnEK0va8tjn8+IOEHW7E6Jq4wz9RCTwu0UvNIwPfAdmMO8pZ",
+	"1i3qP4lYwrphAlXn9zmuU3d+t6vb1IKaG7zrZW8TP9jVP2n5pkbzP1MV51MF2opSM9rvyiasq5ZQYtg0",
+	"hm3F+HvQG2T4CyUSu/i5uEZYQ8aDZnjQDP+xmuFeGcbvQW+vFNamnS7qFdLu+aafUgTuHD9izpW2j4o8",
+	"TNN4RShZNzKyOu+wPizyBEdETrhyKRA7BRInQ8aGGjhrAzWKbZq3bbhumKLxYrFo52rFLhSLfFqzjrei",
+	"Z8/fx2tJDOLQwaZa/cIpLzvqcKuE11fW6UjQDo3+LStDltF8NvzBwjxYmL+c7+mGzcYrd233DgYn053z",
+	"Y7vVdR3rLp9aVrdYnPD61g3f8RUyfysMklSvjBwk1LfhGJtV7gFq0ct2XO9XV8x3qkV8ZdWcD3f/g6nm",
+	"h3z7g27+q+tmr0y3VMmNdF9RVtvm5ls+T1SveREQtjfZlrBSsZzyCa975e4+qQdoSkqNAViMdDNUGt0A",
+	"E+6nq527m7TFW3psVx+hZFpKK1f6B94axpme4zyTMVYz/vl6WrnDfR0LIxJFyrkQiHyOGRFywu0goZ6R",
+	"aPvwa6JcvtpWy6MBeUuZiydCmqb2BXx7Q//cM1tr9xPvsbbCFIFwIXCOuwcUeAj9Q/vUtM1w1bM05XfT",
+	"rA/8TAy1uz8kab6RGpCpx0ss+LyfijhWWBTOUkOf/JiDrlYAC3XQHgTi2qPht2lSW1t4v6pzuSihsVSy",
+	"aBKlPY1doWuBYF/mdojYWE+/T2nO0MxesEjrQuL6VbaoyT3Y0wd7+tfKpnX2pLVNfvoPzbQ13QA3LXY7",
+	"J8A2lZWmW28/xHbCj2m48BN9PwCkqjxHivKI8DWTnPNprxOes2u4/Uhspre6euFuhtzlar1Hgd/pelWM",
+	"+ix6+Kugdt7CqIwA/+TLGBawT7mMcfCJlzG+Sud5PmO5s2iUM2lT1h5ix7+IrbOmxOieB6tX6VLXVRH5",
+	"81q6YtDpJkMXV96j4d7k2JGyrE4hx5DOvc3Ov9y4MilRbRPBXRavIf0613b8u/XWxEXZgwJ9CBYe1GY5",
+	"WFjzmtf/YKVpZ+D/7xo9mQqpi7dwlV6x60MDvFCQcc74vLWp6IXb4l4Krnsk//a8BPlQ2HZ9547DFKEx",
+	"W8Lg25U8vqNR0d162wsOvpna8khxI9SNZTO4wdfU+fcW1MSuIjX/yq5BctBQ+h2+jKAkK0gbn65mfCZ2",
+	"MNdMUzZY6CRew5M8AqlsL7oGSUPNlkBeXL16mVfwrWbFi6h+2umhWdyYDD+D3Q6cnPBLlrCY4n2yS5zK",
+	"iD85S4Efnp/28iHcSxaBUeFLpjIa47B8Id0r3twLkQXXBpous+8hMJBuFgcNH/WOR0Q5XKvObX/10h4T",
+	"VTUk1xBFEBWHrSJkyeCmPHGwuE7TrL4VmO1YzGz77WTlqkzS/JIEVSShsYlijeFfw6BGrXec681peZrG",
+	"+ekpn4lWLvVH6vIwcZBXlff2BsMq/5VuYCrsfBpMOM7rtatduyliyGB9N6XRJz1yfiteM+hff76eAc0+",
+	"n6SP7dv/GGcJteyZ4/WV/bA4llpAHAelkakefTim0c4JNt4yB5xvXBp+WRsT2TZqGvmi4Kjt36x7ksXx",
+	"ysgw5RVxMfTJu4CaI1bXc+EWxP1zyIs5pD/SellxIzk3KfSSqDi9m+/hlGWDl91zn6RLCzdpkg2He6F5",
+	"Av8Fg8HAfrRTfLaN2rQpKG4H0qBy9uanfK5vywUeEgTvfpzglBPjc2Lwg69CtJS259uKJ7ZXn3WecHzX",
+	"xRN3V28Wgf/4jFpuGyW2HDWn1o5KU2vvr94uaVJgzZ0ZZ7UZr4WFTMcrklD5wV0Bxo5bHBpNiRYidm/o",
+	"qii/XpBxMMejGqL6/gbOMj6XPBoIygbubIjYZ+7g/9wbjP4USK7ZEI/uvcGo24RswOJaeTULd5gX+JhK",
+	"+05IJx2emu7dBoM/hsa5n7IpaxZzPoRwrYbZnPwrdEs13VW8EKGhXL58nm6bYLUV2m9K3PxdofehbRf2",
+	"1xC39GKKzdT1L6rAV6pspvFP+SspvhiR/RYd2Ky9WeMPQWEP0/1IvJ4E7YTGq9n3y0phRxmN8KWdtpxM",
+	"tKSzGQtbs1MXdqfPlpyyL1b8Qvmp/GB2UkhxsIeMVVvGyqCL3SdlVfywO2eFK8lle3L3ysfimGyxr0PY",
+	"CW7f5QvVf3Ds3/ZUmtKzNO61MZv56wQoj6q5iiIh7OWn2Szgh8zkL5QiESSCK20Ewc03qqQXboT8MIvF",
+	"TbF4ntVurl7Cmnufqp05VEGgKjcM4t/NhXBsKbNQWf1XmtPuD4mjTW/f3f7/AAAA///gpnKuYpsAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
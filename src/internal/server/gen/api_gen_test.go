@@ -123,15 +123,13 @@ func TestTooManyValuesForParamError(t *testing.T) {
 }
 
 // TestResponseTypes tests the response type Visit implementations
-func TestCreateExampleRecord201JSONResponse(t *testing.T) {
+func TestCreateExampleRecord202JSONResponse(t *testing.T) {
 	t.Parallel()
 
 	w := httptest.NewRecorder()
-	msg := "created"
-	status := "success"
-	response := CreateExampleRecord201JSONResponse{
-		Message: &msg,
-		Status:  &status,
+	response := CreateExampleRecord202JSONResponse{
+		Body:    ExampleOperation{RecordId: "EX-1", Status: Pending, RecordStatus: EXAMPLERECORDSTATUSQUEUED},
+		Headers: CreateExampleRecord202ResponseHeaders{Location: "/examples/EX-1/operation"},
 	}
 
 	err := response.VisitCreateExampleRecordResponse(w)
@@ -139,12 +137,15 @@ func TestCreateExampleRecord201JSONResponse(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if w.Code != 201 {
-		t.Errorf("expected status 201, got %d", w.Code)
+	if w.Code != 202 {
+		t.Errorf("expected status 202, got %d", w.Code)
 	}
 	if w.Header().Get("Content-Type") != "application/json" {
 		t.Errorf("expected application/json content type")
 	}
+	if w.Header().Get("Location") != "/examples/EX-1/operation" {
+		t.Errorf("expected the operation location, got %q", w.Header().Get("Location"))
+	}
 }
 
 func TestCreateExampleRecord400JSONResponse(t *testing.T) {
@@ -538,10 +539,61 @@ func TestPathToRawSpec(t *testing.T) {
 // Mock implementation of ServerInterface for testing
 type mockServerImpl struct{}
 
-func (m *mockServerImpl) CreateExampleRecord(w http.ResponseWriter, r *http.Request) {
+func (m *mockServerImpl) CreateExampleRecord(w http.ResponseWriter, r *http.Request, params CreateExampleRecordParams) {
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (m *mockServerImpl) ListExampleRecords(w http.ResponseWriter, r *http.Request, params ListExampleRecordsParams) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServerImpl) GetExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	w.Header().Set("X-Record-Id", id)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServerImpl) GetExampleRecordStatus(w http.ResponseWriter, r *http.Request, id string) {
+	w.Header().Set("X-Record-Id", id)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServerImpl) ListExampleRecordResults(w http.ResponseWriter, r *http.Request, id string, params ListExampleRecordResultsParams) {
+	w.Header().Set("X-Record-Id", id)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServerImpl) ListAPIKeys(w http.ResponseWriter, r *http.Request, params ListAPIKeysParams) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServerImpl) IssueAPIKey(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 }
 
+func (m *mockServerImpl) RevokeAPIKey(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockServerImpl) GetExampleRecordOperation(w http.ResponseWriter, r *http.Request, id string, params GetExampleRecordOperationParams) {
+	w.Header().Set("X-Record-Id", id)
+	if params.Prefer != nil {
+		w.Header().Set("X-Prefer", *params.Prefer)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServerImpl) UpdateExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServerImpl) PatchExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServerImpl) DeleteExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (m *mockServerImpl) GetAsyncAPIHTML(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
@@ -597,6 +649,64 @@ func TestHandlerFromMux(t *testing.T) {
 	}
 }
 
+func TestHandlerRoutesExampleRecordPaths(t *testing.T) {
+	t.Parallel()
+
+	handler := Handler(&mockServerImpl{})
+
+	testCases := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/examples?limit=5&tag=a&tag=b", http.StatusOK},
+		{http.MethodGet, "/examples/EX-1", http.StatusOK},
+		{http.MethodPut, "/examples/EX-1", http.StatusOK},
+		{http.MethodPatch, "/examples/EX-1", http.StatusOK},
+		{http.MethodDelete, "/examples/EX-1", http.StatusNoContent},
+		{http.MethodGet, "/examples/EX-1/status", http.StatusOK},
+		{http.MethodGet, "/examples/EX-1/results", http.StatusOK},
+		{http.MethodGet, "/examples/EX-1/operation", http.StatusOK},
+		{http.MethodGet, "/admin/api-keys", http.StatusOK},
+		{http.MethodPost, "/admin/api-keys", http.StatusCreated},
+		{http.MethodDelete, "/admin/api-keys/01A", http.StatusNoContent},
+		{http.MethodGet, "/examples?limit=abc", http.StatusBadRequest},
+		{http.MethodGet, "/examples?desiredStartFrom=not-a-date", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tc.want {
+			t.Errorf("%s %s: status = %d, want %d", tc.method, tc.path, rec.Code, tc.want)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/examples/EX-42", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Record-Id"); got != "EX-42" {
+		t.Errorf("path parameter id = %q, want 'EX-42'", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/examples/EX-43/status", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Record-Id"); got != "EX-43" {
+		t.Errorf("status path parameter id = %q, want 'EX-43'", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/examples/EX-44/operation", nil)
+	req.Header.Set("Prefer", "wait=5")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Prefer"); got != "wait=5" {
+		t.Errorf("Prefer header parameter = %q, want 'wait=5'", got)
+	}
+}
+
 func TestHandlerFromMuxWithBaseURL(t *testing.T) {
 	t.Parallel()
 
@@ -676,8 +786,8 @@ func TestServerInterfaceWrapperCreateExampleRecord(t *testing.T) {
 	w := httptest.NewRecorder()
 	wrapper.CreateExampleRecord(w, req)
 
-	if w.Code != http.StatusCreated {
-		t.Errorf("expected status 201, got %d", w.Code)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected status 202, got %d", w.Code)
 	}
 }
 
@@ -711,8 +821,54 @@ func TestServerInterfaceWrapperWithMiddleware(t *testing.T) {
 type mockStrictServerImpl struct{}
 
 func (m *mockStrictServerImpl) CreateExampleRecord(ctx context.Context, request CreateExampleRecordRequestObject) (CreateExampleRecordResponseObject, error) {
-	msg := "created"
-	return CreateExampleRecord201JSONResponse{Message: &msg}, nil
+	return CreateExampleRecord202JSONResponse{
+		Body:    ExampleOperation{RecordId: "123", Status: Pending, RecordStatus: EXAMPLERECORDSTATUSQUEUED},
+		Headers: CreateExampleRecord202ResponseHeaders{Location: "/examples/123/operation"},
+	}, nil
+}
+
+func (m *mockStrictServerImpl) ListExampleRecords(ctx context.Context, request ListExampleRecordsRequestObject) (ListExampleRecordsResponseObject, error) {
+	return ListExampleRecords200JSONResponse{Items: []ExampleRecord{}}, nil
+}
+
+func (m *mockStrictServerImpl) GetExampleRecord(ctx context.Context, request GetExampleRecordRequestObject) (GetExampleRecordResponseObject, error) {
+	return GetExampleRecord200JSONResponse{RecordId: request.Id}, nil
+}
+
+func (m *mockStrictServerImpl) GetExampleRecordStatus(ctx context.Context, request GetExampleRecordStatusRequestObject) (GetExampleRecordStatusResponseObject, error) {
+	return GetExampleRecordStatus200JSONResponse{RecordId: request.Id}, nil
+}
+
+func (m *mockStrictServerImpl) ListExampleRecordResults(ctx context.Context, request ListExampleRecordResultsRequestObject) (ListExampleRecordResultsResponseObject, error) {
+	return ListExampleRecordResults200JSONResponse{Items: []ExampleResult{}}, nil
+}
+
+func (m *mockStrictServerImpl) ListAPIKeys(ctx context.Context, request ListAPIKeysRequestObject) (ListAPIKeysResponseObject, error) {
+	return ListAPIKeys200JSONResponse{Items: []APIKey{}}, nil
+}
+
+func (m *mockStrictServerImpl) IssueAPIKey(ctx context.Context, request IssueAPIKeyRequestObject) (IssueAPIKeyResponseObject, error) {
+	return IssueAPIKey201JSONResponse{ApiKey: APIKey{Owner: request.Body.Owner}}, nil
+}
+
+func (m *mockStrictServerImpl) RevokeAPIKey(ctx context.Context, request RevokeAPIKeyRequestObject) (RevokeAPIKeyResponseObject, error) {
+	return RevokeAPIKey204Response{}, nil
+}
+
+func (m *mockStrictServerImpl) GetExampleRecordOperation(ctx context.Context, request GetExampleRecordOperationRequestObject) (GetExampleRecordOperationResponseObject, error) {
+	return GetExampleRecordOperation200JSONResponse{Body: ExampleOperation{RecordId: request.Id, Status: Succeeded}}, nil
+}
+
+func (m *mockStrictServerImpl) UpdateExampleRecord(ctx context.Context, request UpdateExampleRecordRequestObject) (UpdateExampleRecordResponseObject, error) {
+	return UpdateExampleRecord200JSONResponse{RecordId: request.Id}, nil
+}
+
+func (m *mockStrictServerImpl) PatchExampleRecord(ctx context.Context, request PatchExampleRecordRequestObject) (PatchExampleRecordResponseObject, error) {
+	return PatchExampleRecord200JSONResponse{RecordId: request.Id}, nil
+}
+
+func (m *mockStrictServerImpl) DeleteExampleRecord(ctx context.Context, request DeleteExampleRecordRequestObject) (DeleteExampleRecordResponseObject, error) {
+	return DeleteExampleRecord204Response{}, nil
 }
 
 func (m *mockStrictServerImpl) GetAsyncAPIHTML(ctx context.Context, request GetAsyncAPIHTMLRequestObject) (GetAsyncAPIHTMLResponseObject, error) {
@@ -828,10 +984,10 @@ func TestStrictHandlerCreateExampleRecord(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(w, req)
+	handler.CreateExampleRecord(w, req, CreateExampleRecordParams{})
 
-	if w.Code != http.StatusCreated {
-		t.Errorf("expected status 201, got %d", w.Code)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected status 202, got %d", w.Code)
 	}
 }
 
@@ -846,7 +1002,7 @@ func TestStrictHandlerCreateExampleRecordInvalidJSON(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(w, req)
+	handler.CreateExampleRecord(w, req, CreateExampleRecordParams{})
 
 	if w.Code != http.StatusBadRequest {
 		t.Errorf("expected status 400, got %d", w.Code)
@@ -860,6 +1016,50 @@ func (m *mockStrictServerImplWithError) CreateExampleRecord(ctx context.Context,
 	return nil, errors.New("internal error")
 }
 
+func (m *mockStrictServerImplWithError) ListExampleRecords(ctx context.Context, request ListExampleRecordsRequestObject) (ListExampleRecordsResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
+func (m *mockStrictServerImplWithError) GetExampleRecord(ctx context.Context, request GetExampleRecordRequestObject) (GetExampleRecordResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
+func (m *mockStrictServerImplWithError) GetExampleRecordStatus(ctx context.Context, request GetExampleRecordStatusRequestObject) (GetExampleRecordStatusResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
+func (m *mockStrictServerImplWithError) ListExampleRecordResults(ctx context.Context, request ListExampleRecordResultsRequestObject) (ListExampleRecordResultsResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
+func (m *mockStrictServerImplWithError) ListAPIKeys(ctx context.Context, request ListAPIKeysRequestObject) (ListAPIKeysResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
+func (m *mockStrictServerImplWithError) IssueAPIKey(ctx context.Context, request IssueAPIKeyRequestObject) (IssueAPIKeyResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
+func (m *mockStrictServerImplWithError) RevokeAPIKey(ctx context.Context, request RevokeAPIKeyRequestObject) (RevokeAPIKeyResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
+func (m *mockStrictServerImplWithError) GetExampleRecordOperation(ctx context.Context, request GetExampleRecordOperationRequestObject) (GetExampleRecordOperationResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
+func (m *mockStrictServerImplWithError) UpdateExampleRecord(ctx context.Context, request UpdateExampleRecordRequestObject) (UpdateExampleRecordResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
+func (m *mockStrictServerImplWithError) PatchExampleRecord(ctx context.Context, request PatchExampleRecordRequestObject) (PatchExampleRecordResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
+func (m *mockStrictServerImplWithError) DeleteExampleRecord(ctx context.Context, request DeleteExampleRecordRequestObject) (DeleteExampleRecordResponseObject, error) {
+	return nil, errors.New("internal error")
+}
+
 func (m *mockStrictServerImplWithError) GetAsyncAPIHTML(ctx context.Context, request GetAsyncAPIHTMLRequestObject) (GetAsyncAPIHTMLResponseObject, error) {
 	return nil, errors.New("internal error")
 }
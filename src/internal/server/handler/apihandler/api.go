@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"strings"
 
+	"drblury/event-driven-service/internal/auth"
 	"drblury/event-driven-service/internal/domain"
+	"drblury/event-driven-service/internal/ratelimit"
 	generator "drblury/event-driven-service/internal/server/gen"
 	"drblury/event-driven-service/internal/usecase"
 	"log/slog"
@@ -106,9 +108,24 @@ var (
 </html>`))
 )
 
+// RequestValidator checks decoded request payloads before they reach the app logic.
+// Requests are not validated when the APIHandler has no validator.
+type RequestValidator interface {
+	Validate(value any) error
+}
+
 type APIHandler struct {
 	*infohandler.InfoHandler
-	AppLogic        *usecase.AppLogic
+	AppLogic  *usecase.AppLogic
+	Validator RequestValidator
+	// Authenticator verifies the bearer tokens of secured operations, see Authenticate.
+	Authenticator auth.Authenticator
+	// APIKeys verifies the API keys of secured operations, see Authenticate.
+	APIKeys auth.Authenticator
+	// Policy holds the scopes each operation requires, see Authenticate.
+	Policy *auth.Policy
+	// Limiter limits the request rate of each client, see RateLimit.
+	Limiter         *ratelimit.Limiter
 	log             *slog.Logger
 	baseURL         string
 	uiHandlers      map[string]*infohandler.InfoHandler
@@ -143,12 +160,40 @@ func NewAPIHandler(
 func createResponder(logger *slog.Logger) *responder.Responder {
 	return responder.NewResponder(
 		responder.WithLogger(logger),
+		responder.WithStatusMetadata(http.StatusNotFound, responder.StatusMetadata{
+			LogLevel: slog.LevelInfo,
+			LogMsg:   "Not Found",
+		}),
+		responder.WithStatusMetadata(http.StatusUnauthorized, responder.StatusMetadata{
+			LogLevel: slog.LevelInfo,
+			LogMsg:   "Unauthorized",
+		}),
+		responder.WithStatusMetadata(http.StatusForbidden, responder.StatusMetadata{
+			LogLevel: slog.LevelInfo,
+			LogMsg:   "Forbidden",
+		}),
+		responder.WithStatusMetadata(http.StatusTooManyRequests, responder.StatusMetadata{
+			LogLevel: slog.LevelInfo,
+			LogMsg:   "Too Many Requests",
+		}),
 		responder.WithErrorClassifier(func(err error) (int, bool) {
 			switch {
 			case errors.Is(err, domain.ErrorUpstreamService):
 				return http.StatusInternalServerError, true
-			case errors.Is(err, domain.ErrorNotFound), errors.Is(err, domain.ErrorBadRequest):
+			case errors.Is(err, domain.ErrorNotFound):
+				return http.StatusNotFound, true
+			case errors.Is(err, domain.ErrorBadRequest):
 				return http.StatusBadRequest, true
+			case errors.Is(err, domain.ErrorConflict):
+				return http.StatusConflict, true
+			case errors.Is(err, domain.ErrorUnauthorized):
+				return http.StatusUnauthorized, true
+			case errors.Is(err, domain.ErrorForbidden):
+				return http.StatusForbidden, true
+			case errors.Is(err, domain.ErrorUnprocessable):
+				return http.StatusUnprocessableEntity, true
+			case errors.Is(err, domain.ErrorTooManyRequests):
+				return http.StatusTooManyRequests, true
 			default:
 				var validationErr domain.ErrValidations
 				if errors.As(err, &validationErr) {
@@ -0,0 +1,73 @@
+package apihandler
+
+import (
+	"net/http"
+
+	"drblury/event-driven-service/internal/database"
+	generator "drblury/event-driven-service/internal/server/gen"
+
+	"github.com/samber/lo"
+)
+
+// ListAPIKeys returns the issued API keys, optionally of one owner.
+func (ah *APIHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request, params generator.ListAPIKeysParams) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	keys, err := ah.AppLogic.ListAPIKeys(r.Context(), lo.FromPtr(params.Owner))
+	if err != nil {
+		ah.HandleErrors(w, r, err, "Listing API keys failed")
+		return
+	}
+	ah.RespondWithJSON(w, r, http.StatusOK, generator.APIKeyList{
+		Items: lo.Map(keys, func(key *database.StoredAPIKey, _ int) generator.APIKey {
+			return apiKey(key)
+		}),
+	})
+}
+
+// IssueAPIKey issues a new API key and returns it once.
+func (ah *APIHandler) IssueAPIKey(w http.ResponseWriter, r *http.Request) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	var request generator.APIKeyRequest
+	if ok := ah.ReadRequestBody(w, r, &request); !ok {
+		return
+	}
+
+	stored, key, err := ah.AppLogic.IssueAPIKey(r.Context(), request.Owner, lo.FromPtr(request.Scopes), request.ExpiresAt)
+	if err != nil {
+		ah.HandleErrors(w, r, err, "Issuing API key failed")
+		return
+	}
+	ah.RespondWithJSON(w, r, http.StatusCreated, generator.IssuedAPIKey{Key: key, ApiKey: apiKey(stored)})
+}
+
+// RevokeAPIKey revokes an API key.
+func (ah *APIHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request, id string) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	if err := ah.AppLogic.RevokeAPIKey(r.Context(), id); err != nil {
+		ah.HandleErrors(w, r, err, "Revoking API key failed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiKey maps a stored API key onto the API representation.
+func apiKey(key *database.StoredAPIKey) generator.APIKey {
+	return generator.APIKey{
+		Id:         key.ID,
+		Owner:      key.Owner,
+		Scopes:     lo.Ternary(key.Scopes == nil, []string{}, key.Scopes),
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
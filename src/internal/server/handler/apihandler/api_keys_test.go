@@ -0,0 +1,118 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+	generator "drblury/event-driven-service/internal/server/gen"
+	"drblury/event-driven-service/internal/usecase"
+)
+
+func TestAPIKeyEndpoints(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	store := database.NewMemoryStore()
+	appLogic, _ := usecase.NewAppLogic(store, logger)
+	_, adminKey, err := appLogic.IssueAPIKey(context.Background(), "ops", []string{"admin"}, nil)
+	if err != nil {
+		t.Fatalf("IssueAPIKey() error = %v", err)
+	}
+	appLogic.RequireAuthentication(true)
+
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	handler.APIKeys = auth.NewAPIKeyAuthenticator(store)
+	mux := generator.HandlerWithOptions(handler, generator.StdHTTPServerOptions{
+		Middlewares: []generator.MiddlewareFunc{handler.Authenticate},
+	})
+
+	serve := func(method, target, key, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(auth.APIKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/admin/api-keys", adminKey, `{"owner":"billing","scopes":["examples:read"]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /admin/api-keys status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var issued generator.IssuedAPIKey
+	if err := json.Unmarshal(rec.Body.Bytes(), &issued); err != nil {
+		t.Fatalf("decode issued key: %v", err)
+	}
+	if issued.Key == "" || issued.ApiKey.Owner != "billing" || len(issued.ApiKey.Scopes) != 1 {
+		t.Errorf("issued key = %+v", issued)
+	}
+
+	if rec := serve(http.MethodGet, "/examples", issued.Key, ""); rec.Code != http.StatusOK {
+		t.Errorf("GET /examples status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	rec = serve(http.MethodPost, "/examples", issued.Key, `{"recordId":"EX-1","title":"Test"}`)
+	if rec.Code != http.StatusForbidden || rec.Header().Get("WWW-Authenticate") != apiKeyChallenge {
+		t.Errorf("POST /examples with read scope = %d, %q; want 403", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+	if rec := serve(http.MethodGet, "/admin/api-keys", issued.Key, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /admin/api-keys without admin scope status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	rec = serve(http.MethodGet, "/admin/api-keys", "", "")
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != apiKeyChallenge {
+		t.Errorf("GET /admin/api-keys without key = %d, %q; want 401", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+	if rec := serve(http.MethodPost, "/admin/api-keys", adminKey, `{"owner":" "}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("POST /admin/api-keys without owner status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = serve(http.MethodGet, "/admin/api-keys?owner=billing", adminKey, "")
+	var list generator.APIKeyList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GET /admin/api-keys = %d, %s", rec.Code, rec.Body.String())
+	}
+	if len(list.Items) != 1 || list.Items[0].Id != issued.ApiKey.Id || list.Items[0].LastUsedAt == nil {
+		t.Errorf("listed keys = %+v, want the used billing key", list.Items)
+	}
+	if strings.Contains(rec.Body.String(), issued.Key) {
+		t.Error("listed keys expose the key")
+	}
+
+	if rec := serve(http.MethodDelete, "/admin/api-keys/"+issued.ApiKey.Id, adminKey, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("DELETE /admin/api-keys status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec := serve(http.MethodGet, "/examples", issued.Key, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("GET /examples with revoked key status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := serve(http.MethodDelete, "/admin/api-keys/unknown", adminKey, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("DELETE unknown key status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestAPIKeyEndpointsNilHandler(t *testing.T) {
+	var handler *APIHandler
+
+	calls := map[string]func(http.ResponseWriter, *http.Request){
+		"list":   func(w http.ResponseWriter, r *http.Request) { handler.ListAPIKeys(w, r, generator.ListAPIKeysParams{}) },
+		"issue":  func(w http.ResponseWriter, r *http.Request) { handler.IssueAPIKey(w, r) },
+		"revoke": func(w http.ResponseWriter, r *http.Request) { handler.RevokeAPIKey(w, r, "01A") },
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			call(rec, httptest.NewRequest(http.MethodGet, "/admin/api-keys", strings.NewReader(`{}`)))
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("Status code = %d, want %d", rec.Code, http.StatusInternalServerError)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package apihandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/domain"
+	generator "drblury/event-driven-service/internal/server/gen"
+)
+
+// apiKeyChallenge names the header API keys are expected in.
+const apiKeyChallenge = `APIKey header="X-API-Key"`
+
+// Authenticate is a middleware for the generated operations. Operations with a
+// requirement in the Policy need an API key in X-API-Key that APIKeys accepts, or
+// a bearer token the Authenticator accepts, and the scopes the requirement lists;
+// the verified principal is stored in the request context. Requests routed to an
+// operation the Policy does not know are denied. Without a Policy the scopes the
+// generated wrappers attach to secured operations apply. Without Authenticator and
+// APIKeys every request passes unauthenticated.
+func (ah *APIHandler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ah.Authenticator == nil && ah.APIKeys == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		requirement, err := ah.requirement(r)
+		if err != nil {
+			ah.HandleErrors(w, r, err, "Access denied")
+			return
+		}
+		if requirement == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if key := r.Header.Get(auth.APIKeyHeader); key != "" && ah.APIKeys != nil {
+			principal, err := ah.APIKeys.Authenticate(r.Context(), key)
+			if err != nil {
+				ah.respondDenied(w, r, err, apiKeyChallenge)
+				return
+			}
+			ah.serveAuthorized(w, r, next, requirement, principal, apiKeyChallenge)
+			return
+		}
+
+		token, ok := auth.BearerToken(r.Header.Get("Authorization"))
+		if !ok || ah.Authenticator == nil {
+			ah.respondDenied(w, r, fmt.Errorf("%w: credentials missing", domain.ErrorUnauthorized), ah.challenge())
+			return
+		}
+		principal, err := ah.Authenticator.Authenticate(r.Context(), token)
+		if err != nil {
+			ah.respondDenied(w, r, err, `Bearer error="invalid_token"`)
+			return
+		}
+		ah.serveAuthorized(w, r, next, requirement, principal,
+			fmt.Sprintf(`Bearer error="insufficient_scope", scope=%q`, strings.Join(requirement[0], " ")))
+	})
+}
+
+// serveAuthorized passes the request on with the principal in its context when the
+// principal satisfies the requirement.
+func (ah *APIHandler) serveAuthorized(
+	w http.ResponseWriter,
+	r *http.Request,
+	next http.Handler,
+	requirement auth.Requirement,
+	principal *auth.Principal,
+	challenge string,
+) {
+	if err := requirement.Authorize(principal); err != nil {
+		ah.respondDenied(w, r, err, challenge)
+		return
+	}
+	next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+}
+
+// challenge lists the schemes a caller without credentials can authenticate with.
+func (ah *APIHandler) challenge() string {
+	var schemes []string
+	if ah.Authenticator != nil {
+		schemes = append(schemes, "Bearer")
+	}
+	if ah.APIKeys != nil {
+		schemes = append(schemes, apiKeyChallenge)
+	}
+	return strings.Join(schemes, ", ")
+}
+
+// requirement returns the requirement of the operation r is routed to. Public
+// operations have no requirement, so a pattern the Policy has no operation for is
+// reported with domain.ErrorForbidden instead of being served unauthenticated.
+func (ah *APIHandler) requirement(r *http.Request) (auth.Requirement, error) {
+	if ah.Policy != nil {
+		operation, ok := ah.Policy.Operation(r)
+		if !ok {
+			return nil, fmt.Errorf("%w: no operation is defined for %q", domain.ErrorForbidden, r.Pattern)
+		}
+		return ah.Policy.Requirement(operation), nil
+	}
+	// The generated wrappers mark secured operations with the scopes of each scheme.
+	var requirement auth.Requirement
+	for _, scheme := range []string{generator.BearerAuthScopes, generator.ApiKeyAuthScopes} {
+		if scopes, ok := r.Context().Value(scheme).([]string); ok {
+			requirement = append(requirement, scopes)
+		}
+	}
+	return requirement, nil
+}
+
+// respondDenied writes the problem of a failed authentication or
+// authorization together with the challenge of RFC 6750 in WWW-Authenticate.
+// The denial takes a rate limit token from the bucket of the client's IP address,
+// as r carries no principal yet; exhausted clients get 429 instead.
+func (ah *APIHandler) respondDenied(w http.ResponseWriter, r *http.Request, err error, challenge string) {
+	if !ah.limit(w, r) {
+		return
+	}
+	if errors.Is(err, domain.ErrorUnauthorized) || errors.Is(err, domain.ErrorForbidden) {
+		w.Header().Set("WWW-Authenticate", challenge)
+	}
+	ah.HandleErrors(w, r, err, "Access denied")
+}
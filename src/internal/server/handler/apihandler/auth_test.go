@@ -0,0 +1,224 @@
+package apihandler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+	generator "drblury/event-driven-service/internal/server/gen"
+	"drblury/event-driven-service/internal/usecase"
+)
+
+func TestAuthenticate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	issuer, err := auth.NewLocalIssuer("https://issuer.example", "event-driven-service")
+	if err != nil {
+		t.Fatalf("NewLocalIssuer() error = %v", err)
+	}
+	keys, err := issuer.KeySet()
+	if err != nil {
+		t.Fatalf("KeySet() error = %v", err)
+	}
+
+	appLogic, _ := usecase.NewAppLogic(database.NewMemoryStore(), logger)
+	appLogic.RequireAuthentication(true)
+	appLogic.SetExampleTopic("examples")
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	handler.Authenticator = auth.NewJWTAuthenticator(keys, issuer.Config())
+	mux := generator.HandlerWithOptions(handler, generator.StdHTTPServerOptions{
+		Middlewares: []generator.MiddlewareFunc{handler.Authenticate},
+	})
+
+	valid, err := issuer.Token("client-1", time.Minute, "examples:read", "examples:write")
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	readOnly, err := issuer.Token("client-2", time.Minute, "examples:read")
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	expired, err := issuer.Token("client-1", -time.Minute)
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+
+	serve := func(method, target, authorization, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		challenge     string
+	}{
+		{"missing token", "", `Bearer`},
+		{"other scheme", "Basic dXNlcjpwYXNz", `Bearer`},
+		{"expired token", "Bearer " + expired, `Bearer error="invalid_token"`},
+		{"invalid token", "Bearer not-a-token", `Bearer error="invalid_token"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(http.MethodGet, "/examples", tt.authorization, "")
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.challenge {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.challenge)
+			}
+			var problem generator.ProblemDetails
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil || problem.Status != http.StatusUnauthorized {
+				t.Errorf("body = %s, want a 401 problem", rec.Body.String())
+			}
+		})
+	}
+
+	if rec := serve(http.MethodGet, "/healthz", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("GET /healthz status = %d, want unsecured operations to pass", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/examples", "Bearer "+valid, ""); rec.Code != http.StatusOK {
+		t.Errorf("GET /examples status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	rec := serve(http.MethodPost, "/examples", "Bearer "+readOnly, `{"recordId":"EX-1","title":"Test"}`)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("POST /examples with read scope status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if got, want := rec.Header().Get("WWW-Authenticate"), `Bearer error="insufficient_scope", scope="examples:write"`; got != want {
+		t.Errorf("WWW-Authenticate = %q, want %q", got, want)
+	}
+	// The app logic rejects submissions without the principal the middleware stores.
+	if rec := serve(http.MethodPost, "/examples", "Bearer "+valid, `{"recordId":"EX-1","title":"Test"}`); rec.Code != http.StatusAccepted {
+		t.Errorf("POST /examples status = %d, want %d: %s", rec.Code, http.StatusAccepted, rec.Body.String())
+	}
+}
+
+func TestAuthenticatePolicy(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	issuer, err := auth.NewLocalIssuer("https://issuer.example", "event-driven-service")
+	if err != nil {
+		t.Fatalf("NewLocalIssuer() error = %v", err)
+	}
+	keys, err := issuer.KeySet()
+	if err != nil {
+		t.Fatalf("KeySet() error = %v", err)
+	}
+	swagger, err := generator.GetSwagger()
+	if err != nil {
+		t.Fatalf("GetSwagger() error = %v", err)
+	}
+	policy, err := auth.NewPolicy(swagger)
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+	// Production setups restrict the docs to administrators.
+	if err := policy.Require("GetOpenAPIJSON", "admin"); err != nil {
+		t.Fatalf("Require() error = %v", err)
+	}
+
+	handler := NewAPIHandler(nil, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	handler.Authenticator = auth.NewJWTAuthenticator(keys, issuer.Config())
+	handler.Policy = policy
+	mux := generator.HandlerWithOptions(handler, generator.StdHTTPServerOptions{
+		Middlewares: []generator.MiddlewareFunc{handler.Authenticate},
+	})
+
+	tests := []struct {
+		name   string
+		target string
+		scopes []string
+		want   int
+	}{
+		{"docs without admin scope", "/info/openapi.json", []string{"examples:read"}, http.StatusForbidden},
+		{"docs with admin scope", "/info/openapi.json", []string{"admin"}, http.StatusOK},
+		{"probe stays public", "/healthz", nil, http.StatusOK},
+		{"scope from the spec", "/examples/EX-1/status", []string{"examples:write"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.scopes != nil {
+				token, err := issuer.Token("client-1", time.Minute, tt.scopes...)
+				if err != nil {
+					t.Fatalf("Token() error = %v", err)
+				}
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("GET %s status = %d, want %d", tt.target, rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticatePolicyDeniesUnknownOperations(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	issuer, err := auth.NewLocalIssuer("https://issuer.example", "event-driven-service")
+	if err != nil {
+		t.Fatalf("NewLocalIssuer() error = %v", err)
+	}
+	keys, err := issuer.KeySet()
+	if err != nil {
+		t.Fatalf("KeySet() error = %v", err)
+	}
+	swagger, err := generator.GetSwagger()
+	if err != nil {
+		t.Fatalf("GetSwagger() error = %v", err)
+	}
+	policy, err := auth.NewPolicy(swagger)
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+
+	handler := NewAPIHandler(nil, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	handler.Authenticator = auth.NewJWTAuthenticator(keys, issuer.Config())
+	handler.Policy = policy
+	served := false
+	next := handler.Authenticate(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { served = true }))
+
+	token, err := issuer.Token("client-1", time.Minute, "admin")
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	// A route the spec does not define is denied even to a valid caller.
+	req := httptest.NewRequest(http.MethodGet, "/internal/debug", nil)
+	req.Pattern = "GET /internal/debug"
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	next.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden || served {
+		t.Errorf("status = %d, served = %v; want %d without serving", rec.Code, served, http.StatusForbidden)
+	}
+}
+
+func TestCreateExampleRecordWithoutPrincipal(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	appLogic, _ := usecase.NewAppLogic(database.NewMemoryStore(), logger)
+	appLogic.RequireAuthentication(true)
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+
+	req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(`{"recordId":"EX-1","title":"Test"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -1,21 +1,44 @@
 package apihandler
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"time"
 
+	"drblury/event-driven-service/internal/database"
 	"drblury/event-driven-service/internal/domain"
+	generator "drblury/event-driven-service/internal/server/gen"
+	"drblury/event-driven-service/internal/usecase"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/samber/lo"
+	date "google.golang.org/genproto/googleapis/type/date"
 )
 
-// CreateExampleRecord accepts example data and publishes it as a proto event.
-func (ah *APIHandler) CreateExampleRecord(w http.ResponseWriter, r *http.Request) {
+// CreateExampleRecord accepts example data and publishes it as a proto event. It
+// responds with 202 Accepted and links to the processing operation in Location.
+// With an Idempotency-Key retries replay the first response.
+func (ah *APIHandler) CreateExampleRecord(w http.ResponseWriter, r *http.Request, params generator.CreateExampleRecordParams) {
 	if ah == nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	if params.IdempotencyKey != nil {
+		ah.idempotent(w, r, *params.IdempotencyKey, ah.createExampleRecord)
+		return
+	}
+	ah.createExampleRecord(w, r)
+}
 
+func (ah *APIHandler) createExampleRecord(w http.ResponseWriter, r *http.Request) {
 	record := &domain.ExampleRecord{}
-	if ok := ah.ReadRequestBody(w, r, record); !ok {
+	if ok := ah.readProtoBody(w, r, record); !ok {
+		return
+	}
+	if err := ah.validate(record); err != nil {
+		ah.HandleErrors(w, r, err, "Example validation failed")
 		return
 	}
 
@@ -24,14 +47,228 @@ func (ah *APIHandler) CreateExampleRecord(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	token := r.Header.Get("Authorization")
-	if err := ah.AppLogic.HandleExample(r.Context(), record, token); err != nil {
+	if err := ah.AppLogic.HandleExample(r.Context(), record); err != nil {
 		ah.HandleErrors(w, r, err, "Example processing failed")
 		return
 	}
 
-	ah.RespondWithJSON(w, r, http.StatusCreated, map[string]string{
-		"status":  "queued",
-		"message": "example event accepted",
+	w.Header().Set("Location", operationLocation(record.GetRecordId()))
+	ah.RespondWithJSON(w, r, http.StatusAccepted, exampleOperation(usecase.ExampleOperationOf(record)))
+}
+
+// ListExampleRecords returns a page of stored example records.
+func (ah *APIHandler) ListExampleRecords(w http.ResponseWriter, r *http.Request, params generator.ListExampleRecordsParams) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	records, nextCursor, err := ah.AppLogic.ListExamples(r.Context(), exampleRecordFilter(params))
+	if err != nil {
+		ah.HandleErrors(w, r, err, "Listing example records failed")
+		return
+	}
+
+	items := make([]json.RawMessage, 0, len(records))
+	for _, record := range records {
+		item, err := protoJSON.Marshal(record)
+		if err != nil {
+			ah.HandleInternalServerError(w, r, err, "failed to encode example record")
+			return
+		}
+		items = append(items, item)
+	}
+
+	page := map[string]any{"items": items}
+	if nextCursor != "" {
+		page["nextCursor"] = nextCursor
+	}
+	ah.RespondWithJSON(w, r, http.StatusOK, page)
+}
+
+// GetExampleRecord returns a single stored example record.
+func (ah *APIHandler) GetExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	record, err := ah.AppLogic.GetExample(r.Context(), id)
+	if err != nil {
+		ah.HandleErrors(w, r, err, "Loading example record failed")
+		return
+	}
+	ah.respondWithRecord(w, r, http.StatusOK, record)
+}
+
+// GetExampleRecordStatus returns the lifecycle status and status history of a stored
+// example record.
+func (ah *APIHandler) GetExampleRecordStatus(w http.ResponseWriter, r *http.Request, id string) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	record, err := ah.AppLogic.GetExample(r.Context(), id)
+	if err != nil {
+		ah.HandleErrors(w, r, err, "Loading example record status failed")
+		return
+	}
+	ah.RespondWithJSON(w, r, http.StatusOK, exampleRecordLifecycle(record))
+}
+
+// ListExampleRecordResults returns the stored processing results of an example record.
+func (ah *APIHandler) ListExampleRecordResults(w http.ResponseWriter, r *http.Request, id string, params generator.ListExampleRecordResultsParams) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	results, err := ah.AppLogic.ListExampleResults(r.Context(), database.ExampleResultFilter{
+		RecordID:      id,
+		CorrelationID: lo.FromPtr(params.CorrelationId),
+		Limit:         int(lo.FromPtr(params.Limit)),
 	})
+	if err != nil {
+		ah.HandleErrors(w, r, err, "Loading example results failed")
+		return
+	}
+	ah.RespondWithJSON(w, r, http.StatusOK, generator.ExampleResultList{
+		Items: lo.Map(results, func(result *database.StoredExampleResult, _ int) generator.ExampleResult {
+			return exampleResult(result)
+		}),
+	})
+}
+
+// UpdateExampleRecord replaces a stored example record.
+func (ah *APIHandler) UpdateExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	record := &domain.ExampleRecord{}
+	if ok := ah.readProtoBody(w, r, record); !ok {
+		return
+	}
+
+	updated, err := ah.AppLogic.UpdateExample(r.Context(), id, record)
+	if err != nil {
+		ah.HandleErrors(w, r, err, "Updating example record failed")
+		return
+	}
+	ah.respondWithRecord(w, r, http.StatusOK, updated)
+}
+
+// PatchExampleRecord applies a JSON merge patch to a stored example record.
+func (ah *APIHandler) PatchExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		ah.HandleBadRequestError(w, r, err, "failed to read request body")
+		return
+	}
+
+	patched, err := ah.AppLogic.PatchExample(r.Context(), id, patch)
+	if err != nil {
+		ah.HandleErrors(w, r, err, "Patching example record failed")
+		return
+	}
+	ah.respondWithRecord(w, r, http.StatusOK, patched)
+}
+
+// DeleteExampleRecord removes a stored example record.
+func (ah *APIHandler) DeleteExampleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	if err := ah.AppLogic.DeleteExample(r.Context(), id); err != nil {
+		ah.HandleErrors(w, r, err, "Deleting example record failed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ready reports whether the handler can serve requests and writes a 500 otherwise.
+func (ah *APIHandler) ready(w http.ResponseWriter, r *http.Request) bool {
+	if ah == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return false
+	}
+	if ah.AppLogic == nil {
+		ah.HandleInternalServerError(w, r, errors.New("application logic not configured"), "example processing unavailable")
+		return false
+	}
+	return true
+}
+
+// validate runs the configured request validator on payload.
+func (ah *APIHandler) validate(payload any) error {
+	if ah.Validator == nil {
+		return nil
+	}
+	return ah.Validator.Validate(payload)
+}
+
+// exampleRecordLifecycle maps the status fields of record onto the API representation.
+func exampleRecordLifecycle(record *domain.ExampleRecord) generator.ExampleRecordLifecycle {
+	lifecycle := generator.ExampleRecordLifecycle{
+		RecordId: record.GetRecordId(),
+		Status:   generator.ExampleRecordStatus(record.GetStatus().String()),
+		History:  make([]generator.StatusTransition, 0, len(record.GetStatusHistory())),
+	}
+	for _, transition := range record.GetStatusHistory() {
+		lifecycle.History = append(lifecycle.History, generator.StatusTransition{
+			From:       generator.ExampleRecordStatus(transition.GetFrom().String()),
+			To:         generator.ExampleRecordStatus(transition.GetTo().String()),
+			OccurredAt: transition.GetOccurredAt().AsTime(),
+			Reason:     lo.EmptyableToPtr(transition.GetReason()),
+		})
+	}
+	if n := len(lifecycle.History); n > 0 {
+		lifecycle.UpdatedAt = lo.ToPtr(lifecycle.History[n-1].OccurredAt)
+	}
+	return lifecycle
+}
+
+// exampleResult maps a stored result onto the API representation.
+func exampleResult(result *database.StoredExampleResult) generator.ExampleResult {
+	item := generator.ExampleResult{
+		Id:            result.ID,
+		RecordId:      result.RecordID,
+		CorrelationId: lo.EmptyableToPtr(result.CorrelationID),
+		Status:        result.Status,
+		Note:          lo.EmptyableToPtr(result.Note),
+		StoredAt:      result.StoredAt,
+	}
+	if date := result.ProcessedOn; date != nil {
+		item.ProcessedOn = &openapi_types.Date{Time: time.Date(int(date.GetYear()), time.Month(date.GetMonth()), int(date.GetDay()), 0, 0, 0, 0, time.UTC)}
+	}
+	return item
+}
+
+// exampleRecordFilter maps the list query parameters onto the repository filter.
+func exampleRecordFilter(params generator.ListExampleRecordsParams) database.ExampleRecordFilter {
+	filter := database.ExampleRecordFilter{
+		Cursor:      lo.FromPtr(params.Cursor),
+		Limit:       int(lo.FromPtr(params.Limit)),
+		Tags:        lo.FromPtr(params.Tag),
+		Priority:    lo.FromPtr(params.Priority),
+		RequestedBy: lo.FromPtr(params.RequestedBy),
+	}
+	if params.DesiredStartFrom != nil {
+		filter.DesiredStartFrom = toDomainDate(params.DesiredStartFrom.Time)
+	}
+	if params.DesiredStartTo != nil {
+		filter.DesiredStartTo = toDomainDate(params.DesiredStartTo.Time)
+	}
+	return filter
+}
+
+// toDomainDate converts a calendar date into its proto representation.
+func toDomainDate(t time.Time) *date.Date {
+	return &date.Date{
+		Year:  int32(t.Year()),  // #nosec G115 -- calendar years fit into int32
+		Month: int32(t.Month()), // #nosec G115 -- months are 1..12
+		Day:   int32(t.Day()),   // #nosec G115 -- days are 1..31
+	}
 }
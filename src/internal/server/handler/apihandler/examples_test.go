@@ -1,15 +1,25 @@
 package apihandler
 
 import (
+	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"drblury/event-driven-service/internal/database"
 	"drblury/event-driven-service/internal/domain"
+	"drblury/event-driven-service/internal/events"
+	generator "drblury/event-driven-service/internal/server/gen"
 	"drblury/event-driven-service/internal/usecase"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/samber/lo"
+	date "google.golang.org/genproto/googleapis/type/date"
 )
 
 func TestCreateExampleRecordNilHandler(t *testing.T) {
@@ -18,7 +28,7 @@ func TestCreateExampleRecordNilHandler(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(`{}`))
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
 	if rec.Code != http.StatusInternalServerError {
 		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusInternalServerError)
@@ -31,12 +41,12 @@ func TestCreateExampleRecordNilAppLogic(t *testing.T) {
 
 	handler := NewAPIHandler(nil, info, logger, "", "")
 
-	body := `{"record_id": "test-123", "title": "Test"}`
+	body := `{"recordId": "test-123", "title": "Test"}`
 	req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
 	if rec.Code != http.StatusInternalServerError {
 		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusInternalServerError)
@@ -54,9 +64,9 @@ func TestCreateExampleRecordInvalidJSON(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
-	if rec.Code == http.StatusOK || rec.Code == http.StatusCreated {
+	if rec.Code == http.StatusOK || rec.Code == http.StatusAccepted {
 		t.Errorf("Invalid JSON should not result in success, got status %d", rec.Code)
 	}
 }
@@ -71,10 +81,10 @@ func TestCreateExampleRecordEmptyBody(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
 	// Should fail with empty body (either bad request or internal server error due to nil AppLogic)
-	if rec.Code == http.StatusCreated {
+	if rec.Code == http.StatusAccepted {
 		t.Errorf("Empty body should not result in created, got status %d", rec.Code)
 	}
 }
@@ -85,13 +95,13 @@ func TestCreateExampleRecordWithAuthHeader(t *testing.T) {
 
 	handler := NewAPIHandler(nil, info, logger, "", "")
 
-	body := `{"record_id": "test-123", "title": "Test"}`
+	body := `{"recordId": "test-123", "title": "Test"}`
 	req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer test-token")
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
 	// Should fail because AppLogic is nil
 	if rec.Code != http.StatusInternalServerError {
@@ -106,15 +116,15 @@ func TestCreateExampleRecordWithFullBody(t *testing.T) {
 	handler := NewAPIHandler(nil, info, logger, "/api", "")
 
 	body := `{
-		"record_id": "test-456",
+		"recordId": "test-456",
 		"title": "Full Test",
 		"description": "Test description",
 		"tags": ["test", "unit"],
 		"meta": {
-			"requested_by": "test-user",
-			"requires_follow_up": true,
+			"requestedBy": "test-user",
+			"requiresFollowUp": true,
 			"priority": 5,
-			"desired_start_date": {
+			"desiredStartDate": {
 				"year": 2024,
 				"month": 6,
 				"day": 15
@@ -125,7 +135,7 @@ func TestCreateExampleRecordWithFullBody(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
 	// Should fail because AppLogic is nil
 	if rec.Code != http.StatusInternalServerError {
@@ -139,12 +149,12 @@ func TestCreateExampleRecordMinimalPayload(t *testing.T) {
 
 	handler := NewAPIHandler(nil, info, logger, "", "")
 
-	body := `{"record_id": "min-001"}`
+	body := `{"recordId": "min-001"}`
 	req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
 	// Should fail because AppLogic is nil
 	if rec.Code != http.StatusInternalServerError {
@@ -166,12 +176,12 @@ func TestCreateExampleRecordWithContentType(t *testing.T) {
 
 	for _, ct := range contentTypes {
 		t.Run(ct, func(t *testing.T) {
-			body := `{"record_id": "ct-test"}`
+			body := `{"recordId": "ct-test"}`
 			req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
 			req.Header.Set("Content-Type", ct)
 			rec := httptest.NewRecorder()
 
-			handler.CreateExampleRecord(rec, req)
+			handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
 			// Should fail because AppLogic is nil, not because of content type
 			if rec.Code != http.StatusInternalServerError {
@@ -191,7 +201,7 @@ func TestCreateExampleRecordWithMalformedJSON(t *testing.T) {
 		`{`,
 		`}`,
 		`{"key": }`,
-		`{"record_id": "unclosed`,
+		`{"recordId": "unclosed`,
 		`not json at all`,
 		`<xml>not json</xml>`,
 	}
@@ -202,10 +212,10 @@ func TestCreateExampleRecordWithMalformedJSON(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 			rec := httptest.NewRecorder()
 
-			handler.CreateExampleRecord(rec, req)
+			handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
 			// Should fail with bad request or error status
-			if rec.Code == http.StatusCreated || rec.Code == http.StatusOK {
+			if rec.Code == http.StatusAccepted || rec.Code == http.StatusOK {
 				t.Errorf("Malformed JSON should not succeed: %s", body)
 			}
 		})
@@ -222,10 +232,10 @@ func TestCreateExampleRecordNilBody(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
 	// Should fail with some error status
-	if rec.Code == http.StatusCreated {
+	if rec.Code == http.StatusAccepted {
 		t.Error("Nil body should not result in created")
 	}
 }
@@ -238,16 +248,16 @@ func TestCreateExampleRecordWithAppLogic(t *testing.T) {
 	appLogic, _ := usecase.NewAppLogic(nil, logger)
 	handler := NewAPIHandler(appLogic, info, logger, "", "")
 
-	body := `{"record_id": "test-with-logic", "title": "Test with AppLogic"}`
+	body := `{"recordId": "test-with-logic", "title": "Test with AppLogic"}`
 	req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
 	// Should succeed because AppLogic.HandleExample returns nil when db is nil
-	if rec.Code != http.StatusCreated {
-		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusCreated)
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusAccepted)
 	}
 }
 
@@ -259,13 +269,13 @@ func TestCreateExampleRecordWithAppLogicFullPayload(t *testing.T) {
 	handler := NewAPIHandler(appLogic, info, logger, "/api", "")
 
 	body := `{
-		"record_id": "full-payload-test",
+		"recordId": "full-payload-test",
 		"title": "Full Payload Test",
 		"description": "Testing with full payload",
 		"tags": ["test", "full", "payload"],
 		"meta": {
-			"requested_by": "test-user",
-			"requires_follow_up": true,
+			"requestedBy": "test-user",
+			"requiresFollowUp": true,
 			"priority": 5
 		}
 	}`
@@ -274,10 +284,10 @@ func TestCreateExampleRecordWithAppLogicFullPayload(t *testing.T) {
 	req.Header.Set("Authorization", "Bearer test-token")
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
 
-	if rec.Code != http.StatusCreated {
-		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusCreated)
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusAccepted)
 	}
 }
 
@@ -288,23 +298,327 @@ func TestCreateExampleRecordSuccessResponse(t *testing.T) {
 	appLogic, _ := usecase.NewAppLogic(nil, logger)
 	handler := NewAPIHandler(appLogic, info, logger, "", "")
 
-	body := `{"record_id": "response-test", "title": "Response Test"}`
+	body := `{"recordId": "response-test", "title": "Response Test"}`
+	req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
+
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+
+	if got := rec.Header().Get("Location"); got != "/examples/response-test/operation" {
+		t.Errorf("Location = %q, want the operation of the record", got)
+	}
+	var operation generator.ExampleOperation
+	if err := json.Unmarshal(rec.Body.Bytes(), &operation); err != nil {
+		t.Fatalf("decode operation: %v", err)
+	}
+	if operation.Status != generator.Pending || operation.RecordStatus != generator.EXAMPLERECORDSTATUSQUEUED || lo.FromPtr(operation.Reason) != "submitted" {
+		t.Errorf("operation = %+v, want a pending operation of a queued record", operation)
+	}
+}
+
+func TestExampleRecordEndpointsNilHandler(t *testing.T) {
+	var handler *APIHandler
+
+	calls := map[string]func(http.ResponseWriter, *http.Request){
+		"list": func(w http.ResponseWriter, r *http.Request) {
+			handler.ListExampleRecords(w, r, generator.ListExampleRecordsParams{})
+		},
+		"get":    func(w http.ResponseWriter, r *http.Request) { handler.GetExampleRecord(w, r, "EX-1") },
+		"update": func(w http.ResponseWriter, r *http.Request) { handler.UpdateExampleRecord(w, r, "EX-1") },
+		"patch":  func(w http.ResponseWriter, r *http.Request) { handler.PatchExampleRecord(w, r, "EX-1") },
+		"delete": func(w http.ResponseWriter, r *http.Request) { handler.DeleteExampleRecord(w, r, "EX-1") },
+		"status": func(w http.ResponseWriter, r *http.Request) { handler.GetExampleRecordStatus(w, r, "EX-1") },
+		"results": func(w http.ResponseWriter, r *http.Request) {
+			handler.ListExampleRecordResults(w, r, "EX-1", generator.ListExampleRecordResultsParams{})
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			call(rec, httptest.NewRequest(http.MethodGet, "/examples/EX-1", strings.NewReader(`{}`)))
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("Status code = %d, want %d", rec.Code, http.StatusInternalServerError)
+			}
+		})
+	}
+}
+
+func TestExampleRecordEndpointsWithoutDatabase(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	appLogic, _ := usecase.NewAppLogic(nil, logger)
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+
+	rec := httptest.NewRecorder()
+	handler.GetExampleRecord(rec, httptest.NewRequest(http.MethodGet, "/examples/EX-1", nil), "EX-1")
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestUpdateExampleRecordInvalidBody(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	appLogic, _ := usecase.NewAppLogic(nil, logger)
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+
+	req := httptest.NewRequest(http.MethodPut, "/examples/EX-1", strings.NewReader(`{"title": 5}`))
+	rec := httptest.NewRecorder()
+	handler.UpdateExampleRecord(rec, req, "EX-1")
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestNotFoundMapsTo404(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	handler := NewAPIHandler(nil, &domain.Info{Version: "1.0.0"}, logger, "", "")
+
+	req := httptest.NewRequest(http.MethodGet, "/examples/EX-1", nil)
+	rec := httptest.NewRecorder()
+	handler.HandleErrors(rec, req, database.ErrExampleRecordNotFound)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+	if !strings.Contains(rec.Body.String(), `"status":404`) {
+		t.Errorf("expected ProblemDetails body with status 404, got %s", rec.Body.String())
+	}
+}
+
+func TestCreateExampleRecordDuplicateMapsTo409(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	appLogic, _ := usecase.NewAppLogic(database.NewMemoryStore(), logger)
+	appLogic.SetExampleTopic("examples")
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+
+	create := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(`{"recordId": "EX-1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
+		return rec.Code
+	}
+
+	if code := create(); code != http.StatusAccepted {
+		t.Fatalf("first create status = %d, want %d", code, http.StatusAccepted)
+	}
+	if code := create(); code != http.StatusConflict {
+		t.Errorf("duplicate create status = %d, want %d", code, http.StatusConflict)
+	}
+}
+
+func TestExampleRecordFilterFromParams(t *testing.T) {
+	limit := int32(5)
+	priority := int32(2)
+	tags := []string{"demo"}
+	from := openapi_types.Date{Time: time.Date(2025, 4, 18, 0, 0, 0, 0, time.UTC)}
+
+	filter := exampleRecordFilter(generator.ListExampleRecordsParams{
+		Limit:            &limit,
+		Priority:         &priority,
+		Tag:              &tags,
+		DesiredStartFrom: &from,
+	})
+
+	if filter.Limit != 5 || filter.Priority != 2 || len(filter.Tags) != 1 {
+		t.Errorf("unexpected filter: %+v", filter)
+	}
+	if filter.DesiredStartFrom.GetYear() != 2025 || filter.DesiredStartFrom.GetMonth() != 4 || filter.DesiredStartFrom.GetDay() != 18 {
+		t.Errorf("DesiredStartFrom = %v, want 2025-04-18", filter.DesiredStartFrom)
+	}
+	if filter.DesiredStartTo != nil {
+		t.Error("DesiredStartTo should be nil when not provided")
+	}
+}
+
+func TestCreateExampleRecordRejectsInvalidPayload(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	validator, err := events.NewValidator()
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	store := database.NewMemoryStore()
+	appLogic, _ := usecase.NewAppLogic(store, logger)
+	appLogic.SetExampleTopic("examples")
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	handler.Validator = validator
+
+	body := `{"recordId": "EX-1", "title": "", "meta": {"priority": 9, "desiredStartDate": {"year": 2025, "month": 2, "day": 30}}}`
 	req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
-	handler.CreateExampleRecord(rec, req)
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Status code = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	var problem generator.ProblemDetails
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Errors == nil {
+		t.Fatal("expected violations in the problem details")
+	}
+	fields := make([]string, 0, len(*problem.Errors))
+	for _, violation := range *problem.Errors {
+		fields = append(fields, lo.FromPtr(violation.Field))
+	}
+	want := []string{"title", "meta.desired_start_date", "meta.priority"}
+	if strings.Join(fields, ",") != strings.Join(want, ",") {
+		t.Errorf("violation fields = %v, want %v", fields, want)
+	}
+	if _, err := store.GetExampleRecordByID(context.Background(), "EX-1"); err == nil {
+		t.Error("invalid record should not be stored")
+	}
+}
+
+func TestReplacingExampleRecordRejectsInvalidResult(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	validator, err := events.NewValidator()
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	store := database.NewMemoryStore()
+	_ = store.StoreExampleRecord(context.Background(), &domain.ExampleRecord{RecordId: "EX-1", Title: "Valid"})
+	appLogic, _ := usecase.NewAppLogic(store, logger)
+	appLogic.SetValidator(validator)
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+
+	testCases := []struct {
+		method string
+		serve  func(w http.ResponseWriter, r *http.Request)
+		body   string
+		field  string
+	}{
+		{http.MethodPut, func(w http.ResponseWriter, r *http.Request) { handler.UpdateExampleRecord(w, r, "EX-1") }, `{"title": ""}`, "title"},
+		{http.MethodPatch, func(w http.ResponseWriter, r *http.Request) { handler.PatchExampleRecord(w, r, "EX-1") }, `{"meta": {"priority": 9}}`, "meta.priority"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.method, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tc.serve(rec, httptest.NewRequest(tc.method, "/examples/EX-1", strings.NewReader(tc.body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("Status code = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			if !strings.Contains(rec.Body.String(), `"field":"`+tc.field+`"`) {
+				t.Errorf("expected a violation of %s, got %s", tc.field, rec.Body.String())
+			}
+			if stored, _ := store.GetExampleRecordByID(context.Background(), "EX-1"); stored.GetTitle() != "Valid" || stored.GetVersion() != 0 {
+				t.Errorf("stored = %v, want the record unchanged", stored)
+			}
+		})
+	}
+}
+
+func TestGetExampleRecordStatus(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	appLogic, _ := usecase.NewAppLogic(database.NewMemoryStore(), logger)
+	appLogic.SetExampleTopic("examples")
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	ctx := context.Background()
+
+	if err := appLogic.HandleExample(ctx, &domain.ExampleRecord{RecordId: "EX-1", Title: "Test"}); err != nil {
+		t.Fatalf("HandleExample() error = %v", err)
+	}
+	if _, err := appLogic.TransitionExampleStatus(ctx, "EX-1", domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_IN_PROGRESS, "processing"); err != nil {
+		t.Fatalf("TransitionExampleStatus() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.GetExampleRecordStatus(rec, httptest.NewRequest(http.MethodGet, "/examples/EX-1/status", nil), "EX-1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status code = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var lifecycle generator.ExampleRecordLifecycle
+	if err := json.Unmarshal(rec.Body.Bytes(), &lifecycle); err != nil {
+		t.Fatalf("decode lifecycle: %v", err)
+	}
+	if lifecycle.Status != generator.EXAMPLERECORDSTATUSINPROGRESS || len(lifecycle.History) != 2 {
+		t.Fatalf("lifecycle = %+v, want in progress after two transitions", lifecycle)
+	}
+	last := lifecycle.History[1]
+	if last.From != generator.EXAMPLERECORDSTATUSQUEUED || lo.FromPtr(last.Reason) != "processing" {
+		t.Errorf("last transition = %+v", last)
+	}
+	if lifecycle.UpdatedAt == nil || !lifecycle.UpdatedAt.Equal(last.OccurredAt) {
+		t.Errorf("UpdatedAt = %v, want %v", lifecycle.UpdatedAt, last.OccurredAt)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.GetExampleRecordStatus(rec, httptest.NewRequest(http.MethodGet, "/examples/missing/status", nil), "missing")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestListExampleRecordResults(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	store := database.NewMemoryStore()
+	appLogic, _ := usecase.NewAppLogic(store, logger)
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	ctx := context.Background()
+
+	if err := store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: "EX-2", Title: "No results yet"}); err != nil {
+		t.Fatalf("StoreExampleRecord() error = %v", err)
+	}
+	storedAt := time.Date(2025, 4, 18, 9, 30, 0, 0, time.UTC)
+	for _, result := range []*database.StoredExampleResult{
+		{ID: "01A", RecordID: "EX-1", CorrelationID: "corr-1", Status: "completed", Note: "first run", ProcessedOn: &date.Date{Year: 2025, Month: 4, Day: 18}, StoredAt: storedAt},
+		{ID: "01B", RecordID: "EX-1", CorrelationID: "corr-2", Status: "completed", StoredAt: storedAt},
+	} {
+		if err := store.StoreExampleResult(ctx, result); err != nil {
+			t.Fatalf("StoreExampleResult() error = %v", err)
+		}
+	}
+
+	list := func(id string, params generator.ListExampleRecordResultsParams) (*httptest.ResponseRecorder, generator.ExampleResultList) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ListExampleRecordResults(rec, httptest.NewRequest(http.MethodGet, "/examples/"+id+"/results", nil), id, params)
+		var page generator.ExampleResultList
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+				t.Fatalf("decode results: %v", err)
+			}
+		}
+		return rec, page
+	}
+
+	rec, page := list("EX-1", generator.ListExampleRecordResultsParams{})
+	if rec.Code != http.StatusOK || len(page.Items) != 2 {
+		t.Fatalf("results = %d %+v, want two results", rec.Code, page)
+	}
+	first := page.Items[1]
+	if page.Items[0].Id != "01B" || lo.FromPtr(first.CorrelationId) != "corr-1" || lo.FromPtr(first.Note) != "first run" {
+		t.Errorf("results = %+v, want the newest first", page.Items)
+	}
+	if first.ProcessedOn == nil || first.ProcessedOn.String() != "2025-04-18" || !first.StoredAt.Equal(storedAt) {
+		t.Errorf("first result = %+v", first)
+	}
 
-	if rec.Code != http.StatusCreated {
-		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusCreated)
+	_, page = list("EX-1", generator.ListExampleRecordResultsParams{CorrelationId: lo.ToPtr("corr-2")})
+	if len(page.Items) != 1 || page.Items[0].Id != "01B" {
+		t.Errorf("results of corr-2 = %+v", page.Items)
 	}
 
-	// Check response body contains expected fields
-	responseBody := rec.Body.String()
-	if !strings.Contains(responseBody, "queued") {
-		t.Error("Response should contain 'queued' status")
+	rec, page = list("EX-2", generator.ListExampleRecordResultsParams{})
+	if rec.Code != http.StatusOK || len(page.Items) != 0 {
+		t.Errorf("results of a record without results = %d %+v, want an empty list", rec.Code, page)
 	}
-	if !strings.Contains(responseBody, "example event accepted") {
-		t.Error("Response should contain success message")
+	if rec, _ = list("missing", generator.ListExampleRecordResultsParams{}); rec.Code != http.StatusNotFound {
+		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusNotFound)
 	}
 }
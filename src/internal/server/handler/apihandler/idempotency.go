@@ -0,0 +1,131 @@
+package apihandler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"drblury/event-driven-service/internal/domain"
+	"drblury/event-driven-service/internal/usecase"
+)
+
+const (
+	// maxIdempotencyKeyLength bounds the Idempotency-Key header.
+	maxIdempotencyKeyLength = 255
+	// idempotencyRetryAfter is the delay suggested while the first request with a key
+	// is in flight.
+	idempotencyRetryAfter = "1"
+)
+
+// replayedHeaders are the response headers stored with an idempotent response.
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// idempotent serves the request through handle once per idempotency key. Retries
+// with the same key and body get the stored response with Idempotent-Replayed set;
+// the same key with another body fails with 422 and a retry racing the first
+// request with 409. Server errors are not stored, so the request can be retried.
+func (ah *APIHandler) idempotent(w http.ResponseWriter, r *http.Request, key string, handle http.HandlerFunc) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		ah.HandleErrors(w, r, fmt.Errorf("idempotency key must have 1 to %d characters: %w", maxIdempotencyKeyLength, domain.ErrorBadRequest), "Invalid idempotency key")
+		return
+	}
+	if ah.AppLogic == nil {
+		ah.HandleInternalServerError(w, r, errors.New("application logic not configured"), "idempotent requests unavailable")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		ah.HandleErrors(w, r, fmt.Errorf("reading request body: %w", domain.ErrorBadRequest), "Invalid request body")
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	owner, replay, err := ah.AppLogic.BeginIdempotentRequest(r.Context(), key, requestFingerprint(r, body))
+	if err != nil {
+		if errors.Is(err, usecase.ErrIdempotencyKeyInFlight) {
+			w.Header().Set("Retry-After", idempotencyRetryAfter)
+		}
+		ah.HandleErrors(w, r, err, "Idempotency key rejected")
+		return
+	}
+	if replay != nil {
+		for name, value := range replay.Header {
+			w.Header().Set(name, value)
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(replay.StatusCode)
+		_, _ = w.Write(replay.Body)
+		return
+	}
+
+	// The outcome is recorded even when the client went away in the meantime.
+	ctx := context.WithoutCancel(r.Context())
+	rec := &responseCapture{ResponseWriter: w}
+	completed := false
+	defer func() {
+		if !completed {
+			if err := ah.AppLogic.AbortIdempotentRequest(ctx, key, owner); err != nil {
+				ah.log.Warn("Releasing idempotency key failed", "error", err)
+			}
+		}
+	}()
+
+	handle(rec, r)
+	if rec.status() >= http.StatusInternalServerError {
+		return
+	}
+	header := map[string]string{}
+	for _, name := range replayedHeaders {
+		if value := rec.Header().Get(name); value != "" {
+			header[name] = value
+		}
+	}
+	if err := ah.AppLogic.CompleteIdempotentRequest(ctx, key, owner, rec.status(), header, rec.body.Bytes()); err != nil {
+		ah.log.Warn("Storing idempotent response failed", "error", err)
+		return
+	}
+	completed = true
+}
+
+// requestFingerprint identifies a request by its method, path and body.
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.Path)
+	_, _ = hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseCapture passes a response through while keeping its status and body.
+type responseCapture struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(code int) {
+	if c.code == 0 {
+		c.code = code
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *responseCapture) Write(p []byte) (int, error) {
+	if c.code == 0 {
+		c.code = http.StatusOK
+	}
+	c.body.Write(p)
+	return c.ResponseWriter.Write(p)
+}
+
+func (c *responseCapture) status() int {
+	if c.code == 0 {
+		return http.StatusOK
+	}
+	return c.code
+}
@@ -0,0 +1,119 @@
+package apihandler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+	generator "drblury/event-driven-service/internal/server/gen"
+	"drblury/event-driven-service/internal/usecase"
+
+	"github.com/samber/lo"
+)
+
+func TestCreateExampleRecordIdempotencyKey(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	store := database.NewMemoryStore()
+	appLogic, _ := usecase.NewAppLogic(store, logger)
+	appLogic.SetExampleTopic("examples")
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	mux := generator.Handler(handler)
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := post("key-1", `{"recordId":"EX-1","title":"Test"}`)
+	if first.Code != http.StatusAccepted || first.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("first POST = %d, %v: %s", first.Code, first.Header(), first.Body.String())
+	}
+
+	// With the default reject policy a second submission would conflict.
+	retry := post("key-1", `{"recordId":"EX-1","title":"Test"}`)
+	if retry.Code != http.StatusAccepted || retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("retry = %d, %v; want the replayed 202", retry.Code, retry.Header())
+	}
+	if retry.Body.String() != first.Body.String() || retry.Header().Get("Location") != first.Header().Get("Location") {
+		t.Errorf("replayed response = %s %q, want %s %q", retry.Body.String(), retry.Header().Get("Location"),
+			first.Body.String(), first.Header().Get("Location"))
+	}
+	if retry.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("replayed Content-Type = %q, want %q", retry.Header().Get("Content-Type"), first.Header().Get("Content-Type"))
+	}
+
+	if rec := post("key-1", `{"recordId":"EX-2","title":"Other"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key with other body = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	if rec := post(strings.Repeat("k", maxIdempotencyKeyLength+1), `{"recordId":"EX-3","title":"Test"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("oversized key = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := post("", `{"recordId":"EX-1","title":"Test"}`); rec.Code != http.StatusConflict {
+		t.Errorf("POST without key = %d, want the duplicate to conflict", rec.Code)
+	}
+
+	if _, _, err := appLogic.BeginIdempotentRequest(context.Background(), "key-2", "other request"); err != nil {
+		t.Fatalf("BeginIdempotentRequest() error = %v", err)
+	}
+	rec := post("key-2", `{"recordId":"EX-4","title":"Test"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("key claimed by another request = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestCreateExampleRecordIdempotencyInFlight(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	appLogic, _ := usecase.NewAppLogic(database.NewMemoryStore(), logger)
+	appLogic.SetExampleTopic("examples")
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+
+	body := `{"recordId":"EX-1","title":"Test"}`
+	blocked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan int)
+	go func() {
+		req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.idempotent(rec, req, "key-1", func(w http.ResponseWriter, r *http.Request) {
+			close(blocked)
+			<-release
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		done <- rec.Code
+	}()
+	<-blocked
+
+	req := httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{IdempotencyKey: lo.ToPtr("key-1")})
+	if rec.Code != http.StatusConflict || rec.Header().Get("Retry-After") != idempotencyRetryAfter {
+		t.Errorf("concurrent duplicate = %d, Retry-After %q; want 409", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	close(release)
+	if code := <-done; code != http.StatusInternalServerError {
+		t.Fatalf("first request = %d, want %d", code, http.StatusInternalServerError)
+	}
+
+	// A failed request is not stored, so the retry is processed.
+	req = httptest.NewRequest(http.MethodPost, "/examples", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.CreateExampleRecord(rec, req, generator.CreateExampleRecordParams{IdempotencyKey: lo.ToPtr("key-1")})
+	if rec.Code != http.StatusAccepted || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("retry after failure = %d, %v; want a processed 202", rec.Code, rec.Header())
+	}
+}
@@ -0,0 +1,83 @@
+package apihandler
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	generator "drblury/event-driven-service/internal/server/gen"
+	"drblury/event-driven-service/internal/usecase"
+
+	"github.com/samber/lo"
+)
+
+const (
+	// maxPreferWait caps Prefer: wait, so long polls end well before the server timeout.
+	maxPreferWait = 30 * time.Second
+	// operationRetryAfter is the polling interval suggested for pending operations.
+	operationRetryAfter = "1"
+)
+
+// GetExampleRecordOperation returns the processing operation of an example record.
+// With Prefer: wait=N it blocks until the operation is done or the wait elapsed.
+func (ah *APIHandler) GetExampleRecordOperation(w http.ResponseWriter, r *http.Request, id string, params generator.GetExampleRecordOperationParams) {
+	if !ah.ready(w, r) {
+		return
+	}
+
+	wait, ok := preferredWait(lo.FromPtr(params.Prefer))
+	operation, err := ah.AppLogic.WaitForExampleOperation(r.Context(), id, wait)
+	if err != nil {
+		ah.HandleErrors(w, r, err, "Loading example operation failed")
+		return
+	}
+
+	if ok {
+		w.Header().Set("Preference-Applied", "wait="+strconv.Itoa(int(wait/time.Second)))
+	}
+	if !operation.Done() {
+		w.Header().Set("Retry-After", operationRetryAfter)
+	}
+	ah.RespondWithJSON(w, r, http.StatusOK, exampleOperation(operation))
+}
+
+// operationLocation returns the URL of the operation resource of a record.
+func operationLocation(recordID string) string {
+	return "/examples/" + url.PathEscape(recordID) + "/operation"
+}
+
+// preferredWait extracts the wait preference of an RFC 7240 Prefer header and caps it
+// at maxPreferWait. It reports false when the header holds no valid wait preference.
+func preferredWait(prefer string) (time.Duration, bool) {
+	for _, preference := range strings.Split(prefer, ",") {
+		// Parameters after the value, separated by ';', do not apply to wait.
+		token, _, _ := strings.Cut(preference, ";")
+		name, value, found := strings.Cut(strings.TrimSpace(token), "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(name), "wait") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`))
+		if err != nil || seconds < 0 {
+			return 0, false
+		}
+		return min(time.Duration(seconds)*time.Second, maxPreferWait), true
+	}
+	return 0, false
+}
+
+// exampleOperation maps an operation onto the API representation.
+func exampleOperation(operation *usecase.ExampleOperation) generator.ExampleOperation {
+	item := generator.ExampleOperation{
+		RecordId:     operation.RecordID,
+		Status:       generator.OperationStatus(operation.Status),
+		RecordStatus: generator.ExampleRecordStatus(operation.RecordStatus.String()),
+		Reason:       lo.EmptyableToPtr(operation.Reason),
+		UpdatedAt:    lo.EmptyableToPtr(operation.UpdatedAt),
+	}
+	if operation.Result != nil {
+		item.Result = lo.ToPtr(exampleResult(operation.Result))
+	}
+	return item
+}
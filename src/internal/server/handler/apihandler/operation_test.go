@@ -0,0 +1,93 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+	generator "drblury/event-driven-service/internal/server/gen"
+	"drblury/event-driven-service/internal/usecase"
+
+	"github.com/samber/lo"
+)
+
+func TestPreferredWait(t *testing.T) {
+	tests := []struct {
+		prefer string
+		want   time.Duration
+		ok     bool
+	}{
+		{"", 0, false},
+		{"wait=5", 5 * time.Second, true},
+		{"respond-async, Wait=2", 2 * time.Second, true},
+		{`wait="3"; foo=bar`, 3 * time.Second, true},
+		{"wait=0", 0, true},
+		{"wait=3600", maxPreferWait, true},
+		{"wait=-1", 0, false},
+		{"wait=soon", 0, false},
+		{"return=minimal", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := preferredWait(tt.prefer)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("preferredWait(%q) = %s, %v; want %s, %v", tt.prefer, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetExampleRecordOperation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	appLogic, _ := usecase.NewAppLogic(database.NewMemoryStore(), logger)
+	appLogic.SetExampleTopic("examples")
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	ctx := context.Background()
+
+	if err := appLogic.HandleExample(ctx, &domain.ExampleRecord{RecordId: "EX-1", Title: "Test"}); err != nil {
+		t.Fatalf("HandleExample() error = %v", err)
+	}
+
+	get := func(prefer string) (*httptest.ResponseRecorder, generator.ExampleOperation) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		params := generator.GetExampleRecordOperationParams{Prefer: lo.EmptyableToPtr(prefer)}
+		handler.GetExampleRecordOperation(rec, httptest.NewRequest(http.MethodGet, "/examples/EX-1/operation", nil), "EX-1", params)
+		var operation generator.ExampleOperation
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &operation); err != nil {
+				t.Fatalf("decode operation: %v", err)
+			}
+		}
+		return rec, operation
+	}
+
+	rec, operation := get("")
+	if rec.Code != http.StatusOK || operation.Status != generator.Pending {
+		t.Fatalf("operation = %d %+v, want a pending operation", rec.Code, operation)
+	}
+	if rec.Header().Get("Retry-After") != operationRetryAfter || rec.Header().Get("Preference-Applied") != "" {
+		t.Errorf("headers = %v, want Retry-After without Preference-Applied", rec.Header())
+	}
+
+	for _, status := range []domain.ExampleRecordStatus{
+		domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_IN_PROGRESS,
+		domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_COMPLETED,
+	} {
+		if _, err := appLogic.TransitionExampleStatus(ctx, "EX-1", status, "test"); err != nil {
+			t.Fatalf("TransitionExampleStatus() error = %v", err)
+		}
+	}
+	rec, operation = get("wait=10")
+	if operation.Status != generator.Succeeded || operation.RecordStatus != generator.EXAMPLERECORDSTATUSCOMPLETED {
+		t.Errorf("operation = %+v, want a succeeded operation", operation)
+	}
+	if rec.Header().Get("Preference-Applied") != "wait=10" || rec.Header().Get("Retry-After") != "" {
+		t.Errorf("headers = %v, want Preference-Applied without Retry-After", rec.Header())
+	}
+}
@@ -0,0 +1,71 @@
+package apihandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+	generator "drblury/event-driven-service/internal/server/gen"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/samber/lo"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	problemTypeBaseURL = "https://httpstatuses.io"
+)
+
+// HandleErrors extends the responder's error handling with the violations of
+// domain.ErrValidations, which are listed in the errors member of the problem
+// document. Every other error is handled by the responder.
+func (ah *APIHandler) HandleErrors(w http.ResponseWriter, r *http.Request, err error, msgs ...string) {
+	var validationErr domain.ErrValidations
+	if !errors.As(err, &validationErr) {
+		ah.InfoHandler.HandleErrors(w, r, err, msgs...)
+		return
+	}
+	ah.respondWithViolations(w, r, err, validationErr.Errors, msgs)
+}
+
+func (ah *APIHandler) respondWithViolations(w http.ResponseWriter, r *http.Request, err error, violations []domain.Violation, msgs []string) {
+	status := http.StatusBadRequest
+	now := time.Now().UTC()
+	problem := generator.ProblemDetails{
+		Type:      lo.ToPtr(fmt.Sprintf("%s/%d", problemTypeBaseURL, status)),
+		Title:     http.StatusText(status),
+		Status:    int32(status),
+		Detail:    lo.ToPtr(err.Error()),
+		Instance:  lo.ToPtr(r.URL.RequestURI()),
+		TraceId:   lo.ToPtr(ulid.Make().String()),
+		Timestamp: &now,
+		Errors: lo.ToPtr(lo.Map(violations, func(v domain.Violation, _ int) generator.Violation {
+			return generator.Violation{
+				Field:   lo.EmptyableToPtr(v.Field),
+				Rule:    lo.EmptyableToPtr(v.Rule),
+				Message: v.Message,
+				Value:   v.Value,
+			}
+		})),
+	}
+
+	logger := ah.Logger().With("error", err.Error(), "traceId", *problem.TraceId, "status", status)
+	if len(msgs) > 0 {
+		logger = logger.With("logMessages", msgs)
+	}
+	logger.InfoContext(r.Context(), "Validation failed")
+
+	body, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		ah.HandleInternalServerError(w, r, marshalErr, "encoding validation problem failed")
+		return
+	}
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(status)
+	if _, writeErr := w.Write(append(body, '\n')); writeErr != nil {
+		logger.Error("failed to write response", "error", writeErr)
+	}
+}
@@ -0,0 +1,81 @@
+package apihandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"drblury/event-driven-service/internal/domain"
+	generator "drblury/event-driven-service/internal/server/gen"
+)
+
+func TestHandleErrorsListsViolations(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	handler := NewAPIHandler(nil, &domain.Info{Version: "1.0.0"}, logger, "", "")
+
+	err := fmt.Errorf("handle example: %w", domain.ErrValidations{Errors: []domain.Violation{
+		{Field: "meta.priority", Rule: "int32.lte", Message: "value must be less than or equal to 5", Value: int32(7)},
+		{Message: "record must have a title or a description"},
+	}})
+	req := httptest.NewRequest(http.MethodPost, "/examples?dry_run=true", nil)
+	rec := httptest.NewRecorder()
+
+	handler.HandleErrors(rec, req, err, "Example processing failed")
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Status code = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", got)
+	}
+
+	var problem generator.ProblemDetails
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Instance == nil || *problem.Instance != "/examples?dry_run=true" {
+		t.Errorf("Instance = %v, want the request URI", problem.Instance)
+	}
+	if problem.TraceId == nil || *problem.TraceId == "" {
+		t.Error("TraceId should be set")
+	}
+	if problem.Errors == nil || len(*problem.Errors) != 2 {
+		t.Fatalf("Errors = %v, want 2 violations", problem.Errors)
+	}
+	first := (*problem.Errors)[0]
+	if first.Field == nil || *first.Field != "meta.priority" || first.Rule == nil || *first.Rule != "int32.lte" {
+		t.Errorf("first violation = %+v, want field and rule", first)
+	}
+	if first.Value != float64(7) {
+		t.Errorf("first violation value = %v, want 7", first.Value)
+	}
+	second := (*problem.Errors)[1]
+	if second.Field != nil || second.Rule != nil {
+		t.Errorf("second violation = %+v, want no field or rule", second)
+	}
+}
+
+func TestHandleErrorsDelegatesOtherErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	handler := NewAPIHandler(nil, &domain.Info{Version: "1.0.0"}, logger, "", "")
+
+	req := httptest.NewRequest(http.MethodGet, "/examples/missing", nil)
+	rec := httptest.NewRecorder()
+	handler.HandleErrors(rec, req, errors.Join(errors.New("lookup"), domain.ErrorNotFound))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Status code = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	var problem generator.ProblemDetails
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Errors != nil {
+		t.Errorf("Errors = %v, want none", *problem.Errors)
+	}
+}
@@ -0,0 +1,41 @@
+package apihandler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// protoJSON renders proto messages with the lowerCamelCase field names used by the OpenAPI spec.
+var protoJSON = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// readProtoBody decodes the request body into msg. Both lowerCamelCase and proto
+// field names are accepted.
+func (ah *APIHandler) readProtoBody(w http.ResponseWriter, r *http.Request, msg proto.Message) bool {
+	if r.Body == nil {
+		ah.HandleBadRequestError(w, r, io.ErrUnexpectedEOF, "failed to parse request body")
+		return false
+	}
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = protojson.Unmarshal(body, msg)
+	}
+	if err != nil {
+		ah.HandleBadRequestError(w, r, err, "failed to parse request body")
+		return false
+	}
+	return true
+}
+
+// respondWithRecord writes msg as JSON using the proto field mapping.
+func (ah *APIHandler) respondWithRecord(w http.ResponseWriter, r *http.Request, status int, msg proto.Message) {
+	body, err := protoJSON.Marshal(msg)
+	if err != nil {
+		ah.HandleInternalServerError(w, r, err, "failed to encode response")
+		return
+	}
+	ah.RespondWithJSON(w, r, status, json.RawMessage(body))
+}
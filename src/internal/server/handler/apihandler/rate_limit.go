@@ -0,0 +1,49 @@
+package apihandler
+
+import (
+	"fmt"
+	"net/http"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+// RateLimit is a middleware for the generated operations that takes a token from
+// the bucket of the client and operation, see ratelimit.Limiter. It runs after
+// Authenticate, so authenticated callers are limited per API key or principal and
+// anonymous ones per IP address. Requests Authenticate denies never reach it and
+// are charged to the bucket of their IP address there instead, so clients trying
+// credentials are limited too. Every response carries the RateLimit-* headers;
+// exhausted clients get 429 with Retry-After. When the store fails requests pass,
+// so an unavailable database does not take the API down. Without Limiter every
+// request passes.
+func (ah *APIHandler) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ah.limit(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// limit takes a token for r and reports whether it may be served. It sets the
+// RateLimit-* headers and answers exhausted clients with 429 itself.
+func (ah *APIHandler) limit(w http.ResponseWriter, r *http.Request) bool {
+	if ah.Limiter == nil {
+		return true
+	}
+	var operation string
+	if ah.Policy != nil {
+		operation, _ = ah.Policy.Operation(r)
+	}
+
+	decision, err := ah.Limiter.Take(r, operation)
+	if err != nil {
+		ah.log.Warn("Rate limit store unavailable, request passes unlimited", "error", err)
+		return true
+	}
+	decision.SetHeaders(w.Header())
+	if !decision.Allowed {
+		ah.HandleErrors(w, r, fmt.Errorf("%w: limit of %s exceeded", domain.ErrorTooManyRequests, decision.Limit), "Rate limit exceeded")
+		return false
+	}
+	return true
+}
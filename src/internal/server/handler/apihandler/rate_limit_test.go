@@ -0,0 +1,143 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+	"drblury/event-driven-service/internal/ratelimit"
+	generator "drblury/event-driven-service/internal/server/gen"
+	"drblury/event-driven-service/internal/usecase"
+)
+
+func TestRateLimit(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	store := database.NewMemoryStore()
+	appLogic, _ := usecase.NewAppLogic(store, logger)
+	appLogic.SetExampleTopic("examples")
+	_, billing, err := appLogic.IssueAPIKey(context.Background(), "billing", []string{"examples:read", "examples:write"}, nil)
+	if err != nil {
+		t.Fatalf("IssueAPIKey() error = %v", err)
+	}
+	_, shipping, err := appLogic.IssueAPIKey(context.Background(), "shipping", []string{"examples:write"}, nil)
+	if err != nil {
+		t.Fatalf("IssueAPIKey() error = %v", err)
+	}
+
+	swagger, err := generator.GetSwagger()
+	if err != nil {
+		t.Fatalf("GetSwagger() error = %v", err)
+	}
+	policy, err := auth.NewPolicy(swagger)
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+	limiter, err := ratelimit.New(&ratelimit.Config{
+		Enabled: true,
+		Default: ratelimit.Limit{Requests: 2, Period: time.Minute},
+		Routes:  map[string]ratelimit.Limit{"CreateExampleRecord": {Requests: 1, Period: time.Minute}},
+	}, ratelimit.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("ratelimit.New() error = %v", err)
+	}
+
+	handler := NewAPIHandler(appLogic, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	handler.APIKeys = auth.NewAPIKeyAuthenticator(store)
+	handler.Policy = policy
+	handler.Limiter = limiter
+	mux := generator.HandlerWithOptions(handler, generator.StdHTTPServerOptions{
+		Middlewares: []generator.MiddlewareFunc{handler.RateLimit, handler.Authenticate},
+	})
+
+	serve := func(method, target, key, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(auth.APIKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/examples", billing, `{"recordId":"EX-1","title":"Test"}`)
+	if rec.Code != http.StatusAccepted || rec.Header().Get("RateLimit-Limit") != "1" || rec.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("first POST = %d, %v", rec.Code, rec.Header())
+	}
+	rec = serve(http.MethodPost, "/examples", billing, `{"recordId":"EX-2","title":"Test"}`)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("second POST = %d, Retry-After %q; want 429", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(rec.Header().Get("Content-Type"), "problem+json") {
+		t.Errorf("429 Content-Type = %q, want a problem document", rec.Header().Get("Content-Type"))
+	}
+	if _, err := store.GetExampleRecordByID(context.Background(), "EX-2"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("limited request was processed, GetExampleRecordByID() error = %v", err)
+	}
+
+	if rec := serve(http.MethodPost, "/examples", shipping, `{"recordId":"EX-3","title":"Test"}`); rec.Code != http.StatusAccepted {
+		t.Errorf("POST with another key = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	rec = serve(http.MethodGet, "/examples", billing, "")
+	if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "2" {
+		t.Errorf("GET /examples = %d, limit %q; want the default bucket", rec.Code, rec.Header().Get("RateLimit-Limit"))
+	}
+
+	// Denied requests are charged to the address of the caller.
+	rec = serve(http.MethodPost, "/examples", "not-a-key", `{"recordId":"EX-4","title":"Test"}`)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("RateLimit-Remaining") != "0" {
+		t.Errorf("POST with invalid key = %d, %v; want 401 with the RateLimit headers", rec.Code, rec.Header())
+	}
+	if rec := serve(http.MethodPost, "/examples", "not-a-key", `{"recordId":"EX-4","title":"Test"}`); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("second POST with invalid key = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+
+	// Anonymous callers share the bucket of their address.
+	for range 2 {
+		if rec := serve(http.MethodGet, "/info/version", "", ""); rec.Code != http.StatusOK {
+			t.Fatalf("GET /info/version = %d, want %d", rec.Code, http.StatusOK)
+		}
+	}
+	if rec := serve(http.MethodGet, "/healthz", "", ""); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("third anonymous request = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+}
+
+// failingCounter is a shared counter whose database is unreachable.
+type failingCounter struct{}
+
+func (failingCounter) TakeRateLimitToken(context.Context, string, int, time.Duration, time.Time) (float64, bool, error) {
+	return 0, false, errors.New("database unreachable")
+}
+
+func TestRateLimitPassesWhenStoreFails(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	limiter, err := ratelimit.New(&ratelimit.Config{
+		Enabled: true,
+		Default: ratelimit.Limit{Requests: 1, Period: time.Minute},
+	}, ratelimit.NewSharedStore(failingCounter{}))
+	if err != nil {
+		t.Fatalf("ratelimit.New() error = %v", err)
+	}
+	handler := NewAPIHandler(nil, &domain.Info{Version: "1.0.0"}, logger, "", "")
+	handler.Limiter = limiter
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	for range 2 {
+		rec := httptest.NewRecorder()
+		handler.RateLimit(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/info/version", nil))
+		if rec.Code != http.StatusNoContent || rec.Header().Get("RateLimit-Limit") != "" {
+			t.Errorf("request with failing store = %d, %v; want it passed unlimited", rec.Code, rec.Header())
+		}
+	}
+}
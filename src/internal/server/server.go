@@ -24,6 +24,11 @@ func NewServer(cfg *Config, mux http.Handler) *Server {
 	}
 }
 
+// Handler returns the handler that serves the requests.
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
 func (s *Server) ListenAndServe() error {
 	return s.server.ListenAndServe()
 }
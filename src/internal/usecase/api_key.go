@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// IssueAPIKey creates an API key for owner that grants scopes until expiresAt, or
+// indefinitely when expiresAt is nil. Only the hash of the key is stored, so the
+// returned key cannot be retrieved again.
+func (a *AppLogic) IssueAPIKey(ctx context.Context, owner string, scopes []string, expiresAt *time.Time) (*database.StoredAPIKey, string, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, "", err
+	}
+	if err := a.authenticated(ctx); err != nil {
+		return nil, "", err
+	}
+
+	owner = strings.TrimSpace(owner)
+	if owner == "" {
+		return nil, "", fmt.Errorf("api key owner is required: %w", domain.ErrorBadRequest)
+	}
+	for _, scope := range scopes {
+		if scope == "" || strings.ContainsFunc(scope, unicode.IsSpace) {
+			return nil, "", fmt.Errorf("api key scope %q must be a non-empty word: %w", scope, domain.ErrorBadRequest)
+		}
+	}
+	now := time.Now().UTC()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", fmt.Errorf("api key expiry must be in the future: %w", domain.ErrorBadRequest)
+	}
+
+	key, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	stored := &database.StoredAPIKey{
+		ID:        ulid.Make().String(),
+		Hash:      auth.HashAPIKey(key),
+		Owner:     owner,
+		Scopes:    slices.Compact(scopes),
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	if err := a.db.StoreAPIKey(ctx, stored); err != nil {
+		return nil, "", err
+	}
+	return stored, key, nil
+}
+
+// ListAPIKeys returns the issued keys of owner, or all keys for an empty owner,
+// oldest first.
+func (a *AppLogic) ListAPIKeys(ctx context.Context, owner string) ([]*database.StoredAPIKey, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, err
+	}
+	if err := a.authenticated(ctx); err != nil {
+		return nil, err
+	}
+	return a.db.ListAPIKeys(ctx, owner)
+}
+
+// RevokeAPIKey revokes the key with the given ID. Revoked keys stay listed but no
+// longer authenticate; revoking a key twice succeeds.
+func (a *AppLogic) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := a.requireDatabase(); err != nil {
+		return err
+	}
+	if err := a.authenticated(ctx); err != nil {
+		return err
+	}
+	return a.db.RevokeAPIKey(ctx, id, time.Now())
+}
+
+// APIKeyAuthenticator returns an authenticator accepting the keys issued through
+// IssueAPIKey.
+func (a *AppLogic) APIKeyAuthenticator() (*auth.APIKeyAuthenticator, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, err
+	}
+	return auth.NewAPIKeyAuthenticator(a.db), nil
+}
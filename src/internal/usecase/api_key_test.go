@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+)
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := database.NewMemoryStore()
+	logic, _ := NewAppLogic(store, nil)
+
+	expiresAt := time.Now().Add(24 * time.Hour).UTC()
+	stored, key, err := logic.IssueAPIKey(ctx, " billing ", []string{"examples:write", "examples:read", "examples:write"}, &expiresAt)
+	if err != nil {
+		t.Fatalf("IssueAPIKey() error = %v", err)
+	}
+	if stored.ID == "" || stored.Owner != "billing" || !reflect.DeepEqual(stored.Scopes, []string{"examples:read", "examples:write"}) {
+		t.Errorf("issued key = %+v", stored)
+	}
+	if stored.Hash != auth.HashAPIKey(key) || stored.Hash == key {
+		t.Error("issued key is not stored as its hash")
+	}
+
+	principal, err := auth.NewAPIKeyAuthenticator(store).Authenticate(ctx, key)
+	if err != nil || principal.Subject != "billing" {
+		t.Fatalf("Authenticate() = %+v, %v", principal, err)
+	}
+
+	keys, err := logic.ListAPIKeys(ctx, "billing")
+	if err != nil || len(keys) != 1 || keys[0].ID != stored.ID {
+		t.Fatalf("ListAPIKeys() = %+v, %v", keys, err)
+	}
+
+	if err := logic.RevokeAPIKey(ctx, stored.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	if _, err := auth.NewAPIKeyAuthenticator(store).Authenticate(ctx, key); !errors.Is(err, domain.ErrorUnauthorized) {
+		t.Errorf("Authenticate(revoked) error = %v, want unauthorized", err)
+	}
+	if err := logic.RevokeAPIKey(ctx, "unknown"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("RevokeAPIKey(unknown) error = %v, want not found", err)
+	}
+}
+
+func TestIssueAPIKeyValidation(t *testing.T) {
+	ctx := context.Background()
+	logic, _ := NewAppLogic(database.NewMemoryStore(), nil)
+	past := time.Now().Add(-time.Minute)
+
+	tests := map[string]struct {
+		owner     string
+		scopes    []string
+		expiresAt *time.Time
+	}{
+		"missing owner":  {owner: " "},
+		"empty scope":    {owner: "billing", scopes: []string{""}},
+		"scope with tab": {owner: "billing", scopes: []string{"examples:read\tadmin"}},
+		"expired":        {owner: "billing", expiresAt: &past},
+	}
+	for name, tt := range tests {
+		if _, _, err := logic.IssueAPIKey(ctx, tt.owner, tt.scopes, tt.expiresAt); !errors.Is(err, domain.ErrorBadRequest) {
+			t.Errorf("%s: IssueAPIKey() error = %v, want bad request", name, err)
+		}
+	}
+
+	logic.RequireAuthentication(true)
+	if _, _, err := logic.IssueAPIKey(ctx, "billing", nil, nil); !errors.Is(err, domain.ErrorUnauthorized) {
+		t.Errorf("IssueAPIKey() without principal error = %v, want unauthorized", err)
+	}
+}
@@ -3,68 +3,261 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"drblury/event-driven-service/internal/database"
 	"drblury/event-driven-service/internal/domain"
 
 	"github.com/drblury/protoflow"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// HandleExample persists the received example payload. Token handling is left as an
-// exercise for service integrators so the sample stays vendor-neutral.
-//
-// NOTE: The token parameter is intentionally unused in this reference implementation.
-// Integrators should implement proper token validation (e.g., JWT verification,
-// API key validation) based on their authentication requirements.
-func (a *AppLogic) HandleExample(ctx context.Context, record *domain.ExampleRecord, token string) error {
+// HandleExample persists the received example payload together with the event that
+// announces it. The caller is authenticated by the HTTP layer; when authentication is
+// required ctx must carry the verified principal.
+func (a *AppLogic) HandleExample(ctx context.Context, record *domain.ExampleRecord) error {
 	if a == nil {
 		return errors.New("applogic is nil")
 	}
 	if record == nil {
 		return errors.New("example payload is required")
 	}
-	// TODO: Implement token validation for your authentication scheme.
-	// Example: if err := validateToken(ctx, token); err != nil { return err }
-	_ = token
+	if err := a.authenticated(ctx); err != nil {
+		return err
+	}
+
+	// The lifecycle is owned by the service, so whatever the client sent is replaced.
+	record.Status = domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_UNSPECIFIED
+	record.StatusHistory = nil
+	if err := transitionStatus(record, domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED, StatusReasonSubmitted, time.Now()); err != nil {
+		return err
+	}
 
 	if a.db == nil {
 		return nil
 	}
 
-	// we can do something with the event here or just store it...
-	err := a.emitExampleEvent(ctx, record)
+	// The record and its event are committed together. The outbox relay publishes the
+	// event afterwards, so consumers never observe a record that was not stored.
+	msg, err := a.newExampleOutboxMessage(record)
 	if err != nil {
 		return err
 	}
-
-	return a.db.StoreExampleRecord(ctx, record)
+	return a.db.StoreExampleRecordWithOutbox(ctx, record, msg)
 }
 
-// EmitExampleEvent publishes the example payload so downstream processors can pick it up.
+// exampleOutboxHandler names the outbox rows of submitted example records. The
+// MongoDB store keeps them in the <handler>_outbox collection, so the name must
+// not change between releases.
+const exampleOutboxHandler = "example_records"
+
+// newExampleOutboxMessage encodes the example payload as an outbox row for the example
+// topic, using the same envelope as protoflow's PublishProto.
 // This method acquires a read lock to safely access shared configuration.
-func (a *AppLogic) emitExampleEvent(ctx context.Context, record *domain.ExampleRecord) error {
+func (a *AppLogic) newExampleOutboxMessage(record *domain.ExampleRecord) (*database.OutboxMessage, error) {
 	if a == nil {
-		return errors.New("applogic is nil")
+		return nil, errors.New("applogic is nil")
 	}
 	if record == nil {
-		return errors.New("example payload is required")
+		return nil, errors.New("example payload is required")
 	}
 
-	// Acquire read lock to safely access shared fields
 	a.mu.RLock()
 	topic := a.exampleTopic
-	producer := a.eventProducer
 	a.mu.RUnlock()
 
 	if topic == "" {
-		return errors.New("example topic not configured")
+		return nil, errors.New("example topic not configured")
+	}
+
+	payload, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	return &database.OutboxMessage{
+		Handler: exampleOutboxHandler,
+		UUID:    protoflow.CreateULID(),
+		Payload: string(payload),
+		Topic:   topic,
+		Metadata: map[string]string{
+			"source":                         "api.examples",
+			protoflow.MetadataKeyEventSchema: fmt.Sprintf("%T", record),
+		},
+	}, nil
+}
+
+// GetExample returns the stored example record with the given ID.
+func (a *AppLogic) GetExample(ctx context.Context, id string) (*domain.ExampleRecord, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, err
+	}
+	return a.db.GetExampleRecordByID(ctx, id)
+}
+
+// ListExamples returns one page of stored example records and the cursor of the next page.
+func (a *AppLogic) ListExamples(ctx context.Context, filter database.ExampleRecordFilter) ([]*domain.ExampleRecord, string, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, "", err
+	}
+	return a.db.ListExampleRecords(ctx, filter)
+}
+
+// ListExampleResults returns the stored processing results matching filter, newest
+// first. When there are none, the record must exist, so unknown IDs are reported as
+// not found.
+func (a *AppLogic) ListExampleResults(ctx context.Context, filter database.ExampleResultFilter) ([]*database.StoredExampleResult, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, err
+	}
+	results, err := a.db.ListExampleResults(ctx, filter)
+	if err != nil || len(results) > 0 {
+		return results, err
+	}
+	if _, err := a.db.GetExampleRecordByID(ctx, filter.RecordID); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// UpdateExample replaces the stored example record with the given ID. The status and
+// status history of the stored record are kept and its version is bumped.
+func (a *AppLogic) UpdateExample(ctx context.Context, id string, record *domain.ExampleRecord) (*domain.ExampleRecord, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, errors.New("example payload is required")
 	}
-	if producer == nil {
-		return errors.New("event producer not configured")
+	if err := checkRecordID(record, id); err != nil {
+		return nil, err
 	}
 
-	metadata := protoflow.Metadata{
-		"source": "api.examples",
+	return a.changeExample(ctx, id, func(current *domain.ExampleRecord) (*domain.ExampleRecord, error) {
+		return a.validReplacement(id, record, current)
+	})
+}
+
+// PatchExample applies a JSON merge patch (RFC 7396) to the stored example record.
+func (a *AppLogic) PatchExample(ctx context.Context, id string, patch []byte) (*domain.ExampleRecord, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, err
+	}
+
+	return a.changeExample(ctx, id, func(current *domain.ExampleRecord) (*domain.ExampleRecord, error) {
+		patched, err := applyMergePatch(current, patch)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRecordID(patched, id); err != nil {
+			return nil, err
+		}
+		return a.validReplacement(id, patched, current)
+	})
+}
+
+// validReplacement prepares record to be stored in place of current and validates
+// the result.
+func (a *AppLogic) validReplacement(id string, record, current *domain.ExampleRecord) (*domain.ExampleRecord, error) {
+	record.RecordId = id
+	keepLifecycle(record, current)
+	if err := a.validateRecord(record); err != nil {
+		return nil, err
 	}
+	return record, nil
+}
+
+// maxChangeAttempts bounds how often changeExample reapplies a change to a record
+// that other writers keep changing.
+const maxChangeAttempts = 5
+
+// changeExample reads the stored record with the given ID, derives its successor
+// through change and stores it with the next version. The write is conditional on
+// the version that was read; when another writer changed the record in between,
+// change is applied again to the new record. When change returns the stored record
+// itself, nothing is written.
+func (a *AppLogic) changeExample(ctx context.Context, id string, change func(current *domain.ExampleRecord) (*domain.ExampleRecord, error)) (*domain.ExampleRecord, error) {
+	for attempt := 1; ; attempt++ {
+		current, err := a.db.GetExampleRecordByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		record, err := change(current)
+		if err != nil {
+			return nil, err
+		}
+		if record == current {
+			return current, nil
+		}
+		record.Version = current.GetVersion() + 1
+
+		err = a.db.ReplaceExampleRecordVersion(ctx, id, current.GetVersion(), record)
+		if errors.Is(err, database.ErrExampleRecordVersionConflict) && attempt < maxChangeAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+}
+
+// checkRecordID rejects a record whose ID is set and differs from the path id.
+func checkRecordID(record *domain.ExampleRecord, id string) error {
+	if record.GetRecordId() != "" && record.GetRecordId() != id {
+		return fmt.Errorf("record id %q does not match path id %q: %w", record.GetRecordId(), id, domain.ErrorBadRequest)
+	}
+	return nil
+}
 
-	return producer.PublishProto(ctx, topic, record, metadata)
+// ApplyExampleRecordUpdate applies a field-mask update to the stored record, bumps its
+// version and returns the stored result. A result that violates the record rules is
+// reported as domain.ErrValidations and not stored.
+func (a *AppLogic) ApplyExampleRecordUpdate(ctx context.Context, update *domain.ExampleRecordUpdate) (*domain.ExampleRecord, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, err
+	}
+	id := update.GetRecordId()
+	if id == "" {
+		return nil, fmt.Errorf("record id is required: %w", domain.ErrorBadRequest)
+	}
+
+	return a.changeExample(ctx, id, func(current *domain.ExampleRecord) (*domain.ExampleRecord, error) {
+		patched, err := applyFieldMask(current, update.GetUpdates(), update.GetUpdateMask())
+		if err != nil {
+			return nil, err
+		}
+		// The updated record is processed again, so it is queued unless it still waits
+		// for or is in processing.
+		keepLifecycle(patched, current)
+		if CanTransition(patched.GetStatus(), domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED) {
+			if err := transitionStatus(patched, domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED, StatusReasonUpdated, time.Now()); err != nil {
+				return nil, err
+			}
+		}
+		if err := a.validateRecord(patched); err != nil {
+			return nil, err
+		}
+		return patched, nil
+	})
+}
+
+// DeleteExample removes the stored example record with the given ID.
+func (a *AppLogic) DeleteExample(ctx context.Context, id string) error {
+	if err := a.requireDatabase(); err != nil {
+		return err
+	}
+	return a.db.DeleteExampleRecord(ctx, id)
+}
+
+// requireDatabase reports an error when no database is wired into the app logic.
+func (a *AppLogic) requireDatabase() error {
+	if a == nil {
+		return errors.New("applogic is nil")
+	}
+	if a.db == nil {
+		return errors.New("database not configured")
+	}
+	return nil
 }
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"drblury/event-driven-service/internal/domain"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// immutableMaskFields are managed by the service and cannot be patched.
+var immutableMaskFields = map[string]bool{"record_id": true, "version": true, "status": true, "status_history": true}
+
+// applyFieldMask copies the fields named by mask from updates into a copy of record.
+// Paths use proto field names and may address nested fields, e.g. "meta.priority".
+// A named field that is unset in updates is cleared. Without a mask, every populated
+// top-level field of updates is applied.
+func applyFieldMask(record *domain.ExampleRecord, updates *domain.ExampleRecord, mask *fieldmaskpb.FieldMask) (*domain.ExampleRecord, error) {
+	if updates == nil {
+		updates = &domain.ExampleRecord{}
+	}
+	// Cloned so that lists and messages copied into the result are not shared.
+	updates = proto.CloneOf(updates)
+
+	paths := mask.GetPaths()
+	if len(paths) == 0 {
+		paths = populatedFieldPaths(updates)
+	}
+
+	result := proto.CloneOf(record)
+	for _, path := range paths {
+		segments := strings.Split(path, ".")
+		if immutableMaskFields[segments[0]] {
+			return nil, fmt.Errorf("field %q cannot be updated: %w", path, domain.ErrorBadRequest)
+		}
+		if err := copyFieldPath(result.ProtoReflect(), updates.ProtoReflect(), segments); err != nil {
+			return nil, fmt.Errorf("invalid update mask path %q: %v: %w", path, err, domain.ErrorBadRequest)
+		}
+	}
+	return result, nil
+}
+
+// copyFieldPath copies the field at path from src to dst, descending into messages.
+func copyFieldPath(dst protoreflect.Message, src protoreflect.Message, path []string) error {
+	field := dst.Descriptor().Fields().ByName(protoreflect.Name(path[0]))
+	if field == nil {
+		return fmt.Errorf("unknown field %q", path[0])
+	}
+
+	if len(path) == 1 {
+		if src.Has(field) {
+			dst.Set(field, src.Get(field))
+		} else {
+			dst.Clear(field)
+		}
+		return nil
+	}
+
+	if field.Message() == nil || field.IsList() || field.IsMap() {
+		return fmt.Errorf("field %q has no subfields", path[0])
+	}
+	return copyFieldPath(dst.Mutable(field).Message(), src.Get(field).Message(), path[1:])
+}
+
+// populatedFieldPaths lists the patchable top-level fields that are set in msg.
+func populatedFieldPaths(msg *domain.ExampleRecord) []string {
+	var paths []string
+	msg.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if name := string(field.Name()); !immutableMaskFields[name] {
+			paths = append(paths, name)
+		}
+		return true
+	})
+	return paths
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestApplyFieldMask(t *testing.T) {
+	record := &domain.ExampleRecord{
+		RecordId:    "EX-1",
+		Title:       "Original",
+		Description: "drop me",
+		Tags:        []string{"a"},
+		Meta:        &domain.ExampleMeta{RequestedBy: "bot", Priority: 3},
+	}
+	updates := &domain.ExampleRecord{
+		Title: "Renamed",
+		Tags:  []string{"ignored"},
+		Meta:  &domain.ExampleMeta{Priority: 1},
+	}
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"title", "description", "meta.priority"}}
+
+	patched, err := applyFieldMask(record, updates, mask)
+	if err != nil {
+		t.Fatalf("applyFieldMask() error = %v", err)
+	}
+
+	if patched.GetTitle() != "Renamed" {
+		t.Errorf("Title = %q, want 'Renamed'", patched.GetTitle())
+	}
+	if patched.GetDescription() != "" {
+		t.Errorf("Description = %q, want masked unset field to be cleared", patched.GetDescription())
+	}
+	if len(patched.GetTags()) != 1 || patched.GetTags()[0] != "a" {
+		t.Errorf("Tags = %v, want unmasked field to be kept", patched.GetTags())
+	}
+	if patched.GetMeta().GetPriority() != 1 || patched.GetMeta().GetRequestedBy() != "bot" {
+		t.Errorf("Meta = %v, want patched priority and kept requester", patched.GetMeta())
+	}
+	if record.GetTitle() != "Original" {
+		t.Error("applyFieldMask must not modify the input record")
+	}
+}
+
+func TestApplyFieldMaskWithoutMask(t *testing.T) {
+	record := &domain.ExampleRecord{RecordId: "EX-1", Title: "Original", Description: "keep me", Version: 4}
+	updates := &domain.ExampleRecord{RecordId: "EX-2", Title: "Renamed", Version: 9}
+
+	patched, err := applyFieldMask(record, updates, nil)
+	if err != nil {
+		t.Fatalf("applyFieldMask() error = %v", err)
+	}
+	if patched.GetTitle() != "Renamed" || patched.GetDescription() != "keep me" {
+		t.Errorf("patched = %v, want only populated fields applied", patched)
+	}
+	if patched.GetRecordId() != "EX-1" || patched.GetVersion() != 4 {
+		t.Errorf("patched = %v, want record id and version untouched", patched)
+	}
+}
+
+func TestApplyFieldMaskInvalidPaths(t *testing.T) {
+	record := &domain.ExampleRecord{RecordId: "EX-1"}
+
+	for _, path := range []string{"unknown", "title.length", "tags.first", "record_id", "version", "status", "status_history"} {
+		_, err := applyFieldMask(record, &domain.ExampleRecord{}, &fieldmaskpb.FieldMask{Paths: []string{path}})
+		if !errors.Is(err, domain.ErrorBadRequest) {
+			t.Errorf("path %q: error = %v, want ErrorBadRequest", path, err)
+		}
+	}
+}
+
+func TestApplyExampleRecordUpdateBumpsVersion(t *testing.T) {
+	ctx := context.Background()
+	store := database.NewMemoryStore()
+	if err := store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: "EX-1", Title: "Original"}); err != nil {
+		t.Fatalf("StoreExampleRecord() error = %v", err)
+	}
+	logic, _ := NewAppLogic(store, nil)
+
+	update := &domain.ExampleRecordUpdate{
+		RecordId:   "EX-1",
+		Updates:    &domain.ExampleRecord{Title: "Renamed"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	}
+	for want := int32(1); want <= 2; want++ {
+		updated, err := logic.ApplyExampleRecordUpdate(ctx, update)
+		if err != nil {
+			t.Fatalf("ApplyExampleRecordUpdate() error = %v", err)
+		}
+		if updated.GetVersion() != want {
+			t.Errorf("Version = %d, want %d", updated.GetVersion(), want)
+		}
+	}
+
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	if stored.GetTitle() != "Renamed" || stored.GetVersion() != 2 {
+		t.Errorf("stored = %v, want renamed record at version 2", stored)
+	}
+}
+
+// racingStore changes the record behind the back of the first reader, as a concurrent
+// writer would between its read and its replace.
+type racingStore struct {
+	*database.MemoryStore
+	once sync.Once
+}
+
+func (s *racingStore) GetExampleRecordByID(ctx context.Context, id string) (*domain.ExampleRecord, error) {
+	record, err := s.MemoryStore.GetExampleRecordByID(ctx, id)
+	s.once.Do(func() {
+		racer := proto.Clone(record).(*domain.ExampleRecord)
+		racer.Description = "racer"
+		racer.Version++
+		_ = s.ReplaceExampleRecordVersion(ctx, id, record.GetVersion(), racer)
+	})
+	return record, err
+}
+
+func TestApplyExampleRecordUpdateRetriesConcurrentChange(t *testing.T) {
+	ctx := context.Background()
+	store := &racingStore{MemoryStore: database.NewMemoryStore()}
+	_ = store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: "EX-1", Title: "Original"})
+	logic, _ := NewAppLogic(store, nil)
+
+	_, err := logic.ApplyExampleRecordUpdate(ctx, &domain.ExampleRecordUpdate{
+		RecordId:   "EX-1",
+		Updates:    &domain.ExampleRecord{Title: "Renamed"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	if err != nil {
+		t.Fatalf("ApplyExampleRecordUpdate() error = %v", err)
+	}
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	if stored.GetTitle() != "Renamed" || stored.GetDescription() != "racer" || stored.GetVersion() != 2 {
+		t.Errorf("stored = %v, want both changes at version 2", stored)
+	}
+}
+
+func TestApplyExampleRecordUpdateConcurrently(t *testing.T) {
+	ctx := context.Background()
+	store := database.NewMemoryStore()
+	_ = store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: "EX-1", Title: "Original"})
+	logic, _ := NewAppLogic(store, nil)
+
+	const writers = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	applied := 0
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := logic.ApplyExampleRecordUpdate(ctx, &domain.ExampleRecordUpdate{
+				RecordId:   "EX-1",
+				Updates:    &domain.ExampleRecord{Title: "Renamed"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+			})
+			switch {
+			case err == nil:
+				mu.Lock()
+				applied++
+				mu.Unlock()
+			case !errors.Is(err, domain.ErrorConflict):
+				t.Errorf("ApplyExampleRecordUpdate() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every update that reported success bumped the version once; none was lost.
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	if applied == 0 || stored.GetVersion() != int32(applied) {
+		t.Errorf("version = %d after %d applied updates", stored.GetVersion(), applied)
+	}
+}
+
+func TestApplyExampleRecordUpdateErrors(t *testing.T) {
+	logic, _ := NewAppLogic(database.NewMemoryStore(), nil)
+
+	if _, err := logic.ApplyExampleRecordUpdate(context.Background(), &domain.ExampleRecordUpdate{}); !errors.Is(err, domain.ErrorBadRequest) {
+		t.Errorf("missing record id: error = %v, want ErrorBadRequest", err)
+	}
+	_, err := logic.ApplyExampleRecordUpdate(context.Background(), &domain.ExampleRecordUpdate{RecordId: "missing"})
+	if !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("unknown record: error = %v, want ErrorNotFound", err)
+	}
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+)
+
+// DefaultIdempotencyTTL is how long the response of a request sent with an
+// idempotency key is replayed unless SetIdempotencyTTL configures otherwise.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencyLease is how long a key stays blocked for the request in flight. A
+// request outliving it no longer blocks retries.
+var idempotencyLease = time.Minute
+
+var (
+	// ErrIdempotencyKeyReused is returned when a key is sent with a different request
+	// than the one it was first used for.
+	ErrIdempotencyKeyReused = fmt.Errorf("idempotency key was used for a different request: %w", domain.ErrorUnprocessable)
+	// ErrIdempotencyKeyInFlight is returned while the first request with a key is
+	// still being processed.
+	ErrIdempotencyKeyInFlight = fmt.Errorf("a request with this idempotency key is in progress: %w", domain.ErrorConflict)
+)
+
+// SetIdempotencyTTL configures how long responses of requests with an idempotency
+// key are replayed. Zero restores DefaultIdempotencyTTL. This method is thread-safe.
+func (a *AppLogic) SetIdempotencyTTL(ttl time.Duration) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.idempotencyTTL = ttl
+}
+
+// IdempotencyTTL returns how long responses of idempotent requests are replayed.
+// This method is thread-safe.
+func (a *AppLogic) IdempotencyTTL() time.Duration {
+	if a == nil {
+		return DefaultIdempotencyTTL
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.idempotencyTTL <= 0 {
+		return DefaultIdempotencyTTL
+	}
+	return a.idempotencyTTL
+}
+
+// BeginIdempotentRequest claims key for the request identified by fingerprint. When
+// the caller should process the request, it returns the owner token that completes
+// or aborts it; when the request was processed before, the stored response. A key
+// reused for another request fails with ErrIdempotencyKeyReused, a key whose
+// request is still processed with ErrIdempotencyKeyInFlight.
+//
+// Keys are scoped to the authenticated caller, so callers cannot replay each
+// other's responses.
+func (a *AppLogic) BeginIdempotentRequest(ctx context.Context, key string, fingerprint string) (string, *database.IdempotencyRecord, error) {
+	if err := a.requireDatabase(); err != nil {
+		return "", nil, err
+	}
+	record, claimed, err := a.db.ClaimIdempotencyKey(ctx, idempotencyScope(ctx, key), fingerprint, time.Now(), idempotencyLease)
+	if err != nil {
+		return "", nil, err
+	}
+	switch {
+	case claimed:
+		return record.Owner, nil, nil
+	case record.Fingerprint != fingerprint:
+		return "", nil, ErrIdempotencyKeyReused
+	case record.Status != database.IdempotencyStatusCompleted:
+		return "", nil, ErrIdempotencyKeyInFlight
+	}
+	return "", record, nil
+}
+
+// CompleteIdempotentRequest stores the response of the request that claimed key as
+// owner, to be replayed for retries until the idempotency TTL elapses. Nothing is
+// stored once the lease expired and another request claimed the key.
+func (a *AppLogic) CompleteIdempotentRequest(
+	ctx context.Context,
+	key string,
+	owner string,
+	statusCode int,
+	header map[string]string,
+	body []byte,
+) error {
+	if err := a.requireDatabase(); err != nil {
+		return err
+	}
+	return a.db.CompleteIdempotencyKey(ctx, &database.IdempotencyRecord{
+		Key:        idempotencyScope(ctx, key),
+		Owner:      owner,
+		StatusCode: statusCode,
+		Header:     header,
+		Body:       body,
+		ExpiresAt:  time.Now().Add(a.IdempotencyTTL()).UTC(),
+	})
+}
+
+// AbortIdempotentRequest releases the key claimed as owner by a request that failed,
+// so a retry processes it again. The lease of a request that claimed the key after
+// owner's lease expired is kept.
+func (a *AppLogic) AbortIdempotentRequest(ctx context.Context, key string, owner string) error {
+	if err := a.requireDatabase(); err != nil {
+		return err
+	}
+	return a.db.ReleaseIdempotencyKey(ctx, idempotencyScope(ctx, key), owner)
+}
+
+// idempotencyScope is the stored key of key sent by the caller in ctx.
+func idempotencyScope(ctx context.Context, key string) string {
+	var issuer, subject string
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		issuer, subject = principal.Issuer, principal.Subject
+	}
+	sum := sha256.Sum256([]byte(issuer + "\x00" + subject + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+)
+
+func TestIdempotentRequest(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Issuer: "api-key", Subject: "billing"})
+	logic, _ := NewAppLogic(database.NewMemoryStore(), nil)
+
+	owner, replay, err := logic.BeginIdempotentRequest(ctx, "key-1", "fp-1")
+	if owner == "" || replay != nil || err != nil {
+		t.Fatalf("first BeginIdempotentRequest() = %q, %+v, %v; want a claim", owner, replay, err)
+	}
+	if _, _, err := logic.BeginIdempotentRequest(ctx, "key-1", "fp-1"); !errors.Is(err, ErrIdempotencyKeyInFlight) || !errors.Is(err, domain.ErrorConflict) {
+		t.Errorf("retry in flight error = %v, want ErrIdempotencyKeyInFlight", err)
+	}
+	if _, _, err := logic.BeginIdempotentRequest(ctx, "key-1", "fp-2"); !errors.Is(err, ErrIdempotencyKeyReused) || !errors.Is(err, domain.ErrorUnprocessable) {
+		t.Errorf("other request error = %v, want ErrIdempotencyKeyReused", err)
+	}
+
+	other := auth.WithPrincipal(context.Background(), &auth.Principal{Issuer: "api-key", Subject: "shipping"})
+	if _, replay, err := logic.BeginIdempotentRequest(other, "key-1", "fp-2"); replay != nil || err != nil {
+		t.Errorf("key of another caller = %+v, %v; want a claim", replay, err)
+	}
+
+	header := map[string]string{"Content-Type": "application/json"}
+	if err := logic.CompleteIdempotentRequest(ctx, "key-1", owner, 201, header, []byte(`{}`)); err != nil {
+		t.Fatalf("CompleteIdempotentRequest() error = %v", err)
+	}
+	_, replay, err = logic.BeginIdempotentRequest(ctx, "key-1", "fp-1")
+	if err != nil || replay == nil || replay.StatusCode != 201 || string(replay.Body) != `{}` {
+		t.Fatalf("retry after completion = %+v, %v; want the stored response", replay, err)
+	}
+	if replay.ExpiresAt.Before(time.Now().Add(DefaultIdempotencyTTL - time.Minute)) {
+		t.Errorf("stored response expires at %v, want about %v from now", replay.ExpiresAt, DefaultIdempotencyTTL)
+	}
+	if _, _, err := logic.BeginIdempotentRequest(ctx, "key-1", "fp-2"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("completed key with other request error = %v, want ErrIdempotencyKeyReused", err)
+	}
+
+	owner, _, err = logic.BeginIdempotentRequest(ctx, "key-2", "fp-1")
+	if err != nil {
+		t.Fatalf("BeginIdempotentRequest(key-2) error = %v", err)
+	}
+	if err := logic.AbortIdempotentRequest(ctx, "key-2", owner); err != nil {
+		t.Fatalf("AbortIdempotentRequest() error = %v", err)
+	}
+	retry, replay, err := logic.BeginIdempotentRequest(ctx, "key-2", "fp-2")
+	if replay != nil || err != nil {
+		t.Errorf("retry after abort = %+v, %v; want a claim", replay, err)
+	}
+	// The aborted request no longer owns the key, so aborting again keeps the retry's lease.
+	_ = logic.AbortIdempotentRequest(ctx, "key-2", owner)
+	if _, _, err := logic.BeginIdempotentRequest(ctx, "key-2", "fp-2"); !errors.Is(err, ErrIdempotencyKeyInFlight) {
+		t.Errorf("retry after a stale abort error = %v, want ErrIdempotencyKeyInFlight", err)
+	}
+	if err := logic.AbortIdempotentRequest(ctx, "key-2", retry); err != nil {
+		t.Errorf("AbortIdempotentRequest() of the retry error = %v", err)
+	}
+}
+
+func TestIdempotencyTTL(t *testing.T) {
+	logic, _ := NewAppLogic(database.NewMemoryStore(), nil)
+	if got := logic.IdempotencyTTL(); got != DefaultIdempotencyTTL {
+		t.Errorf("default IdempotencyTTL() = %v, want %v", got, DefaultIdempotencyTTL)
+	}
+	logic.SetIdempotencyTTL(time.Hour)
+	if got := logic.IdempotencyTTL(); got != time.Hour {
+		t.Errorf("IdempotencyTTL() = %v, want 1h", got)
+	}
+
+	var nilLogic *AppLogic
+	if _, _, err := nilLogic.BeginIdempotentRequest(context.Background(), "key", "fp"); err == nil {
+		t.Error("BeginIdempotentRequest() on nil logic should fail")
+	}
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"drblury/event-driven-service/internal/domain"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Reasons recorded in the status history by the service itself.
+const (
+	StatusReasonSubmitted = "submitted"
+	StatusReasonUpdated   = "updated"
+)
+
+// ErrInvalidStatusTransition is returned when a record cannot move to the requested status.
+var ErrInvalidStatusTransition = fmt.Errorf("invalid status transition: %w", domain.ErrorConflict)
+
+// statusTransitions lists the statuses a record may move to from each status.
+var statusTransitions = map[domain.ExampleRecordStatus][]domain.ExampleRecordStatus{
+	domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_UNSPECIFIED: {
+		domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED,
+	},
+	domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED: {
+		domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_IN_PROGRESS,
+		domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_FAILED,
+	},
+	domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_IN_PROGRESS: {
+		domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_COMPLETED,
+		domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_FAILED,
+	},
+	domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_COMPLETED: {
+		domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED,
+	},
+	domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_FAILED: {
+		domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED,
+	},
+}
+
+// CanTransition reports whether a record in status from may move to status to.
+func CanTransition(from, to domain.ExampleRecordStatus) bool {
+	return slices.Contains(statusTransitions[from], to)
+}
+
+// TransitionExampleStatus moves the stored record with the given ID to status to,
+// appends the change to its status history and bumps its version. A record that
+// already has status to is returned unchanged, so redelivered events do not fail.
+func (a *AppLogic) TransitionExampleStatus(ctx context.Context, id string, to domain.ExampleRecordStatus, reason string) (*domain.ExampleRecord, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, err
+	}
+
+	return a.changeExample(ctx, id, func(current *domain.ExampleRecord) (*domain.ExampleRecord, error) {
+		if current.GetStatus() == to {
+			return current, nil
+		}
+		record := proto.Clone(current).(*domain.ExampleRecord)
+		if err := transitionStatus(record, to, reason, time.Now()); err != nil {
+			return nil, fmt.Errorf("record %q: %w", id, err)
+		}
+		return record, nil
+	})
+}
+
+// transitionStatus sets the status of record to to and records the change at time at.
+func transitionStatus(record *domain.ExampleRecord, to domain.ExampleRecordStatus, reason string, at time.Time) error {
+	from := record.GetStatus()
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w from %s to %s", ErrInvalidStatusTransition, from, to)
+	}
+	record.Status = to
+	record.StatusHistory = append(record.StatusHistory, &domain.StatusTransition{
+		From:       from,
+		To:         to,
+		OccurredAt: timestamppb.New(at),
+		Reason:     reason,
+	})
+	return nil
+}
+
+// keepLifecycle copies the status and history of current onto record, which replaces
+// it. Clients cannot change the lifecycle through record payloads.
+func keepLifecycle(record, current *domain.ExampleRecord) {
+	record.Status = current.GetStatus()
+	record.StatusHistory = current.GetStatusHistory()
+}
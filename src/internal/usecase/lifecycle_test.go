@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	statusQueued     = domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED
+	statusInProgress = domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_IN_PROGRESS
+	statusCompleted  = domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_COMPLETED
+	statusFailed     = domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_FAILED
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to domain.ExampleRecordStatus
+		want     bool
+	}{
+		{domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_UNSPECIFIED, statusQueued, true},
+		{statusQueued, statusInProgress, true},
+		{statusQueued, statusFailed, true},
+		{statusInProgress, statusCompleted, true},
+		{statusInProgress, statusFailed, true},
+		{statusCompleted, statusQueued, true},
+		{statusFailed, statusQueued, true},
+		{statusQueued, statusCompleted, false},
+		{statusCompleted, statusInProgress, false},
+		{statusFailed, statusCompleted, false},
+		{statusInProgress, statusQueued, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+// newLifecycleLogic returns app logic over a store holding one submitted record.
+func newLifecycleLogic(t *testing.T) (*AppLogic, *database.MemoryStore) {
+	t.Helper()
+	store := database.NewMemoryStore()
+	logic, _ := NewAppLogic(store, nil)
+	logic.SetExampleTopic("examples")
+	record := &domain.ExampleRecord{RecordId: "EX-1", Title: "first", Status: statusCompleted}
+	if err := logic.HandleExample(context.Background(), record); err != nil {
+		t.Fatalf("HandleExample() error = %v", err)
+	}
+	return logic, store
+}
+
+func assertHistory(t *testing.T, record *domain.ExampleRecord, want ...domain.ExampleRecordStatus) {
+	t.Helper()
+	history := record.GetStatusHistory()
+	if len(history) != len(want) {
+		t.Fatalf("history = %v, want %d transitions", history, len(want))
+	}
+	for i, transition := range history {
+		if transition.GetTo() != want[i] {
+			t.Errorf("transition %d to %s, want %s", i, transition.GetTo(), want[i])
+		}
+		if i > 0 && transition.GetFrom() != history[i-1].GetTo() {
+			t.Errorf("transition %d from %s, want %s", i, transition.GetFrom(), history[i-1].GetTo())
+		}
+		if transition.GetOccurredAt() == nil {
+			t.Errorf("transition %d has no timestamp", i)
+		}
+	}
+	if record.GetStatus() != want[len(want)-1] {
+		t.Errorf("status = %s, want %s", record.GetStatus(), want[len(want)-1])
+	}
+}
+
+func TestHandleExampleQueuesRecord(t *testing.T) {
+	_, store := newLifecycleLogic(t)
+
+	stored, err := store.GetExampleRecordByID(context.Background(), "EX-1")
+	if err != nil {
+		t.Fatalf("GetExampleRecordByID() error = %v", err)
+	}
+	// The client-provided status is ignored.
+	assertHistory(t, stored, statusQueued)
+	if reason := stored.GetStatusHistory()[0].GetReason(); reason != StatusReasonSubmitted {
+		t.Errorf("reason = %q, want %q", reason, StatusReasonSubmitted)
+	}
+}
+
+func TestTransitionExampleStatus(t *testing.T) {
+	logic, store := newLifecycleLogic(t)
+	ctx := context.Background()
+
+	for _, to := range []domain.ExampleRecordStatus{statusInProgress, statusInProgress, statusCompleted} {
+		if _, err := logic.TransitionExampleStatus(ctx, "EX-1", to, "test"); err != nil {
+			t.Fatalf("TransitionExampleStatus(%s) error = %v", to, err)
+		}
+	}
+	_, err := logic.TransitionExampleStatus(ctx, "EX-1", statusFailed, "test")
+	if !errors.Is(err, ErrInvalidStatusTransition) || !errors.Is(err, domain.ErrorConflict) {
+		t.Errorf("completed to failed: error = %v, want ErrInvalidStatusTransition", err)
+	}
+	if _, err := logic.TransitionExampleStatus(ctx, "missing", statusInProgress, "test"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("unknown record: error = %v, want ErrorNotFound", err)
+	}
+
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	assertHistory(t, stored, statusQueued, statusInProgress, statusCompleted)
+}
+
+func TestTransitionExampleStatusKeepsConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := &racingStore{MemoryStore: database.NewMemoryStore()}
+	_ = store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: "EX-1", Title: "Original", Status: statusQueued})
+	logic, _ := NewAppLogic(store, nil)
+
+	// The racer replaces the record between the read and the write of the transition.
+	if _, err := logic.TransitionExampleStatus(ctx, "EX-1", statusInProgress, "test"); err != nil {
+		t.Fatalf("TransitionExampleStatus() error = %v", err)
+	}
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	if stored.GetDescription() != "racer" || stored.GetStatus() != statusInProgress || stored.GetVersion() != 2 {
+		t.Errorf("stored = %v, want the racer's change in progress at version 2", stored)
+	}
+}
+
+func TestReplacingRecordKeepsLifecycle(t *testing.T) {
+	logic, store := newLifecycleLogic(t)
+	ctx := context.Background()
+
+	if _, err := logic.UpdateExample(ctx, "EX-1", &domain.ExampleRecord{Title: "replaced", Status: statusFailed}); err != nil {
+		t.Fatalf("UpdateExample() error = %v", err)
+	}
+	if _, err := logic.PatchExample(ctx, "EX-1", []byte(`{"title": "patched", "status": "EXAMPLE_RECORD_STATUS_COMPLETED"}`)); err != nil {
+		t.Fatalf("PatchExample() error = %v", err)
+	}
+
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	if stored.GetTitle() != "patched" {
+		t.Errorf("title = %q, want patched", stored.GetTitle())
+	}
+	assertHistory(t, stored, statusQueued)
+}
+
+func TestReplacingFinishedRecordDoesNotRequeue(t *testing.T) {
+	logic, store := newLifecycleLogic(t)
+	ctx := context.Background()
+	for _, to := range []domain.ExampleRecordStatus{statusInProgress, statusCompleted} {
+		if _, err := logic.TransitionExampleStatus(ctx, "EX-1", to, "test"); err != nil {
+			t.Fatalf("TransitionExampleStatus(%s) error = %v", to, err)
+		}
+	}
+	queued := len(store.OutboxMessages(exampleOutboxHandler))
+
+	// Only example.record.updated events queue a finished record again.
+	if _, err := logic.UpdateExample(ctx, "EX-1", &domain.ExampleRecord{Title: "replaced"}); err != nil {
+		t.Fatalf("UpdateExample() error = %v", err)
+	}
+	if _, err := logic.PatchExample(ctx, "EX-1", []byte(`{"title": "patched"}`)); err != nil {
+		t.Fatalf("PatchExample() error = %v", err)
+	}
+
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	assertHistory(t, stored, statusQueued, statusInProgress, statusCompleted)
+	if got := len(store.OutboxMessages(exampleOutboxHandler)); got != queued {
+		t.Errorf("outbox rows = %d, want %d", got, queued)
+	}
+}
+
+func TestApplyExampleRecordUpdateRequeuesRecord(t *testing.T) {
+	logic, store := newLifecycleLogic(t)
+	ctx := context.Background()
+	update := &domain.ExampleRecordUpdate{
+		RecordId:   "EX-1",
+		Updates:    &domain.ExampleRecord{Title: "renamed"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	}
+
+	// A queued record stays queued.
+	if _, err := logic.ApplyExampleRecordUpdate(ctx, update); err != nil {
+		t.Fatalf("ApplyExampleRecordUpdate() error = %v", err)
+	}
+	for _, to := range []domain.ExampleRecordStatus{statusInProgress, statusCompleted} {
+		if _, err := logic.TransitionExampleStatus(ctx, "EX-1", to, "test"); err != nil {
+			t.Fatalf("TransitionExampleStatus(%s) error = %v", to, err)
+		}
+	}
+	updated, err := logic.ApplyExampleRecordUpdate(ctx, update)
+	if err != nil {
+		t.Fatalf("ApplyExampleRecordUpdate() error = %v", err)
+	}
+	assertHistory(t, updated, statusQueued, statusInProgress, statusCompleted, statusQueued)
+
+	stored, _ := store.GetExampleRecordByID(ctx, "EX-1")
+	assertHistory(t, stored, statusQueued, statusInProgress, statusCompleted, statusQueued)
+	if reason := stored.GetStatusHistory()[3].GetReason(); reason != StatusReasonUpdated {
+		t.Errorf("reason = %q, want %q", reason, StatusReasonUpdated)
+	}
+}
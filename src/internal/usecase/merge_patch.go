@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"drblury/event-driven-service/internal/domain"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// applyMergePatch merges a JSON merge patch (RFC 7396) into a copy of record. Keys may
+// use either the proto field names or their lowerCamelCase JSON names.
+func applyMergePatch(record *domain.ExampleRecord, patch []byte) (*domain.ExampleRecord, error) {
+	var patchDoc map[string]any
+	if err := json.Unmarshal(patch, &patchDoc); err != nil {
+		return nil, fmt.Errorf("patch must be a JSON object: %w", domain.ErrorBadRequest)
+	}
+
+	current, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(current, &doc); err != nil {
+		return nil, err
+	}
+
+	merged, err := json.Marshal(mergeJSON(doc, normalizePatchKeys(patchDoc)))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ExampleRecord{}
+	if err := protojson.Unmarshal(merged, result); err != nil {
+		return nil, fmt.Errorf("invalid patch: %v: %w", err, domain.ErrorBadRequest)
+	}
+	return result, nil
+}
+
+// mergeJSON implements the RFC 7396 merge algorithm for decoded JSON objects.
+func mergeJSON(target map[string]any, patch map[string]any) map[string]any {
+	if target == nil {
+		target = map[string]any{}
+	}
+	for key, value := range patch {
+		switch typed := value.(type) {
+		case nil:
+			delete(target, key)
+		case map[string]any:
+			existing, _ := target[key].(map[string]any)
+			target[key] = mergeJSON(existing, typed)
+		default:
+			target[key] = typed
+		}
+	}
+	return target
+}
+
+// normalizePatchKeys rewrites lowerCamelCase keys to proto field names so they merge
+// with the stored document, which is rendered with proto names.
+func normalizePatchKeys(patch map[string]any) map[string]any {
+	normalized := make(map[string]any, len(patch))
+	for key, value := range patch {
+		if nested, ok := value.(map[string]any); ok {
+			value = normalizePatchKeys(nested)
+		}
+		normalized[toSnakeCase(key)] = value
+	}
+	return normalized
+}
+
+// toSnakeCase converts lowerCamelCase identifiers such as recordId to record_id.
+func toSnakeCase(key string) string {
+	out := make([]byte, 0, len(key)+4)
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c >= 'A' && c <= 'Z' {
+			out = append(out, '_', c+('a'-'A'))
+			continue
+		}
+		out = append(out, c)
+	}
+	return string(out)
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"drblury/event-driven-service/internal/domain"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	record := &domain.ExampleRecord{
+		RecordId:    "EX-1",
+		Title:       "Original",
+		Description: "keep me",
+		Tags:        []string{"a", "b"},
+		Meta: &domain.ExampleMeta{
+			RequestedBy: "bot",
+			Priority:    3,
+		},
+	}
+
+	patched, err := applyMergePatch(record, []byte(`{"title":"Renamed","tags":["c"],"meta":{"priority":1},"description":null}`))
+	if err != nil {
+		t.Fatalf("applyMergePatch() error = %v", err)
+	}
+
+	if patched.GetTitle() != "Renamed" {
+		t.Errorf("Title = %q, want 'Renamed'", patched.GetTitle())
+	}
+	if patched.GetDescription() != "" {
+		t.Errorf("Description = %q, want it to be cleared", patched.GetDescription())
+	}
+	if len(patched.GetTags()) != 1 || patched.GetTags()[0] != "c" {
+		t.Errorf("Tags = %v, want [c]", patched.GetTags())
+	}
+	if patched.GetMeta().GetPriority() != 1 || patched.GetMeta().GetRequestedBy() != "bot" {
+		t.Errorf("Meta = %v, want merged priority and kept requester", patched.GetMeta())
+	}
+	if record.GetTitle() != "Original" {
+		t.Error("applyMergePatch must not modify the input record")
+	}
+}
+
+func TestApplyMergePatchAcceptsCamelCase(t *testing.T) {
+	record := &domain.ExampleRecord{RecordId: "EX-1", Meta: &domain.ExampleMeta{RequestedBy: "bot"}}
+
+	patched, err := applyMergePatch(record, []byte(`{"meta":{"requestedBy":"human","requiresFollowUp":true}}`))
+	if err != nil {
+		t.Fatalf("applyMergePatch() error = %v", err)
+	}
+	if patched.GetMeta().GetRequestedBy() != "human" || !patched.GetMeta().GetRequiresFollowUp() {
+		t.Errorf("Meta = %v, want camelCase keys to be applied", patched.GetMeta())
+	}
+}
+
+func TestApplyMergePatchInvalid(t *testing.T) {
+	record := &domain.ExampleRecord{RecordId: "EX-1"}
+
+	testCases := map[string]string{
+		"not an object": `[1,2]`,
+		"malformed":     `{`,
+		"unknown field": `{"unknown":1}`,
+		"wrong type":    `{"title":5}`,
+	}
+
+	for name, patch := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := applyMergePatch(record, []byte(patch))
+			if !errors.Is(err, domain.ErrorBadRequest) {
+				t.Errorf("expected ErrorBadRequest, got %v", err)
+			}
+		})
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	testCases := map[string]string{
+		"recordId":         "record_id",
+		"desiredStartDate": "desired_start_date",
+		"record_id":        "record_id",
+		"title":            "title",
+	}
+	for in, want := range testCases {
+		if got := toSnakeCase(in); got != want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+)
+
+// States of the processing operation started by submitting a record.
+const (
+	OperationPending   = "pending"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+)
+
+// operationPollInterval is how often WaitForExampleOperation reloads the record.
+var operationPollInterval = 200 * time.Millisecond
+
+// ExampleOperation is the processing of a submitted record as seen by its submitter.
+// It follows the record lifecycle, so resubmitting a record starts the operation over.
+type ExampleOperation struct {
+	RecordID     string
+	Status       string
+	RecordStatus domain.ExampleRecordStatus
+	// Reason is the reason of the latest status change, the error code for failed
+	// operations.
+	Reason    string
+	UpdatedAt time.Time
+	// Result is the latest stored result of a succeeded operation, if one was stored.
+	Result *database.StoredExampleResult
+}
+
+// Done reports whether the operation succeeded or failed.
+func (o *ExampleOperation) Done() bool {
+	return o.Status != OperationPending
+}
+
+// GetExampleOperation returns the processing operation of the record with the given ID.
+func (a *AppLogic) GetExampleOperation(ctx context.Context, id string) (*ExampleOperation, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, err
+	}
+	record, err := a.db.GetExampleRecordByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := ExampleOperationOf(record)
+	if operation.Status == OperationSucceeded {
+		results, err := a.db.ListExampleResults(ctx, database.ExampleResultFilter{RecordID: id, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(results) > 0 {
+			operation.Result = results[0]
+		}
+	}
+	return operation, nil
+}
+
+// ExampleOperationOf returns the operation of record without its result.
+func ExampleOperationOf(record *domain.ExampleRecord) *ExampleOperation {
+	operation := &ExampleOperation{
+		RecordID:     record.GetRecordId(),
+		Status:       operationStatus(record.GetStatus()),
+		RecordStatus: record.GetStatus(),
+	}
+	if history := record.GetStatusHistory(); len(history) > 0 {
+		latest := history[len(history)-1]
+		operation.Reason = latest.GetReason()
+		operation.UpdatedAt = latest.GetOccurredAt().AsTime()
+	}
+	return operation
+}
+
+// WaitForExampleOperation returns the operation once it is done or wait elapsed,
+// whichever comes first. A wait of zero returns the current state right away.
+func (a *AppLogic) WaitForExampleOperation(ctx context.Context, id string, wait time.Duration) (*ExampleOperation, error) {
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(operationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		operation, err := a.GetExampleOperation(ctx, id)
+		if err != nil || operation.Done() || wait <= 0 {
+			return operation, err
+		}
+		select {
+		case <-ctx.Done():
+			return operation, nil
+		case <-deadline.C:
+			return a.GetExampleOperation(ctx, id)
+		case <-ticker.C:
+		}
+	}
+}
+
+// operationStatus maps a record status onto the state of its operation. Records
+// without a status were stored before the lifecycle existed and count as succeeded.
+func operationStatus(status domain.ExampleRecordStatus) string {
+	switch status {
+	case domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_QUEUED, domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_IN_PROGRESS:
+		return OperationPending
+	case domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_FAILED:
+		return OperationFailed
+	default:
+		return OperationSucceeded
+	}
+}
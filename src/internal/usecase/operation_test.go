@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/domain"
+)
+
+func TestGetExampleOperation(t *testing.T) {
+	logic, store := newLifecycleLogic(t)
+	ctx := context.Background()
+
+	operation, err := logic.GetExampleOperation(ctx, "EX-1")
+	if err != nil {
+		t.Fatalf("GetExampleOperation() error = %v", err)
+	}
+	if operation.Status != OperationPending || operation.Done() || operation.Reason != StatusReasonSubmitted || operation.UpdatedAt.IsZero() {
+		t.Errorf("operation = %+v, want a pending submitted operation", operation)
+	}
+
+	if err := store.StoreExampleResult(ctx, &database.StoredExampleResult{ID: "01A", RecordID: "EX-1", Status: "completed"}); err != nil {
+		t.Fatalf("StoreExampleResult() error = %v", err)
+	}
+	for _, status := range []domain.ExampleRecordStatus{statusInProgress, statusCompleted} {
+		if _, err := logic.TransitionExampleStatus(ctx, "EX-1", status, "test"); err != nil {
+			t.Fatalf("TransitionExampleStatus(%s) error = %v", status, err)
+		}
+	}
+	operation, err = logic.GetExampleOperation(ctx, "EX-1")
+	if err != nil {
+		t.Fatalf("GetExampleOperation() error = %v", err)
+	}
+	if operation.Status != OperationSucceeded || operation.Result == nil || operation.Result.ID != "01A" {
+		t.Errorf("operation = %+v, want a succeeded operation with its result", operation)
+	}
+
+	if _, err := logic.GetExampleOperation(ctx, "missing"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("GetExampleOperation(missing) error = %v, want not found", err)
+	}
+}
+
+func TestOperationStatus(t *testing.T) {
+	tests := map[domain.ExampleRecordStatus]string{
+		domain.ExampleRecordStatus_EXAMPLE_RECORD_STATUS_UNSPECIFIED: OperationSucceeded,
+		statusQueued:     OperationPending,
+		statusInProgress: OperationPending,
+		statusCompleted:  OperationSucceeded,
+		statusFailed:     OperationFailed,
+	}
+	for status, want := range tests {
+		if got := operationStatus(status); got != want {
+			t.Errorf("operationStatus(%s) = %s, want %s", status, got, want)
+		}
+	}
+}
+
+func TestWaitForExampleOperation(t *testing.T) {
+	previous := operationPollInterval
+	operationPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { operationPollInterval = previous })
+
+	logic, _ := newLifecycleLogic(t)
+	ctx := context.Background()
+
+	start := time.Now()
+	operation, err := logic.WaitForExampleOperation(ctx, "EX-1", 30*time.Millisecond)
+	if err != nil || operation.Done() {
+		t.Fatalf("WaitForExampleOperation() = %+v, %v; want a pending operation", operation, err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("returned after %s, want the full wait", elapsed)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = logic.TransitionExampleStatus(ctx, "EX-1", statusFailed, "PROCESSING_ERROR")
+	}()
+	operation, err = logic.WaitForExampleOperation(ctx, "EX-1", 5*time.Second)
+	if err != nil {
+		t.Fatalf("WaitForExampleOperation() error = %v", err)
+	}
+	if operation.Status != OperationFailed || operation.Reason != "PROCESSING_ERROR" {
+		t.Errorf("operation = %+v, want it failed with the error code", operation)
+	}
+}
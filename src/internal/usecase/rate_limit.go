@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"errors"
+
+	"drblury/event-driven-service/internal/database"
+	"drblury/event-driven-service/internal/ratelimit"
+)
+
+var _ ratelimit.SharedCounter = (*database.Database)(nil)
+
+// RateLimitCounter returns the database as counter for rate limits shared by all
+// replicas. Only MongoDB keeps shared rate limits.
+func (a *AppLogic) RateLimitCounter() (ratelimit.SharedCounter, error) {
+	if err := a.requireDatabase(); err != nil {
+		return nil, err
+	}
+	counter, ok := a.db.(ratelimit.SharedCounter)
+	if !ok {
+		return nil, errors.New("the configured database keeps no shared rate limits, use DB_DRIVER=mongo")
+	}
+	return counter, nil
+}
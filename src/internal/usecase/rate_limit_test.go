@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"testing"
+
+	"drblury/event-driven-service/internal/database"
+)
+
+func TestRateLimitCounter(t *testing.T) {
+	mongo, _ := NewAppLogic(&database.Database{}, nil)
+	if counter, err := mongo.RateLimitCounter(); counter == nil || err != nil {
+		t.Errorf("RateLimitCounter() with MongoDB = %v, %v; want the database", counter, err)
+	}
+
+	memory, _ := NewAppLogic(database.NewMemoryStore(), nil)
+	if _, err := memory.RateLimitCounter(); err == nil {
+		t.Error("RateLimitCounter() with the memory store should fail")
+	}
+	withoutDatabase, _ := NewAppLogic(nil, nil)
+	if _, err := withoutDatabase.RateLimitCounter(); err == nil {
+		t.Error("RateLimitCounter() without database should fail")
+	}
+}
@@ -3,24 +3,33 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
+	"drblury/event-driven-service/internal/auth"
 	"drblury/event-driven-service/internal/database"
-
-	"github.com/drblury/protoflow"
+	"drblury/event-driven-service/internal/domain"
 )
 
+// RecordValidator checks example records before changes to them are stored.
+type RecordValidator interface {
+	Validate(value any) error
+}
+
 type AppLogic struct {
-	db            *database.Database
-	log           *slog.Logger
-	eventProducer protoflow.Producer
-	exampleTopic  string
-	mu            sync.RWMutex // protects eventProducer and exampleTopic
+	db             database.Repository
+	log            *slog.Logger
+	exampleTopic   string
+	authRequired   bool
+	idempotencyTTL time.Duration
+	validator      RecordValidator
+	mu             sync.RWMutex // protects exampleTopic, authRequired, idempotencyTTL and validator
 }
 
 func NewAppLogic(
-	db *database.Database,
+	db database.Repository,
 	logger *slog.Logger,
 ) (*AppLogic, error) {
 
@@ -30,17 +39,6 @@ func NewAppLogic(
 	}, nil
 }
 
-// SetEventProducer wires the event producer used by PublishEvent.
-// This method is thread-safe.
-func (a *AppLogic) SetEventProducer(producer protoflow.Producer) {
-	if a == nil {
-		return
-	}
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	a.eventProducer = producer
-}
-
 // SetExampleTopic configures the queue/topic used for outgoing example events.
 // This method is thread-safe.
 func (a *AppLogic) SetExampleTopic(topic string) {
@@ -63,6 +61,51 @@ func (a *AppLogic) ExampleTopic() string {
 	return a.exampleTopic
 }
 
+// SetValidator configures the validator that replaced, patched and updated records
+// must pass before they are stored. Without one every record is accepted.
+// This method is thread-safe.
+func (a *AppLogic) SetValidator(validator RecordValidator) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.validator = validator
+}
+
+// validateRecord checks record with the configured validator.
+func (a *AppLogic) validateRecord(record *domain.ExampleRecord) error {
+	a.mu.RLock()
+	validator := a.validator
+	a.mu.RUnlock()
+	if validator == nil {
+		return nil
+	}
+	return validator.Validate(record)
+}
+
+// RequireAuthentication makes operations acting on behalf of a caller reject contexts
+// without a verified principal. This method is thread-safe.
+func (a *AppLogic) RequireAuthentication(required bool) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.authRequired = required
+}
+
+// authenticated checks that ctx carries a principal when authentication is required.
+func (a *AppLogic) authenticated(ctx context.Context) error {
+	a.mu.RLock()
+	required := a.authRequired
+	a.mu.RUnlock()
+	if _, ok := auth.PrincipalFrom(ctx); required && !ok {
+		return fmt.Errorf("%w: no verified caller", domain.ErrorUnauthorized)
+	}
+	return nil
+}
+
 // DatabaseProbe ensures the backing database remains reachable for readiness checks.
 func (a *AppLogic) DatabaseProbe(ctx context.Context) error {
 	if a == nil {
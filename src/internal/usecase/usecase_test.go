@@ -6,28 +6,14 @@ import (
 	"log/slog"
 	"testing"
 
+	"drblury/event-driven-service/internal/auth"
+	"drblury/event-driven-service/internal/database"
 	"drblury/event-driven-service/internal/domain"
 
 	"github.com/drblury/protoflow"
-	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// mockProducer implements protoflow.Producer for testing
-type mockProducer struct {
-	published     []proto.Message
-	publishErr    error
-	publishCalled bool
-}
-
-func (m *mockProducer) PublishProto(ctx context.Context, topic string, msg proto.Message, md protoflow.Metadata) error {
-	m.publishCalled = true
-	if m.publishErr != nil {
-		return m.publishErr
-	}
-	m.published = append(m.published, msg)
-	return nil
-}
-
 func TestNewAppLogic(t *testing.T) {
 	logger := slog.Default()
 
@@ -53,25 +39,6 @@ func TestNewAppLogicWithNilLogger(t *testing.T) {
 	}
 }
 
-func TestSetEventProducer(t *testing.T) {
-	logic, _ := NewAppLogic(nil, nil)
-	producer := &mockProducer{}
-
-	logic.SetEventProducer(producer)
-
-	if logic.eventProducer == nil {
-		t.Error("Event producer not set")
-	}
-}
-
-func TestSetEventProducerNilReceiver(t *testing.T) {
-	var logic *AppLogic
-	producer := &mockProducer{}
-
-	// Should not panic
-	logic.SetEventProducer(producer)
-}
-
 func TestSetExampleTopic(t *testing.T) {
 	logic, _ := NewAppLogic(nil, nil)
 	topic := "test-topic"
@@ -114,7 +81,7 @@ func TestHandleExampleNilReceiver(t *testing.T) {
 	var logic *AppLogic
 	record := &domain.ExampleRecord{}
 
-	err := logic.HandleExample(context.Background(), record, "token")
+	err := logic.HandleExample(context.Background(), record)
 	if err == nil {
 		t.Error("Expected error for nil receiver")
 	}
@@ -126,7 +93,7 @@ func TestHandleExampleNilReceiver(t *testing.T) {
 func TestHandleExampleNilRecord(t *testing.T) {
 	logic, _ := NewAppLogic(nil, nil)
 
-	err := logic.HandleExample(context.Background(), nil, "token")
+	err := logic.HandleExample(context.Background(), nil)
 	if err == nil {
 		t.Error("Expected error for nil record")
 	}
@@ -143,31 +110,24 @@ func TestHandleExampleWithoutDatabase(t *testing.T) {
 		Title:    "Test Record",
 	}
 
-	err := logic.HandleExample(context.Background(), record, "token")
+	err := logic.HandleExample(context.Background(), record)
 	if err != nil {
 		t.Errorf("HandleExample with nil db should succeed: %v", err)
 	}
 }
 
-func TestHandleExampleWithProducerNoDb(t *testing.T) {
-	// Even with producer configured, if db is nil, it returns early
+func TestHandleExampleRequiresPrincipal(t *testing.T) {
 	logic, _ := NewAppLogic(nil, nil)
-	producer := &mockProducer{}
-	logic.SetEventProducer(producer)
-	logic.SetExampleTopic("test-topic")
+	logic.RequireAuthentication(true)
 
-	record := &domain.ExampleRecord{
-		RecordId: "test-123",
-		Title:    "Test Record",
+	err := logic.HandleExample(context.Background(), &domain.ExampleRecord{RecordId: "test-123"})
+	if !errors.Is(err, domain.ErrorUnauthorized) {
+		t.Errorf("HandleExample without principal error = %v, want unauthorized", err)
 	}
 
-	err := logic.HandleExample(context.Background(), record, "token")
-	if err != nil {
-		t.Errorf("HandleExample with nil db should succeed even with producer: %v", err)
-	}
-	// Producer should NOT be called because db is nil (early return)
-	if producer.publishCalled {
-		t.Error("Producer should not be called when db is nil")
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "client-1"})
+	if err := logic.HandleExample(ctx, &domain.ExampleRecord{RecordId: "test-123"}); err != nil {
+		t.Errorf("HandleExample with principal error = %v", err)
 	}
 }
 
@@ -195,91 +155,151 @@ func TestDatabaseProbeNilDatabase(t *testing.T) {
 	}
 }
 
-func TestEmitExampleEventNilReceiver(t *testing.T) {
+func TestNewExampleOutboxMessageNilReceiver(t *testing.T) {
 	var logic *AppLogic
-	record := &domain.ExampleRecord{}
 
-	err := logic.emitExampleEvent(context.Background(), record)
-	if err == nil {
+	if _, err := logic.newExampleOutboxMessage(&domain.ExampleRecord{}); err == nil {
 		t.Error("Expected error for nil receiver")
 	}
 }
 
-func TestEmitExampleEventNilRecord(t *testing.T) {
+func TestNewExampleOutboxMessageNilRecord(t *testing.T) {
 	logic, _ := NewAppLogic(nil, nil)
 
-	err := logic.emitExampleEvent(context.Background(), nil)
-	if err == nil {
+	if _, err := logic.newExampleOutboxMessage(nil); err == nil {
 		t.Error("Expected error for nil record")
 	}
 }
 
-func TestEmitExampleEventNoTopic(t *testing.T) {
+func TestNewExampleOutboxMessageNoTopic(t *testing.T) {
 	logic, _ := NewAppLogic(nil, nil)
-	record := &domain.ExampleRecord{}
 
-	err := logic.emitExampleEvent(context.Background(), record)
+	_, err := logic.newExampleOutboxMessage(&domain.ExampleRecord{})
 	if err == nil {
-		t.Error("Expected error for missing topic")
+		t.Fatal("Expected error for missing topic")
 	}
 	if err.Error() != "example topic not configured" {
 		t.Errorf("Unexpected error: %v", err)
 	}
 }
 
-func TestEmitExampleEventNoProducer(t *testing.T) {
+func TestNewExampleOutboxMessage(t *testing.T) {
 	logic, _ := NewAppLogic(nil, nil)
 	logic.SetExampleTopic("test-topic")
-	record := &domain.ExampleRecord{}
 
-	err := logic.emitExampleEvent(context.Background(), record)
-	if err == nil {
-		t.Error("Expected error for missing producer")
+	msg, err := logic.newExampleOutboxMessage(&domain.ExampleRecord{RecordId: "test-123"})
+	if err != nil {
+		t.Fatalf("newExampleOutboxMessage failed: %v", err)
 	}
-	if err.Error() != "event producer not configured" {
-		t.Errorf("Unexpected error: %v", err)
+	if msg.Topic != "test-topic" || msg.UUID == "" {
+		t.Errorf("Unexpected outbox row: %+v", msg)
+	}
+	if msg.Handler != exampleOutboxHandler || msg.Metadata[protoflow.MetadataKeyEventSchema] != "*domain.ExampleRecord" {
+		t.Errorf("Unexpected event schema: handler=%q metadata=%v", msg.Handler, msg.Metadata)
+	}
+	if msg.Metadata["source"] != "api.examples" {
+		t.Errorf("source = %q, want 'api.examples'", msg.Metadata["source"])
+	}
+
+	decoded := &domain.ExampleRecord{}
+	if err := protojson.Unmarshal([]byte(msg.Payload), decoded); err != nil || decoded.GetRecordId() != "test-123" {
+		t.Errorf("Payload does not decode to the record: %v, %v", decoded, err)
 	}
 }
 
-func TestEmitExampleEventSuccess(t *testing.T) {
-	logic, _ := NewAppLogic(nil, nil)
-	producer := &mockProducer{}
-	logic.SetEventProducer(producer)
+func TestHandleExampleStoresRecordWithOutboxRow(t *testing.T) {
+	store := database.NewMemoryStore()
+	logic, _ := NewAppLogic(store, nil)
 	logic.SetExampleTopic("test-topic")
+	ctx := context.Background()
 
-	record := &domain.ExampleRecord{
-		RecordId: "test-123",
-		Title:    "Test Record",
+	if err := logic.HandleExample(ctx, &domain.ExampleRecord{RecordId: "test-123"}); err != nil {
+		t.Fatalf("HandleExample failed: %v", err)
 	}
-
-	err := logic.emitExampleEvent(context.Background(), record)
-	if err != nil {
-		t.Errorf("emitExampleEvent failed: %v", err)
+	if _, err := store.GetExampleRecordByID(ctx, "test-123"); err != nil {
+		t.Errorf("record was not stored: %v", err)
 	}
-	if !producer.publishCalled {
-		t.Error("Producer.PublishProto was not called")
+	rows := store.OutboxMessages(exampleOutboxHandler)
+	if len(rows) != 1 || rows[0].Topic != "test-topic" || rows[0].Status != database.OutboxStatusPending {
+		t.Errorf("Unexpected outbox rows: %+v", rows)
 	}
-	if len(producer.published) != 1 {
-		t.Errorf("Expected 1 published message, got %d", len(producer.published))
+}
+
+func TestHandleExampleWithoutTopicStoresNothing(t *testing.T) {
+	store := database.NewMemoryStore()
+	logic, _ := NewAppLogic(store, nil)
+	ctx := context.Background()
+
+	if err := logic.HandleExample(ctx, &domain.ExampleRecord{RecordId: "test-123"}); err == nil {
+		t.Fatal("Expected error without example topic")
+	}
+	if _, err := store.GetExampleRecordByID(ctx, "test-123"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("record must not be stored without its event, got %v", err)
 	}
 }
 
-func TestEmitExampleEventPublishError(t *testing.T) {
+func TestExampleCRUDWithoutDatabase(t *testing.T) {
 	logic, _ := NewAppLogic(nil, nil)
-	producer := &mockProducer{publishErr: errors.New("publish failed")}
-	logic.SetEventProducer(producer)
-	logic.SetExampleTopic("test-topic")
+	ctx := context.Background()
 
-	record := &domain.ExampleRecord{
-		RecordId: "test-123",
-		Title:    "Test Record",
+	if _, err := logic.GetExample(ctx, "id"); err == nil {
+		t.Error("GetExample: expected error without database")
+	}
+	if _, _, err := logic.ListExamples(ctx, database.ExampleRecordFilter{}); err == nil {
+		t.Error("ListExamples: expected error without database")
+	}
+	if _, err := logic.UpdateExample(ctx, "id", &domain.ExampleRecord{}); err == nil {
+		t.Error("UpdateExample: expected error without database")
 	}
+	if _, err := logic.PatchExample(ctx, "id", []byte(`{}`)); err == nil {
+		t.Error("PatchExample: expected error without database")
+	}
+	if err := logic.DeleteExample(ctx, "id"); err == nil {
+		t.Error("DeleteExample: expected error without database")
+	}
+}
 
-	err := logic.emitExampleEvent(context.Background(), record)
-	if err == nil {
-		t.Error("Expected error when publish fails")
+func TestUpdateExampleRejectsMismatchedID(t *testing.T) {
+	logic, _ := NewAppLogic(&database.Database{}, nil)
+
+	_, err := logic.UpdateExample(context.Background(), "EX-1", &domain.ExampleRecord{RecordId: "EX-2"})
+	if !errors.Is(err, domain.ErrorBadRequest) {
+		t.Errorf("expected ErrorBadRequest, got %v", err)
 	}
-	if err.Error() != "publish failed" {
-		t.Errorf("Unexpected error: %v", err)
+}
+
+func TestExampleCRUDWithMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := database.NewMemoryStore()
+	logic, _ := NewAppLogic(store, nil)
+
+	_ = store.StoreExampleRecord(ctx, &domain.ExampleRecord{RecordId: "EX-1", Title: "first"})
+
+	updated, err := logic.UpdateExample(ctx, "EX-1", &domain.ExampleRecord{Title: "second"})
+	if err != nil {
+		t.Fatalf("UpdateExample() error = %v", err)
+	}
+	if updated.GetRecordId() != "EX-1" {
+		t.Errorf("RecordId = %q, want the path id", updated.GetRecordId())
+	}
+
+	patched, err := logic.PatchExample(ctx, "EX-1", []byte(`{"description":"patched"}`))
+	if err != nil {
+		t.Fatalf("PatchExample() error = %v", err)
+	}
+	if patched.GetTitle() != "second" || patched.GetDescription() != "patched" {
+		t.Errorf("unexpected patched record: %v", patched)
+	}
+
+	records, _, err := logic.ListExamples(ctx, database.ExampleRecordFilter{})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("ListExamples() = %v, %v", records, err)
+	}
+
+	if err := logic.DeleteExample(ctx, "EX-1"); err != nil {
+		t.Fatalf("DeleteExample() error = %v", err)
+	}
+	if _, err := logic.GetExample(ctx, "EX-1"); !errors.Is(err, domain.ErrorNotFound) {
+		t.Errorf("GetExample() after delete error = %v, want ErrorNotFound", err)
 	}
 }
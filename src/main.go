@@ -20,7 +20,9 @@ var (
 	commitDate  string
 )
 
-// main just loads config and inits logger. Rest is done in app.Run.
+// main just loads config and inits logger. Rest is done in app.Run, in
+// app.Migrate when started as "migrate [up|down|status]", or in app.APIKeys when
+// started as "api-keys issue".
 func main() {
 	appCfg, err := app.LoadConfig(
 		version,
@@ -34,6 +36,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.Migrate(appCfg, os.Args[2:], os.Stdout); err != nil {
+			fmt.Printf("migrate failed: %s\n", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "api-keys" {
+		if err := app.APIKeys(appCfg, os.Args[2:], os.Stdout); err != nil {
+			fmt.Printf("api-keys failed: %s\n", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 